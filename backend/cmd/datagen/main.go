@@ -23,9 +23,26 @@ func main() {
 		seed              = flag.Int64("seed", cfg.Seed, "random seed for deterministic generation")
 		outputDir         = flag.String("output-dir", "data", "directory to write users.json and transactions.json")
 		writeStdout       = flag.Bool("stdout", false, "write combined dataset to stdout instead of files")
+		sizeThreshold     = flag.Int64("size-threshold", defaultSizeThreshold, "refuse to generate when the estimated output exceeds this many bytes (0 disables)")
+		force             = flag.Bool("force", false, "generate even when the estimated output exceeds -size-threshold")
+		maxBytes          = flag.Int64("max-bytes", 0, "stop writing once output files reach this many bytes (0 = unlimited)")
+		extendDir         = flag.String("extend-dir", "", "directory with an existing dataset (flat or sharded) to extend; -users/-transactions are added to it")
+		printSchema       = flag.Bool("print-schema", false, "print the JSON Schema of the generated dataset and exit")
+		hubBias           = flag.Float64("hub-bias", cfg.HubBias, "probability of picking transaction endpoints by degree, growing hub users (0 = uniform, max 0.9)")
+		shardSize         = flag.Int("shard-size", 0, "split output into users-0001.json, transactions-0001.json, ... of at most this many records each (0 = single files)")
 	)
 	flag.Parse()
 
+	if *printSchema {
+		schema, err := generator.SchemaJSON()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to render schema: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintln(os.Stdout, string(schema))
+		return
+	}
+
 	genCfg := generator.Config{
 		NumUsers:                 *users,
 		NumTransactions:          *transactions,
@@ -34,13 +51,41 @@ func main() {
 		IPShareChance:            clampProbability(*ipShareChance),
 		DeviceShareChance:        clampProbability(*deviceShareChance),
 		Seed:                     *seed,
+		HubBias:                  *hubBias,
+	}
+
+	if *shardSize < 0 {
+		fmt.Fprintln(os.Stderr, "-shard-size must not be negative")
+		os.Exit(1)
+	}
+
+	var existing generator.Dataset
+	if *extendDir != "" {
+		loaded, err := generator.ReadDataset(*extendDir)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to read dataset to extend: %v\n", err)
+			os.Exit(1)
+		}
+		existing = loaded
+	}
+
+	estimate := generator.EstimateSize(generator.Config{
+		NumUsers:        genCfg.NumUsers + len(existing.Users),
+		NumTransactions: genCfg.NumTransactions + len(existing.Transactions),
+	})
+	if *sizeThreshold > 0 && estimate > *sizeThreshold {
+		if !*force {
+			fmt.Fprintf(os.Stderr, "estimated output %s exceeds -size-threshold %s; rerun with -force to continue\n", formatBytes(estimate), formatBytes(*sizeThreshold))
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "warning: estimated output %s exceeds -size-threshold %s (continuing because -force is set)\n", formatBytes(estimate), formatBytes(*sizeThreshold))
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
 	defer cancel()
 
 	gen := generator.New(genCfg)
-	dataset, err := gen.Generate(ctx)
+	dataset, err := gen.Extend(ctx, existing)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "generation failed: %v\n", err)
 		os.Exit(1)
@@ -54,12 +99,39 @@ func main() {
 		return
 	}
 
-	if err := generator.WriteDataset(dataset, *outputDir); err != nil {
+	report, err := generator.WriteDatasetSharded(dataset, *outputDir, *maxBytes, *shardSize)
+	if err != nil {
 		fmt.Fprintf(os.Stderr, "failed to write dataset: %v\n", err)
 		os.Exit(1)
 	}
+	if report.Truncated {
+		fmt.Fprintf(os.Stderr, "warning: output truncated at -max-bytes %s: wrote %d/%d users and %d/%d transactions\n",
+			formatBytes(*maxBytes), report.UsersWritten, len(dataset.Users), report.TransactionsWritten, len(dataset.Transactions))
+	}
+
+	if *extendDir != "" {
+		fmt.Fprintf(os.Stdout, "Extended %s with %d users and %d transactions\n", *extendDir, len(dataset.Users)-len(existing.Users), len(dataset.Transactions)-len(existing.Transactions))
+	}
+	fmt.Fprintf(os.Stdout, "Generated %d users and %d transactions into %s (%s)\n", report.UsersWritten, report.TransactionsWritten, *outputDir, formatBytes(report.BytesWritten))
+	if *shardSize > 0 {
+		fmt.Fprintf(os.Stdout, "Wrote %d shard files of up to %d records\n", len(report.Files), *shardSize)
+	}
+}
 
-	fmt.Fprintf(os.Stdout, "Generated %d users and %d transactions into %s\n", len(dataset.Users), len(dataset.Transactions), *outputDir)
+// defaultSizeThreshold comfortably covers the 10k/100k default (~70 MB) while catching runaway sizes.
+const defaultSizeThreshold = 1 << 30
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
 }
 
 func clampProbability(value float64) float64 {
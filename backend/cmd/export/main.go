@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/config"
+	"github.com/vanshika/fintrace/backend/internal/export"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+	"github.com/vanshika/fintrace/backend/internal/logging"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func main() {
+	filters := filterFlag{}
+	var (
+		entityName = flag.String("entity", "users", "Entity to export: users, transactions, edges or nodes")
+		formatName = flag.String("format", "json", "Output format: json, ndjson or csv")
+		output     = flag.String("output", "", "Output file (defaults to stdout)")
+		batchSize  = flag.Int("batch-size", 1000, "Records fetched per graph query")
+		checkpoint = flag.String("checkpoint", "", "Checkpoint file recording progress (defaults to <output>.checkpoint)")
+		resume     = flag.Bool("resume", false, "Continue an interrupted export from its checkpoint")
+		retries    = flag.Int("retries", 5, "Attempts per batch before giving up on transient graph errors")
+		redactPII  = flag.Bool("redact-pii", false, "Mask email and phone in user exports (implied by AUTH_REDACT_PII)")
+		amountFmt  = flag.String("amount-format", "raw", "Transaction amounts: raw floats, or currency to round to each currency's minor units")
+		columns    = flag.String("columns", "", "Comma-separated CSV columns to write, in order (defaults to all)")
+		delimiter  = flag.String("delimiter", ",", "CSV field separator: one character, or tab")
+	)
+	flag.Var(filters, "filter", "Filter as key=value using the API query parameter names (repeatable)")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(cfg.Logging).With("component", "export")
+
+	req, err := buildRequest(*entityName, *formatName, url.Values(filters), *batchSize, *retries)
+	if err != nil {
+		logger.Error("invalid export options", "error", err)
+		os.Exit(2)
+	}
+	req.RedactPII = *redactPII || cfg.Auth.RedactPII
+	if req.Amounts, err = export.ParseAmountFormat(*amountFmt); err != nil {
+		logger.Error("invalid export options", "error", err)
+		os.Exit(2)
+	}
+	if req.Format != export.FormatCSV && (*columns != "" || *delimiter != ",") {
+		logger.Error("invalid export options", "error", "-columns and -delimiter apply only to -format=csv")
+		os.Exit(2)
+	}
+	if req.Columns, err = export.ParseColumns(req.Entity, *columns); err != nil {
+		logger.Error("invalid export options", "error", err)
+		os.Exit(2)
+	}
+	if req.Delimiter, err = export.ParseDelimiter(*delimiter); err != nil {
+		logger.Error("invalid export options", "error", err)
+		os.Exit(2)
+	}
+
+	checkpointPath := *checkpoint
+	if checkpointPath == "" && *output != "" {
+		checkpointPath = *output + ".checkpoint"
+	}
+	if *resume && *output == "" {
+		logger.Error("-resume requires -output")
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	graphClient, err := graph.NewClientFromConfig(ctx, logger, cfg.Graph)
+	if err != nil {
+		logger.Error("failed to create graph client", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Graph.StrictDecoding {
+		repository.EnableStrictDecoding(logger.With("component", "decoding"))
+	}
+	defer func() {
+		if err := graphClient.Close(context.Background()); err != nil {
+			logger.Warn("closing graph client failed", "error", err)
+		}
+	}()
+
+	start := time.Now()
+	result, err := run(ctx, logger, repository.New(graphClient), req, *output, checkpointPath, *resume, filters.String())
+	if err != nil {
+		logger.Error("export failed", "error", err, "records", result.Records, "checkpoint", checkpointPath)
+		os.Exit(1)
+	}
+	attrs := []any{"records", result.Records, "duration", time.Since(start).String()}
+	if !result.MaxUpdatedAt.IsZero() {
+		// The next incremental export passes this as -filter since=...
+		attrs = append(attrs, "maxUpdatedAt", result.MaxUpdatedAt.UTC().Format(time.RFC3339Nano))
+	}
+	logger.Info("export complete", attrs...)
+}
+
+func buildRequest(entityName, formatName string, filters url.Values, batchSize, retries int) (export.Request, error) {
+	entity, err := export.ParseEntity(entityName)
+	if err != nil {
+		return export.Request{}, err
+	}
+	format, err := export.ParseFormat(formatName)
+	if err != nil {
+		return export.Request{}, err
+	}
+	req := export.Request{
+		Entity:    entity,
+		Format:    format,
+		BatchSize: batchSize,
+		Retry:     export.RetryPolicy{MaxAttempts: retries},
+	}
+	switch entity {
+	case export.EntityUsers:
+		req.Users, err = export.UsersOptionsFromValues(filters)
+	case export.EntityTransactions:
+		req.Transactions, err = export.TransactionsOptionsFromValues(filters)
+	case export.EntityEdges, export.EntityNodes:
+		req.Graph, err = export.GraphOptionsFromValues(filters)
+	}
+	return req, err
+}
+
+// checkpointState records how far an export to a file has progressed. Bytes is the
+// length of the output at the end of the last completed batch, so a resumed run can
+// drop any partially written batch before continuing.
+type checkpointState struct {
+	Entity  export.Entity `json:"entity"`
+	Format  export.Format `json:"format"`
+	Filters string        `json:"filters"`
+	// Layout records non-default CSV columns and delimiter, which a resumed run must
+	// match for the output to stay consistent.
+	Layout  string `json:"layout,omitempty"`
+	Records int    `json:"records"`
+	Bytes   int64  `json:"bytes"`
+	// MaxUpdatedAt carries Result.MaxUpdatedAt of the completed batches over a resume.
+	MaxUpdatedAt time.Time `json:"maxUpdatedAt"`
+}
+
+func csvLayout(req export.Request) string {
+	if len(req.Columns) == 0 && (req.Delimiter == 0 || req.Delimiter == ',') {
+		return ""
+	}
+	return fmt.Sprintf("columns=%s delimiter=%q", strings.Join(req.Columns, ","), req.Delimiter)
+}
+
+func run(ctx context.Context, logger *slog.Logger, repo *repository.Repository, req export.Request, output, checkpointPath string, resume bool, filters string) (export.Result, error) {
+	if output == "" {
+		return export.Run(ctx, repo, os.Stdout, req)
+	}
+
+	state := checkpointState{Entity: req.Entity, Format: req.Format, Filters: filters, Layout: csvLayout(req)}
+	flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	if resume {
+		saved, err := loadCheckpoint(checkpointPath)
+		if err != nil {
+			return export.Result{}, err
+		}
+		if saved.Entity != state.Entity || saved.Format != state.Format || saved.Filters != state.Filters {
+			return export.Result{}, fmt.Errorf("checkpoint %s was written for -entity=%s -format=%s with filters %q", checkpointPath, saved.Entity, saved.Format, saved.Filters)
+		}
+		if saved.Layout != state.Layout {
+			return export.Result{}, fmt.Errorf("checkpoint %s was written with CSV layout %q", checkpointPath, saved.Layout)
+		}
+		if saved.Records > 0 {
+			state = saved
+			flags = os.O_WRONLY
+		}
+	}
+
+	file, err := os.OpenFile(output, flags, 0o644)
+	if err != nil {
+		return export.Result{}, fmt.Errorf("open %s: %w", output, err)
+	}
+	defer file.Close()
+
+	if state.Records > 0 {
+		if err := file.Truncate(state.Bytes); err != nil {
+			return export.Result{}, fmt.Errorf("truncate %s: %w", output, err)
+		}
+		if _, err := file.Seek(state.Bytes, io.SeekStart); err != nil {
+			return export.Result{}, fmt.Errorf("seek %s: %w", output, err)
+		}
+		logger.Info("resuming export", "records", state.Records, "bytes", state.Bytes)
+	}
+
+	counter := &countingWriter{w: file, n: state.Bytes}
+	req.Offset = state.Records
+	resumedMax := state.MaxUpdatedAt
+	req.OnBatch = func(progress export.Result) error {
+		state.Records = progress.Records
+		state.Bytes = counter.n
+		if progress.MaxUpdatedAt.After(resumedMax) {
+			state.MaxUpdatedAt = progress.MaxUpdatedAt
+		}
+		logger.Debug("export batch written", "records", progress.Records, "bytes", counter.n)
+		return saveCheckpoint(checkpointPath, state)
+	}
+
+	result, err := export.Run(ctx, repo, counter, req)
+	if resumedMax.After(result.MaxUpdatedAt) {
+		result.MaxUpdatedAt = resumedMax
+	}
+	if err != nil {
+		return result, err
+	}
+	if err := file.Sync(); err != nil {
+		return result, fmt.Errorf("sync %s: %w", output, err)
+	}
+	if err := os.Remove(checkpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		logger.Warn("failed to remove checkpoint", "path", checkpointPath, "error", err)
+	}
+	return result, nil
+}
+
+func loadCheckpoint(path string) (checkpointState, error) {
+	var state checkpointState
+	payload, err := os.ReadFile(path)
+	if err != nil {
+		return state, fmt.Errorf("read checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(payload, &state); err != nil {
+		return state, fmt.Errorf("decode checkpoint %s: %w", path, err)
+	}
+	return state, nil
+}
+
+// saveCheckpoint writes the checkpoint via a temporary file so an interruption never
+// leaves a truncated checkpoint behind.
+func saveCheckpoint(path string, state checkpointState) error {
+	payload, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode checkpoint: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("create checkpoint: %w", err)
+	}
+	if _, err := tmp.Write(payload); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("replace checkpoint: %w", err)
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// filterFlag collects repeated -filter key=value flags.
+type filterFlag url.Values
+
+func (f filterFlag) String() string {
+	return url.Values(f).Encode()
+}
+
+func (f filterFlag) Set(value string) error {
+	key, val, ok := strings.Cut(value, "=")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" {
+		return fmt.Errorf("filter %q must be key=value", value)
+	}
+	url.Values(f).Set(key, strings.TrimSpace(val))
+	return nil
+}
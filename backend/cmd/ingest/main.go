@@ -6,7 +6,6 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"log/slog"
 	"os"
 	"os/signal"
 	"path/filepath"
@@ -14,6 +13,7 @@ import (
 	"time"
 
 	"github.com/vanshika/fintrace/backend/internal/config"
+	"github.com/vanshika/fintrace/backend/internal/generator"
 	"github.com/vanshika/fintrace/backend/internal/graph"
 	"github.com/vanshika/fintrace/backend/internal/logging"
 	"github.com/vanshika/fintrace/backend/internal/repository"
@@ -26,10 +26,16 @@ var (
 
 func main() {
 	var (
-		datasetDir   = flag.String("dataset-dir", "./seed-data", "Directory containing users.json and transactions.json")
-		usersPath    = flag.String("users", "", "Path to users.json (overrides dataset-dir)")
-		transactions = flag.String("transactions", "", "Path to transactions.json (overrides dataset-dir)")
+		datasetDir   = flag.String("dataset-dir", "./seed-data", "Directory containing users.json and transactions.json, or their users-NNNN.json/transactions-NNNN.json shards")
+		usersPath    = flag.String("users", "", "Users file, shard directory or glob such as 'data/users-000*.json' (overrides dataset-dir)")
+		transactions = flag.String("transactions", "", "Transactions file, shard directory or glob (overrides dataset-dir)")
 		workers      = flag.Int("workers", 4, "Number of concurrent workers for ingestion")
+		sessionWrite = flag.Bool("session-per-write", false, "Open a graph session for every write instead of one per worker")
+		userBatch    = flag.Int("user-batch-size", 500, "Users written per graph statement (1 writes each user separately)")
+		txBatch      = flag.Int("transaction-batch-size", 500, "Transactions written per graph statement (1 writes each transaction separately)")
+		skipSame     = flag.Bool("skip-unchanged", false, "Store a content hash on each node and skip records whose hash is unchanged")
+		deadLetter   = flag.String("dead-letter", "", "Write records that fail, with their errors, to this newline-JSON file instead of failing the run")
+		orderedErrs  = flag.Bool("ordered-errors", false, "Report failures in input order, each with the positions of the records it covers")
 	)
 	flag.Parse()
 
@@ -41,104 +47,185 @@ func main() {
 
 	logger := logging.New(cfg.Logging).With("component", "ingest")
 
-	userFile, txFile, err := resolveDatasetPaths(*datasetDir, *usersPath, *transactions)
+	userFiles, txFiles, err := resolveDatasetPaths(*datasetDir, *usersPath, *transactions)
 	if err != nil {
 		logger.Error("dataset resolution failed", "error", err)
 		os.Exit(1)
 	}
 
-	users, err := loadUserInputs(userFile)
+	users, err := loadUserInputs(userFiles)
 	if err != nil {
-		logger.Error("failed to load users", "error", err, "path", userFile)
+		logger.Error("failed to load users", "error", err)
 		os.Exit(1)
 	}
 	if len(users) == 0 {
-		logger.Error("users dataset empty", "path", userFile)
+		logger.Error("users dataset empty", "files", userFiles)
 		os.Exit(1)
 	}
 
-	txs, err := loadTransactionInputs(txFile)
+	txs, err := loadTransactionInputs(txFiles)
 	if err != nil {
-		logger.Error("failed to load transactions", "error", err, "path", txFile)
+		logger.Error("failed to load transactions", "error", err)
 		os.Exit(1)
 	}
 	if len(txs) == 0 {
-		logger.Error("transactions dataset empty", "path", txFile)
+		logger.Error("transactions dataset empty", "files", txFiles)
 		os.Exit(1)
 	}
+	logger.Info("loaded dataset", "userFiles", len(userFiles), "transactionFiles", len(txFiles))
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	graphClient, err := buildGraphClient(ctx, logger, cfg)
+	graphClient, err := graph.NewClientFromConfig(ctx, logger, cfg.Graph)
 	if err != nil {
 		logger.Error("failed to create graph client", "error", err)
 		os.Exit(1)
 	}
+	if cfg.Graph.StrictDecoding {
+		repository.EnableStrictDecoding(logger.With("component", "decoding"))
+	}
 	defer func() {
 		if err := graphClient.Close(context.Background()); err != nil {
 			logger.Warn("closing graph client failed", "error", err)
 		}
 	}()
 
-	repo := repository.New(graphClient)
-	svc := service.NewRelationshipService(repo, nil)
-	ingestor := service.NewBulkIngestor(svc, *workers)
+	attributes, err := service.NewAttributeGeneratorFromConfig(logger, cfg.Attributes)
+	if err != nil {
+		logger.Error("invalid attribute configuration", "error", err)
+		os.Exit(1)
+	}
+
+	repo := repository.New(graphClient).WithDuplicateTransactions(repository.DuplicateTransactionOptions{
+		Mode:   repository.DuplicateTransactionMode(cfg.Ingest.DuplicateTransactions),
+		Logger: logger.With("component", "duplicates"),
+	}).WithContentHashing(*skipSame).WithPromotedMetadataKeys(cfg.Graph.PromotedMetadataKeys...).
+		WithLinkDecay(repository.LinkDecay{HalfLife: cfg.Graph.LinkDecayHalfLife, OnWrite: cfg.Graph.LinkDecayOnWrite}).
+		WithReceivedFromEdges(cfg.Graph.ReceivedFromEdges)
+	svc := service.NewRelationshipService(repo, attributes)
+	svc.WithValueSynonyms(service.ValueSynonyms{Types: cfg.Ingest.TypeSynonyms, Channels: cfg.Ingest.ChannelSynonyms})
+	svc.WithEntityLimits(service.EntityLimits{
+		MaxAttributes:     cfg.Ingest.MaxAttributes,
+		MaxPaymentMethods: cfg.Ingest.MaxPaymentMethods,
+		Strict:            cfg.Ingest.StrictLimits,
+	}, logger)
+	ingestor := service.NewBulkIngestor(svc, *workers).WithSessionPerWrite(*sessionWrite).
+		WithUserBatchSize(*userBatch).
+		WithTransactionBatchSize(*txBatch).
+		WithOrderedResults(*orderedErrs)
+	if *deadLetter != "" {
+		file, err := os.Create(*deadLetter)
+		if err != nil {
+			logger.Error("failed to open dead-letter file", "error", err)
+			os.Exit(1)
+		}
+		defer file.Close()
+		ingestor.WithDeadLetterSink(service.NewJSONLinesDeadLetterSink(file))
+	}
 
 	start := time.Now()
-	logger.Info("ingesting users", "count", len(users), "workers", *workers)
-	if err := ingestor.IngestUsers(ctx, users); err != nil {
-		logger.Error("user ingestion failed", "error", err)
+	logger.Info("ingesting users", "count", len(users), "workers", *workers, "sessionPerWrite", *sessionWrite, "skipUnchanged", *skipSame)
+	userResult, err := ingestor.IngestUsers(ctx, users)
+	if err != nil {
+		logger.Error("user ingestion failed", "error", err, "written", userResult.Written, "skipped", userResult.Skipped)
 		os.Exit(1)
 	}
 
 	logger.Info("ingesting transactions", "count", len(txs))
-	if err := ingestor.IngestTransactions(ctx, txs); err != nil {
-		logger.Error("transaction ingestion failed", "error", err)
+	txResult, err := ingestor.IngestTransactions(ctx, txs)
+	if err != nil {
+		logger.Error("transaction ingestion failed", "error", err, "written", txResult.Written, "skipped", txResult.Skipped)
 		os.Exit(1)
 	}
 
-	logger.Info("ingestion complete", "duration", time.Since(start).String(), "users", len(users), "transactions", len(txs))
+	logger.Info("ingestion complete",
+		"duration", time.Since(start).String(),
+		"users", len(users),
+		"usersSkipped", userResult.Skipped,
+		"transactions", len(txs),
+		"transactionsSkipped", txResult.Skipped,
+	)
+	if failed := userResult.DeadLettered + txResult.DeadLettered; failed > 0 {
+		logger.Warn("some records failed and were dead-lettered",
+			"file", *deadLetter,
+			"users", userResult.DeadLettered,
+			"transactions", txResult.DeadLettered,
+		)
+	}
 }
 
-func resolveDatasetPaths(baseDir, usersPath, transactionsPath string) (string, string, error) {
-	resolve := func(explicitPath, fallbackFile string) (string, error) {
-		if explicitPath != "" {
-			if _, err := os.Stat(explicitPath); err != nil {
-				return "", fmt.Errorf("stat %s: %w", explicitPath, err)
+// resolveDatasetPaths returns the user and transaction files to load. An explicit path may
+// be a file, a directory of shards or a glob; otherwise baseDir is searched for the flat
+// file, then for its shards.
+func resolveDatasetPaths(baseDir, usersPath, transactionsPath string) ([]string, []string, error) {
+	resolve := func(explicitPath, name string) ([]string, error) {
+		if explicitPath == "" {
+			files, err := generator.DatasetFiles(baseDir, name)
+			if err != nil {
+				return nil, err
 			}
-			return explicitPath, nil
+			if len(files) == 0 {
+				return nil, fmt.Errorf("%w: %s", errMissingDataset, filepath.Join(baseDir, name+".json"))
+			}
+			return files, nil
 		}
-		path := filepath.Join(baseDir, fallbackFile)
-		if _, err := os.Stat(path); err != nil {
-			return "", fmt.Errorf("%w: %s", errMissingDataset, path)
+
+		if info, err := os.Stat(explicitPath); err == nil {
+			if !info.IsDir() {
+				return []string{explicitPath}, nil
+			}
+			files, err := generator.DatasetFiles(explicitPath, name)
+			if err != nil {
+				return nil, err
+			}
+			if len(files) == 0 {
+				return nil, fmt.Errorf("%w: no %s files in %s", errMissingDataset, name, explicitPath)
+			}
+			return files, nil
+		}
+		files, err := filepath.Glob(explicitPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %s: %w", explicitPath, err)
+		}
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w: %s", errMissingDataset, explicitPath)
 		}
-		return path, nil
+		generator.SortShardFiles(files)
+		return files, nil
 	}
 
-	usersFile, err := resolve(usersPath, "users.json")
+	usersFiles, err := resolve(usersPath, "users")
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
-	txsFile, err := resolve(transactionsPath, "transactions.json")
+	txsFiles, err := resolve(transactionsPath, "transactions")
 	if err != nil {
-		return "", "", err
+		return nil, nil, err
 	}
-	return usersFile, txsFile, nil
+	return usersFiles, txsFiles, nil
 }
 
-func loadUserInputs(path string) ([]service.UserInput, error) {
+func loadUserInputs(paths []string) ([]service.UserInput, error) {
 	var users []service.UserInput
-	if err := loadJSON(path, &users); err != nil {
-		return nil, err
+	for _, path := range paths {
+		var shard []service.UserInput
+		if err := loadJSON(path, &shard); err != nil {
+			return nil, err
+		}
+		users = append(users, shard...)
 	}
 	return users, nil
 }
 
-func loadTransactionInputs(path string) ([]service.TransactionInput, error) {
+func loadTransactionInputs(paths []string) ([]service.TransactionInput, error) {
 	var txs []service.TransactionInput
-	if err := loadJSON(path, &txs); err != nil {
-		return nil, err
+	for _, path := range paths {
+		var shard []service.TransactionInput
+		if err := loadJSON(path, &shard); err != nil {
+			return nil, err
+		}
+		txs = append(txs, shard...)
 	}
 	return txs, nil
 }
@@ -151,31 +238,9 @@ func loadJSON(path string, target any) error {
 	defer file.Close()
 
 	decoder := json.NewDecoder(file)
+	decoder.UseNumber()
 	if err := decoder.Decode(target); err != nil {
 		return fmt.Errorf("decode %s: %w", path, err)
 	}
 	return nil
 }
-
-func buildGraphClient(ctx context.Context, logger *slog.Logger, cfg config.Config) (graph.Client, error) {
-	if cfg.Graph.URI == "" {
-		return nil, fmt.Errorf("GRAPH_URI is required for ingestion")
-	}
-	opts := graph.Options{
-		URI:            cfg.Graph.URI,
-		Database:       cfg.Graph.Database,
-		Username:       cfg.Graph.Username,
-		Password:       cfg.Graph.Password,
-		MaxConnections: cfg.Graph.MaxConnections,
-	}
-	client, err := graph.NewNeo4jClient(ctx, opts)
-	if err != nil {
-		return nil, err
-	}
-	if err := client.VerifyConnectivity(ctx); err != nil {
-		_ = client.Close(ctx)
-		return nil, err
-	}
-	logger.Info("connected to graph", "uri", cfg.Graph.URI, "database", cfg.Graph.Database)
-	return client, nil
-}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/config"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+	"github.com/vanshika/fintrace/backend/internal/logging"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+const usage = `Usage: maintenance <command> [flags]
+
+Commands:
+  prune              Delete Attribute and PaymentMethod nodes that have no relationships
+  backfill-origins   Set origin on HAS_ATTRIBUTE edges written before origins were tracked
+  rebuild-stats      Recompute the denormalised counters served by /stats
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+	command, args := os.Args[1], os.Args[2:]
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load config: %v\n", err)
+		os.Exit(1)
+	}
+
+	logger := logging.New(cfg.Logging).With("component", "maintenance", "command", command)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	var run func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error
+	switch command {
+	case "prune":
+		run = pruneCommand(args)
+	case "backfill-origins":
+		run = backfillOriginsCommand(args)
+	case "rebuild-stats":
+		run = rebuildStatsCommand(args)
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	graphClient, err := graph.NewClientFromConfig(ctx, logger, cfg.Graph)
+	if err != nil {
+		logger.Error("failed to create graph client", "error", err)
+		os.Exit(1)
+	}
+	if cfg.Graph.StrictDecoding {
+		repository.EnableStrictDecoding(logger.With("component", "decoding"))
+	}
+	defer func() {
+		if err := graphClient.Close(context.Background()); err != nil {
+			logger.Warn("closing graph client failed", "error", err)
+		}
+	}()
+
+	start := time.Now()
+	if err := run(ctx, logger, repository.New(graphClient)); err != nil {
+		logger.Error("maintenance command failed", "error", err)
+		os.Exit(1)
+	}
+	logger.Info("maintenance command complete", "duration", time.Since(start).String())
+}
+
+func pruneCommand(args []string) func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	batchSize := fs.Int("batch-size", 1000, "Maximum nodes deleted per write transaction")
+	_ = fs.Parse(args)
+
+	return func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+		removed, err := repo.PruneOrphansWithOptions(ctx, repository.BatchOptions{
+			BatchSize: *batchSize,
+			OnBatch: func(label string, count int) {
+				logger.Info("pruned batch", "label", label, "removed", count)
+			},
+		})
+		if err != nil {
+			return err
+		}
+		logger.Info("pruned orphaned nodes", "removed", removed)
+		return nil
+	}
+}
+
+func backfillOriginsCommand(args []string) func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+	fs := flag.NewFlagSet("backfill-origins", flag.ExitOnError)
+	batchSize := fs.Int("batch-size", 1000, "Maximum edges updated per write transaction")
+	_ = fs.Parse(args)
+
+	return func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+		updated, err := repo.BackfillAttributeOrigins(ctx, repository.BatchOptions{
+			BatchSize: *batchSize,
+			OnBatch: func(label string, count int) {
+				logger.Info("backfilled batch", "label", label, "updated", count)
+			},
+		})
+		if err != nil {
+			return err
+		}
+		logger.Info("backfilled attribute origins", "updated", updated)
+		return nil
+	}
+}
+
+func rebuildStatsCommand(args []string) func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+	fs := flag.NewFlagSet("rebuild-stats", flag.ExitOnError)
+	_ = fs.Parse(args)
+
+	return func(ctx context.Context, logger *slog.Logger, repo *repository.Repository) error {
+		stats, err := repo.RebuildStats(ctx)
+		if err != nil {
+			return err
+		}
+		logger.Info("rebuilt stats",
+			"users", stats.TotalUsers,
+			"transactions", stats.TotalTransactions,
+			"statuses", len(stats.TransactionsByStatus),
+		)
+		return nil
+	}
+}
@@ -4,13 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log/slog"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/vanshika/fintrace/backend/internal/config"
+	"github.com/vanshika/fintrace/backend/internal/export"
 	"github.com/vanshika/fintrace/backend/internal/graph"
 	"github.com/vanshika/fintrace/backend/internal/logging"
 	"github.com/vanshika/fintrace/backend/internal/repository"
@@ -18,6 +19,8 @@ import (
 	"github.com/vanshika/fintrace/backend/internal/service"
 )
 
+const exportCleanupInterval = 10 * time.Minute
+
 func main() {
 	ctx := context.Background()
 
@@ -29,11 +32,15 @@ func main() {
 
 	logger := logging.New(cfg.Logging)
 
-	graphClient, err := buildGraphClient(ctx, logger, cfg)
+	queries := graph.NewQueryRegistry()
+	graphClient, err := graph.NewClientFromConfig(ctx, logger, cfg.Graph, graph.WithQueryRegistry(queries))
 	if err != nil {
 		logger.Error("failed to create graph client", "error", err)
 		os.Exit(1)
 	}
+	if cfg.Graph.StrictDecoding {
+		repository.EnableStrictDecoding(logger.With("component", "decoding"))
+	}
 	defer func() {
 		if graphClient != nil {
 			if err := graphClient.Close(context.Background()); err != nil {
@@ -42,15 +49,70 @@ func main() {
 		}
 	}()
 
-	repo := repository.New(graphClient)
-	relationshipService := service.NewRelationshipService(repo, nil)
-	apiHandlers := server.NewAPIHandlers(logger, relationshipService)
+	attributes, err := service.NewAttributeGeneratorFromConfig(logger, cfg.Attributes)
+	if err != nil {
+		logger.Error("invalid attribute configuration", "error", err)
+		os.Exit(1)
+	}
+
+	repo := repository.New(graphClient).WithDuplicateTransactions(repository.DuplicateTransactionOptions{
+		Mode:   repository.DuplicateTransactionMode(cfg.Ingest.DuplicateTransactions),
+		Logger: logger.With("component", "duplicates"),
+	}).WithRelationshipSectionCap(cfg.Graph.SectionCap).WithPromotedMetadataKeys(cfg.Graph.PromotedMetadataKeys...).
+		WithLinkDecay(repository.LinkDecay{HalfLife: cfg.Graph.LinkDecayHalfLife, OnWrite: cfg.Graph.LinkDecayOnWrite}).
+		WithReceivedFromEdges(cfg.Graph.ReceivedFromEdges)
+	relationshipService := service.NewRelationshipService(repo, attributes)
+	relationshipService.WithValueSynonyms(service.ValueSynonyms{Types: cfg.Ingest.TypeSynonyms, Channels: cfg.Ingest.ChannelSynonyms})
+	relationshipService.WithEntityLimits(service.EntityLimits{
+		MaxAttributes:     cfg.Ingest.MaxAttributes,
+		MaxPaymentMethods: cfg.Ingest.MaxPaymentMethods,
+		Strict:            cfg.Ingest.StrictLimits,
+	}, logger)
+	if err := relationshipService.WithSortDefaults(service.SortDefaults{
+		Users:        cfg.HTTP.UserSortDefaults,
+		Transactions: cfg.HTTP.TransactionSortDefaults,
+	}); err != nil {
+		logger.Error("invalid sort defaults", "error", err)
+		os.Exit(1)
+	}
+	apiHandlers := server.NewAPIHandlers(logger, relationshipService).
+		WithPIIRedaction(cfg.Auth.RedactPII).
+		WithLookupLimit(cfg.HTTP.LookupMaxIDs).
+		WithPathBatchLimit(cfg.HTTP.PathBatchMaxPairs).
+		WithPathPayloadLimit(cfg.HTTP.PathMaxElements).
+		WithExportSource(repo).
+		WithQueryRegistry(queries)
+
+	var exportJobs *export.JobManager
+	if cfg.Export.JobsEnabled {
+		exportJobs, err = export.NewJobManager(repo, export.JobManagerOptions{
+			Dir:        cfg.Export.JobDir,
+			Retention:  cfg.Export.JobRetention,
+			MaxRunning: cfg.Export.MaxRunningJobs,
+			Logger:     logger.With("component", "export-jobs"),
+		})
+		if err != nil {
+			logger.Error("failed to set up export jobs", "error", err)
+			os.Exit(1)
+		}
+		apiHandlers.WithExportJobs(exportJobs)
+	}
+	cleanupCtx, stopCleanup := context.WithCancel(ctx)
+	defer stopCleanup()
+	if exportJobs != nil {
+		go exportJobs.RunCleanup(cleanupCtx, exportCleanupInterval)
+	}
 
+	readiness := &server.Readiness{}
 	router := server.NewRouter(logger, server.RouterDependencies{
-		Health:           server.GraphHealthService{Client: graphClient},
+		Health:           server.GraphHealthService{Client: graphClient, Query: cfg.Graph.HealthQuery},
 		API:              apiHandlers,
+		Auth:             server.NewAuthorizer(cfg.Auth.APIKeys),
+		Readiness:        readiness,
 		AllowedOrigins:   parseAllowedOrigins(cfg.HTTP.AllowedOriginsCSV),
 		AllowCredentials: true,
+		MetricsEnabled:   cfg.HTTP.MetricsEnabled,
+		Cache:            server.CachePolicy{Stats: cfg.HTTP.StatsCacheMaxAge},
 	})
 
 	srv := server.New(logger, cfg.HTTP, router)
@@ -66,6 +128,10 @@ func main() {
 	select {
 	case sig := <-sigCh:
 		logger.Info("received shutdown signal", "signal", sig.String())
+		// A second signal skips the rest of the drain delay.
+		drainCtx, stopDrain := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+		srv.Drain(drainCtx, readiness)
+		stopDrain()
 	case err := <-errCh:
 		if err != nil && !errors.Is(err, context.Canceled) {
 			logger.Error("server stopped unexpectedly", "error", err)
@@ -78,21 +144,11 @@ func main() {
 	if err := srv.Shutdown(shutdownCtx); err != nil {
 		logger.Error("graceful shutdown failed", "error", err)
 	}
-}
-
-func buildGraphClient(ctx context.Context, logger *slog.Logger, cfg config.Config) (graph.Client, error) {
-	if cfg.Graph.URI == "" {
-		return nil, graph.ErrMissingURI
-	}
-
-	opts := graph.Options{
-		URI:            cfg.Graph.URI,
-		Database:       cfg.Graph.Database,
-		Username:       cfg.Graph.Username,
-		Password:       cfg.Graph.Password,
-		MaxConnections: cfg.Graph.MaxConnections,
+	if exportJobs != nil {
+		if err := exportJobs.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("export jobs did not stop cleanly", "error", err)
+		}
 	}
-	return graph.NewNeo4jClient(ctx, opts)
 }
 
 func parseAllowedOrigins(csv string) []string {
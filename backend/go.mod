@@ -2,4 +2,9 @@ module github.com/vanshika/fintrace/backend
 
 go 1.21
 
-require github.com/neo4j/neo4j-go-driver/v5 v5.28.4
+require (
+	github.com/neo4j/neo4j-go-driver/v5 v5.28.4
+	golang.org/x/net v0.25.0
+)
+
+require golang.org/x/text v0.15.0 // indirect
@@ -3,15 +3,37 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
 // Config aggregates application configuration values.
 type Config struct {
-	HTTP    HTTPConfig
-	Graph   GraphConfig
-	Logging LoggingConfig
+	HTTP       HTTPConfig
+	Graph      GraphConfig
+	Logging    LoggingConfig
+	Attributes AttributeConfig
+	Auth       AuthConfig
+	Export     ExportConfig
+	Ingest     IngestConfig
+}
+
+// IngestConfig governs how writes treat existing data.
+type IngestConfig struct {
+	// DuplicateTransactions is overwrite (default), reject or version.
+	DuplicateTransactions string
+	// TypeSynonyms and ChannelSynonyms map synonyms of transaction types and channels
+	// to the canonical value stored in their place.
+	TypeSynonyms    map[string]string
+	ChannelSynonyms map[string]string
+	// MaxAttributes and MaxPaymentMethods cap what one user or transaction may carry;
+	// zero disables a cap. StrictLimits rejects an entity over a cap instead of
+	// truncating it.
+	MaxAttributes     int
+	MaxPaymentMethods int
+	StrictLimits      bool
 }
 
 // HTTPConfig governs HTTP server behaviour.
@@ -24,6 +46,31 @@ type HTTPConfig struct {
 	ShutdownTimeout   time.Duration
 	MetricsEnabled    bool
 	AllowedOriginsCSV string
+	// ListenNetwork selects the listener type: "tcp" (default) or "unix".
+	ListenNetwork string
+	// ListenAddress overrides Host:Port for tcp and is the socket path for unix.
+	ListenAddress string
+	// H2CEnabled serves cleartext HTTP/2 alongside HTTP/1.1 when TLS is not configured.
+	H2CEnabled  bool
+	TLSCertFile string
+	TLSKeyFile  string
+	// DrainDelay is how long /healthz reports draining before shutdown begins, giving
+	// load balancers time to stop routing traffic. Zero shuts down immediately.
+	DrainDelay time.Duration
+	// LookupMaxIDs caps the IDs accepted by one batch lookup request.
+	LookupMaxIDs int
+	// PathBatchMaxPairs caps the pairs accepted by one batch shortest-path request.
+	PathBatchMaxPairs int
+	// StatsCacheMaxAge is how long clients may reuse a GET /stats response. Zero sends
+	// no-store.
+	StatsCacheMaxAge time.Duration
+	// PathMaxElements caps the nodes plus edges of one path, cycle or neighborhood
+	// response.
+	PathMaxElements int
+	// UserSortDefaults and TransactionSortDefaults map sort fields to the direction used
+	// when a listing request gives no sortOrder.
+	UserSortDefaults        map[string]string
+	TransactionSortDefaults map[string]string
 }
 
 // GraphConfig describes connectivity to the graph database (Neptune/Neo4j).
@@ -33,8 +80,79 @@ type GraphConfig struct {
 	Username       string
 	Password       string
 	MaxConnections int
+	// StrictDecoding logs record values whose type does not match what the queries expect.
+	StrictDecoding bool
+	// SectionCap is the most rows any relationship view section returns.
+	SectionCap int
+	// MaxConcurrentReads and MaxConcurrentWrites cap in-flight operations per class
+	// (0 = unlimited); callers wait up to AcquireTimeout for a free slot.
+	MaxConcurrentReads  int
+	MaxConcurrentWrites int
+	AcquireTimeout      time.Duration
+	// ReadTimeout and WriteTimeout bound individual graph reads and writes (0 = unbounded).
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// PromotedMetadataKeys are transaction metadata keys stored as native properties.
+	PromotedMetadataKeys []string
+	// QueryLog is the debug query logging mode: off (default), omit, redact or hash.
+	QueryLog string
+	// HealthQuery, when set, is run against the configured database by readiness probes.
+	HealthQuery string
+	// LinkDecayHalfLife is the timestamp gap that halves a LINKED_TO score (0 = no decay);
+	// LinkDecayOnWrite also decays links written at ingest, not just recomputed ones.
+	LinkDecayHalfLife time.Duration
+	LinkDecayOnWrite  bool
+	// ReceivedFromEdges keeps writing RECEIVED_FROM edges beside SENT_TO for tools that
+	// still read them.
+	ReceivedFromEdges bool
+}
+
+// AttributeConfig tunes derived attribute generation.
+type AttributeConfig struct {
+	// DayBucketSampleRate is the fraction of transactions emitting TX_DAY_BUCKET (1 = all).
+	DayBucketSampleRate float64
+	SamplingSeed        int64
+	// HashAlgorithm is sha256 (default), sha512 or hmac-sha256.
+	HashAlgorithm string
+	// HashKey is the secret for HMAC algorithms.
+	HashKey string
+	// HashLength truncates attribute hashes to this many hex characters (0 = full digest).
+	HashLength int
+	// CustomTypes are deployment-specific attribute types such as NATIONAL_ID.
+	CustomTypes []CustomAttributeType
+}
+
+// CustomAttributeType declares an extra attribute type and how its values are normalised.
+type CustomAttributeType struct {
+	Type       string
+	Normalizer string
+	Redact     bool
+}
+
+// AuthConfig configures API-key authentication. Authentication is disabled when no
+// keys are configured.
+type AuthConfig struct {
+	// APIKeys maps each accepted key to the scopes it grants.
+	APIKeys map[string][]string
+	// RedactPII masks email, phone and address fields in user lists and exports for
+	// callers whose key lacks the pii scope.
+	RedactPII bool
+}
+
+// ExportConfig configures asynchronous export jobs served by the API.
+type ExportConfig struct {
+	JobsEnabled bool
+	// JobDir receives export artifacts.
+	JobDir string
+	// JobRetention is how long finished jobs and their artifacts are kept.
+	JobRetention time.Duration
+	// MaxRunningJobs caps the export jobs running at once (0 = unlimited).
+	MaxRunningJobs int
 }
 
+// KnownScopes lists the scopes an API key may be granted.
+var KnownScopes = []string{"read", "write", "export", "pii", "debug", "admin"}
+
 // LoggingConfig controls structured logging settings.
 type LoggingConfig struct {
 	Level         string
@@ -45,6 +163,7 @@ type LoggingConfig struct {
 
 const (
 	defaultHost             = "0.0.0.0"
+	defaultListenNetwork    = "tcp"
 	defaultPort             = 8080
 	defaultReadTimeout      = 10 * time.Second
 	defaultWriteTimeout     = 15 * time.Second
@@ -53,6 +172,19 @@ const (
 	defaultLoggingLevel     = "info"
 	defaultLoggingFormat    = "text"
 	defaultGraphMaxSessions = 10
+	defaultSectionCap       = 1000
+	defaultAcquireTimeout   = 5 * time.Second
+	defaultLookupMaxIDs     = 500
+	defaultPathBatchMax     = 100
+	defaultPathMaxElements  = 5000
+	defaultStatsCacheMaxAge = 30 * time.Second
+	defaultLinkDecay        = 90 * 24 * time.Hour
+	defaultDayBucketSample  = 1.0
+	defaultHashAlgorithm    = "sha256"
+	defaultExportRetention  = 24 * time.Hour
+	defaultExportMaxRunning = 2
+	defaultMaxAttributes    = 200
+	defaultMaxPayments      = 50
 )
 
 // Load reads configuration from environment variables, applying defaults.
@@ -77,7 +209,31 @@ func Load() (Config, error) {
 			Username:       os.Getenv("GRAPH_USERNAME"),
 			Password:       os.Getenv("GRAPH_PASSWORD"),
 			MaxConnections: parseIntWithDefault("GRAPH_MAX_CONNECTIONS", defaultGraphMaxSessions),
+			StrictDecoding: parseBoolWithDefault("GRAPH_STRICT_DECODING", false),
+			SectionCap:     parseIntWithDefault("GRAPH_RELATIONSHIP_SECTION_CAP", defaultSectionCap),
 		},
+		Attributes: AttributeConfig{
+			SamplingSeed:  int64(parseIntWithDefault("ATTRIBUTE_SAMPLING_SEED", 0)),
+			HashAlgorithm: strings.ToLower(valueOrDefault("ATTRIBUTE_HASH_ALGORITHM", defaultHashAlgorithm)),
+			HashKey:       os.Getenv("ATTRIBUTE_HASH_KEY"),
+		},
+	}
+
+	dayBucketRate, err := parseFloatWithDefault("ATTRIBUTE_DAY_BUCKET_SAMPLE_RATE", defaultDayBucketSample)
+	if err != nil {
+		return Config{}, err
+	}
+	if dayBucketRate <= 0 || dayBucketRate > 1 {
+		return Config{}, fmt.Errorf("ATTRIBUTE_DAY_BUCKET_SAMPLE_RATE must be in (0, 1], got %v", dayBucketRate)
+	}
+	cfg.Attributes.DayBucketSampleRate = dayBucketRate
+
+	if v := os.Getenv("ATTRIBUTE_HASH_LENGTH"); v != "" {
+		length, err := strconv.Atoi(v)
+		if err != nil || length < 0 {
+			return Config{}, fmt.Errorf("invalid ATTRIBUTE_HASH_LENGTH %q: expected a non-negative integer", v)
+		}
+		cfg.Attributes.HashLength = length
 	}
 
 	port, err := parsePort("SERVER_PORT", defaultPort)
@@ -118,6 +274,58 @@ func Load() (Config, error) {
 		}
 	}
 
+	cfg.Graph.MaxConcurrentReads = parseIntWithDefault("GRAPH_MAX_CONCURRENT_READS", 0)
+	cfg.Graph.MaxConcurrentWrites = parseIntWithDefault("GRAPH_MAX_CONCURRENT_WRITES", 0)
+	cfg.Graph.AcquireTimeout = defaultAcquireTimeout
+	if v := os.Getenv("GRAPH_ACQUIRE_TIMEOUT"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("invalid GRAPH_ACQUIRE_TIMEOUT %q: expected a non-negative duration", v)
+		}
+		cfg.Graph.AcquireTimeout = d
+	}
+	for _, setting := range []struct {
+		env string
+		dst *time.Duration
+	}{
+		{"GRAPH_READ_TIMEOUT", &cfg.Graph.ReadTimeout},
+		{"GRAPH_WRITE_TIMEOUT", &cfg.Graph.WriteTimeout},
+	} {
+		if v := os.Getenv(setting.env); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return Config{}, fmt.Errorf("invalid %s %q: expected a non-negative duration", setting.env, v)
+			}
+			*setting.dst = d
+		}
+	}
+
+	if v := os.Getenv("SERVER_DRAIN_DELAY"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("invalid SERVER_DRAIN_DELAY %q: expected a non-negative duration", v)
+		}
+		cfg.HTTP.DrainDelay = d
+	}
+
+	cfg.HTTP.StatsCacheMaxAge = defaultStatsCacheMaxAge
+	if v := os.Getenv("SERVER_CACHE_STATS_MAX_AGE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("invalid SERVER_CACHE_STATS_MAX_AGE %q: expected a non-negative duration", v)
+		}
+		cfg.HTTP.StatsCacheMaxAge = d
+	}
+
+	cfg.HTTP.LookupMaxIDs = parseIntWithDefault("SERVER_LOOKUP_MAX_IDS", defaultLookupMaxIDs)
+	cfg.HTTP.PathBatchMaxPairs = parseIntWithDefault("SERVER_PATH_BATCH_MAX_PAIRS", defaultPathBatchMax)
+	cfg.HTTP.PathMaxElements = parseIntWithDefault("SERVER_PATH_MAX_ELEMENTS", defaultPathMaxElements)
+	if cfg.HTTP.UserSortDefaults, err = parseSortDefaults("SERVER_USER_SORT_DEFAULTS", os.Getenv("SERVER_USER_SORT_DEFAULTS")); err != nil {
+		return Config{}, err
+	}
+	if cfg.HTTP.TransactionSortDefaults, err = parseSortDefaults("SERVER_TRANSACTION_SORT_DEFAULTS", os.Getenv("SERVER_TRANSACTION_SORT_DEFAULTS")); err != nil {
+		return Config{}, err
+	}
 	cfg.HTTP.MetricsEnabled = parseBoolWithDefault("SERVER_METRICS_ENABLED", false)
 	allowedOriginsCSV := os.Getenv("SERVER_ALLOWED_ORIGINS")
 	if allowedOriginsCSV == "" {
@@ -125,9 +333,241 @@ func Load() (Config, error) {
 	}
 	cfg.HTTP.AllowedOriginsCSV = allowedOriginsCSV
 
+	cfg.HTTP.ListenNetwork = strings.ToLower(valueOrDefault("SERVER_LISTEN_NETWORK", defaultListenNetwork))
+	cfg.HTTP.ListenAddress = os.Getenv("SERVER_LISTEN_ADDRESS")
+	switch cfg.HTTP.ListenNetwork {
+	case "tcp":
+	case "unix":
+		if cfg.HTTP.ListenAddress == "" {
+			return Config{}, fmt.Errorf("SERVER_LISTEN_ADDRESS is required when SERVER_LISTEN_NETWORK=unix")
+		}
+	default:
+		return Config{}, fmt.Errorf("invalid SERVER_LISTEN_NETWORK %q: expected tcp or unix", cfg.HTTP.ListenNetwork)
+	}
+	cfg.HTTP.H2CEnabled = parseBoolWithDefault("SERVER_H2C_ENABLED", false)
+	cfg.HTTP.TLSCertFile = os.Getenv("SERVER_TLS_CERT_FILE")
+	cfg.HTTP.TLSKeyFile = os.Getenv("SERVER_TLS_KEY_FILE")
+	if (cfg.HTTP.TLSCertFile == "") != (cfg.HTTP.TLSKeyFile == "") {
+		return Config{}, fmt.Errorf("SERVER_TLS_CERT_FILE and SERVER_TLS_KEY_FILE must be set together")
+	}
+
+	cfg.Ingest.DuplicateTransactions = strings.ToLower(valueOrDefault("INGEST_DUPLICATE_TRANSACTIONS", "overwrite"))
+	switch cfg.Ingest.DuplicateTransactions {
+	case "overwrite", "reject", "version":
+	default:
+		return Config{}, fmt.Errorf("invalid INGEST_DUPLICATE_TRANSACTIONS %q: expected overwrite, reject or version", cfg.Ingest.DuplicateTransactions)
+	}
+	if cfg.Ingest.TypeSynonyms, err = parseSynonyms("INGEST_TYPE_SYNONYMS", os.Getenv("INGEST_TYPE_SYNONYMS")); err != nil {
+		return Config{}, err
+	}
+	if cfg.Ingest.ChannelSynonyms, err = parseSynonyms("INGEST_CHANNEL_SYNONYMS", os.Getenv("INGEST_CHANNEL_SYNONYMS")); err != nil {
+		return Config{}, err
+	}
+	cfg.Ingest.MaxAttributes = parseIntWithDefault("INGEST_MAX_ATTRIBUTES", defaultMaxAttributes)
+	cfg.Ingest.MaxPaymentMethods = parseIntWithDefault("INGEST_MAX_PAYMENT_METHODS", defaultMaxPayments)
+	cfg.Ingest.StrictLimits = parseBoolWithDefault("INGEST_STRICT_LIMITS", false)
+
+	cfg.Export = ExportConfig{
+		JobsEnabled:    parseBoolWithDefault("EXPORT_JOBS_ENABLED", true),
+		JobDir:         valueOrDefault("EXPORT_JOB_DIR", filepath.Join(os.TempDir(), "fintrace-exports")),
+		JobRetention:   defaultExportRetention,
+		MaxRunningJobs: parseIntWithDefault("EXPORT_MAX_RUNNING_JOBS", defaultExportMaxRunning),
+	}
+	if v := os.Getenv("EXPORT_JOB_RETENTION"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d <= 0 {
+			return Config{}, fmt.Errorf("invalid EXPORT_JOB_RETENTION %q: expected a positive duration", v)
+		}
+		cfg.Export.JobRetention = d
+	}
+
+	promoted, err := parsePromotedMetadataKeys(os.Getenv("GRAPH_PROMOTED_METADATA_KEYS"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Graph.PromotedMetadataKeys = promoted
+	cfg.Graph.QueryLog = valueOrDefault("GRAPH_QUERY_LOG", "off")
+	cfg.Graph.HealthQuery = strings.TrimSpace(os.Getenv("GRAPH_HEALTH_QUERY"))
+	cfg.Graph.LinkDecayHalfLife = defaultLinkDecay
+	if v := os.Getenv("GRAPH_LINK_DECAY_HALF_LIFE"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return Config{}, fmt.Errorf("invalid GRAPH_LINK_DECAY_HALF_LIFE %q: expected a non-negative duration", v)
+		}
+		cfg.Graph.LinkDecayHalfLife = d
+	}
+	cfg.Graph.LinkDecayOnWrite = parseBoolWithDefault("GRAPH_LINK_DECAY_ON_WRITE", false)
+	cfg.Graph.ReceivedFromEdges = parseBoolWithDefault("GRAPH_RECEIVED_FROM_EDGES", false)
+
+	customTypes, err := parseCustomAttributeTypes(os.Getenv("ATTRIBUTE_CUSTOM_TYPES"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Attributes.CustomTypes = customTypes
+
+	apiKeys, err := parseAPIKeys(os.Getenv("AUTH_API_KEYS"))
+	if err != nil {
+		return Config{}, err
+	}
+	cfg.Auth.APIKeys = apiKeys
+	cfg.Auth.RedactPII = parseBoolWithDefault("AUTH_REDACT_PII", false)
+
 	return cfg, nil
 }
 
+// parseAPIKeys reads "key=scope,scope;key=scope" into a key to scopes map.
+func parseAPIKeys(raw string) (map[string][]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	keys := make(map[string][]string)
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		key, scopeList, ok := strings.Cut(entry, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid AUTH_API_KEYS entry: expected key=scope[,scope]")
+		}
+		if _, dup := keys[key]; dup {
+			return nil, fmt.Errorf("invalid AUTH_API_KEYS: duplicate key")
+		}
+		var scopes []string
+		for _, scope := range strings.Split(scopeList, ",") {
+			scope = strings.ToLower(strings.TrimSpace(scope))
+			if scope == "" {
+				continue
+			}
+			if !isKnownScope(scope) {
+				return nil, fmt.Errorf("invalid AUTH_API_KEYS scope %q: expected one of %s", scope, strings.Join(KnownScopes, ", "))
+			}
+			scopes = append(scopes, scope)
+		}
+		if len(scopes) == 0 {
+			return nil, fmt.Errorf("invalid AUTH_API_KEYS entry: key has no scopes")
+		}
+		keys[key] = scopes
+	}
+	return keys, nil
+}
+
+// parseSynonyms reads "CANONICAL=synonym,synonym;CANONICAL=synonym" into a synonym to
+// canonical value map. Names are upper-cased, and a synonym may belong to only one
+// canonical value.
+func parseSynonyms(env, raw string) (map[string]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	synonyms := make(map[string]string)
+	for _, entry := range strings.Split(raw, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		canonical, list, ok := strings.Cut(entry, "=")
+		canonical = strings.ToUpper(strings.TrimSpace(canonical))
+		if !ok || canonical == "" {
+			return nil, fmt.Errorf("invalid %s entry %q: expected CANONICAL=synonym[,synonym]", env, entry)
+		}
+		for _, synonym := range strings.Split(list, ",") {
+			synonym = strings.ToUpper(strings.TrimSpace(synonym))
+			if synonym == "" {
+				continue
+			}
+			if existing, dup := synonyms[synonym]; dup && existing != canonical {
+				return nil, fmt.Errorf("invalid %s: %q is a synonym for both %s and %s", env, synonym, existing, canonical)
+			}
+			synonyms[synonym] = canonical
+		}
+	}
+	return synonyms, nil
+}
+
+// parseSortDefaults reads "field=ASC,field=DESC" into a sort field to direction map.
+// Directions are upper-cased; fields are checked against the listings by the service.
+func parseSortDefaults(env, raw string) (map[string]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	defaults := make(map[string]string)
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		field, order, ok := strings.Cut(entry, "=")
+		field = strings.TrimSpace(field)
+		order = strings.ToUpper(strings.TrimSpace(order))
+		if !ok || field == "" || (order != "ASC" && order != "DESC") {
+			return nil, fmt.Errorf("invalid %s entry %q: expected field=ASC or field=DESC", env, entry)
+		}
+		defaults[field] = order
+	}
+	return defaults, nil
+}
+
+// parseCustomAttributeTypes reads "TYPE:normalizer[:redact],TYPE:normalizer". Collisions
+// with built-in types are rejected when the attribute registry is built.
+func parseCustomAttributeTypes(raw string) ([]CustomAttributeType, error) {
+	if strings.TrimSpace(raw) == "" {
+		return nil, nil
+	}
+	var types []CustomAttributeType
+	for _, entry := range strings.Split(raw, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) > 3 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("invalid ATTRIBUTE_CUSTOM_TYPES entry %q: expected TYPE[:normalizer[:redact]]", entry)
+		}
+		t := CustomAttributeType{Type: strings.ToUpper(strings.TrimSpace(parts[0]))}
+		if len(parts) > 1 {
+			t.Normalizer = strings.ToLower(strings.TrimSpace(parts[1]))
+		}
+		if len(parts) > 2 {
+			if flag := strings.ToLower(strings.TrimSpace(parts[2])); flag != "redact" {
+				return nil, fmt.Errorf("invalid ATTRIBUTE_CUSTOM_TYPES flag %q: expected redact", parts[2])
+			}
+			t.Redact = true
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// parsePromotedMetadataKeys reads a comma-separated list of metadata keys. Keys become
+// part of a property name, so they are limited to letters, digits and underscores.
+func parsePromotedMetadataKeys(raw string) ([]string, error) {
+	var keys []string
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		for i, r := range key {
+			letter := r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+			if !letter && (i == 0 || r < '0' || r > '9') {
+				return nil, fmt.Errorf("invalid GRAPH_PROMOTED_METADATA_KEYS key %q: use letters, digits and underscores, not starting with a digit", key)
+			}
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func isKnownScope(scope string) bool {
+	for _, known := range KnownScopes {
+		if scope == known {
+			return true
+		}
+	}
+	return false
+}
+
 func valueOrDefault(key, fallback string) string {
 	if v := os.Getenv(key); v != "" {
 		return v
@@ -155,6 +595,17 @@ func parseIntWithDefault(key string, fallback int) int {
 	return fallback
 }
 
+func parseFloatWithDefault(key string, fallback float64) (float64, error) {
+	if v := os.Getenv(key); v != "" {
+		val, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s value %q: %w", key, v, err)
+		}
+		return val, nil
+	}
+	return fallback, nil
+}
+
 func parsePort(key string, fallback int) (int, error) {
 	if v := os.Getenv(key); v != "" {
 		port, err := strconv.Atoi(v)
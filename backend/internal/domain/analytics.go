@@ -0,0 +1,205 @@
+package domain
+
+import "time"
+
+// AttributeRef identifies a single attribute node.
+type AttributeRef struct {
+	AttributeType string
+	AttributeHash string
+}
+
+// UserComparison summarises the overlap between two users.
+type UserComparison struct {
+	UserA                string
+	UserB                string
+	SharedAttributes     []AttributeRef
+	CommonCounterparties []string
+	DirectTransactions   []DirectUserLink
+	// FieldMatches lists profile fields holding identical values on both users.
+	FieldMatches []string
+	// Similarity is a 0..1 summary of the overlap, populated by the service layer.
+	Similarity float64
+}
+
+// OnboardingBucket counts users created in one time window, optionally within a group
+// such as an email domain or IP subnet.
+type OnboardingBucket struct {
+	Start    time.Time
+	Group    string
+	Count    int
+	Baseline float64
+	Spike    bool
+	// UserIDs lists the users created in the window; populated only for spikes.
+	UserIDs []string
+}
+
+// OnboardingVelocity reports account creation rates over a time range.
+type OnboardingVelocity struct {
+	Window  time.Duration
+	GroupBy string
+	Since   time.Time
+	Until   time.Time
+	Buckets []OnboardingBucket
+}
+
+// ComponentSize reports how many nodes are reachable from a user over a set of edge types.
+type ComponentSize struct {
+	UserID    string
+	EdgeTypes []string
+	// Size counts every reachable node, the user included, up to the traversal cap.
+	Size int64
+	// Truncated is set when the traversal stopped at the cap with nodes left unvisited.
+	Truncated bool
+}
+
+// UserComponent is a set of users connected through payments and shared attributes. ID
+// is its smallest user ID, so it is the same whichever way the component was computed.
+// Size counts every member; UserIDs lists them in order, up to a cap.
+type UserComponent struct {
+	ID      string
+	Size    int
+	UserIDs []string
+}
+
+// UserComponents is the result of a connected components run over all users.
+// Components are largest first. Truncated is set when more components matched than
+// were returned. Algorithm names how the components were computed: "gds-wcc" or
+// "cypher".
+type UserComponents struct {
+	MinSize    int
+	Algorithm  string
+	Components []UserComponent
+	Truncated  bool
+}
+
+// PaymentFingerprintUser is a user holding at least one payment method with a given
+// fingerprint, with usage bounds taken across those payment methods.
+type PaymentFingerprintUser struct {
+	User             UserSummary
+	PaymentMethodIDs []string
+	FirstUsedAt      *time.Time
+	LastUsedAt       *time.Time
+}
+
+// PaymentMethodCluster is a payment method with the users linked to it. UserCount counts
+// every user, UserIDs lists them in order up to a cap.
+type PaymentMethodCluster struct {
+	PaymentMethodID string
+	MethodType      string
+	Provider        string
+	Fingerprint     string
+	UserCount       int
+	UserIDs         []string
+}
+
+// PaymentMethodClusters lists the payment methods shared by at least MinUsers users.
+type PaymentMethodClusters struct {
+	MinUsers int
+	Clusters []PaymentMethodCluster
+	// Truncated is set when more payment methods matched than were returned.
+	Truncated bool
+}
+
+// SharedPaymentMethod lists the users whose payment methods share a card fingerprint.
+type SharedPaymentMethod struct {
+	Fingerprint string
+	Users       []PaymentFingerprintUser
+	// Truncated is set when more users share the fingerprint than were returned.
+	Truncated bool
+}
+
+// BankTransaction is a transaction routed through a bank, with the BICs of both sides.
+type BankTransaction struct {
+	Transaction    TransactionSummary
+	OriginatorBIC  string
+	BeneficiaryBIC string
+}
+
+// BankTransactions lists the transactions routed through one bank, newest first.
+type BankTransactions struct {
+	BIC          string
+	Transactions []BankTransaction
+	// Truncated is set when more transactions went through the bank than were returned.
+	Truncated bool
+}
+
+// CentralUser is a user with its centrality score under UserCentrality.Metric.
+type CentralUser struct {
+	User  UserSummary
+	Score float64
+}
+
+// UserCentrality ranks users by a centrality metric, highest score first.
+type UserCentrality struct {
+	Metric string
+	Users  []CentralUser
+}
+
+// UserHub is a user with its number of distinct payment counterparties. Degree counts
+// each counterparty once, so it can be less than InDegree plus OutDegree.
+type UserHub struct {
+	User      UserSummary
+	Degree    int
+	InDegree  int
+	OutDegree int
+}
+
+// ClusterAttributeShare describes how much one attribute type binds a cluster.
+type ClusterAttributeShare struct {
+	AttributeType string
+	// Attributes counts the distinct attributes of this type shared inside the cluster.
+	Attributes int
+	// Pairs counts the member pairs sharing at least one attribute of this type.
+	Pairs int
+	// Users counts the members in at least one of those pairs.
+	Users int
+	// Share is Pairs as a fraction of the pairs of every type.
+	Share float64
+}
+
+// ClusterProfile summarizes the shared attribute types binding a set of users, most
+// linking type first.
+type ClusterProfile struct {
+	// Users counts the distinct requested IDs and FoundUsers those that exist.
+	Users      int
+	FoundUsers int
+	Types      []ClusterAttributeShare
+}
+
+// AttributeCluster is an attribute with the users sharing it. UserCount counts every
+// user, UserIDs lists them in order up to a cap.
+type AttributeCluster struct {
+	AttributeType string
+	AttributeHash string
+	RawValue      string
+	UserCount     int
+	UserIDs       []string
+}
+
+// AttributeClusters lists the attributes shared by at least MinUsers users, grouped by
+// type. AttributeType is empty when every type was listed.
+type AttributeClusters struct {
+	AttributeType string
+	MinUsers      int
+	// Order is the ordering the clusters were returned in, size or type.
+	Order    string
+	Clusters []AttributeCluster
+	// Truncated is set when more attributes matched than were returned.
+	Truncated bool
+}
+
+// CorrelatedUserPair is a pair of users with the attribute types they share.
+type CorrelatedUserPair struct {
+	UserA       string
+	UserB       string
+	SharedTypes []string
+}
+
+// CorrelatedUsers lists the pairs of users sharing at least MinSharedTypes distinct
+// attribute types, most shared types first.
+type CorrelatedUsers struct {
+	MinSharedTypes int
+	Pairs          []CorrelatedUserPair
+	// Truncated is set when more pairs matched than were returned.
+	Truncated bool
+}
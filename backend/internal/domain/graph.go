@@ -0,0 +1,19 @@
+package domain
+
+// GraphNode is one node of an edge-list export. ID is unique across labels and stable
+// across exports, e.g. "User:USR-1" or "Attribute:EMAIL:<hash>"; Key is the same ID
+// without the label prefix.
+type GraphNode struct {
+	ID            string
+	Label         string
+	Key           string
+	AttributeType string
+}
+
+// GraphEdge is one relationship of an edge-list export, between GraphNode IDs.
+type GraphEdge struct {
+	Source string
+	Target string
+	Type   string
+	Weight float64
+}
@@ -33,6 +33,9 @@ type TransactionSummary struct {
 type UserListResult struct {
 	Items []UserSummary
 	Total int64
+	// NextCursor resumes the listing after the last item when it was requested in
+	// cursor mode and the page was full; it is empty otherwise.
+	NextCursor string
 }
 
 // TransactionListResult captures paginated transaction list results.
@@ -0,0 +1,15 @@
+package domain
+
+// UserLookup is the outcome for one ID of a batch user lookup.
+type UserLookup struct {
+	ID    string
+	Found bool
+	User  UserSummary
+}
+
+// TransactionLookup is the outcome for one ID of a batch transaction lookup.
+type TransactionLookup struct {
+	ID          string
+	Found       bool
+	Transaction TransactionSummary
+}
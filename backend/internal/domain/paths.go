@@ -0,0 +1,95 @@
+package domain
+
+// PathNode is a node on a graph path.
+type PathNode struct {
+	// ID is the node's business key: userId, transactionId or paymentMethodId, or the
+	// attribute hash for Attribute nodes.
+	ID    string
+	Label string
+	// AttributeType is set on Attribute nodes.
+	AttributeType string
+	// Properties holds the stored properties a caller asked for, when it did.
+	Properties map[string]any
+}
+
+// PathEdge is a relationship on a graph path. Source and Target are PathNode IDs in the
+// relationship's stored direction, which need not match the direction of travel.
+type PathEdge struct {
+	Type   string
+	Source string
+	Target string
+	Weight float64
+	// Properties holds the stored properties a caller asked for, when it did.
+	Properties map[string]any
+}
+
+// ShortestPath is a path between two nodes. Nodes run from source to target and Edges[i]
+// joins Nodes[i] and Nodes[i+1]. Both are empty when the nodes are not connected.
+type ShortestPath struct {
+	SourceID string
+	TargetID string
+	Nodes    []PathNode
+	Edges    []PathEdge
+}
+
+// Found reports whether a path exists.
+func (p ShortestPath) Found() bool {
+	return len(p.Nodes) > 0
+}
+
+// Hops is the number of relationships on the path.
+func (p ShortestPath) Hops() int {
+	return len(p.Edges)
+}
+
+// Cost is the summed weight of the path's edges.
+func (p ShortestPath) Cost() float64 {
+	var cost float64
+	for _, edge := range p.Edges {
+		cost += edge.Weight
+	}
+	return cost
+}
+
+// Neighborhood is the subgraph within Depth hops of a user. Edges holds every
+// relationship between two of its nodes; Truncated is set when the expansion stopped at
+// its node cap before reaching Depth.
+type Neighborhood struct {
+	UserID    string
+	Depth     int
+	Nodes     []PathNode
+	Edges     []PathEdge
+	Truncated bool
+}
+
+// Cycle is a closed loop of payments between users. Nodes are the users in the order
+// money moves, starting from the one with the smallest ID, and Edges[i] is a SENT_TO
+// from Nodes[i] to Nodes[(i+1) % len(Nodes)]. TotalAmount sums the edge amounts.
+type Cycle struct {
+	Nodes       []PathNode
+	Edges       []PathEdge
+	TotalAmount float64
+}
+
+// Length is the number of payments in the cycle.
+func (c Cycle) Length() int {
+	return len(c.Edges)
+}
+
+// CycleSet is the result of a cycle search. Truncated is set when more cycles matched
+// than were returned.
+type CycleSet struct {
+	MaxLength int
+	MinAmount float64
+	Cycles    []Cycle
+	Truncated bool
+}
+
+// ShortestPathResult is the outcome for one pair of a batch shortest-path request. Err
+// is set, and Path empty, when that pair could not be computed.
+type ShortestPathResult struct {
+	SourceUserID string
+	TargetUserID string
+	Path         ShortestPath
+	Err          error
+}
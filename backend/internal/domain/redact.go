@@ -0,0 +1,102 @@
+package domain
+
+import "strings"
+
+const redactionMask = "***"
+
+// RedactEmail keeps the first character of the local part and the domain, e.g.
+// "jane.doe@example.com" becomes "j***@example.com".
+func RedactEmail(email string) string {
+	if email == "" {
+		return ""
+	}
+	local, domain, ok := strings.Cut(email, "@")
+	if !ok || local == "" {
+		return redactionMask
+	}
+	return local[:1] + redactionMask + "@" + domain
+}
+
+// RedactPhone masks every digit except the last four, keeping separators so the
+// shape of the number survives, e.g. "+1 555-010-1234" becomes "+* ***-***-1234".
+func RedactPhone(phone string) string {
+	digits := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits++
+		}
+	}
+	keep := 4
+	if digits <= keep {
+		keep = 0
+	}
+	var b strings.Builder
+	b.Grow(len(phone))
+	seen := 0
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			seen++
+			if seen <= digits-keep {
+				b.WriteByte('*')
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Redacted returns a copy of the address with everything below country level masked.
+func (a Address) Redacted() Address {
+	return Address{
+		Line1:      redactNonEmpty(a.Line1),
+		Line2:      redactNonEmpty(a.Line2),
+		City:       redactNonEmpty(a.City),
+		State:      a.State,
+		PostalCode: redactNonEmpty(a.PostalCode),
+		Country:    a.Country,
+	}
+}
+
+// Redacted returns a copy of the user with email, phone and address masked as for
+// summaries, the date of birth removed and the raw values of attributes masked, since
+// those hold the original email, phone or address.
+func (u User) Redacted() User {
+	u.Email = RedactEmail(u.Email)
+	u.Phone = RedactPhone(u.Phone)
+	u.Address = u.Address.Redacted()
+	u.DateOfBirth = nil
+	attrs := make([]Attribute, len(u.Attributes))
+	for i, attr := range u.Attributes {
+		attrs[i] = attr.Redacted()
+	}
+	u.Attributes = attrs
+	return u
+}
+
+// Redacted returns a copy of the summary with email and phone masked.
+func (u UserSummary) Redacted() UserSummary {
+	u.Email = RedactEmail(u.Email)
+	u.Phone = RedactPhone(u.Phone)
+	return u
+}
+
+// Redacted returns a copy of the attribute with its raw value masked, since for EMAIL,
+// PHONE and ADDRESS attributes it is the original email, phone or address. The hashed
+// value still identifies the attribute.
+func (a Attribute) Redacted() Attribute {
+	a.RawValue = RedactRawValue(a.RawValue)
+	return a
+}
+
+// RedactRawValue masks the raw value of an attribute, leaving empty values empty.
+func RedactRawValue(raw string) string {
+	return redactNonEmpty(raw)
+}
+
+func redactNonEmpty(value string) string {
+	if value == "" {
+		return ""
+	}
+	return redactionMask
+}
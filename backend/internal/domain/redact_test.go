@@ -0,0 +1,55 @@
+package domain
+
+import "testing"
+
+func TestRedactEmail(t *testing.T) {
+	tests := map[string]string{
+		"":                     "",
+		"jane.doe@example.com": "j***@example.com",
+		"@example.com":         "***",
+		"not-an-email":         "***",
+	}
+	for in, want := range tests {
+		if got := RedactEmail(in); got != want {
+			t.Errorf("RedactEmail(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRedactPhone(t *testing.T) {
+	tests := map[string]string{
+		"":                "",
+		"+1 555-010-1234": "+* ***-***-1234",
+		"5550101234":      "******1234",
+		"1234":            "****",
+	}
+	for in, want := range tests {
+		if got := RedactPhone(in); got != want {
+			t.Errorf("RedactPhone(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestUserRedacted(t *testing.T) {
+	user := User{
+		ID:         "USR-1",
+		Email:      "jane.doe@example.com",
+		Phone:      "555-010-1234",
+		Address:    Address{Line1: "1 Main St", City: "Springfield", State: "IL", PostalCode: "62701", Country: "US"},
+		Attributes: []Attribute{{Type: "EMAIL", Value: "hash", RawValue: "jane.doe@example.com"}},
+	}
+	got := user.Redacted()
+	if got.Email != "j***@example.com" || got.Phone != "***-***-1234" {
+		t.Errorf("email, phone = %q, %q", got.Email, got.Phone)
+	}
+	want := Address{Line1: "***", City: "***", State: "IL", PostalCode: "***", Country: "US"}
+	if got.Address != want {
+		t.Errorf("address = %+v, want %+v", got.Address, want)
+	}
+	if got.Attributes[0].RawValue != "***" || got.Attributes[0].Value != "hash" {
+		t.Errorf("attribute = %+v, want the raw value masked and the hash kept", got.Attributes[0])
+	}
+	if user.Attributes[0].RawValue != "jane.doe@example.com" {
+		t.Error("Redacted modified the original user's attributes")
+	}
+}
@@ -29,12 +29,28 @@ type SharedAttributeLink struct {
 	UserIDs       []string
 }
 
+// SectionLimits reports the per-section row limit applied to a relationship view and
+// which sections held more rows than were returned.
+type SectionLimits struct {
+	// Limit is the number of rows returned at most per section.
+	Limit int
+	// Cap is the server-side maximum that Limit cannot exceed.
+	Cap               int
+	TruncatedSections []string
+}
+
+// Truncated reports whether any section was cut short.
+func (l SectionLimits) Truncated() bool {
+	return len(l.TruncatedSections) > 0
+}
+
 // UserRelationships encapsulates all relationship views for a user.
 type UserRelationships struct {
 	UserID           string
 	DirectLinks      []DirectUserLink
 	Transactions     []UserTransactionLink
 	SharedAttributes []SharedAttributeLink
+	Limits           SectionLimits
 }
 
 // TransactionUserLink represents a user involved with a transaction.
@@ -52,7 +68,20 @@ type LinkedTransaction struct {
 	LinkType      string
 	AttributeHash string
 	Score         float64
-	LastUpdated   *time.Time
+	// DecayedScore is Score decayed by the timestamp gap between the two transactions;
+	// nil when the link was written without decay.
+	DecayedScore *float64
+	LastUpdated  *time.Time
+}
+
+// ReversalLink is one transaction in a reversal chain. ReversesTransactionID is the
+// transaction it reverses, empty for the original at the head of the chain.
+type ReversalLink struct {
+	TransactionID         string
+	ReversesTransactionID string
+	Amount                float64
+	Currency              string
+	Timestamp             *time.Time
 }
 
 // TransactionRelationships encapsulates relationship data for a transaction.
@@ -60,4 +89,21 @@ type TransactionRelationships struct {
 	TransactionID      string
 	Users              []TransactionUserLink
 	LinkedTransactions []LinkedTransaction
+	// ReversalChain lists every transaction connected to this one through REVERSES,
+	// oldest first, including the transaction itself when it is part of a chain.
+	ReversalChain []ReversalLink
+	Limits        SectionLimits
+}
+
+// UserRelationshipCounts summarises how many relationships of each kind a user has,
+// without materialising them.
+type UserRelationshipCounts struct {
+	UserID       string
+	Outbound     int64
+	Inbound      int64
+	Transactions int64
+	Attributes   int64
+	// SharedByType maps an attribute type to the number of other users linked to the
+	// user through attributes of that type.
+	SharedByType map[string]int64
 }
@@ -0,0 +1,40 @@
+package domain
+
+// UserRiskFeatures are the graph-derived signals a risk model scores a user on.
+type UserRiskFeatures struct {
+	UserID      string
+	StoredScore float64
+	// Transactions24h and Transactions7d count monetary transactions the user took part in.
+	Transactions24h int64
+	Transactions7d  int64
+	// Counterparties is the number of distinct users the user has sent to or received from.
+	Counterparties      int64
+	AvgCounterpartyRisk float64
+	MaxCounterpartyRisk float64
+	// SharedAttributeUsers counts other users sharing at least one attribute with the user.
+	SharedAttributeUsers int64
+	SharedAttributes     int64
+	// StructuringTransactions counts recent outgoing transactions just below the reporting threshold.
+	StructuringTransactions int64
+}
+
+// RiskComponent is a single feature's share of a risk score.
+type RiskComponent struct {
+	Name string
+	// Value is the raw feature value, Score its 0..1 normalisation.
+	Value        float64
+	Score        float64
+	Weight       float64
+	Contribution float64
+	Detail       string
+}
+
+// RiskExplanation breaks a user's risk score down into its contributing components.
+type RiskExplanation struct {
+	UserID      string
+	Model       string
+	StoredScore float64
+	// ComputedScore is the sum of component contributions under Model.
+	ComputedScore float64
+	Components    []RiskComponent
+}
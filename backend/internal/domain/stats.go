@@ -0,0 +1,8 @@
+package domain
+
+// GraphStats captures denormalised counters maintained on write.
+type GraphStats struct {
+	TotalUsers           int64
+	TotalTransactions    int64
+	TransactionsByStatus map[string]int64
+}
@@ -1,6 +1,30 @@
 package domain
 
-import "time"
+import (
+	"strings"
+	"time"
+)
+
+// Account event types. Events are stored as Transaction nodes but carry no amount or
+// currency, may omit the receiver, and never create SENT_TO/RECEIVED_FROM edges.
+const (
+	EventTypeLogin          = "LOGIN"
+	EventTypeLogout         = "LOGOUT"
+	EventTypeKYCUpdate      = "KYC_UPDATE"
+	EventTypeProfileUpdate  = "PROFILE_UPDATE"
+	EventTypePasswordChange = "PASSWORD_CHANGE"
+)
+
+// IsMonetaryType reports whether transactions of the given type move money. Every type
+// other than the account event types above is monetary.
+func IsMonetaryType(txType string) bool {
+	switch strings.ToUpper(strings.TrimSpace(txType)) {
+	case EventTypeLogin, EventTypeLogout, EventTypeKYCUpdate, EventTypeProfileUpdate, EventTypePasswordChange:
+		return false
+	default:
+		return true
+	}
+}
 
 // Transaction models a transaction node in the graph.
 type Transaction struct {
@@ -15,8 +39,20 @@ type Transaction struct {
 	IPAddress       string
 	DeviceID        string
 	PaymentMethodID string
-	Timestamp       time.Time
-	Metadata        map[string]any
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
+	// OriginatorBIC and BeneficiaryBIC are the normalized BIC/SWIFT codes of the sending
+	// and receiving banks; empty when unknown.
+	OriginatorBIC  string
+	BeneficiaryBIC string
+	// Monetary is derived from Type; false for account events.
+	Monetary  bool
+	Timestamp time.Time
+	Metadata  map[string]any
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// CreatedAtDefaulted and UpdatedAtDefaulted are set when the caller left the timestamp
+	// out and it was stamped with the write time. Content hashes leave those out.
+	CreatedAtDefaulted bool
+	UpdatedAtDefaulted bool
+	// ReversalOf is the ID of the transaction this one reverses; empty when it is not a reversal.
+	ReversalOf string
 }
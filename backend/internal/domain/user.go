@@ -12,12 +12,23 @@ type Address struct {
 	Country    string
 }
 
+// Attribute origins recorded on HAS_ATTRIBUTE edges.
+const (
+	// AttributeOriginUser marks attributes declared on a user profile.
+	AttributeOriginUser = "USER"
+	// AttributeOriginTransaction marks attributes observed on a transaction.
+	AttributeOriginTransaction = "TRANSACTION"
+	// AttributeOriginDerived marks attributes computed from other fields rather than observed.
+	AttributeOriginDerived = "DERIVED"
+)
+
 // Attribute represents a normalized attribute used for shared links.
 type Attribute struct {
 	Type            string
 	Value           string
 	RawValue        string
 	ConfidenceScore float64
+	Origin          string
 }
 
 // PaymentMethod represents a unique payment instrument associated with a user.
@@ -45,4 +56,8 @@ type User struct {
 	PaymentMethods []PaymentMethod
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
+	// CreatedAtDefaulted and UpdatedAtDefaulted are set when the caller left the timestamp
+	// out and it was stamped with the write time. Content hashes leave those out.
+	CreatedAtDefaulted bool
+	UpdatedAtDefaulted bool
 }
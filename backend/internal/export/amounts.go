@@ -0,0 +1,84 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// AmountFormat selects how transaction amounts are rendered.
+type AmountFormat string
+
+const (
+	// AmountsRaw writes amounts as stored, with as many digits as the float needs. It is
+	// the default.
+	AmountsRaw AmountFormat = "raw"
+	// AmountsCurrency writes amounts rounded to the minor units of their currency, e.g.
+	// 1234.50 for USD and 1235 for JPY.
+	AmountsCurrency AmountFormat = "currency"
+)
+
+// ParseAmountFormat validates a user supplied amount format name; empty means AmountsRaw.
+func ParseAmountFormat(value string) (AmountFormat, error) {
+	switch f := AmountFormat(strings.ToLower(strings.TrimSpace(value))); f {
+	case AmountsRaw, AmountsCurrency:
+		return f, nil
+	case "":
+		return AmountsRaw, nil
+	default:
+		return "", fmt.Errorf("unsupported amount format %q (expected raw or currency)", value)
+	}
+}
+
+// defaultMinorUnits is used for currencies missing from currencyMinorUnits, which covers
+// most ISO 4217 codes.
+const defaultMinorUnits = 2
+
+// currencyMinorUnits lists the ISO 4217 currencies whose minor unit is not two digits.
+var currencyMinorUnits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0,
+	"PYG": 0, "RWF": 0, "UGX": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+}
+
+// minorUnits returns the number of decimal places used for currency.
+func minorUnits(currency string) int {
+	if units, ok := currencyMinorUnits[strings.ToUpper(strings.TrimSpace(currency))]; ok {
+		return units
+	}
+	return defaultMinorUnits
+}
+
+// amount is an exported transaction amount. Formatted amounts keep a fixed number of
+// decimals in both JSON, where they stay numbers, and CSV.
+type amount struct {
+	value     float64
+	decimals  int
+	formatted bool
+}
+
+func newAmount(value float64, currency string, format AmountFormat) amount {
+	if format != AmountsCurrency {
+		return amount{value: value}
+	}
+	return amount{value: value, decimals: minorUnits(currency), formatted: true}
+}
+
+func (a amount) String() string {
+	if !a.formatted {
+		return formatFloat(a.value)
+	}
+	// strconv rounds halves to even; amounts round them away from zero, so 1234.5 JPY
+	// is 1235 as it would be on an invoice.
+	scale := math.Pow10(a.decimals)
+	return strconv.FormatFloat(math.Round(a.value*scale)/scale, 'f', a.decimals, 64)
+}
+
+func (a amount) MarshalJSON() ([]byte, error) {
+	if !a.formatted {
+		return json.Marshal(a.value)
+	}
+	return []byte(a.String()), nil
+}
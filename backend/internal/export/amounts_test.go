@@ -0,0 +1,52 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAmountFormatting(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    float64
+		currency string
+		format   AmountFormat
+		want     string
+	}{
+		{name: "JPY has no minor units", value: 1234.5, currency: "JPY", format: AmountsCurrency, want: "1235"},
+		{name: "USD has two", value: 12.5, currency: "USD", format: AmountsCurrency, want: "12.50"},
+		{name: "KWD has three", value: 1.5, currency: "KWD", format: AmountsCurrency, want: "1.500"},
+		{name: "lower case code", value: 99.4, currency: "jpy", format: AmountsCurrency, want: "99"},
+		{name: "unknown currency uses two", value: 3, currency: "XYZ", format: AmountsCurrency, want: "3.00"},
+		{name: "missing currency uses two", value: 0.125, currency: "", format: AmountsCurrency, want: "0.13"},
+		{name: "negative half rounds away from zero", value: -2.5, currency: "JPY", format: AmountsCurrency, want: "-3"},
+		{name: "raw keeps the stored digits", value: 1234.5, currency: "JPY", format: AmountsRaw, want: "1234.5"},
+		{name: "raw whole number", value: 12, currency: "USD", format: AmountsRaw, want: "12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := newAmount(tt.value, tt.currency, tt.format)
+			if got := a.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+			data, err := json.Marshal(a)
+			if err != nil {
+				t.Fatalf("MarshalJSON() error = %v", err)
+			}
+			if string(data) != tt.want {
+				t.Errorf("MarshalJSON() = %s, want %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAmountFormat(t *testing.T) {
+	for value, want := range map[string]AmountFormat{"": AmountsRaw, "raw": AmountsRaw, " Currency ": AmountsCurrency} {
+		if got, err := ParseAmountFormat(value); err != nil || got != want {
+			t.Errorf("ParseAmountFormat(%q) = %q, %v, want %q", value, got, err, want)
+		}
+	}
+	if _, err := ParseAmountFormat("cents"); err == nil {
+		t.Error("ParseAmountFormat(cents) succeeded, want an error")
+	}
+}
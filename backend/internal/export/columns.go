@@ -0,0 +1,64 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ParseColumns validates a comma-separated list of CSV column names for entity, such as
+// "userId,fullName,riskScore". Empty means every column. Columns keep the given order
+// and may not repeat.
+func ParseColumns(entity Entity, value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	available, err := entityColumns(entity)
+	if err != nil {
+		return nil, err
+	}
+	var columns []string
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		for _, seen := range columns {
+			if seen == name {
+				return nil, fmt.Errorf("column %q is listed twice", name)
+			}
+		}
+		columns = append(columns, name)
+	}
+	if _, err := columnIndices(available, columns); err != nil {
+		return nil, err
+	}
+	return columns, nil
+}
+
+// ParseDelimiter validates a CSV field separator given as a single character, e.g. ";"
+// for locales that use the comma as a decimal mark, or "tab". Empty means a comma.
+func ParseDelimiter(value string) (rune, error) {
+	switch value {
+	case "":
+		return ',', nil
+	case "tab", `\t`:
+		return '\t', nil
+	}
+	r, size := utf8.DecodeRuneInString(value)
+	if size != len(value) {
+		return 0, fmt.Errorf("invalid delimiter %q: expected a single character", value)
+	}
+	if err := checkDelimiter(r); err != nil {
+		return 0, err
+	}
+	return r, nil
+}
+
+// checkDelimiter rejects separators encoding/csv cannot write unambiguously.
+func checkDelimiter(r rune) error {
+	if r == '"' || r == '\r' || r == '\n' || r == utf8.RuneError {
+		return fmt.Errorf("invalid delimiter %q", r)
+	}
+	return nil
+}
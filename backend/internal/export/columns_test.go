@@ -0,0 +1,134 @@
+package export
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name    string
+		entity  Entity
+		value   string
+		want    []string
+		wantErr bool
+	}{
+		{name: "empty means every column", entity: EntityUsers, value: ""},
+		{name: "subset keeps the given order", entity: EntityUsers, value: "riskScore, userId,fullName", want: []string{"riskScore", "userId", "fullName"}},
+		{name: "empty entries are skipped", entity: EntityTransactions, value: "amount,,currency,", want: []string{"amount", "currency"}},
+		{name: "unknown column", entity: EntityUsers, value: "userId,password", wantErr: true},
+		{name: "column of another entity", entity: EntityUsers, value: "amount", wantErr: true},
+		{name: "repeated column", entity: EntityUsers, value: "userId,userId", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseColumns(tt.entity, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseColumns(%q) = %v, want an error", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseColumns(%q) error = %v", tt.value, err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ParseColumns(%q) = %v, want %v", tt.value, got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ParseColumns(%q) = %v, want %v", tt.value, got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDelimiter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{value: "", want: ','},
+		{value: ";", want: ';'},
+		{value: "|", want: '|'},
+		{value: "tab", want: '\t'},
+		{value: `\t`, want: '\t'},
+		{value: "§", want: '§'},
+		{value: ";;", wantErr: true},
+		{value: `"`, wantErr: true},
+		{value: "\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDelimiter(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDelimiter(%q) = %q, want an error", tt.value, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseDelimiter(%q) = %q, %v, want %q", tt.value, got, err, tt.want)
+		}
+	}
+}
+
+func TestCSVEncoderColumnsAndDelimiter(t *testing.T) {
+	records := []record{
+		userRecord{UserID: "USR-1", FullName: "Lee; Ann", Email: "ann@example.com", RiskScore: 0.25},
+		userRecord{UserID: "USR-2", FullName: "Bo", Email: "bo@example.com", RiskScore: 0.5},
+	}
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{
+			name: "every column with commas",
+			req:  Request{Entity: EntityUsers, Format: FormatCSV},
+			want: "userId,fullName,email,phone,kycStatus,riskScore,createdAt,updatedAt\n" +
+				"USR-1,Lee; Ann,ann@example.com,,,0.25,,\n" +
+				"USR-2,Bo,bo@example.com,,,0.5,,\n",
+		},
+		{
+			name: "subset with semicolons",
+			req:  Request{Entity: EntityUsers, Format: FormatCSV, Columns: []string{"riskScore", "userId", "fullName"}, Delimiter: ';'},
+			want: "riskScore;userId;fullName\n" +
+				"0.25;USR-1;\"Lee; Ann\"\n" +
+				"0.5;USR-2;Bo\n",
+		},
+		{
+			name: "resumed export writes no header",
+			req:  Request{Entity: EntityUsers, Format: FormatCSV, Columns: []string{"userId"}, Delimiter: '\t', Offset: 10},
+			want: "USR-1\nUSR-2\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			enc, err := newEncoder(&out, tt.req)
+			if err != nil {
+				t.Fatalf("newEncoder() error = %v", err)
+			}
+			if err := enc.begin(); err != nil {
+				t.Fatal(err)
+			}
+			for _, rec := range records {
+				if err := enc.write(rec); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := enc.end(); err != nil {
+				t.Fatal(err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := newEncoder(&bytes.Buffer{}, Request{Entity: EntityUsers, Format: FormatCSV, Columns: []string{"password"}}); err == nil {
+		t.Error("newEncoder accepted an unknown column")
+	}
+}
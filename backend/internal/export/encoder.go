@@ -0,0 +1,187 @@
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+)
+
+// encoder writes records in one output format. begin and end frame the output and are
+// skipped or adapted when continuing an interrupted export; flush is called after every
+// batch so that the bytes on disk always end on a record boundary.
+type encoder interface {
+	begin() error
+	write(rec record) error
+	flush() error
+	end() error
+}
+
+func newEncoder(w io.Writer, req Request) (encoder, error) {
+	columns, err := entityColumns(req.Entity)
+	if err != nil {
+		return nil, err
+	}
+	resumed := req.Offset > 0
+
+	switch req.Format {
+	case FormatJSON:
+		return &jsonEncoder{w: bufio.NewWriter(w), first: !resumed, resumed: resumed}, nil
+	case FormatNDJSON:
+		return &ndjsonEncoder{w: bufio.NewWriter(w)}, nil
+	case FormatCSV:
+		enc := &csvEncoder{w: csv.NewWriter(w), header: columns, resumed: resumed}
+		if req.Delimiter != 0 {
+			if err := checkDelimiter(req.Delimiter); err != nil {
+				return nil, err
+			}
+			enc.w.Comma = req.Delimiter
+		}
+		if len(req.Columns) > 0 {
+			if enc.indices, err = columnIndices(columns, req.Columns); err != nil {
+				return nil, err
+			}
+			enc.header = req.Columns
+		}
+		return enc, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", req.Format)
+	}
+}
+
+func entityColumns(entity Entity) ([]string, error) {
+	switch entity {
+	case EntityUsers:
+		return userColumns, nil
+	case EntityTransactions:
+		return transactionColumns, nil
+	case EntityEdges:
+		return edgeColumns, nil
+	case EntityNodes:
+		return nodeColumns, nil
+	default:
+		return nil, fmt.Errorf("unsupported export entity %q", entity)
+	}
+}
+
+// columnIndices maps each selected column to its position in the full row.
+func columnIndices(columns, selected []string) ([]int, error) {
+	indices := make([]int, 0, len(selected))
+	for _, name := range selected {
+		i := slices.Index(columns, name)
+		if i < 0 {
+			return nil, fmt.Errorf("unknown column %q (expected one of %s)", name, strings.Join(columns, ", "))
+		}
+		indices = append(indices, i)
+	}
+	return indices, nil
+}
+
+// jsonEncoder writes a single JSON array, one record per line.
+type jsonEncoder struct {
+	w       *bufio.Writer
+	first   bool
+	resumed bool
+}
+
+func (e *jsonEncoder) begin() error {
+	if e.resumed {
+		return nil
+	}
+	_, err := e.w.WriteString("[")
+	return err
+}
+
+func (e *jsonEncoder) write(rec record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	sep := ",\n"
+	if e.first {
+		sep = "\n"
+		e.first = false
+	}
+	if _, err := e.w.WriteString(sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(payload)
+	return err
+}
+
+func (e *jsonEncoder) flush() error {
+	return e.w.Flush()
+}
+
+func (e *jsonEncoder) end() error {
+	if _, err := e.w.WriteString("\n]\n"); err != nil {
+		return err
+	}
+	return e.w.Flush()
+}
+
+// ndjsonEncoder writes one JSON object per line.
+type ndjsonEncoder struct {
+	w *bufio.Writer
+}
+
+func (e *ndjsonEncoder) begin() error { return nil }
+
+func (e *ndjsonEncoder) write(rec record) error {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode record: %w", err)
+	}
+	if _, err := e.w.Write(payload); err != nil {
+		return err
+	}
+	return e.w.WriteByte('\n')
+}
+
+func (e *ndjsonEncoder) flush() error {
+	return e.w.Flush()
+}
+
+func (e *ndjsonEncoder) end() error {
+	return e.w.Flush()
+}
+
+// csvEncoder writes a header row followed by one row per record. With indices set, only
+// those fields of each row are written, in that order.
+type csvEncoder struct {
+	w       *csv.Writer
+	header  []string
+	indices []int
+	resumed bool
+}
+
+func (e *csvEncoder) begin() error {
+	if e.resumed {
+		return nil
+	}
+	return e.w.Write(e.header)
+}
+
+func (e *csvEncoder) write(rec record) error {
+	row := rec.csvRow()
+	if e.indices == nil {
+		return e.w.Write(row)
+	}
+	selected := make([]string, len(e.indices))
+	for i, index := range e.indices {
+		selected[i] = row[index]
+	}
+	return e.w.Write(selected)
+}
+
+func (e *csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvEncoder) end() error {
+	return e.flush()
+}
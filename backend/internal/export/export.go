@@ -0,0 +1,219 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// Format identifies the serialisation used for exported records.
+type Format string
+
+const (
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+	FormatCSV    Format = "csv"
+)
+
+// Entity identifies what is exported: a node type, or the graph as an edge list and
+// its companion node list.
+type Entity string
+
+const (
+	EntityUsers        Entity = "users"
+	EntityTransactions Entity = "transactions"
+	EntityEdges        Entity = "edges"
+	EntityNodes        Entity = "nodes"
+)
+
+const defaultBatchSize = 1000
+
+// ParseFormat validates a user supplied format name.
+func ParseFormat(value string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(value))); f {
+	case FormatJSON, FormatNDJSON, FormatCSV:
+		return f, nil
+	case "":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q (expected json, ndjson or csv)", value)
+	}
+}
+
+// ParseEntity validates a user supplied entity name.
+func ParseEntity(value string) (Entity, error) {
+	switch e := Entity(strings.ToLower(strings.TrimSpace(value))); e {
+	case EntityUsers, EntityTransactions, EntityEdges, EntityNodes:
+		return e, nil
+	default:
+		return "", fmt.Errorf("unsupported export entity %q (expected users, transactions, edges or nodes)", value)
+	}
+}
+
+// Source pages through the records to export. *repository.Repository satisfies it.
+type Source interface {
+	ExportUsers(ctx context.Context, opts repository.ListUsersOptions) ([]domain.UserSummary, error)
+	ExportTransactions(ctx context.Context, opts repository.ListTransactionsOptions) ([]domain.TransactionSummary, error)
+	ExportEdges(ctx context.Context, opts repository.GraphExportOptions) ([]domain.GraphEdge, error)
+	ExportNodes(ctx context.Context, opts repository.GraphExportOptions) ([]domain.GraphNode, error)
+}
+
+// Request describes a single export run.
+type Request struct {
+	Entity       Entity
+	Format       Format
+	Users        repository.ListUsersOptions
+	Transactions repository.ListTransactionsOptions
+	// Graph filters edge and node exports.
+	Graph     repository.GraphExportOptions
+	BatchSize int
+	// Offset is the number of records already written by a previous, interrupted run.
+	// When positive the output is treated as a continuation: no CSV header or JSON
+	// array opener is written and the first record is preceded by a separator.
+	Offset int
+	Retry  RetryPolicy
+	// RedactPII masks email and phone on exported user records.
+	RedactPII bool
+	// Amounts selects how transaction amounts are rendered; empty means AmountsRaw.
+	Amounts AmountFormat
+	// Columns selects the CSV columns to write, in order; empty means every column of
+	// the entity. See ParseColumns.
+	Columns []string
+	// Delimiter separates CSV fields; zero means a comma.
+	Delimiter rune
+	// OnBatch, when set, is called after each batch has been written with the progress
+	// so far, whose Records include Offset. Returning an error aborts the export.
+	OnBatch func(progress Result) error
+}
+
+// Result summarises an export run.
+type Result struct {
+	// Records is the total number of records exported, including any already covered
+	// by Request.Offset.
+	Records int
+	// MaxUpdatedAt is the latest updatedAt among the users or transactions exported by
+	// this run, or zero when none were. Passed back as the since filter, it exports only
+	// what changed afterwards. Edge and node exports leave it zero.
+	MaxUpdatedAt time.Time
+}
+
+// Run streams every record matching req from src into w.
+func Run(ctx context.Context, src Source, w io.Writer, req Request) (Result, error) {
+	result := Result{Records: req.Offset}
+	enc, err := newEncoder(w, req)
+	if err != nil {
+		return result, err
+	}
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+
+	if err := enc.begin(); err != nil {
+		return result, err
+	}
+	for {
+		var records []record
+		var updated time.Time
+		err := withRetry(ctx, req.Retry, func(ctx context.Context) error {
+			var err error
+			records, updated, err = fetch(ctx, src, req, result.Records, batchSize)
+			return err
+		})
+		if err != nil {
+			return result, err
+		}
+		for _, rec := range records {
+			if err := enc.write(rec); err != nil {
+				return result, err
+			}
+		}
+		if err := enc.flush(); err != nil {
+			return result, err
+		}
+		result.Records += len(records)
+		if updated.After(result.MaxUpdatedAt) {
+			result.MaxUpdatedAt = updated
+		}
+		if len(records) > 0 && req.OnBatch != nil {
+			if err := req.OnBatch(result); err != nil {
+				return result, err
+			}
+		}
+		if len(records) < batchSize {
+			break
+		}
+	}
+	return result, enc.end()
+}
+
+// fetch returns one batch of records and the latest updatedAt among them.
+func fetch(ctx context.Context, src Source, req Request, offset, limit int) ([]record, time.Time, error) {
+	var updated time.Time
+	switch req.Entity {
+	case EntityUsers:
+		opts := req.Users
+		opts.Offset, opts.Limit = offset, limit
+		users, err := src.ExportUsers(ctx, opts)
+		if err != nil {
+			return nil, updated, err
+		}
+		records := make([]record, 0, len(users))
+		for _, u := range users {
+			if u.UpdatedAt.After(updated) {
+				updated = u.UpdatedAt
+			}
+			if req.RedactPII {
+				u = u.Redacted()
+			}
+			records = append(records, newUserRecord(u))
+		}
+		return records, updated, nil
+	case EntityTransactions:
+		opts := req.Transactions
+		opts.Offset, opts.Limit = offset, limit
+		txs, err := src.ExportTransactions(ctx, opts)
+		if err != nil {
+			return nil, updated, err
+		}
+		records := make([]record, 0, len(txs))
+		for _, t := range txs {
+			if t.UpdatedAt.After(updated) {
+				updated = t.UpdatedAt
+			}
+			records = append(records, newTransactionRecord(t, req.Amounts))
+		}
+		return records, updated, nil
+	case EntityEdges:
+		opts := req.Graph
+		opts.Offset, opts.Limit = offset, limit
+		edges, err := src.ExportEdges(ctx, opts)
+		if err != nil {
+			return nil, updated, err
+		}
+		records := make([]record, 0, len(edges))
+		for _, e := range edges {
+			records = append(records, newEdgeRecord(e))
+		}
+		return records, updated, nil
+	case EntityNodes:
+		opts := req.Graph
+		opts.Offset, opts.Limit = offset, limit
+		nodes, err := src.ExportNodes(ctx, opts)
+		if err != nil {
+			return nil, updated, err
+		}
+		records := make([]record, 0, len(nodes))
+		for _, n := range nodes {
+			records = append(records, newNodeRecord(n))
+		}
+		return records, updated, nil
+	default:
+		return nil, updated, fmt.Errorf("unsupported export entity %q", req.Entity)
+	}
+}
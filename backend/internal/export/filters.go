@@ -0,0 +1,224 @@
+package export
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+var (
+	userFilterKeys        = []string{"search", "kycStatus", "riskMin", "riskMax", "country", "city", "emailDomain", "createdAfter", "createdBefore", "since"}
+	transactionFilterKeys = []string{"search", "userId", "status", "type", "channel", "currency", "deviceId", "ipAddress", "includeLinked", "senderRiskMin", "receiverRiskMin", "anyParticipantRiskMin", "participantKyc", "minAmount", "maxAmount", "start", "end", "since"}
+	graphFilterKeys       = []string{"edgeTypes"}
+	// requestKeys shape the output rather than filter it.
+	requestKeys = []string{"entity", "format", "amountFormat", "columns", "delimiter"}
+)
+
+// RequestFromValues builds an export Request from query-style values: entity, format,
+// amountFormat, and for CSV columns and delimiter, select what is exported and how, and
+// every other key is a filter for that entity.
+func RequestFromValues(values url.Values) (Request, error) {
+	entity, err := ParseEntity(values.Get("entity"))
+	if err != nil {
+		return Request{}, err
+	}
+	format, err := ParseFormat(values.Get("format"))
+	if err != nil {
+		return Request{}, err
+	}
+	amounts, err := ParseAmountFormat(values.Get("amountFormat"))
+	if err != nil {
+		return Request{}, err
+	}
+
+	if format != FormatCSV && (values.Has("columns") || values.Has("delimiter")) {
+		return Request{}, fmt.Errorf("columns and delimiter apply only to csv exports")
+	}
+	columns, err := ParseColumns(entity, values.Get("columns"))
+	if err != nil {
+		return Request{}, err
+	}
+	delimiter, err := ParseDelimiter(values.Get("delimiter"))
+	if err != nil {
+		return Request{}, err
+	}
+
+	filters := url.Values{}
+	for key, vals := range values {
+		if !slices.Contains(requestKeys, key) {
+			filters[key] = vals
+		}
+	}
+
+	req := Request{Entity: entity, Format: format, Amounts: amounts, Columns: columns, Delimiter: delimiter}
+	switch entity {
+	case EntityUsers:
+		req.Users, err = UsersOptionsFromValues(filters)
+	case EntityTransactions:
+		req.Transactions, err = TransactionsOptionsFromValues(filters)
+	case EntityEdges, EntityNodes:
+		req.Graph, err = GraphOptionsFromValues(filters)
+	}
+	return req, err
+}
+
+// UsersOptionsFromValues builds a user filter from query-style values using the same
+// names as GET /users (search, kycStatus, riskMin, riskMax, country, city, emailDomain,
+// createdAfter, createdBefore), plus since, which keeps users updated at or after it.
+func UsersOptionsFromValues(values url.Values) (repository.ListUsersOptions, error) {
+	if err := checkKeys(values, userFilterKeys); err != nil {
+		return repository.ListUsersOptions{}, err
+	}
+	opts := repository.ListUsersOptions{
+		Search:      values.Get("search"),
+		KYCStatus:   values.Get("kycStatus"),
+		Country:     values.Get("country"),
+		City:        values.Get("city"),
+		EmailDomain: values.Get("emailDomain"),
+	}
+	var err error
+	if opts.RiskMin, err = parseFloat(values, "riskMin"); err != nil {
+		return opts, err
+	}
+	if opts.RiskMax, err = parseFloat(values, "riskMax"); err != nil {
+		return opts, err
+	}
+	if opts.CreatedAfter, err = parseTime(values, "createdAfter"); err != nil {
+		return opts, err
+	}
+	if opts.CreatedBefore, err = parseTime(values, "createdBefore"); err != nil {
+		return opts, err
+	}
+	if opts.UpdatedSince, err = parseTime(values, "since"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// TransactionsOptionsFromValues builds a transaction filter from query-style values
+// using the same names as GET /transactions (search, userId, status, type, channel,
+// currency, deviceId, ipAddress, includeLinked, senderRiskMin, receiverRiskMin,
+// anyParticipantRiskMin, participantKyc, minAmount, maxAmount, start, end), plus since,
+// which keeps transactions updated at or after it.
+func TransactionsOptionsFromValues(values url.Values) (repository.ListTransactionsOptions, error) {
+	if err := checkKeys(values, transactionFilterKeys); err != nil {
+		return repository.ListTransactionsOptions{}, err
+	}
+	opts := repository.ListTransactionsOptions{
+		Search:    values.Get("search"),
+		UserID:    values.Get("userId"),
+		Status:    values.Get("status"),
+		Type:      values.Get("type"),
+		Channel:   values.Get("channel"),
+		Currency:  values.Get("currency"),
+		DeviceID:  values.Get("deviceId"),
+		IPAddress: values.Get("ipAddress"),
+	}
+	if raw := values.Get("includeLinked"); raw != "" {
+		linked, err := strconv.ParseBool(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid includeLinked: %w", err)
+		}
+		opts.IncludeLinked = linked
+	}
+	switch kyc := strings.ToUpper(strings.TrimSpace(values.Get("participantKyc"))); kyc {
+	case "", repository.ParticipantKYCNoneVerified, repository.ParticipantKYCAnyVerified:
+		opts.ParticipantKYC = kyc
+	default:
+		return opts, fmt.Errorf("invalid participantKyc %q", values.Get("participantKyc"))
+	}
+	var err error
+	if opts.MinAmount, err = parseFloat(values, "minAmount"); err != nil {
+		return opts, err
+	}
+	if opts.MaxAmount, err = parseFloat(values, "maxAmount"); err != nil {
+		return opts, err
+	}
+	if opts.SenderRiskMin, err = parseFloat(values, "senderRiskMin"); err != nil {
+		return opts, err
+	}
+	if opts.ReceiverRiskMin, err = parseFloat(values, "receiverRiskMin"); err != nil {
+		return opts, err
+	}
+	if opts.AnyParticipantRiskMin, err = parseFloat(values, "anyParticipantRiskMin"); err != nil {
+		return opts, err
+	}
+	if opts.StartTs, err = parseTime(values, "start"); err != nil {
+		return opts, err
+	}
+	if opts.EndTs, err = parseTime(values, "end"); err != nil {
+		return opts, err
+	}
+	if opts.UpdatedSince, err = parseTime(values, "since"); err != nil {
+		return opts, err
+	}
+	return opts, nil
+}
+
+// GraphOptionsFromValues builds an edge or node export filter from query-style values:
+// edgeTypes is a comma-separated list of relationship types from
+// repository.ExportEdgeTypes.
+func GraphOptionsFromValues(values url.Values) (repository.GraphExportOptions, error) {
+	if err := checkKeys(values, graphFilterKeys); err != nil {
+		return repository.GraphExportOptions{}, err
+	}
+	var opts repository.GraphExportOptions
+	for _, edgeType := range strings.Split(values.Get("edgeTypes"), ",") {
+		edgeType = strings.ToUpper(strings.TrimSpace(edgeType))
+		if edgeType == "" {
+			continue
+		}
+		if !slices.Contains(repository.ExportEdgeTypes, edgeType) {
+			return opts, fmt.Errorf("unsupported edge type %q (expected one of %s)", edgeType, strings.Join(repository.ExportEdgeTypes, ", "))
+		}
+		if !slices.Contains(opts.EdgeTypes, edgeType) {
+			opts.EdgeTypes = append(opts.EdgeTypes, edgeType)
+		}
+	}
+	return opts, nil
+}
+
+func checkKeys(values url.Values, allowed []string) error {
+	for key := range values {
+		known := false
+		for _, candidate := range allowed {
+			if key == candidate {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown filter %q", key)
+		}
+	}
+	return nil
+}
+
+func parseFloat(values url.Values, key string) (float64, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %w", key, err)
+	}
+	return v, nil
+}
+
+func parseTime(values url.Values, key string) (*time.Time, error) {
+	raw := values.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	ts, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s timestamp: %w", key, err)
+	}
+	return &ts, nil
+}
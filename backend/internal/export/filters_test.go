@@ -0,0 +1,63 @@
+package export
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestUsersOptionsCreatedRange(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		query      string
+		wantAfter  *time.Time
+		wantBefore *time.Time
+		wantErr    bool
+	}{
+		{name: "only after", query: "createdAfter=2024-01-01T00:00:00Z", wantAfter: &after},
+		{name: "only before", query: "createdBefore=2024-06-30T00:00:00Z", wantBefore: &before},
+		{name: "both", query: "createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-06-30T00:00:00Z", wantAfter: &after, wantBefore: &before},
+		{name: "invalid timestamp", query: "createdAfter=January", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			opts, err := UsersOptionsFromValues(values)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("UsersOptionsFromValues(%q) succeeded, want an error", tt.query)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("UsersOptionsFromValues(%q) error = %v", tt.query, err)
+			}
+			if !sameTime(opts.CreatedAfter, tt.wantAfter) || !sameTime(opts.CreatedBefore, tt.wantBefore) {
+				t.Errorf("range = %v..%v, want %v..%v", opts.CreatedAfter, opts.CreatedBefore, tt.wantAfter, tt.wantBefore)
+			}
+		})
+	}
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func TestTransactionsOptionsCurrency(t *testing.T) {
+	opts, err := TransactionsOptionsFromValues(url.Values{"currency": {"eur"}})
+	if err != nil {
+		t.Fatalf("TransactionsOptionsFromValues() error = %v", err)
+	}
+	// The repository upper-cases it, as for GET /transactions.
+	if opts.Currency != "eur" {
+		t.Errorf("Currency = %q, want eur", opts.Currency)
+	}
+}
@@ -0,0 +1,371 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// JobStatus is the lifecycle state of an export job.
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "PENDING"
+	JobRunning   JobStatus = "RUNNING"
+	JobSucceeded JobStatus = "SUCCEEDED"
+	JobFailed    JobStatus = "FAILED"
+	JobCancelled JobStatus = "CANCELLED"
+)
+
+// Done reports whether the job has reached a terminal state.
+func (s JobStatus) Done() bool {
+	return s == JobSucceeded || s == JobFailed || s == JobCancelled
+}
+
+// ErrJobNotFound indicates the export job does not exist or has been cleaned up.
+var ErrJobNotFound = errors.New("export job not found")
+
+// ErrTooManyJobs is returned by JobManager.Start when JobManagerOptions.MaxRunning jobs
+// are already running.
+var ErrTooManyJobs = errors.New("too many export jobs running")
+
+// Job describes an asynchronous export and its progress.
+type Job struct {
+	ID         string
+	Entity     Entity
+	Format     Format
+	Status     JobStatus
+	Records    int
+	Error      string
+	Path       string
+	CreatedAt  time.Time
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	// MaxUpdatedAt is the Result.MaxUpdatedAt of a finished user or transaction export,
+	// the since value for the next incremental export.
+	MaxUpdatedAt *time.Time
+}
+
+// JobStore persists export job state. MemoryJobStore is the default; durable backends
+// implement the same contract.
+type JobStore interface {
+	Save(ctx context.Context, job Job) error
+	Get(ctx context.Context, id string) (Job, error)
+	List(ctx context.Context) ([]Job, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// MemoryJobStore keeps job state in process memory; it is lost on restart.
+type MemoryJobStore struct {
+	mu   sync.RWMutex
+	jobs map[string]Job
+}
+
+// NewMemoryJobStore creates an empty in-memory store.
+func NewMemoryJobStore() *MemoryJobStore {
+	return &MemoryJobStore{jobs: make(map[string]Job)}
+}
+
+func (s *MemoryJobStore) Save(_ context.Context, job Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[job.ID] = job
+	return nil
+}
+
+func (s *MemoryJobStore) Get(_ context.Context, id string) (Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	job, ok := s.jobs[id]
+	if !ok {
+		return Job{}, ErrJobNotFound
+	}
+	return job, nil
+}
+
+func (s *MemoryJobStore) List(_ context.Context) ([]Job, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	jobs := make([]Job, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		jobs = append(jobs, job)
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt.Before(jobs[j].CreatedAt) })
+	return jobs, nil
+}
+
+func (s *MemoryJobStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.jobs, id)
+	return nil
+}
+
+// JobManagerOptions configures a JobManager.
+type JobManagerOptions struct {
+	// Dir receives export artifacts. It is created if missing.
+	Dir string
+	// Retention is how long finished jobs and their artifacts are kept.
+	Retention time.Duration
+	// MaxRunning caps the jobs running at once, as each reads the whole graph and writes a
+	// full artifact; zero is unlimited.
+	MaxRunning int
+	Store      JobStore
+	Logger     *slog.Logger
+}
+
+const defaultJobRetention = 24 * time.Hour
+
+// JobManager runs exports in the background, decoupled from any HTTP request.
+type JobManager struct {
+	src        Source
+	store      JobStore
+	dir        string
+	retention  time.Duration
+	maxRunning int
+	logger     *slog.Logger
+	nowFn      func() time.Time
+
+	mu      sync.Mutex
+	cancels map[string]context.CancelFunc
+	baseCtx context.Context
+	stop    context.CancelFunc
+	wg      sync.WaitGroup
+}
+
+// NewJobManager creates a JobManager writing artifacts under opts.Dir.
+func NewJobManager(src Source, opts JobManagerOptions) (*JobManager, error) {
+	if opts.Dir == "" {
+		return nil, errors.New("export job directory is required")
+	}
+	if err := os.MkdirAll(opts.Dir, 0o750); err != nil {
+		return nil, fmt.Errorf("create export directory: %w", err)
+	}
+	if opts.Retention <= 0 {
+		opts.Retention = defaultJobRetention
+	}
+	if opts.Store == nil {
+		opts.Store = NewMemoryJobStore()
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.Default()
+	}
+	baseCtx, stop := context.WithCancel(context.Background())
+	return &JobManager{
+		src:        src,
+		store:      opts.Store,
+		dir:        opts.Dir,
+		retention:  opts.Retention,
+		maxRunning: opts.MaxRunning,
+		logger:     opts.Logger,
+		nowFn:      time.Now,
+		cancels:    make(map[string]context.CancelFunc),
+		baseCtx:    baseCtx,
+		stop:       stop,
+	}, nil
+}
+
+// Start records a new job for req and runs it in the background. It returns
+// ErrTooManyJobs when MaxRunning jobs are already running.
+func (m *JobManager) Start(ctx context.Context, req Request) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	job := Job{
+		ID:        id,
+		Entity:    req.Entity,
+		Format:    req.Format,
+		Status:    JobPending,
+		Path:      filepath.Join(m.dir, id+"."+string(req.Format)),
+		CreatedAt: m.nowFn().UTC(),
+	}
+
+	// The slot is taken before the job is saved, so concurrent calls cannot overshoot
+	// the cap.
+	m.mu.Lock()
+	if m.maxRunning > 0 && len(m.cancels) >= m.maxRunning {
+		m.mu.Unlock()
+		return Job{}, fmt.Errorf("%w: the maximum is %d", ErrTooManyJobs, m.maxRunning)
+	}
+	jobCtx, cancel := context.WithCancel(m.baseCtx)
+	m.cancels[id] = cancel
+	m.mu.Unlock()
+
+	if err := m.store.Save(ctx, job); err != nil {
+		m.forget(id)
+		return Job{}, fmt.Errorf("save export job: %w", err)
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.forget(id)
+		m.run(jobCtx, job, req)
+	}()
+	return job, nil
+}
+
+func (m *JobManager) run(ctx context.Context, job Job, req Request) {
+	started := m.nowFn().UTC()
+	job.Status = JobRunning
+	job.StartedAt = &started
+	m.save(job)
+
+	err := m.write(ctx, &job, req)
+	finished := m.nowFn().UTC()
+	job.FinishedAt = &finished
+	switch {
+	case err == nil:
+		job.Status = JobSucceeded
+	case errors.Is(err, context.Canceled):
+		job.Status = JobCancelled
+		_ = os.Remove(job.Path)
+	default:
+		job.Status = JobFailed
+		job.Error = err.Error()
+		_ = os.Remove(job.Path)
+		m.logger.Error("export job failed", "jobId", job.ID, "error", err)
+	}
+	m.save(job)
+}
+
+func (m *JobManager) write(ctx context.Context, job *Job, req Request) error {
+	file, err := os.OpenFile(job.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o640)
+	if err != nil {
+		return fmt.Errorf("create export artifact: %w", err)
+	}
+	defer file.Close()
+
+	req.Offset = 0
+	req.OnBatch = func(progress Result) error {
+		job.Records = progress.Records
+		m.save(*job)
+		return ctx.Err()
+	}
+	result, err := Run(ctx, m.src, file, req)
+	job.Records = result.Records
+	if !result.MaxUpdatedAt.IsZero() {
+		job.MaxUpdatedAt = &result.MaxUpdatedAt
+	}
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func (m *JobManager) save(job Job) {
+	if err := m.store.Save(context.Background(), job); err != nil {
+		m.logger.Warn("failed to save export job", "jobId", job.ID, "error", err)
+	}
+}
+
+func (m *JobManager) forget(id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if cancel, ok := m.cancels[id]; ok {
+		cancel()
+		delete(m.cancels, id)
+	}
+}
+
+// Get returns the current state of a job.
+func (m *JobManager) Get(ctx context.Context, id string) (Job, error) {
+	return m.store.Get(ctx, id)
+}
+
+// Cancel stops a running job, or removes a finished job and its artifact.
+func (m *JobManager) Cancel(ctx context.Context, id string) (Job, error) {
+	job, err := m.store.Get(ctx, id)
+	if err != nil {
+		return Job{}, err
+	}
+	if !job.Status.Done() {
+		m.mu.Lock()
+		cancel, ok := m.cancels[id]
+		m.mu.Unlock()
+		if ok {
+			cancel()
+		}
+		job.Status = JobCancelled
+		return job, nil
+	}
+	return job, m.remove(ctx, job)
+}
+
+func (m *JobManager) remove(ctx context.Context, job Job) error {
+	if err := os.Remove(job.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove export artifact: %w", err)
+	}
+	return m.store.Delete(ctx, job.ID)
+}
+
+// Cleanup removes finished jobs, and their artifacts, older than the retention period.
+func (m *JobManager) Cleanup(ctx context.Context) (int, error) {
+	jobs, err := m.store.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list export jobs: %w", err)
+	}
+	cutoff := m.nowFn().Add(-m.retention)
+	removed := 0
+	for _, job := range jobs {
+		if !job.Status.Done() || job.FinishedAt == nil || job.FinishedAt.After(cutoff) {
+			continue
+		}
+		if err := m.remove(ctx, job); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+// RunCleanup calls Cleanup every interval until ctx is done.
+func (m *JobManager) RunCleanup(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if removed, err := m.Cleanup(ctx); err != nil {
+				m.logger.Warn("export job cleanup failed", "error", err)
+			} else if removed > 0 {
+				m.logger.Info("removed expired export jobs", "count", removed)
+			}
+		}
+	}
+}
+
+// Shutdown cancels running jobs and waits for them to stop or for ctx to expire.
+func (m *JobManager) Shutdown(ctx context.Context) error {
+	m.stop()
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newJobID() (string, error) {
+	var buf [12]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("generate export job id: %w", err)
+	}
+	return hex.EncodeToString(buf[:]), nil
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// record is a single exported row. It is marshalled as-is for JSON formats and
+// flattened through csvRow for CSV.
+type record interface {
+	csvRow() []string
+}
+
+var userColumns = []string{"userId", "fullName", "email", "phone", "kycStatus", "riskScore", "createdAt", "updatedAt"}
+
+type userRecord struct {
+	UserID    string  `json:"userId"`
+	FullName  string  `json:"fullName"`
+	Email     string  `json:"email"`
+	Phone     string  `json:"phone"`
+	KYCStatus string  `json:"kycStatus"`
+	RiskScore float64 `json:"riskScore"`
+	CreatedAt string  `json:"createdAt,omitempty"`
+	UpdatedAt string  `json:"updatedAt,omitempty"`
+}
+
+func newUserRecord(u domain.UserSummary) userRecord {
+	return userRecord{
+		UserID:    u.ID,
+		FullName:  u.FullName,
+		Email:     u.Email,
+		Phone:     u.Phone,
+		KYCStatus: u.KYCStatus,
+		RiskScore: u.RiskScore,
+		CreatedAt: formatTime(u.CreatedAt),
+		UpdatedAt: formatTime(u.UpdatedAt),
+	}
+}
+
+func (r userRecord) csvRow() []string {
+	return []string{r.UserID, r.FullName, r.Email, r.Phone, r.KYCStatus, formatFloat(r.RiskScore), r.CreatedAt, r.UpdatedAt}
+}
+
+var transactionColumns = []string{"transactionId", "senderUserId", "receiverUserId", "amount", "currency", "type", "status", "channel", "timestamp", "createdAt", "updatedAt"}
+
+type transactionRecord struct {
+	TransactionID  string `json:"transactionId"`
+	SenderUserID   string `json:"senderUserId"`
+	ReceiverUserID string `json:"receiverUserId"`
+	Amount         amount `json:"amount"`
+	Currency       string `json:"currency"`
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Channel        string `json:"channel"`
+	Timestamp      string `json:"timestamp,omitempty"`
+	CreatedAt      string `json:"createdAt,omitempty"`
+	UpdatedAt      string `json:"updatedAt,omitempty"`
+}
+
+func newTransactionRecord(t domain.TransactionSummary, amounts AmountFormat) transactionRecord {
+	return transactionRecord{
+		TransactionID:  t.ID,
+		SenderUserID:   t.SenderUserID,
+		ReceiverUserID: t.ReceiverUserID,
+		Amount:         newAmount(t.Amount, t.Currency, amounts),
+		Currency:       t.Currency,
+		Type:           t.Type,
+		Status:         t.Status,
+		Channel:        t.Channel,
+		Timestamp:      formatTime(t.Timestamp),
+		CreatedAt:      formatTime(t.CreatedAt),
+		UpdatedAt:      formatTime(t.UpdatedAt),
+	}
+}
+
+func (r transactionRecord) csvRow() []string {
+	return []string{
+		r.TransactionID, r.SenderUserID, r.ReceiverUserID, r.Amount.String(), r.Currency,
+		r.Type, r.Status, r.Channel, r.Timestamp, r.CreatedAt, r.UpdatedAt,
+	}
+}
+
+var edgeColumns = []string{"source", "target", "type", "weight"}
+
+type edgeRecord struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Type   string  `json:"type"`
+	Weight float64 `json:"weight"`
+}
+
+func newEdgeRecord(e domain.GraphEdge) edgeRecord {
+	return edgeRecord{Source: e.Source, Target: e.Target, Type: e.Type, Weight: e.Weight}
+}
+
+func (r edgeRecord) csvRow() []string {
+	return []string{r.Source, r.Target, r.Type, formatFloat(r.Weight)}
+}
+
+var nodeColumns = []string{"id", "label", "key", "attributeType"}
+
+type nodeRecord struct {
+	ID            string `json:"id"`
+	Label         string `json:"label"`
+	Key           string `json:"key"`
+	AttributeType string `json:"attributeType,omitempty"`
+}
+
+func newNodeRecord(n domain.GraphNode) nodeRecord {
+	return nodeRecord{ID: n.ID, Label: n.Label, Key: n.Key, AttributeType: n.AttributeType}
+}
+
+func (r nodeRecord) csvRow() []string {
+	return []string{r.ID, r.Label, r.Key, r.AttributeType}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,69 @@
+package export
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestRecordsOmitMissingTimestamps(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.FixedZone("CET", 3600))
+	tests := []struct {
+		name    string
+		record  record
+		absent  []string
+		present map[string]string
+	}{
+		{
+			name:   "user without timestamps",
+			record: newUserRecord(domain.UserSummary{ID: "USR-1"}),
+			absent: []string{"createdAt", "updatedAt"},
+		},
+		{
+			name:    "user with createdAt",
+			record:  newUserRecord(domain.UserSummary{ID: "USR-1", CreatedAt: created}),
+			absent:  []string{"updatedAt"},
+			present: map[string]string{"createdAt": "2024-03-01T08:30:00Z"},
+		},
+		{
+			name:   "transaction without timestamps",
+			record: newTransactionRecord(domain.TransactionSummary{ID: "TX-1"}, AmountsRaw),
+			absent: []string{"timestamp", "createdAt", "updatedAt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.record)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var object map[string]any
+			if err := json.Unmarshal(data, &object); err != nil {
+				t.Fatal(err)
+			}
+			for _, key := range tt.absent {
+				if value, ok := object[key]; ok {
+					t.Errorf("%s = %q, want it omitted: %s", key, value, data)
+				}
+			}
+			for key, want := range tt.present {
+				if object[key] != want {
+					t.Errorf("%s = %v, want %s", key, object[key], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCSVRowLeavesMissingTimestampsBlank(t *testing.T) {
+	row := newUserRecord(domain.UserSummary{ID: "USR-1"}).csvRow()
+	if len(row) != len(userColumns) {
+		t.Fatalf("row has %d cells, want %d", len(row), len(userColumns))
+	}
+	// CSV has no null, so the columns stay and the cells are empty.
+	if created, updated := row[6], row[7]; created != "" || updated != "" {
+		t.Errorf("createdAt, updatedAt = %q, %q, want empty cells", created, updated)
+	}
+}
@@ -0,0 +1,56 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// userSource serves users as a single export page.
+type userSource struct {
+	Source
+	users []domain.UserSummary
+}
+
+func (s userSource) ExportUsers(_ context.Context, opts repository.ListUsersOptions) ([]domain.UserSummary, error) {
+	if opts.Offset >= len(s.users) {
+		return nil, nil
+	}
+	return s.users[opts.Offset:], nil
+}
+
+func TestRunRedactsUserPII(t *testing.T) {
+	src := userSource{users: []domain.UserSummary{{ID: "USR-1", Email: "jane.doe@example.com", Phone: "555-010-1234"}}}
+	tests := []struct {
+		name      string
+		redact    bool
+		wantEmail string
+		wantPhone string
+	}{
+		{name: "unmasked", wantEmail: "jane.doe@example.com", wantPhone: "555-010-1234"},
+		{name: "masked", redact: true, wantEmail: "j***@example.com", wantPhone: "***-***-1234"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			_, err := Run(context.Background(), src, &out, Request{Entity: EntityUsers, Format: FormatJSON, RedactPII: tt.redact})
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			var users []userRecord
+			if err := json.Unmarshal(out.Bytes(), &users); err != nil {
+				t.Fatalf("decode export: %v\n%s", err, out.String())
+			}
+			if len(users) != 1 {
+				t.Fatalf("exported %d users, want 1", len(users))
+			}
+			if users[0].Email != tt.wantEmail || users[0].Phone != tt.wantPhone {
+				t.Errorf("email, phone = %q, %q, want %q, %q", users[0].Email, users[0].Phone, tt.wantEmail, tt.wantPhone)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// changedSource serves users and transactions, keeping those updated at or after the
+// since filter the way the repository does.
+type changedSource struct {
+	Source
+	users []domain.UserSummary
+	txs   []domain.TransactionSummary
+}
+
+func (s changedSource) ExportUsers(_ context.Context, opts repository.ListUsersOptions) ([]domain.UserSummary, error) {
+	var out []domain.UserSummary
+	for _, u := range s.users {
+		if opts.UpdatedSince == nil || !u.UpdatedAt.Before(*opts.UpdatedSince) {
+			out = append(out, u)
+		}
+	}
+	if opts.Offset >= len(out) {
+		return nil, nil
+	}
+	return out[opts.Offset:], nil
+}
+
+func (s changedSource) ExportTransactions(_ context.Context, opts repository.ListTransactionsOptions) ([]domain.TransactionSummary, error) {
+	var out []domain.TransactionSummary
+	for _, t := range s.txs {
+		if opts.UpdatedSince == nil || !t.UpdatedAt.Before(*opts.UpdatedSince) {
+			out = append(out, t)
+		}
+	}
+	if opts.Offset >= len(out) {
+		return nil, nil
+	}
+	return out[opts.Offset:], nil
+}
+
+func TestRunSinceExportsOnlyChangedRecords(t *testing.T) {
+	lastSync := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	src := changedSource{
+		users: []domain.UserSummary{
+			{ID: "USR-unchanged", UpdatedAt: lastSync.Add(-time.Hour)},
+			{ID: "USR-changed", UpdatedAt: lastSync.Add(2 * time.Hour)},
+			{ID: "USR-latest", UpdatedAt: lastSync.Add(3*time.Hour + 250*time.Millisecond)},
+		},
+		txs: []domain.TransactionSummary{
+			{ID: "TX-unchanged", UpdatedAt: lastSync.Add(-time.Minute)},
+			{ID: "TX-changed", UpdatedAt: lastSync},
+		},
+	}
+
+	var out bytes.Buffer
+	result, err := Run(context.Background(), src, &out, Request{Entity: EntityUsers, Format: FormatJSON, Users: repository.ListUsersOptions{UpdatedSince: &lastSync}})
+	if err != nil {
+		t.Fatalf("Run(users) error = %v", err)
+	}
+	var users []userRecord
+	if err := json.Unmarshal(out.Bytes(), &users); err != nil {
+		t.Fatalf("decode export: %v\n%s", err, out.String())
+	}
+	if len(users) != 2 || users[0].UserID != "USR-changed" || users[1].UserID != "USR-latest" {
+		t.Errorf("exported %+v, want USR-changed and USR-latest", users)
+	}
+	if result.Records != 2 {
+		t.Errorf("Records = %d, want 2", result.Records)
+	}
+	if want := src.users[2].UpdatedAt; !result.MaxUpdatedAt.Equal(want) {
+		t.Errorf("MaxUpdatedAt = %v, want %v", result.MaxUpdatedAt, want)
+	}
+
+	// The cursor from this run exports only what changed after it.
+	next := result.MaxUpdatedAt
+	out.Reset()
+	result, err = Run(context.Background(), src, &out, Request{Entity: EntityUsers, Format: FormatNDJSON, Users: repository.ListUsersOptions{UpdatedSince: &next}})
+	if err != nil {
+		t.Fatalf("Run(users, next) error = %v", err)
+	}
+	if result.Records != 1 {
+		t.Errorf("second run exported %d users, want only USR-latest:\n%s", result.Records, out.String())
+	}
+
+	out.Reset()
+	result, err = Run(context.Background(), src, &out, Request{Entity: EntityTransactions, Format: FormatNDJSON, Transactions: repository.ListTransactionsOptions{UpdatedSince: &lastSync}})
+	if err != nil {
+		t.Fatalf("Run(transactions) error = %v", err)
+	}
+	if result.Records != 1 || !result.MaxUpdatedAt.Equal(lastSync) {
+		t.Errorf("transactions result = %+v, want 1 record updated at %v:\n%s", result, lastSync, out.String())
+	}
+}
+
+func TestRunWithoutChangesLeavesCursorZero(t *testing.T) {
+	since := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	src := changedSource{users: []domain.UserSummary{{ID: "USR-1", UpdatedAt: since.Add(-time.Hour)}}}
+	result, err := Run(context.Background(), src, &bytes.Buffer{}, Request{Entity: EntityUsers, Format: FormatJSON, Users: repository.ListUsersOptions{UpdatedSince: &since}})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Records != 0 || !result.MaxUpdatedAt.IsZero() {
+		t.Errorf("result = %+v, want no records and a zero cursor", result)
+	}
+}
+
+func TestOptionsFromValuesSince(t *testing.T) {
+	values := url.Values{"since": {"2024-03-01T10:00:00.5Z"}}
+	want := time.Date(2024, 3, 1, 10, 0, 0, 500_000_000, time.UTC)
+	users, err := UsersOptionsFromValues(values)
+	if err != nil {
+		t.Fatalf("UsersOptionsFromValues() error = %v", err)
+	}
+	if !sameTime(users.UpdatedSince, &want) {
+		t.Errorf("users UpdatedSince = %v, want %v", users.UpdatedSince, want)
+	}
+	txs, err := TransactionsOptionsFromValues(values)
+	if err != nil {
+		t.Fatalf("TransactionsOptionsFromValues() error = %v", err)
+	}
+	if !sameTime(txs.UpdatedSince, &want) {
+		t.Errorf("transactions UpdatedSince = %v, want %v", txs.UpdatedSince, want)
+	}
+	if _, err := UsersOptionsFromValues(url.Values{"since": {"yesterday"}}); err == nil {
+		t.Error("UsersOptionsFromValues(since=yesterday) succeeded, want an error")
+	}
+}
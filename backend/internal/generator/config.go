@@ -9,8 +9,17 @@ type Config struct {
 	IPShareChance            float64
 	DeviceShareChance        float64
 	Seed                     int64
+	// HubBias is the probability, in [0, MaxHubBias], that a transaction endpoint is
+	// picked in proportion to the user's existing degree rather than uniformly. Zero
+	// keeps the uniform default; higher values grow a few hub users with a power-law
+	// degree distribution.
+	HubBias float64
 }
 
+// MaxHubBias bounds Config.HubBias; at 1 every endpoint after the first transaction
+// would be drawn from users already transacting, collapsing the graph onto a handful.
+const MaxHubBias = 0.9
+
 // DefaultConfig returns baseline settings that satisfy the assignment requirements.
 func DefaultConfig() Config {
 	return Config{
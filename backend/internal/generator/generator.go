@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vanshika/fintrace/backend/internal/service"
@@ -46,6 +48,12 @@ func New(cfg Config) *Generator {
 	if cfg.Seed == 0 {
 		cfg.Seed = time.Now().UnixNano()
 	}
+	if cfg.HubBias < 0 {
+		cfg.HubBias = 0
+	}
+	if cfg.HubBias > MaxHubBias {
+		cfg.HubBias = MaxHubBias
+	}
 
 	return &Generator{
 		cfg:           cfg,
@@ -57,8 +65,26 @@ func New(cfg Config) *Generator {
 
 // Generate synthesises users and transactions. It respects context cancellation.
 func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
-	users := make([]service.UserInput, g.cfg.NumUsers)
-	userPaymentMethods := make(map[string][]string, g.cfg.NumUsers)
+	return g.Extend(ctx, Dataset{})
+}
+
+// Extend appends NumUsers users and NumTransactions transactions to an existing dataset and
+// returns the merged result. ID sequences continue from the highest USR-/TX- number already
+// present, attribute pools are seeded from the existing records so new records link with old
+// ones, and new transactions may reference existing users.
+func (g *Generator) Extend(ctx context.Context, existing Dataset) (Dataset, error) {
+	g.seedPools(existing)
+	userOffset := maxSequence(existing.Users, func(u service.UserInput) string { return u.ID }, "USR-")
+	txOffset := maxSequence(existing.Transactions, func(t service.TransactionInput) string { return t.ID }, "TX-")
+
+	users := make([]service.UserInput, 0, len(existing.Users)+g.cfg.NumUsers)
+	users = append(users, existing.Users...)
+	userPaymentMethods := make(map[string][]string, cap(users))
+	for _, user := range existing.Users {
+		for _, pm := range user.PaymentMethods {
+			userPaymentMethods[user.ID] = append(userPaymentMethods[user.ID], pm.ID)
+		}
+	}
 	now := time.Now().UTC()
 
 	for i := 0; i < g.cfg.NumUsers; i++ {
@@ -66,7 +92,7 @@ func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
 			return Dataset{}, err
 		}
 
-		userID := fmt.Sprintf("USR-%06d", i+1)
+		userID := fmt.Sprintf("USR-%06d", userOffset+i+1)
 		createdAt := now.Add(-time.Duration(g.rand.Intn(365*24)) * time.Hour)
 		updatedAt := createdAt.Add(time.Duration(g.rand.Intn(72)) * time.Hour)
 
@@ -87,7 +113,7 @@ func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
 
 		dob := time.Date(1960+g.rand.Intn(30), time.Month(1+g.rand.Intn(12)), 1+g.rand.Intn(28), 0, 0, 0, 0, time.UTC)
 
-		users[i] = service.UserInput{
+		users = append(users, service.UserInput{
 			ID:             userID,
 			FullName:       g.randomFullName(),
 			Email:          email,
@@ -99,23 +125,31 @@ func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
 			PaymentMethods: paymentMethods,
 			CreatedAt:      &createdAt,
 			UpdatedAt:      &updatedAt,
-		}
+		})
+	}
+	if len(users) == 0 {
+		return Dataset{}, fmt.Errorf("cannot generate transactions without users")
 	}
 
-	transactions := make([]service.TransactionInput, g.cfg.NumTransactions)
+	transactions := make([]service.TransactionInput, 0, len(existing.Transactions)+g.cfg.NumTransactions)
+	transactions = append(transactions, existing.Transactions...)
 	merchantCategories := []string{"REMITTANCE", "PAYROLL", "E_COMMERCE", "CRYPTO", "GAMBLING", "DONATION"}
+	endpoints := g.seedEndpoints(users, existing.Transactions)
 
 	for i := 0; i < g.cfg.NumTransactions; i++ {
 		if err := ctx.Err(); err != nil {
 			return Dataset{}, err
 		}
 
-		txID := fmt.Sprintf("TX-%07d", i+1)
-		senderIdx := g.rand.Intn(len(users))
-		receiverIdx := g.rand.Intn(len(users))
+		txID := fmt.Sprintf("TX-%07d", txOffset+i+1)
+		senderIdx := g.pickUser(len(users), endpoints)
+		receiverIdx := g.pickUser(len(users), endpoints)
 		if senderIdx == receiverIdx {
 			receiverIdx = (receiverIdx + 1) % len(users)
 		}
+		if g.cfg.HubBias > 0 {
+			endpoints = append(endpoints, senderIdx, receiverIdx)
+		}
 
 		sender := users[senderIdx]
 		receiver := users[receiverIdx]
@@ -133,7 +167,7 @@ func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
 		createdAt := timestamp.Add(-time.Duration(g.rand.Intn(120)) * time.Minute)
 		updatedAt := timestamp.Add(time.Duration(g.rand.Intn(120)) * time.Minute)
 
-		transactions[i] = service.TransactionInput{
+		transactions = append(transactions, service.TransactionInput{
 			ID:              txID,
 			SenderUserID:    sender.ID,
 			ReceiverUserID:  receiver.ID,
@@ -152,12 +186,91 @@ func (g *Generator) Generate(ctx context.Context) (Dataset, error) {
 			},
 			CreatedAt: &createdAt,
 			UpdatedAt: &updatedAt,
-		}
+		})
 	}
 
 	return Dataset{Users: users, Transactions: transactions}, nil
 }
 
+// seedEndpoints returns the user index of every endpoint of existing, so preferential
+// sampling in Extend continues from the degrees already present. It returns nil when
+// HubBias is disabled.
+func (g *Generator) seedEndpoints(users []service.UserInput, existing []service.TransactionInput) []int {
+	if g.cfg.HubBias <= 0 {
+		return nil
+	}
+	index := make(map[string]int, len(users))
+	for i, u := range users {
+		index[u.ID] = i
+	}
+	endpoints := make([]int, 0, 2*(len(existing)+g.cfg.NumTransactions))
+	for _, tx := range existing {
+		for _, id := range []string{tx.SenderUserID, tx.ReceiverUserID} {
+			if idx, ok := index[id]; ok {
+				endpoints = append(endpoints, idx)
+			}
+		}
+	}
+	return endpoints
+}
+
+// pickUser returns a user index. With probability HubBias it draws from endpoints, which
+// favours users in proportion to their degree (preferential attachment); otherwise, or
+// before any endpoint exists, it picks uniformly. No extra random draws are made when
+// HubBias is zero, so existing seeds reproduce the same data.
+func (g *Generator) pickUser(n int, endpoints []int) int {
+	if g.cfg.HubBias > 0 && len(endpoints) > 0 && g.rand.Float64() < g.cfg.HubBias {
+		return endpoints[g.rand.Intn(len(endpoints))]
+	}
+	return g.rand.Intn(n)
+}
+
+// seedPools loads attribute values from existing records so new records can share them.
+func (g *Generator) seedPools(existing Dataset) {
+	seenPayments := make(map[string]struct{})
+	for _, user := range existing.Users {
+		if user.Email != "" {
+			g.pools.emails = append(g.pools.emails, user.Email)
+		}
+		if user.Phone != "" {
+			g.pools.phones = append(g.pools.phones, user.Phone)
+		}
+		if user.Address != (service.AddressInput{}) {
+			g.pools.addresses = append(g.pools.addresses, user.Address)
+		}
+		for _, pm := range user.PaymentMethods {
+			if _, ok := seenPayments[pm.ID]; ok || pm.ID == "" {
+				continue
+			}
+			seenPayments[pm.ID] = struct{}{}
+			g.pools.payments = append(g.pools.payments, pm)
+		}
+	}
+	for _, tx := range existing.Transactions {
+		if tx.IPAddress != "" {
+			g.pools.ips = append(g.pools.ips, tx.IPAddress)
+		}
+		if tx.DeviceID != "" {
+			g.pools.devices = append(g.pools.devices, tx.DeviceID)
+		}
+	}
+}
+
+// maxSequence returns the highest numeric suffix among IDs carrying prefix, ignoring other formats.
+func maxSequence[T any](items []T, id func(T) string, prefix string) int {
+	highest := 0
+	for _, item := range items {
+		value, ok := strings.CutPrefix(id(item), prefix)
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(value); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return highest
+}
+
 type attributePools struct {
 	emails    []string
 	phones    []string
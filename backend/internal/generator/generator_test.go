@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+// degreeSkew returns the highest out-degree divided by the median, over every user.
+func degreeSkew(t *testing.T, cfg Config) float64 {
+	t.Helper()
+	data, err := New(cfg).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	degree := make(map[string]int, len(data.Users))
+	for _, user := range data.Users {
+		degree[user.ID] = 0
+	}
+	for _, tx := range data.Transactions {
+		degree[tx.SenderUserID]++
+	}
+	degrees := make([]int, 0, len(degree))
+	for _, d := range degree {
+		degrees = append(degrees, d)
+	}
+	slices.Sort(degrees)
+	median := degrees[len(degrees)/2]
+	if median == 0 {
+		median = 1
+	}
+	return float64(degrees[len(degrees)-1]) / float64(median)
+}
+
+func TestHubBiasSkewsDegreeDistribution(t *testing.T) {
+	base := Config{NumUsers: 500, NumTransactions: 10000, Seed: 42}
+	uniform := degreeSkew(t, base)
+
+	biased := base
+	biased.HubBias = 0.8
+	skewed := degreeSkew(t, biased)
+
+	if skewed <= uniform {
+		t.Fatalf("max/median out-degree with HubBias = %.2f, uniform = %.2f, want it higher", skewed, uniform)
+	}
+	// Uniform picks stay close to regular; preferential attachment grows real hubs.
+	if uniform > 3 {
+		t.Errorf("uniform max/median out-degree = %.2f, want a near-regular graph", uniform)
+	}
+	if skewed < 2*uniform {
+		t.Errorf("max/median out-degree with HubBias = %.2f, want at least twice the uniform %.2f", skewed, uniform)
+	}
+}
+
+func TestGenerateIsDeterministicForASeed(t *testing.T) {
+	cfg := Config{NumUsers: 50, NumTransactions: 200, Seed: 7, HubBias: 0.5}
+	a, err := New(cfg).Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New(cfg).Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range a.Transactions {
+		if a.Transactions[i].SenderUserID != b.Transactions[i].SenderUserID || a.Transactions[i].ReceiverUserID != b.Transactions[i].ReceiverUserID {
+			t.Fatalf("transaction %d differs between runs with the same seed", i)
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validate checks value, as decoded by encoding/json with UseNumber, against the subset
+// of JSON Schema that Schema emits.
+func validate(schema map[string]any, value any, path string) error {
+	if anyOf, ok := schema["anyOf"].([]any); ok {
+		var errs []string
+		for _, option := range anyOf {
+			err := validate(option.(map[string]any), value, path)
+			if err == nil {
+				return nil
+			}
+			errs = append(errs, err.Error())
+		}
+		return fmt.Errorf("%s matches no anyOf option: %s", path, strings.Join(errs, "; "))
+	}
+	typ, _ := schema["type"].(string)
+	switch typ {
+	case "":
+		return nil
+	case "null":
+		if value != nil {
+			return fmt.Errorf("%s = %v, want null", path, value)
+		}
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s = %v, want a string", path, value)
+		}
+		if schema["format"] == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, s); err != nil {
+				return fmt.Errorf("%s = %q, want a date-time: %v", path, s, err)
+			}
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s = %v, want a boolean", path, value)
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s = %v, want an integer", path, value)
+		}
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s = %v, want an integer", path, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s = %v, want a number", path, value)
+		}
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s = %v, want an array", path, value)
+		}
+		for i, item := range items {
+			if err := validate(schema["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s = %v, want an object", path, value)
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		required, _ := schema["required"].([]string)
+		for _, name := range required {
+			if _, ok := object[name]; !ok {
+				return fmt.Errorf("%s lacks required %q", path, name)
+			}
+		}
+		for name, field := range object {
+			sub, known := properties[name].(map[string]any)
+			if !known {
+				switch extra := schema["additionalProperties"].(type) {
+				case bool:
+					if !extra {
+						return fmt.Errorf("%s has unexpected %q", path, name)
+					}
+					continue
+				case map[string]any:
+					sub = extra
+				default:
+					continue
+				}
+			}
+			if err := validate(sub, field, path+"."+name); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("%s: unsupported schema type %q", path, typ)
+	}
+	return nil
+}
+
+func decodeSample(t *testing.T, data []byte) any {
+	t.Helper()
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		t.Fatalf("decode sample: %v", err)
+	}
+	return value
+}
+
+func TestGeneratedSampleMatchesSchema(t *testing.T) {
+	dataset, err := New(Config{NumUsers: 50, NumTransactions: 200, Seed: 3}).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	// An ungenerated record leaves every optional value nil, which must still validate.
+	dataset.Users = append(dataset.Users, dataset.Users[0])
+	dataset.Users[len(dataset.Users)-1].DateOfBirth = nil
+	dataset.Users[len(dataset.Users)-1].PaymentMethods = nil
+	dataset.Users[len(dataset.Users)-1].CreatedAt = nil
+
+	data, err := json.Marshal(dataset)
+	if err != nil {
+		t.Fatalf("marshal dataset: %v", err)
+	}
+	if err := validate(Schema(), decodeSample(t, data), "$"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSchemaRejectsDriftedSamples(t *testing.T) {
+	dataset, err := New(Config{NumUsers: 2, NumTransactions: 2, Seed: 3}).Generate(context.Background())
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	data, err := json.Marshal(dataset)
+	if err != nil {
+		t.Fatalf("marshal dataset: %v", err)
+	}
+	tests := []struct {
+		name   string
+		mutate func(sample map[string]any)
+	}{
+		{name: "unknown field", mutate: func(s map[string]any) { firstUser(s)["nickname"] = "Bo" }},
+		{name: "missing field", mutate: func(s map[string]any) { delete(firstUser(s), "ID") }},
+		{name: "time not RFC 3339", mutate: func(s map[string]any) { firstUser(s)["CreatedAt"] = "01/02/2024" }},
+		{name: "wrong type", mutate: func(s map[string]any) { firstUser(s)["RiskScore"] = "high" }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sample := decodeSample(t, data).(map[string]any)
+			tt.mutate(sample)
+			if err := validate(Schema(), sample, "$"); err == nil {
+				t.Error("drifted sample validated against the schema")
+			}
+		})
+	}
+}
+
+func firstUser(sample map[string]any) map[string]any {
+	return sample["users"].([]any)[0].(map[string]any)
+}
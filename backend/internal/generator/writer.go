@@ -1,42 +1,271 @@
 package generator
 
 import (
+	"bufio"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 )
 
+// Approximate serialized sizes per record, measured from indented generator output.
+const (
+	estimatedUserBytes        = 1000
+	estimatedTransactionBytes = 620
+)
+
+// EstimateSize returns the approximate number of bytes WriteDataset produces for cfg.
+func EstimateSize(cfg Config) int64 {
+	return int64(cfg.NumUsers)*estimatedUserBytes + int64(cfg.NumTransactions)*estimatedTransactionBytes
+}
+
+// WriteReport summarises what WriteDatasetWithLimit persisted.
+type WriteReport struct {
+	UsersWritten        int
+	TransactionsWritten int
+	BytesWritten        int64
+	// Files lists the written files in order, users first.
+	Files []string
+	// Truncated is true when the byte cap stopped the write before every record was emitted.
+	Truncated bool
+}
+
 // WriteDataset serializes the dataset into users.json and transactions.json under the provided directory.
 func WriteDataset(dataset Dataset, dir string) error {
+	_, err := WriteDatasetWithLimit(dataset, dir, 0)
+	return err
+}
+
+// WriteDatasetWithLimit behaves like WriteDataset but stops once maxBytes (across both files)
+// would be exceeded. Output stays valid JSON: records that do not fit are dropped whole.
+// A maxBytes of zero or less disables the cap.
+func WriteDatasetWithLimit(dataset Dataset, dir string, maxBytes int64) (WriteReport, error) {
+	return WriteDatasetSharded(dataset, dir, maxBytes, 0)
+}
+
+// WriteDatasetSharded behaves like WriteDatasetWithLimit but splits users and transactions
+// into numbered files of at most shardSize records each (users-0001.json, users-0002.json,
+// transactions-0001.json, ...) so they can be ingested in parallel. A shardSize of zero or
+// less writes users.json and transactions.json. Dataset files left in dir by an earlier
+// run, flat or sharded, are removed first so stale shards are never picked up.
+func WriteDatasetSharded(dataset Dataset, dir string, maxBytes int64, shardSize int) (WriteReport, error) {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
-		return fmt.Errorf("create output dir: %w", err)
+		return WriteReport{}, fmt.Errorf("create output dir: %w", err)
+	}
+	for _, name := range []string{usersFileName, transactionsFileName} {
+		if err := removeDatasetFiles(dir, name); err != nil {
+			return WriteReport{}, err
+		}
 	}
 
-	usersPath := filepath.Join(dir, "users.json")
-	if err := writeJSON(usersPath, dataset.Users); err != nil {
-		return err
+	budget := &byteBudget{limit: maxBytes}
+	var report WriteReport
+
+	written, files, err := writeRecords(dir, usersFileName, len(dataset.Users), func(i int) any { return dataset.Users[i] }, budget, shardSize)
+	report.Files = append(report.Files, files...)
+	if err != nil {
+		return report, err
 	}
+	report.UsersWritten = written
 
-	transactionsPath := filepath.Join(dir, "transactions.json")
-	if err := writeJSON(transactionsPath, dataset.Transactions); err != nil {
-		return err
+	written, files, err = writeRecords(dir, transactionsFileName, len(dataset.Transactions), func(i int) any { return dataset.Transactions[i] }, budget, shardSize)
+	report.Files = append(report.Files, files...)
+	if err != nil {
+		return report, err
+	}
+	report.TransactionsWritten = written
+
+	report.BytesWritten = budget.used
+	report.Truncated = report.UsersWritten < len(dataset.Users) || report.TransactionsWritten < len(dataset.Transactions)
+	return report, nil
+}
+
+// ReadDataset loads the users and transactions previously written to dir, flat or sharded.
+func ReadDataset(dir string) (Dataset, error) {
+	var dataset Dataset
+	if err := readDatasetFiles(dir, usersFileName, &dataset.Users); err != nil {
+		return Dataset{}, err
+	}
+	if err := readDatasetFiles(dir, transactionsFileName, &dataset.Transactions); err != nil {
+		return Dataset{}, err
+	}
+	return dataset, nil
+}
+
+const (
+	usersFileName        = "users"
+	transactionsFileName = "transactions"
+)
+
+// DatasetFiles returns the files holding the named dataset ("users" or "transactions") in
+// dir: name.json when it exists, otherwise the name-NNNN.json shards in shard order. It
+// returns no files, and no error, when neither is present.
+func DatasetFiles(dir, name string) ([]string, error) {
+	flat := filepath.Join(dir, name+".json")
+	if _, err := os.Stat(flat); err == nil {
+		return []string{flat}, nil
+	}
+	shards, err := filepath.Glob(filepath.Join(dir, name+"-[0-9]*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("list %s shards: %w", name, err)
+	}
+	SortShardFiles(shards)
+	return shards, nil
+}
+
+// SortShardFiles orders shard paths by shard number, so users-10000.json follows
+// users-9999.json.
+func SortShardFiles(paths []string) {
+	sort.Slice(paths, func(i, j int) bool {
+		if len(paths[i]) != len(paths[j]) {
+			return len(paths[i]) < len(paths[j])
+		}
+		return paths[i] < paths[j]
+	})
+}
+
+func shardFileName(name string, shard int) string {
+	return fmt.Sprintf("%s-%04d.json", name, shard)
+}
+
+func removeDatasetFiles(dir, name string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, name+"-[0-9]*.json"))
+	if err != nil {
+		return fmt.Errorf("list %s shards: %w", name, err)
+	}
+	paths = append(paths, filepath.Join(dir, name+".json"))
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("remove stale %s: %w", path, err)
+		}
 	}
+	return nil
+}
 
+// writeRecords writes count records as name.json, or as shards of shardSize records when
+// shardSize is positive, stopping at the first shard the budget cuts short.
+func writeRecords(dir, name string, count int, element func(i int) any, budget *byteBudget, shardSize int) (int, []string, error) {
+	if shardSize <= 0 {
+		path := filepath.Join(dir, name+".json")
+		written, err := writeJSONArray(path, count, element, budget)
+		return written, []string{path}, err
+	}
+
+	var files []string
+	written := 0
+	for start := 0; start == 0 || start < count; start += shardSize {
+		size := min(shardSize, count-start)
+		path := filepath.Join(dir, shardFileName(name, len(files)+1))
+		n, err := writeJSONArray(path, size, func(i int) any { return element(start + i) }, budget)
+		if err != nil {
+			return written, files, err
+		}
+		if n == 0 && len(files) > 0 {
+			// The budget ran out exactly at a shard boundary; drop the empty shard.
+			if err := os.Remove(path); err != nil {
+				return written, files, fmt.Errorf("remove empty shard %s: %w", path, err)
+			}
+			break
+		}
+		files = append(files, path)
+		written += n
+		if n < size {
+			break
+		}
+	}
+	return written, files, nil
+}
+
+func readDatasetFiles[T any](dir, name string, target *[]T) error {
+	paths, err := DatasetFiles(dir, name)
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("open %s: %w", filepath.Join(dir, name+".json"), os.ErrNotExist)
+	}
+	for _, path := range paths {
+		var records []T
+		if err := readJSON(path, &records); err != nil {
+			return err
+		}
+		*target = append(*target, records...)
+	}
 	return nil
 }
 
-func writeJSON(path string, data any) error {
-	file, err := os.Create(path)
+func readJSON(path string, target any) error {
+	file, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("open %s: %w", path, err)
 	}
 	defer file.Close()
 
-	encoder := json.NewEncoder(file)
-	encoder.SetIndent("", "  ")
-	if err := encoder.Encode(data); err != nil {
-		return fmt.Errorf("encode json for %s: %w", path, err)
+	if err := json.NewDecoder(bufio.NewReader(file)).Decode(target); err != nil {
+		return fmt.Errorf("decode %s: %w", path, err)
 	}
 	return nil
 }
+
+type byteBudget struct {
+	limit int64
+	used  int64
+}
+
+func (b *byteBudget) fits(n int) bool {
+	return b.limit <= 0 || b.used+int64(n) <= b.limit
+}
+
+// writeJSONArray streams count elements as an indented JSON array, returning how many fit the budget.
+func writeJSONArray(path string, count int, element func(i int) any, budget *byteBudget) (int, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return 0, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	emit := func(chunk []byte) error {
+		if _, err := w.Write(chunk); err != nil {
+			return fmt.Errorf("write %s: %w", path, err)
+		}
+		budget.used += int64(len(chunk))
+		return nil
+	}
+
+	// Reserve room for the closing bracket so a truncated file is still well formed.
+	const closing = "\n]\n"
+	if err := emit([]byte("[")); err != nil {
+		return 0, err
+	}
+	written := 0
+	for i := 0; i < count; i++ {
+		data, err := json.MarshalIndent(element(i), "  ", "  ")
+		if err != nil {
+			return written, fmt.Errorf("encode json for %s: %w", path, err)
+		}
+		prefix := "\n  "
+		if written > 0 {
+			prefix = ",\n  "
+		}
+		if !budget.fits(len(prefix) + len(data) + len(closing)) {
+			break
+		}
+		if err := emit(append([]byte(prefix), data...)); err != nil {
+			return written, err
+		}
+		written++
+	}
+	end := closing
+	if written == 0 {
+		end = "]\n"
+	}
+	if err := emit([]byte(end)); err != nil {
+		return written, err
+	}
+	if err := w.Flush(); err != nil {
+		return written, fmt.Errorf("flush %s: %w", path, err)
+	}
+	return written, nil
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"context"
+	"sync"
+)
+
+type bookmarkTrackerKey struct{}
+
+// BookmarkTracker carries causal-consistency bookmarks through a context. Reads issued
+// with a tracked context wait until the serving replica has applied every write the
+// tracker has seen, and writes record the bookmark they produce. Contexts without a
+// tracker keep the default behaviour, where reads may be stale.
+type BookmarkTracker struct {
+	mu        sync.Mutex
+	bookmarks []string
+}
+
+// WithBookmarkTracker returns a context carrying a tracker seeded with initial, for
+// example bookmarks a client received from an earlier write.
+func WithBookmarkTracker(ctx context.Context, initial []string) (context.Context, *BookmarkTracker) {
+	tracker := &BookmarkTracker{}
+	tracker.Set(initial)
+	return context.WithValue(ctx, bookmarkTrackerKey{}, tracker), tracker
+}
+
+// BookmarkTrackerFrom returns the tracker attached to ctx, or nil.
+func BookmarkTrackerFrom(ctx context.Context) *BookmarkTracker {
+	tracker, _ := ctx.Value(bookmarkTrackerKey{}).(*BookmarkTracker)
+	return tracker
+}
+
+// Bookmarks returns a copy of the current bookmarks.
+func (t *BookmarkTracker) Bookmarks() []string {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string(nil), t.bookmarks...)
+}
+
+// Set replaces the tracked bookmarks. Empty values are ignored.
+func (t *BookmarkTracker) Set(bookmarks []string) {
+	if t == nil {
+		return
+	}
+	cleaned := make([]string, 0, len(bookmarks))
+	for _, bm := range bookmarks {
+		if bm != "" {
+			cleaned = append(cleaned, bm)
+		}
+	}
+	if len(cleaned) == 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.bookmarks = cleaned
+}
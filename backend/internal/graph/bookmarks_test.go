@@ -0,0 +1,39 @@
+package graph
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestBookmarkTrackerThroughContext(t *testing.T) {
+	if tracker := BookmarkTrackerFrom(context.Background()); tracker != nil {
+		t.Fatalf("BookmarkTrackerFrom(background) = %v, want nil", tracker)
+	}
+	// A nil tracker is usable, so untracked contexts need no checks.
+	var none *BookmarkTracker
+	none.Set([]string{"bm:1"})
+	if got := none.Bookmarks(); got != nil {
+		t.Errorf("nil tracker Bookmarks() = %v, want nil", got)
+	}
+
+	ctx, tracker := WithBookmarkTracker(context.Background(), []string{"bm:1", ""})
+	if BookmarkTrackerFrom(ctx) != tracker {
+		t.Fatal("BookmarkTrackerFrom did not return the attached tracker")
+	}
+	if got := tracker.Bookmarks(); !slices.Equal(got, []string{"bm:1"}) {
+		t.Errorf("seeded Bookmarks() = %v, want [bm:1]", got)
+	}
+
+	// A write's bookmark replaces the earlier ones; an empty one keeps them.
+	BookmarkTrackerFrom(ctx).Set([]string{"bm:2"})
+	BookmarkTrackerFrom(ctx).Set([]string{""})
+	got := tracker.Bookmarks()
+	if !slices.Equal(got, []string{"bm:2"}) {
+		t.Errorf("Bookmarks() after a write = %v, want [bm:2]", got)
+	}
+	got[0] = "changed"
+	if tracker.Bookmarks()[0] != "bm:2" {
+		t.Error("Bookmarks() returned the tracker's own slice")
+	}
+}
@@ -3,6 +3,7 @@ package graph
 import (
 	"context"
 	"errors"
+	"time"
 )
 
 // Client defines the minimal contract required by the repositories to interact
@@ -29,7 +30,45 @@ type Options struct {
 	Username       string
 	Password       string
 	MaxConnections int
+	// Limits caps concurrent reads and writes separately; the zero value is unlimited.
+	Limits OperationLimits
+	// ReadTimeout and WriteTimeout bound each ExecuteRead and ExecuteWrite through a
+	// context deadline; zero is unbounded. See WithOperationTimeout for per-call overrides.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// QueryLogging logs every query at debug level; the zero value logs nothing.
+	QueryLogging QueryLogging
+	// Queries, when set, lists every running query and lets it be cancelled.
+	Queries *QueryRegistry
 }
 
 // ErrMissingURI indicates the graph URI is not provided.
 var ErrMissingURI = errors.New("graph URI is required")
+
+// Session is a graph session kept open across many operations. A Session is not safe
+// for concurrent use; give each goroutine its own.
+type Session interface {
+	ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error)
+	ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error)
+	Close(ctx context.Context) error
+}
+
+// SessionOpener is implemented by clients that can hand out reusable sessions.
+type SessionOpener interface {
+	OpenSession(ctx context.Context) (Session, error)
+}
+
+// NewSessionClient returns a Client that runs every query on session. Connectivity checks
+// are delegated to parent and Close releases only the session.
+func NewSessionClient(parent Client, session Session) Client {
+	return sessionClient{Session: session, parent: parent}
+}
+
+type sessionClient struct {
+	Session
+	parent Client
+}
+
+func (c sessionClient) VerifyConnectivity(ctx context.Context) error {
+	return c.parent.VerifyConnectivity(ctx)
+}
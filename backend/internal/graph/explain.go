@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"strings"
+	"sync"
+)
+
+type queryRecorderKey struct{}
+
+// ExplainedQuery is a query captured by a QueryRecorder instead of being run.
+type ExplainedQuery struct {
+	Access string
+	Cypher string
+	Params map[string]any
+}
+
+// QueryRecorder captures the queries issued with a context instead of running them, so
+// callers can see the Cypher and parameters a request would generate. Recorded reads and
+// writes return an empty Result, and writes change nothing.
+type QueryRecorder struct {
+	mu      sync.Mutex
+	queries []ExplainedQuery
+}
+
+// WithQueryRecorder returns a context whose queries are recorded rather than run.
+func WithQueryRecorder(ctx context.Context) (context.Context, *QueryRecorder) {
+	recorder := &QueryRecorder{}
+	return context.WithValue(ctx, queryRecorderKey{}, recorder), recorder
+}
+
+// QueryRecorderFrom returns the recorder attached to ctx, or nil.
+func QueryRecorderFrom(ctx context.Context) *QueryRecorder {
+	recorder, _ := ctx.Value(queryRecorderKey{}).(*QueryRecorder)
+	return recorder
+}
+
+// Queries returns the recorded queries in issue order. With redact, parameter values
+// under PII keys are replaced as ParamLogRedact does for query logs.
+func (r *QueryRecorder) Queries(redact bool) []ExplainedQuery {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := make([]ExplainedQuery, 0, len(r.queries))
+	for _, q := range r.queries {
+		if redact {
+			q.Params = renderParams(q.Params, ParamLogRedact)
+		}
+		queries = append(queries, q)
+	}
+	return queries
+}
+
+func (r *QueryRecorder) record(access, cypher string, params map[string]any) (Result, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, ExplainedQuery{
+		Access: access,
+		Cypher: strings.TrimSpace(cypher),
+		Params: params,
+	})
+	return Result{}, nil
+}
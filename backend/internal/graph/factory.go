@@ -0,0 +1,63 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/vanshika/fintrace/backend/internal/config"
+)
+
+// ClientOption adjusts the Options NewClientFromConfig builds before connecting.
+type ClientOption func(*Options)
+
+// WithQueryRegistry lists every query in registry while it runs, so it can be cancelled.
+func WithQueryRegistry(registry *QueryRegistry) ClientOption {
+	return func(o *Options) {
+		o.Queries = registry
+	}
+}
+
+// NewClientFromConfig connects to the graph described by cfg, with the concurrency
+// limits, timeouts and query logging it configures, and verifies connectivity. Every
+// command builds its client here so they all honour the same GRAPH_* settings. The query
+// logging warning goes to logger.
+func NewClientFromConfig(ctx context.Context, logger *slog.Logger, cfg config.GraphConfig, opts ...ClientOption) (Client, error) {
+	if cfg.URI == "" {
+		return nil, ErrMissingURI
+	}
+
+	options := Options{
+		URI:            cfg.URI,
+		Database:       cfg.Database,
+		Username:       cfg.Username,
+		Password:       cfg.Password,
+		MaxConnections: cfg.MaxConnections,
+		Limits: OperationLimits{
+			MaxReads:       cfg.MaxConcurrentReads,
+			MaxWrites:      cfg.MaxConcurrentWrites,
+			AcquireTimeout: cfg.AcquireTimeout,
+		},
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+	}
+	queryLogMode, err := ParseParamLogMode(cfg.QueryLog)
+	if err != nil {
+		return nil, fmt.Errorf("invalid GRAPH_QUERY_LOG: %w", err)
+	}
+	if queryLogMode != ParamLogOff {
+		logger.Warn("graph query logging enabled; queries are logged at debug level", "mode", string(queryLogMode))
+		options.QueryLogging = QueryLogging{Mode: queryLogMode, Logger: logger.With("component", "graph-queries")}
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	// NewNeo4jClient verifies connectivity itself.
+	client, err := NewNeo4jClient(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info("connected to graph", "uri", cfg.URI, "database", cfg.Database)
+	return client, nil
+}
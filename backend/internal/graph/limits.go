@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrOperationLimit is returned when a read or write could not obtain a concurrency slot
+// before the acquire timeout or the context expired.
+var ErrOperationLimit = errors.New("graph operation limit reached")
+
+// OperationLimits bounds how many reads and writes may run at once, so a flood of one
+// class cannot take every pooled connection from the other.
+type OperationLimits struct {
+	// MaxReads and MaxWrites cap concurrent operations per class; zero is unlimited.
+	MaxReads  int
+	MaxWrites int
+	// AcquireTimeout bounds how long a caller waits for a slot; zero waits until the
+	// context is done.
+	AcquireTimeout time.Duration
+}
+
+func (l OperationLimits) enabled() bool {
+	return l.MaxReads > 0 || l.MaxWrites > 0
+}
+
+// NewLimitedClient wraps client so reads and writes each respect their concurrency limit.
+// Sessions opened through the wrapper are limited the same way.
+func NewLimitedClient(client Client, limits OperationLimits) Client {
+	limited := &limitedClient{
+		Client: client,
+		gate: operationGate{
+			reads:   newSemaphore(limits.MaxReads),
+			writes:  newSemaphore(limits.MaxWrites),
+			timeout: limits.AcquireTimeout,
+		},
+	}
+	if opener, ok := client.(SessionOpener); ok {
+		return &limitedSessionClient{limitedClient: limited, opener: opener}
+	}
+	return limited
+}
+
+// semaphore is a counting semaphore; a nil semaphore never blocks.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+type operationGate struct {
+	reads   semaphore
+	writes  semaphore
+	timeout time.Duration
+}
+
+func (g operationGate) run(ctx context.Context, sem semaphore, class string, fn func() (Result, error)) (Result, error) {
+	if sem == nil {
+		return fn()
+	}
+	waitCtx := ctx
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+	select {
+	case sem <- struct{}{}:
+	case <-waitCtx.Done():
+		return Result{}, fmt.Errorf("%w: %d concurrent %s in progress", ErrOperationLimit, cap(sem), class)
+	}
+	defer func() { <-sem }()
+	return fn()
+}
+
+type limitedClient struct {
+	Client
+	gate operationGate
+}
+
+func (c *limitedClient) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.gate.run(ctx, c.gate.writes, "writes", func() (Result, error) {
+		return c.Client.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (c *limitedClient) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.gate.run(ctx, c.gate.reads, "reads", func() (Result, error) {
+		return c.Client.ExecuteRead(ctx, cypher, params)
+	})
+}
+
+type limitedSessionClient struct {
+	*limitedClient
+	opener SessionOpener
+}
+
+func (c *limitedSessionClient) OpenSession(ctx context.Context) (Session, error) {
+	session, err := c.opener.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &limitedSession{Session: session, gate: c.gate}, nil
+}
+
+type limitedSession struct {
+	Session
+	gate operationGate
+}
+
+func (s *limitedSession) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.gate.run(ctx, s.gate.writes, "writes", func() (Result, error) {
+		return s.Session.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (s *limitedSession) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.gate.run(ctx, s.gate.reads, "reads", func() (Result, error) {
+		return s.Session.ExecuteRead(ctx, cypher, params)
+	})
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLimitedClientKeepsReadsFromStarvingWrites(t *testing.T) {
+	const maxReads = 2
+	release := make(chan struct{})
+	var running, peak atomic.Int32
+	started := make(chan struct{}, maxReads)
+	stub := &stubClient{
+		read: func(context.Context) (Result, error) {
+			n := running.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			started <- struct{}{}
+			<-release
+			running.Add(-1)
+			return Result{}, nil
+		},
+	}
+	client := NewLimitedClient(stub, OperationLimits{MaxReads: maxReads, MaxWrites: 1, AcquireTimeout: 50 * time.Millisecond})
+
+	// Saturate the read class with queries that block until released.
+	var wg sync.WaitGroup
+	for i := 0; i < maxReads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.ExecuteRead(context.Background(), "MATCH (n) RETURN n", nil); err != nil {
+				t.Errorf("blocking read error = %v", err)
+			}
+		}()
+	}
+	for i := 0; i < maxReads; i++ {
+		<-started
+	}
+
+	// A write has its own slots and still completes.
+	done := make(chan error, 1)
+	go func() {
+		_, err := client.ExecuteWrite(context.Background(), "CREATE (n)", nil)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("write while reads are saturated error = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("write did not complete while reads were saturated")
+	}
+
+	// One more read waits out the acquire timeout.
+	if _, err := client.ExecuteRead(context.Background(), "MATCH (n) RETURN n", nil); !errors.Is(err, ErrOperationLimit) {
+		t.Errorf("extra read error = %v, want ErrOperationLimit", err)
+	}
+
+	close(release)
+	wg.Wait()
+	if got := peak.Load(); got > maxReads {
+		t.Errorf("peak concurrent reads = %d, want at most %d", got, maxReads)
+	}
+
+	// Freed slots are reusable.
+	stub.read = nil
+	if _, err := client.ExecuteRead(context.Background(), "MATCH (n) RETURN n", nil); err != nil {
+		t.Errorf("read after release error = %v", err)
+	}
+}
+
+func TestLimitedClientUnlimitedClass(t *testing.T) {
+	client := NewLimitedClient(&stubClient{}, OperationLimits{MaxReads: 1})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	// Writes have no limit, so they never wait on a slot, even with a done context.
+	if _, err := client.ExecuteWrite(ctx, "CREATE (n)", nil); err != nil {
+		t.Errorf("unlimited write error = %v", err)
+	}
+}
@@ -34,10 +34,25 @@ func NewNeo4jClient(ctx context.Context, opts Options) (Client, error) {
 		return nil, fmt.Errorf("verify graph connectivity: %w", err)
 	}
 
-	return &neo4jClient{
+	client := &neo4jClient{
 		driver:   driver,
 		database: opts.Database,
-	}, nil
+	}
+	// Timeouts wrap the driver directly so that waiting for a concurrency slot does not
+	// eat into a query's deadline.
+	wrapped := NewTimeoutClient(client, opts.ReadTimeout, opts.WriteTimeout)
+	// Queries are registered only once they hold a slot, so the registry lists what
+	// is running on the database rather than what is queued.
+	if opts.Queries != nil {
+		wrapped = NewQueryRegistryClient(wrapped, opts.Queries)
+	}
+	if opts.QueryLogging.enabled() {
+		wrapped = NewQueryLoggingClient(wrapped, opts.QueryLogging)
+	}
+	if opts.Limits.enabled() {
+		wrapped = NewLimitedClient(wrapped, opts.Limits)
+	}
+	return wrapped, nil
 }
 
 type neo4jClient struct {
@@ -46,9 +61,14 @@ type neo4jClient struct {
 }
 
 func (c *neo4jClient) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	if recorder := QueryRecorderFrom(ctx); recorder != nil {
+		return recorder.record("write", cypher, params)
+	}
+	tracker := BookmarkTrackerFrom(ctx)
 	session := c.driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: c.database,
 		AccessMode:   neo4j.AccessModeWrite,
+		Bookmarks:    tracker.Bookmarks(),
 	})
 	defer session.Close(ctx)
 
@@ -57,13 +77,22 @@ func (c *neo4jClient) ExecuteWrite(ctx context.Context, cypher string, params ma
 		return Result{}, err
 	}
 
-	return consumeResult(ctx, res)
+	result, err := consumeResult(ctx, res)
+	if err != nil {
+		return Result{}, err
+	}
+	tracker.Set(session.LastBookmarks())
+	return result, nil
 }
 
 func (c *neo4jClient) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	if recorder := QueryRecorderFrom(ctx); recorder != nil {
+		return recorder.record("read", cypher, params)
+	}
 	session := c.driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: c.database,
 		AccessMode:   neo4j.AccessModeRead,
+		Bookmarks:    BookmarkTrackerFrom(ctx).Bookmarks(),
 	})
 	defer session.Close(ctx)
 
@@ -83,6 +112,62 @@ func (c *neo4jClient) Close(ctx context.Context) error {
 	return c.driver.Close(ctx)
 }
 
+// OpenSession opens a write session whose queries run as managed transactions, so the
+// driver retries transient failures and the session is reused across calls.
+func (c *neo4jClient) OpenSession(ctx context.Context) (Session, error) {
+	return &neo4jSession{
+		session: c.driver.NewSession(ctx, neo4j.SessionConfig{
+			DatabaseName: c.database,
+			AccessMode:   neo4j.AccessModeWrite,
+			Bookmarks:    BookmarkTrackerFrom(ctx).Bookmarks(),
+		}),
+	}, nil
+}
+
+type neo4jSession struct {
+	session neo4j.SessionWithContext
+}
+
+func (s *neo4jSession) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	if recorder := QueryRecorderFrom(ctx); recorder != nil {
+		return recorder.record("write", cypher, params)
+	}
+	out, err := s.session.ExecuteWrite(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return runInTransaction(ctx, tx, cypher, params)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	BookmarkTrackerFrom(ctx).Set(s.session.LastBookmarks())
+	return out.(Result), nil
+}
+
+func (s *neo4jSession) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	if recorder := QueryRecorderFrom(ctx); recorder != nil {
+		return recorder.record("read", cypher, params)
+	}
+	out, err := s.session.ExecuteRead(ctx, func(tx neo4j.ManagedTransaction) (any, error) {
+		return runInTransaction(ctx, tx, cypher, params)
+	})
+	if err != nil {
+		return Result{}, err
+	}
+	return out.(Result), nil
+}
+
+func (s *neo4jSession) Close(ctx context.Context) error {
+	return s.session.Close(ctx)
+}
+
+func runInTransaction(ctx context.Context, tx neo4j.ManagedTransaction, cypher string, params map[string]any) (Result, error) {
+	res, err := tx.Run(ctx, cypher, params)
+	if err != nil {
+		return Result{}, err
+	}
+	return consumeResult(ctx, res)
+}
+
+// consumeResult drains the result into records, one entry per key the query returned.
 func consumeResult(ctx context.Context, res neo4j.ResultWithContext) (Result, error) {
 	var records []Record
 	for res.Next(ctx) {
@@ -0,0 +1,166 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrQueryNotFound is returned by QueryRegistry.Cancel for an ID that is not running.
+var ErrQueryNotFound = errors.New("query not running")
+
+// ErrQueryCancelled is the cause of the context of a query cancelled through
+// QueryRegistry.Cancel, so callers can tell an operator's kill apart from a client that
+// went away.
+var ErrQueryCancelled = errors.New("query cancelled by operator")
+
+// RunningQuery describes a query that is in flight. Parameters are left out, as they
+// may carry personal data.
+type RunningQuery struct {
+	ID        string
+	Access    string
+	Cypher    string
+	StartedAt time.Time
+}
+
+// QueryRegistry tracks the queries running through clients wrapped by
+// NewQueryRegistryClient so operators can list them and cancel pathological ones. It is
+// safe for concurrent use.
+type QueryRegistry struct {
+	mu      sync.Mutex
+	next    uint64
+	running map[string]*registeredQuery
+	nowFn   func() time.Time
+}
+
+type registeredQuery struct {
+	info   RunningQuery
+	cancel context.CancelCauseFunc
+}
+
+// NewQueryRegistry returns an empty registry.
+func NewQueryRegistry() *QueryRegistry {
+	return &QueryRegistry{running: make(map[string]*registeredQuery), nowFn: time.Now}
+}
+
+// List returns the running queries, oldest first.
+func (r *QueryRegistry) List() []RunningQuery {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := make([]RunningQuery, 0, len(r.running))
+	for _, q := range r.running {
+		queries = append(queries, q.info)
+	}
+	sort.Slice(queries, func(i, j int) bool {
+		return queries[i].StartedAt.Before(queries[j].StartedAt)
+	})
+	return queries
+}
+
+// Cancel cancels the context of the running query id. The driver then abandons the
+// query and the database rolls its transaction back. It returns ErrQueryNotFound when
+// no such query is running, including when it has just finished.
+func (r *QueryRegistry) Cancel(id string) error {
+	r.mu.Lock()
+	q, ok := r.running[id]
+	r.mu.Unlock()
+	if !ok {
+		return ErrQueryNotFound
+	}
+	q.cancel(ErrQueryCancelled)
+	return nil
+}
+
+// run registers the query for the duration of fn, which must run it with the context it
+// is given.
+func (r *QueryRegistry) run(ctx context.Context, access, cypher string, fn func(context.Context) (Result, error)) (Result, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+
+	r.mu.Lock()
+	r.next++
+	id := strconv.FormatUint(r.next, 10)
+	r.running[id] = &registeredQuery{
+		info: RunningQuery{
+			ID:        id,
+			Access:    access,
+			Cypher:    strings.Join(strings.Fields(cypher), " "),
+			StartedAt: r.nowFn().UTC(),
+		},
+		cancel: cancel,
+	}
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		delete(r.running, id)
+		r.mu.Unlock()
+	}()
+
+	res, err := fn(ctx)
+	if err != nil && errors.Is(context.Cause(ctx), ErrQueryCancelled) {
+		return res, errors.Join(ErrQueryCancelled, err)
+	}
+	return res, err
+}
+
+// NewQueryRegistryClient wraps client so every read and write is listed in registry
+// while it runs and can be cancelled through it. Sessions opened through the wrapper are
+// registered the same way.
+func NewQueryRegistryClient(client Client, registry *QueryRegistry) Client {
+	registered := &registryClient{Client: client, registry: registry}
+	if opener, ok := client.(SessionOpener); ok {
+		return &registrySessionClient{registryClient: registered, opener: opener}
+	}
+	return registered
+}
+
+type registryClient struct {
+	Client
+	registry *QueryRegistry
+}
+
+func (c *registryClient) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.registry.run(ctx, "write", cypher, func(ctx context.Context) (Result, error) {
+		return c.Client.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (c *registryClient) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.registry.run(ctx, "read", cypher, func(ctx context.Context) (Result, error) {
+		return c.Client.ExecuteRead(ctx, cypher, params)
+	})
+}
+
+type registrySessionClient struct {
+	*registryClient
+	opener SessionOpener
+}
+
+func (c *registrySessionClient) OpenSession(ctx context.Context) (Session, error) {
+	session, err := c.opener.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &registrySession{Session: session, registry: c.registry}, nil
+}
+
+type registrySession struct {
+	Session
+	registry *QueryRegistry
+}
+
+func (s *registrySession) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.registry.run(ctx, "write", cypher, func(ctx context.Context) (Result, error) {
+		return s.Session.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (s *registrySession) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.registry.run(ctx, "read", cypher, func(ctx context.Context) (Result, error) {
+		return s.Session.ExecuteRead(ctx, cypher, params)
+	})
+}
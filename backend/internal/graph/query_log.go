@@ -0,0 +1,192 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// ParamLogMode controls whether and how query parameters are logged.
+type ParamLogMode string
+
+const (
+	// ParamLogOff disables query logging entirely. It is the default.
+	ParamLogOff ParamLogMode = "off"
+	// ParamLogOmit logs the query text and timing without any parameters.
+	ParamLogOmit ParamLogMode = "omit"
+	// ParamLogRedact logs parameters with values under PII keys replaced by redactedValue.
+	ParamLogRedact ParamLogMode = "redact"
+	// ParamLogHash logs parameters with PII values replaced by a short SHA-256 prefix,
+	// so equal values can still be correlated across queries.
+	ParamLogHash ParamLogMode = "hash"
+)
+
+// ParseParamLogMode validates a mode name; empty means ParamLogOff.
+func ParseParamLogMode(name string) (ParamLogMode, error) {
+	switch mode := ParamLogMode(strings.ToLower(strings.TrimSpace(name))); mode {
+	case "", ParamLogOff:
+		return ParamLogOff, nil
+	case ParamLogOmit, ParamLogRedact, ParamLogHash:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown query log mode %q: expected off, omit, redact or hash", name)
+	}
+}
+
+// QueryLogging configures debug logging of every query run through the client.
+type QueryLogging struct {
+	Mode   ParamLogMode
+	Logger *slog.Logger
+}
+
+func (q QueryLogging) enabled() bool {
+	return q.Logger != nil && q.Mode != "" && q.Mode != ParamLogOff
+}
+
+const redactedValue = "[REDACTED]"
+
+// piiParamKeys are parameter and property names whose values identify a person. Matching
+// is case-insensitive and applies at any depth, including inside props maps and lists.
+var piiParamKeys = map[string]struct{}{
+	"fullname":          {},
+	"email":             {},
+	"phone":             {},
+	"dateofbirth":       {},
+	"addressline1":      {},
+	"addressline2":      {},
+	"addresscity":       {},
+	"addresspostalcode": {},
+	"ipaddress":         {},
+	"deviceid":          {},
+	"fingerprint":       {},
+	"masked":            {},
+	"rawvalue":          {},
+	"value":             {},
+	"search":            {},
+}
+
+// NewQueryLoggingClient wraps client so every read and write is logged at debug level
+// with its duration and the parameters rendered according to logging.Mode. Sessions
+// opened through the wrapper are logged the same way.
+func NewQueryLoggingClient(client Client, logging QueryLogging) Client {
+	logged := &queryLoggingClient{Client: client, logging: logging}
+	if opener, ok := client.(SessionOpener); ok {
+		return &queryLoggingSessionClient{queryLoggingClient: logged, opener: opener}
+	}
+	return logged
+}
+
+func (q QueryLogging) run(ctx context.Context, access, cypher string, params map[string]any, fn func() (Result, error)) (Result, error) {
+	start := time.Now()
+	res, err := fn()
+	attrs := []any{
+		"access", access,
+		"query", strings.Join(strings.Fields(cypher), " "),
+		"duration", time.Since(start).String(),
+		"records", len(res.Records),
+	}
+	if q.Mode != ParamLogOmit {
+		attrs = append(attrs, "params", renderParams(params, q.Mode))
+	}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	q.Logger.DebugContext(ctx, "graph query", attrs...)
+	return res, err
+}
+
+// renderParams returns a copy of params with PII values redacted or hashed per mode.
+func renderParams(params map[string]any, mode ParamLogMode) map[string]any {
+	rendered := make(map[string]any, len(params))
+	for key, value := range params {
+		if _, ok := piiParamKeys[strings.ToLower(key)]; ok {
+			rendered[key] = maskParam(value, mode)
+			continue
+		}
+		rendered[key] = renderValue(value, mode)
+	}
+	return rendered
+}
+
+func renderValue(value any, mode ParamLogMode) any {
+	switch v := value.(type) {
+	case map[string]any:
+		return renderParams(v, mode)
+	case []map[string]any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, renderParams(item, mode))
+		}
+		return items
+	case []any:
+		items := make([]any, 0, len(v))
+		for _, item := range v {
+			items = append(items, renderValue(item, mode))
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+func maskParam(value any, mode ParamLogMode) any {
+	if value == nil || value == "" {
+		return value
+	}
+	if mode != ParamLogHash {
+		return redactedValue
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprint(value)))
+	return "sha256:" + hex.EncodeToString(sum[:6])
+}
+
+type queryLoggingClient struct {
+	Client
+	logging QueryLogging
+}
+
+func (c *queryLoggingClient) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.logging.run(ctx, "write", cypher, params, func() (Result, error) {
+		return c.Client.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (c *queryLoggingClient) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return c.logging.run(ctx, "read", cypher, params, func() (Result, error) {
+		return c.Client.ExecuteRead(ctx, cypher, params)
+	})
+}
+
+type queryLoggingSessionClient struct {
+	*queryLoggingClient
+	opener SessionOpener
+}
+
+func (c *queryLoggingSessionClient) OpenSession(ctx context.Context) (Session, error) {
+	session, err := c.opener.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &queryLoggingSession{Session: session, logging: c.logging}, nil
+}
+
+type queryLoggingSession struct {
+	Session
+	logging QueryLogging
+}
+
+func (s *queryLoggingSession) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.logging.run(ctx, "write", cypher, params, func() (Result, error) {
+		return s.Session.ExecuteWrite(ctx, cypher, params)
+	})
+}
+
+func (s *queryLoggingSession) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	return s.logging.run(ctx, "read", cypher, params, func() (Result, error) {
+		return s.Session.ExecuteRead(ctx, cypher, params)
+	})
+}
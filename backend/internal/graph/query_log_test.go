@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRenderParamsRedactsPII(t *testing.T) {
+	params := map[string]any{
+		"email":  "ann@example.com",
+		"Phone":  "+15550100",
+		"value":  "h4sh",
+		"search": "ann",
+		"limit":  25,
+		"props":  map[string]any{"fullName": "Ann Lee", "kycStatus": "VERIFIED"},
+		"attributes": []map[string]any{
+			{"type": "EMAIL", "value": "abc123"},
+		},
+		"empty": "",
+	}
+	for _, mode := range []ParamLogMode{ParamLogRedact, ParamLogHash} {
+		t.Run(string(mode), func(t *testing.T) {
+			got := renderParams(params, mode)
+			for _, key := range []string{"email", "Phone", "value", "search"} {
+				masked, _ := got[key].(string)
+				if masked == params[key] {
+					t.Errorf("%s = %v, want it masked", key, got[key])
+				}
+				if mode == ParamLogRedact && masked != redactedValue {
+					t.Errorf("%s = %q, want %q", key, masked, redactedValue)
+				}
+				if mode == ParamLogHash && !strings.HasPrefix(masked, "sha256:") {
+					t.Errorf("%s = %q, want a sha256 prefix", key, masked)
+				}
+			}
+			if got["limit"] != 25 {
+				t.Errorf("limit = %v, want the non-PII value kept", got["limit"])
+			}
+			props := got["props"].(map[string]any)
+			if props["fullName"] == "Ann Lee" || props["kycStatus"] != "VERIFIED" {
+				t.Errorf("props = %v, want fullName masked and kycStatus kept", props)
+			}
+			attr := got["attributes"].([]any)[0].(map[string]any)
+			if attr["value"] == "abc123" || attr["type"] != "EMAIL" {
+				t.Errorf("attribute = %v, want value masked and type kept", attr)
+			}
+		})
+	}
+
+	// Equal values hash equally, so they can be correlated across queries.
+	a := renderParams(map[string]any{"email": "ann@example.com"}, ParamLogHash)
+	b := renderParams(map[string]any{"email": "ann@example.com"}, ParamLogHash)
+	if a["email"] != b["email"] {
+		t.Errorf("hashes differ: %v and %v", a["email"], b["email"])
+	}
+	// The input is not modified.
+	if params["email"] != "ann@example.com" {
+		t.Error("renderParams modified its input")
+	}
+}
+
+func TestQueryLoggingClientOutput(t *testing.T) {
+	tests := []struct {
+		mode       ParamLogMode
+		wantParams bool
+	}{
+		{mode: ParamLogRedact, wantParams: true},
+		{mode: ParamLogHash, wantParams: true},
+		{mode: ParamLogOmit},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			var logs bytes.Buffer
+			logger := slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+			client := NewQueryLoggingClient(&stubClient{}, QueryLogging{Mode: tt.mode, Logger: logger})
+
+			params := map[string]any{"email": "ann@example.com", "userId": "USR-1"}
+			if _, err := client.ExecuteRead(context.Background(), "MATCH (u:User {email: $email})\n RETURN u", params); err != nil {
+				t.Fatalf("ExecuteRead() error = %v", err)
+			}
+			out := logs.String()
+			if strings.Contains(out, "ann@example.com") {
+				t.Errorf("log contains the raw email: %s", out)
+			}
+			if !strings.Contains(out, "MATCH (u:User {email: $email}) RETURN u") {
+				t.Errorf("log lacks the query on one line: %s", out)
+			}
+			if got := strings.Contains(out, "USR-1"); got != tt.wantParams {
+				t.Errorf("log contains params = %t, want %t: %s", got, tt.wantParams, out)
+			}
+		})
+	}
+}
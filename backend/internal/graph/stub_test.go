@@ -0,0 +1,28 @@
+package graph
+
+import "context"
+
+// stubClient is a Client that runs read and write for each query, or returns an empty
+// result when they are nil.
+type stubClient struct {
+	read  func(ctx context.Context) (Result, error)
+	write func(ctx context.Context) (Result, error)
+}
+
+func (c *stubClient) ExecuteRead(ctx context.Context, _ string, _ map[string]any) (Result, error) {
+	if c.read == nil {
+		return Result{}, nil
+	}
+	return c.read(ctx)
+}
+
+func (c *stubClient) ExecuteWrite(ctx context.Context, _ string, _ map[string]any) (Result, error) {
+	if c.write == nil {
+		return Result{}, nil
+	}
+	return c.write(ctx)
+}
+
+func (c *stubClient) VerifyConnectivity(context.Context) error { return nil }
+
+func (c *stubClient) Close(context.Context) error { return nil }
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"context"
+	"time"
+)
+
+// operationTimeouts bounds how long a single read or write may run; zero is unbounded.
+type operationTimeouts struct {
+	read  time.Duration
+	write time.Duration
+}
+
+type timeoutOverrideKey struct{}
+
+// WithOperationTimeout overrides the client's configured read or write timeout for
+// operations run with the returned context. Zero or less removes the default deadline,
+// leaving only ctx's own. Use it for the odd analytics query that needs longer than
+// its class normally allows.
+func WithOperationTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, timeoutOverrideKey{}, timeout)
+}
+
+// NewTimeoutClient wraps client so every read and write runs under a context deadline:
+// readTimeout for ExecuteRead and writeTimeout for ExecuteWrite, unless overridden by
+// WithOperationTimeout. A zero timeout leaves that class unbounded. Sessions opened
+// through the wrapper get the same deadlines.
+func NewTimeoutClient(client Client, readTimeout, writeTimeout time.Duration) Client {
+	timed := &timeoutClient{Client: client, timeouts: operationTimeouts{read: readTimeout, write: writeTimeout}}
+	if opener, ok := client.(SessionOpener); ok {
+		return &timeoutSessionClient{timeoutClient: timed, opener: opener}
+	}
+	return timed
+}
+
+// withDeadline applies the override from ctx, or fallback, as a deadline on ctx. An
+// earlier deadline already on ctx still wins.
+func withDeadline(ctx context.Context, fallback time.Duration) (context.Context, context.CancelFunc) {
+	timeout := fallback
+	if override, ok := ctx.Value(timeoutOverrideKey{}).(time.Duration); ok {
+		timeout = override
+	}
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+type timeoutClient struct {
+	Client
+	timeouts operationTimeouts
+}
+
+func (c *timeoutClient) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	ctx, cancel := withDeadline(ctx, c.timeouts.write)
+	defer cancel()
+	return c.Client.ExecuteWrite(ctx, cypher, params)
+}
+
+func (c *timeoutClient) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	ctx, cancel := withDeadline(ctx, c.timeouts.read)
+	defer cancel()
+	return c.Client.ExecuteRead(ctx, cypher, params)
+}
+
+type timeoutSessionClient struct {
+	*timeoutClient
+	opener SessionOpener
+}
+
+func (c *timeoutSessionClient) OpenSession(ctx context.Context) (Session, error) {
+	session, err := c.opener.OpenSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &timeoutSession{Session: session, timeouts: c.timeouts}, nil
+}
+
+type timeoutSession struct {
+	Session
+	timeouts operationTimeouts
+}
+
+func (s *timeoutSession) ExecuteWrite(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	ctx, cancel := withDeadline(ctx, s.timeouts.write)
+	defer cancel()
+	return s.Session.ExecuteWrite(ctx, cypher, params)
+}
+
+func (s *timeoutSession) ExecuteRead(ctx context.Context, cypher string, params map[string]any) (Result, error) {
+	ctx, cancel := withDeadline(ctx, s.timeouts.read)
+	defer cancel()
+	return s.Session.ExecuteRead(ctx, cypher, params)
+}
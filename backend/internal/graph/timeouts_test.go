@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// deadlineRecorder is a stubClient that records the time left on each call's context.
+type deadlineRecorder struct {
+	stubClient
+	read, write       time.Duration
+	readSet, writeSet bool
+}
+
+func newDeadlineRecorder() *deadlineRecorder {
+	r := &deadlineRecorder{}
+	r.stubClient.read = func(ctx context.Context) (Result, error) {
+		r.read, r.readSet = timeLeft(ctx)
+		return Result{}, nil
+	}
+	r.stubClient.write = func(ctx context.Context) (Result, error) {
+		r.write, r.writeSet = timeLeft(ctx)
+		return Result{}, nil
+	}
+	return r
+}
+
+func timeLeft(ctx context.Context) (time.Duration, bool) {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	return time.Until(deadline), true
+}
+
+// near reports whether got is within a second below want, allowing for the time the
+// call took.
+func near(got, want time.Duration) bool {
+	return got <= want && got > want-time.Second
+}
+
+func TestTimeoutClientAppliesDeadlinePerOperation(t *testing.T) {
+	const readTimeout, writeTimeout = time.Minute, 5 * time.Second
+	tests := []struct {
+		name              string
+		ctx               func() (context.Context, context.CancelFunc)
+		read, write       time.Duration
+		readSet, writeSet bool
+	}{
+		{
+			name:     "class defaults",
+			ctx:      func() (context.Context, context.CancelFunc) { return context.Background(), func() {} },
+			read:     readTimeout,
+			readSet:  true,
+			write:    writeTimeout,
+			writeSet: true,
+		},
+		{
+			name: "per-call override",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return WithOperationTimeout(context.Background(), 10*time.Minute), func() {}
+			},
+			read:     10 * time.Minute,
+			readSet:  true,
+			write:    10 * time.Minute,
+			writeSet: true,
+		},
+		{
+			name: "override removes the default",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return WithOperationTimeout(context.Background(), 0), func() {}
+			},
+		},
+		{
+			name: "earlier caller deadline wins",
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 2*time.Second)
+			},
+			read:     2 * time.Second,
+			readSet:  true,
+			write:    2 * time.Second,
+			writeSet: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := newDeadlineRecorder()
+			client := NewTimeoutClient(recorder, readTimeout, writeTimeout)
+			ctx, cancel := tt.ctx()
+			defer cancel()
+
+			if _, err := client.ExecuteRead(ctx, "MATCH (n) RETURN n", nil); err != nil {
+				t.Fatalf("ExecuteRead() error = %v", err)
+			}
+			if _, err := client.ExecuteWrite(ctx, "CREATE (n)", nil); err != nil {
+				t.Fatalf("ExecuteWrite() error = %v", err)
+			}
+			if recorder.readSet != tt.readSet || (tt.readSet && !near(recorder.read, tt.read)) {
+				t.Errorf("read deadline = %v (set %t), want %v (set %t)", recorder.read, recorder.readSet, tt.read, tt.readSet)
+			}
+			if recorder.writeSet != tt.writeSet || (tt.writeSet && !near(recorder.write, tt.write)) {
+				t.Errorf("write deadline = %v (set %t), want %v (set %t)", recorder.write, recorder.writeSet, tt.write, tt.writeSet)
+			}
+		})
+	}
+}
+
+func TestTimeoutClientZeroTimeoutIsUnbounded(t *testing.T) {
+	recorder := newDeadlineRecorder()
+	client := NewTimeoutClient(recorder, 0, time.Second)
+	if _, err := client.ExecuteRead(context.Background(), "MATCH (n) RETURN n", nil); err != nil {
+		t.Fatalf("ExecuteRead() error = %v", err)
+	}
+	if recorder.readSet {
+		t.Errorf("read deadline set to %v, want none", recorder.read)
+	}
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// CompareUsers assembles shared attributes, common counterparties, direct transactions and
+// profile field matches for two users. It returns ErrNotFound when either user is missing.
+func (r *Repository) CompareUsers(ctx context.Context, userA, userB string) (domain.UserComparison, error) {
+	if userA == "" || userB == "" {
+		return domain.UserComparison{}, errors.New("both user ids are required")
+	}
+
+	params := map[string]any{
+		"userA": userA,
+		"userB": userB,
+	}
+
+	profiles, err := r.client.ExecuteRead(ctx, compareUserProfilesCypher, params)
+	if err != nil {
+		return domain.UserComparison{}, fmt.Errorf("compare users profiles: %w", err)
+	}
+	byID := make(map[string]map[string]any, len(profiles.Records))
+	for _, record := range profiles.Records {
+		byID[toString(record["userId"])] = record
+	}
+	profileA, okA := byID[userA]
+	profileB, okB := byID[userB]
+	if !okA || !okB {
+		return domain.UserComparison{}, fmt.Errorf("compare users %s and %s: %w", userA, userB, ErrNotFound)
+	}
+
+	comparison := domain.UserComparison{
+		UserA:        userA,
+		UserB:        userB,
+		FieldMatches: profileFieldMatches(profileA, profileB),
+	}
+
+	shared, err := r.client.ExecuteRead(ctx, compareSharedAttributesCypher, params)
+	if err != nil {
+		return domain.UserComparison{}, fmt.Errorf("compare users shared attributes: %w", err)
+	}
+	for _, record := range shared.Records {
+		comparison.SharedAttributes = append(comparison.SharedAttributes, domain.AttributeRef{
+			AttributeType: toString(record["attributeType"]),
+			AttributeHash: toString(record["attributeHash"]),
+		})
+	}
+
+	counterparties, err := r.client.ExecuteRead(ctx, compareCommonCounterpartiesCypher, params)
+	if err != nil {
+		return domain.UserComparison{}, fmt.Errorf("compare users counterparties: %w", err)
+	}
+	for _, record := range counterparties.Records {
+		if id := toString(record["userId"]); id != "" {
+			comparison.CommonCounterparties = append(comparison.CommonCounterparties, id)
+		}
+	}
+
+	direct, err := r.client.ExecuteRead(ctx, compareDirectTransactionsCypher, params)
+	if err != nil {
+		return domain.UserComparison{}, fmt.Errorf("compare users direct transactions: %w", err)
+	}
+	for _, record := range direct.Records {
+		comparison.DirectTransactions = append(comparison.DirectTransactions, domain.DirectUserLink{
+			UserID:        userB,
+			LinkType:      "SENT_TO",
+			Direction:     toString(record["direction"]),
+			TransactionID: toString(record["transactionId"]),
+			Amount:        toFloat64(record["amount"]),
+			Currency:      toString(record["currency"]),
+			Timestamp:     toTimePtr(record["timestamp"]),
+		})
+	}
+
+	return comparison, nil
+}
+
+// profileFieldMatches returns the profile fields whose non-empty values are identical.
+func profileFieldMatches(a, b map[string]any) []string {
+	var matches []string
+	for _, field := range []string{"email", "phone", "dateOfBirth"} {
+		if va := toString(a[field]); va != "" && va == toString(b[field]) {
+			matches = append(matches, field)
+		}
+	}
+	if na := strings.ToLower(strings.TrimSpace(toString(a["fullName"]))); na != "" &&
+		na == strings.ToLower(strings.TrimSpace(toString(b["fullName"]))) {
+		matches = append(matches, "fullName")
+	}
+
+	addressFields := []string{"addressLine1", "addressLine2", "addressCity", "addressState", "addressPostalCode", "addressCountry"}
+	sameAddress, anyAddress := true, false
+	for _, field := range addressFields {
+		va := strings.ToLower(strings.TrimSpace(toString(a[field])))
+		vb := strings.ToLower(strings.TrimSpace(toString(b[field])))
+		if va != "" {
+			anyAddress = true
+		}
+		if va != vb {
+			sameAddress = false
+			break
+		}
+	}
+	if anyAddress && sameAddress {
+		matches = append(matches, "address")
+	}
+	return matches
+}
+
+const compareUserProfilesCypher = `
+MATCH (u:User)
+WHERE u.userId IN [$userA, $userB]
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.dateOfBirth AS dateOfBirth,
+       u.addressLine1 AS addressLine1,
+       u.addressLine2 AS addressLine2,
+       u.addressCity AS addressCity,
+       u.addressState AS addressState,
+       u.addressPostalCode AS addressPostalCode,
+       u.addressCountry AS addressCountry
+`
+
+const compareSharedAttributesCypher = `
+MATCH (:User {userId: $userA})-[:HAS_ATTRIBUTE]->(a:Attribute)<-[:HAS_ATTRIBUTE]-(:User {userId: $userB})
+RETURN DISTINCT a.attributeType AS attributeType,
+       a.value AS attributeHash
+ORDER BY attributeType
+`
+
+const compareCommonCounterpartiesCypher = `
+MATCH (:User {userId: $userA})-[:SENT_TO]-(peer:User)-[:SENT_TO]-(:User {userId: $userB})
+WHERE NOT peer.userId IN [$userA, $userB]
+RETURN DISTINCT peer.userId AS userId
+ORDER BY userId
+`
+
+const compareDirectTransactionsCypher = `
+MATCH (a:User {userId: $userA})-[r:SENT_TO]-(:User {userId: $userB})
+RETURN r.transactionId AS transactionId,
+       CASE WHEN startNode(r) = a THEN "OUTBOUND" ELSE "INBOUND" END AS direction,
+       r.amount AS amount,
+       r.currency AS currency,
+       r.timestamp AS timestamp
+ORDER BY timestamp DESC
+`
@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// Orderings accepted by AttributeClusters.
+const (
+	// AttributeClusterOrderSize lists the most shared attributes first, whatever their type.
+	AttributeClusterOrderSize = "size"
+	// AttributeClusterOrderType groups attributes by type, most shared first within a type.
+	AttributeClusterOrderType = "type"
+)
+
+// AttributeClusterOrders lists the supported attribute cluster orderings.
+var AttributeClusterOrders = []string{AttributeClusterOrderSize, AttributeClusterOrderType}
+
+const (
+	// DefaultAttributeClusterMinUsers is the fewest users an attribute needs to be listed
+	// by AttributeClusters when the caller does not choose.
+	DefaultAttributeClusterMinUsers = 2
+	// DefaultAttributeClusterMaxUsers is how many user IDs AttributeClusters lists per
+	// cluster when the caller does not choose.
+	DefaultAttributeClusterMaxUsers = 100
+	// MaxAttributeClusterUsers caps the user IDs listed per cluster.
+	MaxAttributeClusterUsers = 1000
+	// DefaultAttributeClusterLimit is how many clusters AttributeClusters returns when the
+	// caller does not choose.
+	DefaultAttributeClusterLimit = 100
+	// MaxAttributeClusterLimit caps the clusters one AttributeClusters call returns.
+	MaxAttributeClusterLimit = 500
+)
+
+// AttributeClusterOptions filters, orders and bounds AttributeClusters.
+type AttributeClusterOptions struct {
+	// Type restricts clusters to one attribute type; empty lists every type.
+	Type string
+	// MinUsers is the fewest users an attribute needs; zero or less means
+	// DefaultAttributeClusterMinUsers.
+	MinUsers int
+	// MaxUsers caps the user IDs listed per cluster, clamped to MaxAttributeClusterUsers;
+	// zero or less means DefaultAttributeClusterMaxUsers.
+	MaxUsers int
+	// Limit caps the clusters returned, clamped to MaxAttributeClusterLimit; zero or less
+	// means DefaultAttributeClusterLimit.
+	Limit int
+	// Order is AttributeClusterOrderSize or AttributeClusterOrderType; empty means size.
+	Order string
+}
+
+// AttributeClusters returns the Attribute nodes linked through HAS_ATTRIBUTE to at least
+// MinUsers users across the whole graph, each with its users. Ordered by size, the most
+// shared come first; ordered by type, they are grouped by attribute type and most shared
+// first within a type. Ties break on the attribute hash. The ordering and limit run in
+// the query, and Truncated is set when more clusters matched than Limit. At most
+// MaxUsers user IDs are listed per cluster, in order, while UserCount counts every user.
+func (r *Repository) AttributeClusters(ctx context.Context, opts AttributeClusterOptions) (domain.AttributeClusters, error) {
+	attributeType := strings.ToUpper(strings.TrimSpace(opts.Type))
+	minUsers := opts.MinUsers
+	if minUsers <= 0 {
+		minUsers = DefaultAttributeClusterMinUsers
+	}
+	maxUsers := opts.MaxUsers
+	if maxUsers <= 0 {
+		maxUsers = DefaultAttributeClusterMaxUsers
+	}
+	maxUsers = min(maxUsers, MaxAttributeClusterUsers)
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultAttributeClusterLimit
+	}
+	limit = min(limit, MaxAttributeClusterLimit)
+
+	order := strings.ToLower(strings.TrimSpace(opts.Order))
+	if order == "" {
+		order = AttributeClusterOrderSize
+	}
+	orderBy, ok := attributeClusterOrderBy[order]
+	if !ok {
+		return domain.AttributeClusters{}, fmt.Errorf("unknown attribute cluster order %q, expected one of %s", opts.Order, strings.Join(AttributeClusterOrders, ", "))
+	}
+
+	res, err := r.client.ExecuteRead(ctx, attributeClustersCypher+orderBy+"LIMIT $limit\n", map[string]any{
+		"attributeType": attributeType,
+		"minUsers":      minUsers,
+		"maxUsers":      maxUsers,
+		"limit":         limit + 1,
+	})
+	if err != nil {
+		return domain.AttributeClusters{}, fmt.Errorf("attribute clusters query: %w", err)
+	}
+
+	result := domain.AttributeClusters{AttributeType: attributeType, MinUsers: minUsers, Order: order, Clusters: []domain.AttributeCluster{}}
+	records := res.Records
+	if len(records) > limit {
+		records = records[:limit]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		values, _ := record["userIds"].([]any)
+		userIDs := make([]string, 0, len(values))
+		for _, value := range values {
+			userIDs = append(userIDs, toString(value))
+		}
+		result.Clusters = append(result.Clusters, domain.AttributeCluster{
+			AttributeType: toString(record["attributeType"]),
+			AttributeHash: toString(record["attributeHash"]),
+			RawValue:      toString(record["rawValue"]),
+			UserCount:     int(toInt64(record["userCount"])),
+			UserIDs:       userIDs,
+		})
+	}
+	return result, nil
+}
+
+// attributeClusterOrderBy is the ORDER BY clause appended to attributeClustersCypher for
+// each ordering.
+var attributeClusterOrderBy = map[string]string{
+	AttributeClusterOrderSize: "ORDER BY userCount DESC, attributeType, attributeHash\n",
+	AttributeClusterOrderType: "ORDER BY attributeType, userCount DESC, attributeHash\n",
+}
+
+const attributeClustersCypher = `
+MATCH (a:Attribute)<-[:HAS_ATTRIBUTE]-(u:User)
+WHERE $attributeType = "" OR a.attributeType = $attributeType
+WITH a, u.userId AS userId
+ORDER BY userId
+WITH a, collect(DISTINCT userId) AS userIds
+WHERE size(userIds) >= $minUsers
+RETURN a.attributeType AS attributeType,
+       a.value AS attributeHash,
+       a.rawValue AS rawValue,
+       size(userIds) AS userCount,
+       userIds[..$maxUsers] AS userIds
+`
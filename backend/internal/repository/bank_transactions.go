@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// maxBankTransactions caps the transactions returned for one bank. Large banks route far
+// more than a reviewer can read, so the newest ones are kept.
+const maxBankTransactions = 500
+
+// TransactionsThroughBank returns the transactions whose originating or beneficiary bank
+// matches bic, newest first. An 8 character bic matches every branch of the bank, an 11
+// character one only that branch. The BIC properties are not indexed, so this scans the
+// transactions. An unknown bank yields no transactions rather than ErrNotFound.
+func (r *Repository) TransactionsThroughBank(ctx context.Context, bic string) (domain.BankTransactions, error) {
+	if bic == "" {
+		return domain.BankTransactions{}, errors.New("bic is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, transactionsThroughBankCypher, map[string]any{
+		"bic":   bic,
+		"limit": maxBankTransactions + 1,
+	})
+	if err != nil {
+		return domain.BankTransactions{}, fmt.Errorf("transactions through bank: %w", err)
+	}
+
+	result := domain.BankTransactions{BIC: bic}
+	records := res.Records
+	if len(records) > maxBankTransactions {
+		records = records[:maxBankTransactions]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		result.Transactions = append(result.Transactions, domain.BankTransaction{
+			Transaction:    decodeTransactionSummary(record),
+			OriginatorBIC:  toString(record["originatorBic"]),
+			BeneficiaryBIC: toString(record["beneficiaryBic"]),
+		})
+	}
+	return result, nil
+}
+
+const transactionsThroughBankCypher = `
+MATCH (t:Transaction)
+WHERE t.originatorBic STARTS WITH $bic OR t.beneficiaryBic STARTS WITH $bic
+RETURN t.transactionId AS transactionId,
+       t.amount AS amount,
+       t.currency AS currency,
+       t.type AS type,
+       t.status AS status,
+       t.channel AS channel,
+       t.timestamp AS timestamp,
+       t.createdAt AS createdAt,
+       t.updatedAt AS updatedAt,
+       t.originatorBic AS originatorBic,
+       t.beneficiaryBic AS beneficiaryBic,
+       head([(sender:User)-[:PARTICIPATED_IN {role: "SENDER"}]->(t) | sender.userId]) AS senderId,
+       head([(receiver:User)-[:PARTICIPATED_IN {role: "RECEIVER"}]->(t) | receiver.userId]) AS receiverId
+ORDER BY t.timestamp DESC, t.transactionId
+LIMIT $limit
+`
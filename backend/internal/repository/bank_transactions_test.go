@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestTransactionsThroughBankDecodesBICs(t *testing.T) {
+	client := &fakeClient{read: records(
+		graph.Record{"transactionId": "TX-2", "amount": 900.0, "currency": "EUR", "originatorBic": "DEUTDEFF500", "beneficiaryBic": "NWBKGB2L", "senderId": "USR-1", "receiverId": "USR-2"},
+		graph.Record{"transactionId": "TX-1", "amount": 15.0, "currency": "EUR", "originatorBic": "COBADEFF", "beneficiaryBic": "DEUTDEFFXXX", "senderId": "USR-3", "receiverId": "USR-1"},
+	)}
+	result, err := New(client).TransactionsThroughBank(context.Background(), "DEUTDEFF")
+	if err != nil {
+		t.Fatalf("TransactionsThroughBank() error = %v", err)
+	}
+	if result.BIC != "DEUTDEFF" || result.Truncated {
+		t.Errorf("result = %+v, want BIC DEUTDEFF untruncated", result)
+	}
+	if len(result.Transactions) != 2 {
+		t.Fatalf("got %d transactions, want 2", len(result.Transactions))
+	}
+	first := result.Transactions[0]
+	if first.Transaction.ID != "TX-2" || first.OriginatorBIC != "DEUTDEFF500" || first.BeneficiaryBIC != "NWBKGB2L" {
+		t.Errorf("first transaction = %+v", first)
+	}
+	if second := result.Transactions[1]; second.BeneficiaryBIC != "DEUTDEFFXXX" {
+		t.Errorf("second beneficiary BIC = %q, want DEUTDEFFXXX", second.BeneficiaryBIC)
+	}
+
+	call := client.reads[0]
+	if call.params["bic"] != "DEUTDEFF" {
+		t.Errorf("queried bic %v, want DEUTDEFF", call.params["bic"])
+	}
+	// Matching on the prefix lets an 8 character BIC cover every branch of the bank.
+	if !strings.Contains(call.cypher, "t.originatorBic STARTS WITH $bic OR t.beneficiaryBic STARTS WITH $bic") {
+		t.Errorf("query does not match either side by prefix:\n%s", call.cypher)
+	}
+}
+
+func TestTransactionsThroughBankTruncates(t *testing.T) {
+	recs := make([]graph.Record, maxBankTransactions+1)
+	for i := range recs {
+		recs[i] = graph.Record{"transactionId": fmt.Sprintf("TX-%d", i), "originatorBic": "DEUTDEFF"}
+	}
+	client := &fakeClient{read: records(recs...)}
+	result, err := New(client).TransactionsThroughBank(context.Background(), "DEUTDEFF")
+	if err != nil {
+		t.Fatalf("TransactionsThroughBank() error = %v", err)
+	}
+	if len(result.Transactions) != maxBankTransactions || !result.Truncated {
+		t.Errorf("got %d transactions, truncated %v, want %d truncated", len(result.Transactions), result.Truncated, maxBankTransactions)
+	}
+	if got := client.reads[0].params["limit"]; got != maxBankTransactions+1 {
+		t.Errorf("queried limit %v, want %d to detect truncation", got, maxBankTransactions+1)
+	}
+}
+
+func TestTransactionsThroughUnknownBankIsEmpty(t *testing.T) {
+	result, err := New(&fakeClient{}).TransactionsThroughBank(context.Background(), "NWBKGB2L")
+	if err != nil {
+		t.Fatalf("TransactionsThroughBank() error = %v, want an empty result", err)
+	}
+	if len(result.Transactions) != 0 || result.Truncated {
+		t.Errorf("result = %+v, want no transactions", result)
+	}
+}
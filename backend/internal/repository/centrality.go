@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// Centrality metrics accepted by UserCentrality.
+const (
+	CentralityPageRank = "pagerank"
+	CentralityDegree   = "degree"
+)
+
+// CentralityMetrics lists the supported centrality metrics.
+var CentralityMetrics = []string{CentralityPageRank, CentralityDegree}
+
+const (
+	// DefaultCentralityLimit is how many users UserCentrality ranks when the caller does
+	// not choose.
+	DefaultCentralityLimit = 50
+	// MaxCentralityLimit caps the users one UserCentrality call returns.
+	MaxCentralityLimit = 500
+)
+
+// CentralityOptions selects the metric and how many of the top users to return.
+type CentralityOptions struct {
+	// Metric is CentralityPageRank or CentralityDegree; empty means PageRank.
+	Metric string
+	// Limit is clamped to [1, MaxCentralityLimit]; zero or less means
+	// DefaultCentralityLimit.
+	Limit int
+}
+
+// UserCentrality ranks users by how central they are in the money-flow graph, highest
+// score first with the user ID breaking ties.
+//
+// PageRank runs gds.pageRank.stream on a temporary projection of users and their
+// SENT_TO edges, together with RECEIVED_FROM edges when those are written, and returns
+// ErrGDSUnavailable without the Graph Data Science plugin. Degree counts the SENT_TO
+// edges of each user in either direction, that is its payments sent and received, and
+// needs no plugin. Users without any payment are left out of the degree ranking.
+func (r *Repository) UserCentrality(ctx context.Context, opts CentralityOptions) (domain.UserCentrality, error) {
+	metric := strings.ToLower(strings.TrimSpace(opts.Metric))
+	if metric == "" {
+		metric = CentralityPageRank
+	}
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = DefaultCentralityLimit
+	}
+	limit = min(limit, MaxCentralityLimit)
+
+	var records []graph.Record
+	switch metric {
+	case CentralityPageRank:
+		available, err := r.gdsAvailable(ctx, "gds.pageRank.stream")
+		if err != nil {
+			return domain.UserCentrality{}, err
+		}
+		if !available {
+			return domain.UserCentrality{}, fmt.Errorf("pagerank: %w", ErrGDSUnavailable)
+		}
+		if records, err = r.gdsPageRank(ctx, limit); err != nil {
+			return domain.UserCentrality{}, err
+		}
+	case CentralityDegree:
+		res, err := r.client.ExecuteRead(ctx, degreeCentralityCypher, map[string]any{"limit": limit})
+		if err != nil {
+			return domain.UserCentrality{}, fmt.Errorf("degree centrality query: %w", err)
+		}
+		records = res.Records
+	default:
+		return domain.UserCentrality{}, fmt.Errorf("unknown centrality metric %q, expected one of %s", opts.Metric, strings.Join(CentralityMetrics, ", "))
+	}
+
+	result := domain.UserCentrality{Metric: metric, Users: make([]domain.CentralUser, 0, len(records))}
+	for _, record := range records {
+		result.Users = append(result.Users, domain.CentralUser{
+			User:  decodeUserSummary(record),
+			Score: toFloat64(record["score"]),
+		})
+	}
+	return result, nil
+}
+
+// gdsPageRank runs PageRank on a projection that is dropped afterwards and returns the
+// limit top-ranked users.
+func (r *Repository) gdsPageRank(ctx context.Context, limit int) ([]graph.Record, error) {
+	// RECEIVED_FROM mirrors SENT_TO, so it is reversed to point along the money flow. Every
+	// payment then counts twice, which leaves the ranking unchanged.
+	relationships := map[string]any{"SENT_TO": map[string]any{"orientation": "NATURAL"}}
+	if r.receivedFromEdges {
+		relationships["RECEIVED_FROM"] = map[string]any{"orientation": "REVERSE"}
+	}
+	return r.runGDSProjection(ctx, "centrality", gdsProjectCentralityCypher, gdsPageRankStreamCypher, map[string]any{
+		"relationships": relationships,
+		"limit":         limit,
+	})
+}
+
+const gdsProjectCentralityCypher = `
+CALL gds.graph.project($graphName, "User", $relationships)
+YIELD graphName
+RETURN graphName
+`
+
+const gdsPageRankStreamCypher = `
+CALL gds.pageRank.stream($graphName) YIELD nodeId, score
+WITH gds.util.asNode(nodeId) AS u, score
+ORDER BY score DESC, u.userId
+LIMIT $limit
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt,
+       score
+`
+
+const degreeCentralityCypher = `
+MATCH (u:User)
+WITH u, COUNT { (u)-[:SENT_TO]-(:User) } AS score
+WHERE score > 0
+ORDER BY score DESC, u.userId
+LIMIT $limit
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt,
+       score
+`
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// MaxClusterProfileUsers caps the user IDs one ClusterProfile call accepts. Every pair of
+// them may be compared, so the cost grows with the square of the cluster.
+const MaxClusterProfileUsers = 1000
+
+// ClusterProfile explains what binds the users in userIDs together. For every attribute
+// type it counts the shared attributes, the pairs of cluster members sharing at least one
+// of them and the members involved, following HAS_ATTRIBUTE between users of the cluster
+// only. Share is the type's part of all linked pairs, counting a pair once per type, so
+// the shares add up to 1 when anything is shared. Repeated and unknown IDs are ignored.
+func (r *Repository) ClusterProfile(ctx context.Context, userIDs []string) (domain.ClusterProfile, error) {
+	if len(userIDs) == 0 {
+		return domain.ClusterProfile{}, errors.New("at least one user id is required")
+	}
+	ids := uniqueIDs(userIDs)
+	if len(ids) > MaxClusterProfileUsers {
+		return domain.ClusterProfile{}, fmt.Errorf("cluster profile: %d users, the maximum is %d", len(ids), MaxClusterProfileUsers)
+	}
+
+	res, err := r.client.ExecuteRead(ctx, clusterProfileCypher, map[string]any{"ids": ids})
+	if err != nil {
+		return domain.ClusterProfile{}, fmt.Errorf("cluster profile query: %w", err)
+	}
+
+	profile := domain.ClusterProfile{Users: len(ids), Types: []domain.ClusterAttributeShare{}}
+	if len(res.Records) == 0 {
+		return profile, nil
+	}
+	record := res.Records[0]
+	profile.FoundUsers = int(toInt64(record["found"]))
+	entries, _ := record["types"].([]any)
+	var totalPairs int
+	for _, entry := range entries {
+		item, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		share := domain.ClusterAttributeShare{
+			AttributeType: toString(item["attributeType"]),
+			Attributes:    int(toInt64(item["attributes"])),
+			Pairs:         int(toInt64(item["pairs"])),
+			Users:         int(toInt64(item["users"])),
+		}
+		totalPairs += share.Pairs
+		profile.Types = append(profile.Types, share)
+	}
+	for i := range profile.Types {
+		if totalPairs > 0 {
+			profile.Types[i].Share = float64(profile.Types[i].Pairs) / float64(totalPairs)
+		}
+	}
+	return profile, nil
+}
+
+const clusterProfileCypher = `
+MATCH (member:User)
+WHERE member.userId IN $ids
+WITH count(member) AS found
+RETURN found,
+       COLLECT {
+         MATCH (u:User)-[:HAS_ATTRIBUTE]->(a:Attribute)<-[:HAS_ATTRIBUTE]-(other:User)
+         WHERE u.userId IN $ids AND other.userId IN $ids AND u.userId < other.userId
+         UNWIND [u.userId, other.userId] AS memberId
+         WITH a.attributeType AS attributeType,
+              count(DISTINCT a) AS attributes,
+              count(DISTINCT [u.userId, other.userId]) AS pairs,
+              count(DISTINCT memberId) AS users
+         ORDER BY pairs DESC, attributeType
+         RETURN {attributeType: attributeType, attributes: attributes, pairs: pairs, users: users}
+       } AS types
+`
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// ComponentEdgeTypes are the relationship types ComponentSize may traverse.
+var ComponentEdgeTypes = []string{
+	"SENT_TO",
+	"RECEIVED_FROM",
+	"HAS_ATTRIBUTE",
+	"PARTICIPATED_IN",
+	"USES_PAYMENT_METHOD",
+	"LINKED_TO",
+	"PAYMENT_METHOD_RELATES",
+	"REVERSES",
+}
+
+// DefaultComponentEdgeTypes connect users through money movement and shared attributes.
+// The traversal ignores direction, so SENT_TO already covers money received.
+var DefaultComponentEdgeTypes = []string{"SENT_TO", "HAS_ATTRIBUTE"}
+
+const (
+	defaultComponentMaxNodes = 1000
+	// MaxComponentNodes caps how many nodes a single ComponentSize call may visit.
+	MaxComponentNodes = 10000
+)
+
+// ComponentSize counts the nodes reachable from userID over edgeTypes, in either
+// direction, with a breadth-first traversal that stops after maxNodes nodes. The result
+// is marked truncated when unvisited nodes remained at that point, so a giant component
+// costs at most maxNodes lookups rather than a scan of the whole graph. Empty edgeTypes
+// means DefaultComponentEdgeTypes; maxNodes is clamped to MaxComponentNodes. It returns
+// ErrNotFound when the user does not exist.
+func (r *Repository) ComponentSize(ctx context.Context, userID string, edgeTypes []string, maxNodes int) (domain.ComponentSize, error) {
+	if userID == "" {
+		return domain.ComponentSize{}, errors.New("user id is required")
+	}
+	if len(edgeTypes) == 0 {
+		edgeTypes = DefaultComponentEdgeTypes
+	}
+	if maxNodes <= 0 {
+		maxNodes = defaultComponentMaxNodes
+	}
+	if maxNodes > MaxComponentNodes {
+		maxNodes = MaxComponentNodes
+	}
+
+	res, err := r.client.ExecuteRead(ctx, componentStartCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return domain.ComponentSize{}, fmt.Errorf("component start query: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.ComponentSize{}, fmt.Errorf("component size for %s: %w", userID, ErrNotFound)
+	}
+
+	start := toString(res.Records[0]["id"])
+	visited := []string{start}
+	seen := map[string]struct{}{start: {}}
+	frontier := []string{start}
+	truncated := false
+	for len(frontier) > 0 {
+		remaining := maxNodes - len(visited)
+		res, err := r.client.ExecuteRead(ctx, componentFrontierCypher, map[string]any{
+			"frontier":  frontier,
+			"visited":   visited,
+			"edgeTypes": edgeTypes,
+			"limit":     remaining + 1,
+		})
+		if err != nil {
+			return domain.ComponentSize{}, fmt.Errorf("component traversal query: %w", err)
+		}
+
+		frontier = frontier[:0]
+		for _, record := range res.Records {
+			id := toString(record["id"])
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			if len(visited) == maxNodes {
+				truncated = true
+				break
+			}
+			seen[id] = struct{}{}
+			visited = append(visited, id)
+			frontier = append(frontier, id)
+		}
+		if truncated {
+			break
+		}
+	}
+
+	return domain.ComponentSize{
+		UserID:    userID,
+		EdgeTypes: edgeTypes,
+		Size:      int64(len(visited)),
+		Truncated: truncated,
+	}, nil
+}
+
+const componentStartCypher = `
+MATCH (u:User {userId: $userId})
+RETURN elementId(u) AS id
+`
+
+const componentFrontierCypher = `
+UNWIND $frontier AS id
+MATCH (n)
+WHERE elementId(n) = id
+MATCH (n)-[rel]-(next)
+WHERE type(rel) IN $edgeTypes
+  AND NOT elementId(next) IN $visited
+RETURN DISTINCT elementId(next) AS id
+LIMIT $limit
+`
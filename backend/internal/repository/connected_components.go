@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// DefaultComponentMinSize is the smallest component ConnectedComponents lists when
+	// the caller does not choose; single users are not worth a review.
+	DefaultComponentMinSize = 2
+	// maxUserComponents caps the components one ConnectedComponents call returns.
+	maxUserComponents = 500
+	// maxComponentMembers caps the user IDs listed per component.
+	maxComponentMembers = 1000
+	// componentEdgePageSize is how many relationships the Cypher fallback reads per
+	// query.
+	componentEdgePageSize = 5000
+)
+
+// Component algorithms reported in domain.UserComponents.Algorithm.
+const (
+	ComponentAlgorithmGDS    = "gds-wcc"
+	ComponentAlgorithmCypher = "cypher"
+)
+
+// ConnectedComponents assigns every user to a component over SENT_TO and HAS_ATTRIBUTE,
+// in either direction, so users sharing an attribute or exchanging money are together,
+// and lists the components with at least minSize users. Zero or less means
+// DefaultComponentMinSize.
+//
+// When the Graph Data Science plugin provides gds.wcc.stream, the components come from
+// weakly connected components over a temporary in-memory projection. Otherwise, or when
+// the projection fails, user to user and user to attribute links are read in pages and
+// merged in memory, which costs a full read of those relationships and memory for
+// every linked node; prefer GDS on large graphs.
+func (r *Repository) ConnectedComponents(ctx context.Context, minSize int) (domain.UserComponents, error) {
+	if minSize <= 0 {
+		minSize = DefaultComponentMinSize
+	}
+
+	available, err := r.gdsAvailable(ctx, "gds.wcc.stream")
+	if err != nil {
+		return domain.UserComponents{}, err
+	}
+	if available {
+		members, err := r.gdsComponents(ctx, minSize)
+		if err == nil {
+			return buildUserComponents(minSize, ComponentAlgorithmGDS, members), nil
+		}
+		if ctx.Err() != nil {
+			return domain.UserComponents{}, err
+		}
+	}
+
+	members, err := r.cypherComponents(ctx, minSize)
+	if err != nil {
+		return domain.UserComponents{}, err
+	}
+	return buildUserComponents(minSize, ComponentAlgorithmCypher, members), nil
+}
+
+// gdsComponents runs weakly connected components on a projection that is dropped
+// afterwards, returning the user IDs of every component with at least minSize users.
+func (r *Repository) gdsComponents(ctx context.Context, minSize int) ([][]string, error) {
+	records, err := r.runGDSProjection(ctx, "components", gdsProjectComponentsCypher, gdsWCCStreamCypher, map[string]any{"minSize": minSize})
+	if err != nil {
+		return nil, err
+	}
+	members := make([][]string, 0, len(records))
+	for _, record := range records {
+		values, _ := record["userIds"].([]any)
+		users := make([]string, 0, len(values))
+		for _, value := range values {
+			users = append(users, toString(value))
+		}
+		members = append(members, users)
+	}
+	return members, nil
+}
+
+// cypherComponents merges users over pages of their SENT_TO and HAS_ATTRIBUTE links,
+// read in elementId order of the relationship, with a union-find over node keys.
+func (r *Repository) cypherComponents(ctx context.Context, minSize int) ([][]string, error) {
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(key string) string {
+		root, ok := parent[key]
+		if !ok {
+			parent[key] = key
+			return key
+		}
+		if root == key {
+			return key
+		}
+		root = find(root)
+		parent[key] = root
+		return root
+	}
+
+	after := ""
+	for {
+		res, err := r.client.ExecuteRead(ctx, componentLinksCypher, map[string]any{
+			"after": after,
+			"limit": componentEdgePageSize,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("component links query: %w", err)
+		}
+		for _, record := range res.Records {
+			a, b := find("User:"+toString(record["userId"])), find(toString(record["peerKey"]))
+			if a != b {
+				parent[a] = b
+			}
+			after = toString(record["relId"])
+		}
+		if len(res.Records) < componentEdgePageSize {
+			break
+		}
+	}
+
+	groups := make(map[string][]string)
+	for key := range parent {
+		if userID, ok := strings.CutPrefix(key, "User:"); ok {
+			root := find(key)
+			groups[root] = append(groups[root], userID)
+		}
+	}
+	members := make([][]string, 0, len(groups))
+	for _, users := range groups {
+		if len(users) >= minSize {
+			members = append(members, users)
+		}
+	}
+	return members, nil
+}
+
+// buildUserComponents orders every component's members and the components themselves,
+// largest first with the component ID breaking ties, and applies the caps.
+func buildUserComponents(minSize int, algorithm string, members [][]string) domain.UserComponents {
+	components := make([]domain.UserComponent, 0, len(members))
+	for _, users := range members {
+		if len(users) == 0 {
+			continue
+		}
+		sort.Strings(users)
+		component := domain.UserComponent{ID: users[0], Size: len(users), UserIDs: users}
+		if len(users) > maxComponentMembers {
+			component.UserIDs = users[:maxComponentMembers]
+		}
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Size != components[j].Size {
+			return components[i].Size > components[j].Size
+		}
+		return components[i].ID < components[j].ID
+	})
+
+	result := domain.UserComponents{MinSize: minSize, Algorithm: algorithm, Components: components}
+	if len(components) > maxUserComponents {
+		result.Components = components[:maxUserComponents]
+		result.Truncated = true
+	}
+	return result
+}
+
+const gdsProjectComponentsCypher = `
+CALL gds.graph.project($graphName, ["User", "Attribute"], {
+	SENT_TO: {orientation: "UNDIRECTED"},
+	HAS_ATTRIBUTE: {orientation: "UNDIRECTED"}
+})
+YIELD graphName
+RETURN graphName
+`
+
+const gdsWCCStreamCypher = `
+CALL gds.wcc.stream($graphName) YIELD nodeId, componentId
+WITH gds.util.asNode(nodeId) AS n, componentId
+WHERE n:User
+WITH componentId, collect(n.userId) AS userIds
+WHERE size(userIds) >= $minSize
+RETURN userIds
+`
+
+// componentLinksCypher pages through user links by relationship elementId. peerKey is
+// "User:<userId>" for a payment peer and the elementId of a shared attribute.
+const componentLinksCypher = `
+MATCH (u:User)-[rel:SENT_TO|HAS_ATTRIBUTE]->(peer)
+WHERE elementId(rel) > $after
+  AND (type(rel) = "HAS_ATTRIBUTE" OR peer:User)
+RETURN elementId(rel) AS relId,
+       u.userId AS userId,
+       CASE WHEN peer:User THEN "User:" + peer.userId ELSE elementId(peer) END AS peerKey
+ORDER BY relId
+LIMIT $limit
+`
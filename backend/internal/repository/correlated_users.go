@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// DefaultCorrelatedMinTypes is how many distinct attribute types a pair of users must
+	// share to be listed by UsersWithSharedAttributeCount when the caller does not choose.
+	DefaultCorrelatedMinTypes = 2
+	// maxCorrelatedPairs caps the pairs one UsersWithSharedAttributeCount call returns.
+	maxCorrelatedPairs = 500
+	// correlatedHubUsers skips attributes linked to more users than this. A public Wi-Fi
+	// IP shared by thousands of users would otherwise pair all of them, which is both
+	// expensive and not evidence of anything.
+	correlatedHubUsers = 200
+)
+
+// UsersWithSharedAttributeCount returns the pairs of users sharing attributes of at least
+// minDistinctTypes distinct types, each with the types they share in order. Pairs sharing
+// the most types come first, then by user IDs; each pair is listed once with the smaller
+// user ID first. Attributes linked to more than correlatedHubUsers users are ignored.
+// Zero or less means DefaultCorrelatedMinTypes.
+func (r *Repository) UsersWithSharedAttributeCount(ctx context.Context, minDistinctTypes int) (domain.CorrelatedUsers, error) {
+	if minDistinctTypes <= 0 {
+		minDistinctTypes = DefaultCorrelatedMinTypes
+	}
+
+	res, err := r.client.ExecuteRead(ctx, correlatedUsersCypher, map[string]any{
+		"minTypes": minDistinctTypes,
+		"maxUsers": correlatedHubUsers,
+		"limit":    maxCorrelatedPairs + 1,
+	})
+	if err != nil {
+		return domain.CorrelatedUsers{}, fmt.Errorf("correlated users query: %w", err)
+	}
+
+	result := domain.CorrelatedUsers{MinSharedTypes: minDistinctTypes, Pairs: []domain.CorrelatedUserPair{}}
+	records := res.Records
+	if len(records) > maxCorrelatedPairs {
+		records = records[:maxCorrelatedPairs]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		values, _ := record["sharedTypes"].([]any)
+		types := make([]string, 0, len(values))
+		for _, value := range values {
+			types = append(types, toString(value))
+		}
+		result.Pairs = append(result.Pairs, domain.CorrelatedUserPair{
+			UserA:       toString(record["userA"]),
+			UserB:       toString(record["userB"]),
+			SharedTypes: types,
+		})
+	}
+	return result, nil
+}
+
+const correlatedUsersCypher = `
+MATCH (a:Attribute)
+WHERE COUNT { (a)<-[:HAS_ATTRIBUTE]-(:User) } <= $maxUsers
+MATCH (u:User)-[:HAS_ATTRIBUTE]->(a)<-[:HAS_ATTRIBUTE]-(other:User)
+WHERE u.userId < other.userId
+WITH u.userId AS userA, other.userId AS userB, a.attributeType AS attributeType
+ORDER BY attributeType
+WITH userA, userB, collect(DISTINCT attributeType) AS sharedTypes
+WHERE size(sharedTypes) >= $minTypes
+RETURN userA, userB, sharedTypes
+ORDER BY size(sharedTypes) DESC, userA, userB
+LIMIT $limit
+`
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestUsersWithSharedAttributeCount(t *testing.T) {
+	client := &fakeClient{read: records(
+		graph.Record{"userA": "USR-1", "userB": "USR-2", "sharedTypes": []any{"EMAIL", "PHONE"}},
+	)}
+	result, err := New(client).UsersWithSharedAttributeCount(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("UsersWithSharedAttributeCount() error = %v", err)
+	}
+	if got := client.reads[0].params["minTypes"]; got != 2 {
+		t.Errorf("minTypes = %v, want 2", got)
+	}
+	if result.MinSharedTypes != 2 || result.Truncated {
+		t.Errorf("result = %+v, want minShared 2 and not truncated", result)
+	}
+	if len(result.Pairs) != 1 {
+		t.Fatalf("got %d pairs, want 1", len(result.Pairs))
+	}
+	pair := result.Pairs[0]
+	if pair.UserA != "USR-1" || pair.UserB != "USR-2" {
+		t.Errorf("pair = %s, %s, want USR-1, USR-2", pair.UserA, pair.UserB)
+	}
+	if want := []string{"EMAIL", "PHONE"}; !reflect.DeepEqual(pair.SharedTypes, want) {
+		t.Errorf("shared types = %v, want %v", pair.SharedTypes, want)
+	}
+}
+
+func TestUsersWithSharedAttributeCountTruncates(t *testing.T) {
+	recs := make([]graph.Record, maxCorrelatedPairs+1)
+	for i := range recs {
+		recs[i] = graph.Record{"userA": fmt.Sprintf("USR-%d", i), "userB": "USR-Z", "sharedTypes": []any{"EMAIL", "PHONE"}}
+	}
+	client := &fakeClient{read: records(recs...)}
+	result, err := New(client).UsersWithSharedAttributeCount(context.Background(), 0)
+	if err != nil {
+		t.Fatalf("UsersWithSharedAttributeCount() error = %v", err)
+	}
+	if result.MinSharedTypes != DefaultCorrelatedMinTypes {
+		t.Errorf("MinSharedTypes = %d, want the default %d", result.MinSharedTypes, DefaultCorrelatedMinTypes)
+	}
+	if !result.Truncated || len(result.Pairs) != maxCorrelatedPairs {
+		t.Errorf("got %d pairs, truncated %v; want %d, true", len(result.Pairs), result.Truncated, maxCorrelatedPairs)
+	}
+}
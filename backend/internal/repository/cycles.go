@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// MinCycleLength is the shortest cycle searched for: two users paying each other.
+	MinCycleLength = 2
+	// DefaultCycleLength is the longest cycle searched for when the caller does not
+	// choose.
+	DefaultCycleLength = 4
+	// MaxCycleLength caps the cycle length. The number of candidate loops grows with the
+	// length times the payments per user, so longer searches are rejected.
+	MaxCycleLength = 6
+	// maxCycles caps the cycles one DetectCycles call returns.
+	maxCycles = 200
+	// cycleCandidates caps the loops a search examines before deduplicating them, so a
+	// dense cluster of small payments cannot make it run unbounded.
+	cycleCandidates = 10000
+)
+
+// cycleEdgeProperties are the payment properties every cycle edge carries.
+var cycleEdgeProperties = []string{"amount", "currency", "transactionId", "timestamp"}
+
+// DetectCycles finds closed loops of money movement, A→B→C→A, over SENT_TO edges of at
+// most maxLength payments, each of at least minAmount. A loop never visits a user twice.
+// Rotations of a loop are one cycle, reported from its smallest user ID, and loops
+// through the same users by different payments are reported once, by the payments with
+// the largest total. Cycles are returned largest TotalAmount first, at most maxCycles,
+// from the first cycleCandidates loops found. Amounts are summed as stored, whatever
+// their currency. maxLength must be between MinCycleLength and MaxCycleLength; zero means
+// DefaultCycleLength.
+func (r *Repository) DetectCycles(ctx context.Context, maxLength int, minAmount float64) (domain.CycleSet, error) {
+	if maxLength == 0 {
+		maxLength = DefaultCycleLength
+	}
+	if maxLength < MinCycleLength || maxLength > MaxCycleLength {
+		return domain.CycleSet{}, fmt.Errorf("max cycle length must be between %d and %d", MinCycleLength, MaxCycleLength)
+	}
+	if minAmount < 0 {
+		minAmount = 0
+	}
+
+	res, err := r.client.ExecuteRead(ctx, detectCyclesCypher(maxLength), map[string]any{
+		"minAmount":  minAmount,
+		"candidates": cycleCandidates,
+		"limit":      maxCycles + 1,
+		"edgeProps":  cycleEdgeProperties,
+	})
+	if err != nil {
+		return domain.CycleSet{}, fmt.Errorf("detect cycles query: %w", err)
+	}
+
+	result := domain.CycleSet{MaxLength: maxLength, MinAmount: minAmount}
+	records := res.Records
+	if len(records) > maxCycles {
+		records = records[:maxCycles]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		path := decodePath("", "", record)
+		edges, _ := record["edges"].([]any)
+		for i := range path.Edges {
+			if edge, ok := edges[i].(map[string]any); ok {
+				path.Edges[i].Properties = decodeProjection(edge["props"])
+			}
+		}
+		result.Cycles = append(result.Cycles, domain.Cycle{
+			Nodes:       path.Nodes,
+			Edges:       path.Edges,
+			TotalAmount: toFloat64(record["totalAmount"]),
+		})
+	}
+	return result, nil
+}
+
+// detectCyclesCypher matches loops from their smallest user ID only, which drops every
+// other rotation, and keeps loops whose users other than the closing one are distinct.
+// maxLength must already be validated; variable-length bounds cannot be parameters.
+func detectCyclesCypher(maxLength int) string {
+	return fmt.Sprintf(`
+MATCH loop = (start:User)-[:SENT_TO*%d..%d]->(start)
+WHERE all(rel IN relationships(loop) WHERE coalesce(rel.amount, 0.0) >= $minAmount)
+  AND all(n IN nodes(loop) WHERE start.userId <= n.userId)
+  AND all(n IN tail(nodes(loop)) WHERE single(m IN tail(nodes(loop)) WHERE m = n))
+WITH loop, reduce(total = 0.0, rel IN relationships(loop) | total + coalesce(rel.amount, 0.0)) AS totalAmount
+LIMIT $candidates
+WITH [n IN nodes(loop) | n.userId] AS ring, loop, totalAmount
+ORDER BY totalAmount DESC
+WITH ring, head(collect(loop)) AS loop, max(totalAmount) AS totalAmount
+RETURN [n IN nodes(loop)[0..-1] | {
+         id: n.userId,
+         label: "User"
+       }] AS nodes,
+       [rel IN relationships(loop) | {
+         type: type(rel),
+         source: startNode(rel).userId,
+         target: endNode(rel).userId,
+         weight: 1.0,
+         props: [key IN $edgeProps WHERE rel[key] IS NOT NULL | [key, rel[key]]]
+       }] AS edges,
+       totalAmount
+ORDER BY totalAmount DESC, ring
+LIMIT $limit
+`, MinCycleLength, maxLength)
+}
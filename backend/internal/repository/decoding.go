@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+// decodeLogger receives type mismatches found while decoding records. It is nil unless
+// strict decoding is enabled, so production decoding stays lenient and silent.
+var decodeLogger atomic.Pointer[slog.Logger]
+
+// EnableStrictDecoding logs every record value whose type does not match what the
+// repository expects. The value still decodes as a zero value, so queries never fail
+// because of it; a key missing from a record decodes as a zero value without a log, as
+// it cannot be told apart from a null property. Pass nil to disable.
+func EnableStrictDecoding(logger *slog.Logger) {
+	if logger != nil {
+		logger.Warn("strict graph decoding enabled; intended for development")
+	}
+	decodeLogger.Store(logger)
+}
+
+func reportDecodeMismatch(expected string, val any) {
+	logger := decodeLogger.Load()
+	if logger == nil {
+		return
+	}
+	logger.Warn("unexpected value type while decoding graph record",
+		"expected", expected,
+		"actual", fmt.Sprintf("%T", val),
+	)
+}
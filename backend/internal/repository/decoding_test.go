@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestStrictDecodingLogsTypeMismatches(t *testing.T) {
+	var logs bytes.Buffer
+	EnableStrictDecoding(slog.New(slog.NewTextHandler(&logs, nil)))
+	t.Cleanup(func() { EnableStrictDecoding(nil) })
+	logs.Reset()
+
+	if got := toString(int64(42)); got != "" {
+		t.Errorf("toString(int64) = %q, want the zero value", got)
+	}
+	if got := toFloat64("0.9"); got != 0 {
+		t.Errorf("toFloat64(string) = %v, want the zero value", got)
+	}
+
+	out := logs.String()
+	for _, want := range []string{
+		"expected=string actual=int64",
+		"expected=float actual=string",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("decode log %q does not contain %q", out, want)
+		}
+	}
+
+	// Values of the expected type, and nulls, decode without a report.
+	logs.Reset()
+	if got := toString("USR-1"); got != "USR-1" {
+		t.Errorf("toString(string) = %q, want %q", got, "USR-1")
+	}
+	if got := toFloat64(nil); got != 0 {
+		t.Errorf("toFloat64(nil) = %v, want 0", got)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("matching values were reported: %s", logs.String())
+	}
+}
+
+func TestLenientDecodingIsSilent(t *testing.T) {
+	var logs bytes.Buffer
+	EnableStrictDecoding(slog.New(slog.NewTextHandler(&logs, nil)))
+	EnableStrictDecoding(nil)
+	logs.Reset()
+
+	if got := toString(int64(42)); got != "" {
+		t.Errorf("toString(int64) = %q, want the zero value", got)
+	}
+	if logs.Len() != 0 {
+		t.Errorf("lenient decoding logged: %s", logs.String())
+	}
+}
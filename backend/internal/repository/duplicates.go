@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// DuplicateTransactionMode decides what happens when an incoming transaction reuses the
+// ID of a stored one with a different sender, receiver or amount.
+type DuplicateTransactionMode string
+
+const (
+	// DuplicateTransactionsOverwrite replaces the stored transaction (the historical behaviour).
+	DuplicateTransactionsOverwrite DuplicateTransactionMode = "overwrite"
+	// DuplicateTransactionsReject fails the write with a *ConflictError.
+	DuplicateTransactionsReject DuplicateTransactionMode = "reject"
+	// DuplicateTransactionsVersion stores the incoming transaction as "<id>~v<n>", with
+	// originalTransactionId set to the reused ID.
+	DuplicateTransactionsVersion DuplicateTransactionMode = "version"
+)
+
+const transactionVersionSeparator = "~v"
+
+// DuplicateTransactionOptions configures duplicate transaction ID handling.
+type DuplicateTransactionOptions struct {
+	Mode DuplicateTransactionMode
+	// Logger receives a warning for every conflicting duplicate, whatever the mode.
+	Logger *slog.Logger
+}
+
+// WithDuplicateTransactions sets how UpsertTransaction treats conflicting duplicate IDs
+// and returns the repository for chaining.
+func (r *Repository) WithDuplicateTransactions(opts DuplicateTransactionOptions) *Repository {
+	r.duplicates = opts
+	return r
+}
+
+type storedTransactionIdentity struct {
+	id         string
+	senderID   string
+	receiverID string
+	amount     float64
+}
+
+func (s storedTransactionIdentity) matches(tx domain.Transaction) bool {
+	amount := tx.Amount
+	if !tx.Monetary {
+		amount = 0
+	}
+	return s.senderID == tx.SenderUserID && s.receiverID == tx.ReceiverUserID && s.amount == amount
+}
+
+// resolveDuplicate applies the duplicate mode to tx. When a new version is stored it
+// rewrites tx.ID and returns the reused original ID. Detection is a read ahead of the
+// write, so two concurrent conflicting writes can still race; it targets replayed
+// upstream data rather than concurrent writers.
+func (r *Repository) resolveDuplicate(ctx context.Context, tx *domain.Transaction) (string, error) {
+	mode := r.duplicates.Mode
+	if mode == "" {
+		mode = DuplicateTransactionsOverwrite
+	}
+	if mode == DuplicateTransactionsOverwrite && r.duplicates.Logger == nil {
+		return "", nil
+	}
+
+	res, err := r.client.ExecuteRead(ctx, transactionVersionsCypher, map[string]any{
+		"transactionId": tx.ID,
+		"versionPrefix": tx.ID + transactionVersionSeparator,
+	})
+	if err != nil {
+		return "", fmt.Errorf("check duplicate transaction %s: %w", tx.ID, err)
+	}
+
+	var original *storedTransactionIdentity
+	versions := make([]storedTransactionIdentity, 0, len(res.Records))
+	for _, record := range res.Records {
+		stored := storedTransactionIdentity{
+			id:         toString(record["transactionId"]),
+			senderID:   toString(record["senderId"]),
+			receiverID: toString(record["receiverId"]),
+			amount:     toFloat64(record["amount"]),
+		}
+		versions = append(versions, stored)
+		if stored.id == tx.ID {
+			original = &versions[len(versions)-1]
+		}
+	}
+	if original == nil || original.matches(*tx) {
+		return "", nil
+	}
+
+	if logger := r.duplicates.Logger; logger != nil {
+		logger.Warn("transaction ID reused with different details",
+			"transactionId", tx.ID,
+			"mode", string(mode),
+			"storedSender", original.senderID,
+			"storedReceiver", original.receiverID,
+			"storedAmount", original.amount,
+			"incomingSender", tx.SenderUserID,
+			"incomingReceiver", tx.ReceiverUserID,
+			"incomingAmount", tx.Amount,
+		)
+	}
+
+	switch mode {
+	case DuplicateTransactionsReject:
+		return "", &ConflictError{Label: "Transaction", Property: "transactionId", Value: tx.ID}
+	case DuplicateTransactionsVersion:
+		originalID := tx.ID
+		tx.ID = nextTransactionVersion(originalID, versions, *tx)
+		return originalID, nil
+	}
+	return "", nil
+}
+
+// nextTransactionVersion reuses a stored version with the same details, so replays stay
+// idempotent, and otherwise allocates the next free suffix.
+func nextTransactionVersion(id string, versions []storedTransactionIdentity, tx domain.Transaction) string {
+	highest := 1
+	for _, v := range versions {
+		if v.id == id {
+			continue
+		}
+		if v.matches(tx) {
+			return v.id
+		}
+		var n int
+		if _, err := fmt.Sscanf(strings.TrimPrefix(v.id, id+transactionVersionSeparator), "%d", &n); err == nil && n > highest {
+			highest = n
+		}
+	}
+	return fmt.Sprintf("%s%s%d", id, transactionVersionSeparator, highest+1)
+}
+
+const transactionVersionsCypher = `
+MATCH (t:Transaction)
+WHERE t.transactionId = $transactionId OR t.transactionId STARTS WITH $versionPrefix
+OPTIONAL MATCH (s:User)-[:PARTICIPATED_IN {transactionId: t.transactionId, role: "SENDER"}]->(t)
+OPTIONAL MATCH (rcv:User)-[:PARTICIPATED_IN {transactionId: t.transactionId, role: "RECEIVER"}]->(t)
+RETURN t.transactionId AS transactionId, s.userId AS senderId, rcv.userId AS receiverId, t.amount AS amount
+`
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestNextTransactionVersion(t *testing.T) {
+	incoming := domain.Transaction{ID: "TX-1", SenderUserID: "U1", ReceiverUserID: "U3", Amount: 30, Monetary: true}
+	original := storedTransactionIdentity{id: "TX-1", senderID: "U1", receiverID: "U2", amount: 10}
+	tests := []struct {
+		name     string
+		versions []storedTransactionIdentity
+		want     string
+	}{
+		{name: "first version", versions: []storedTransactionIdentity{original}, want: "TX-1~v2"},
+		{
+			name: "after the highest suffix",
+			versions: []storedTransactionIdentity{
+				original,
+				{id: "TX-1~v2", senderID: "U1", receiverID: "U4", amount: 20},
+				{id: "TX-1~v5", senderID: "U1", receiverID: "U5", amount: 50},
+			},
+			want: "TX-1~v6",
+		},
+		{
+			name: "replay reuses the matching version",
+			versions: []storedTransactionIdentity{
+				original,
+				{id: "TX-1~v2", senderID: "U1", receiverID: "U3", amount: 30},
+				{id: "TX-1~v3", senderID: "U1", receiverID: "U4", amount: 40},
+			},
+			want: "TX-1~v2",
+		},
+		{
+			name: "unparsable suffix is ignored",
+			versions: []storedTransactionIdentity{
+				original,
+				{id: "TX-1~vx", senderID: "U1", receiverID: "U4", amount: 40},
+			},
+			want: "TX-1~v2",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := nextTransactionVersion("TX-1", tt.versions, incoming); got != tt.want {
+				t.Errorf("nextTransactionVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// storedTransaction answers the duplicate check with one stored TX-1 from U1 to U2.
+func storedTransaction() *fakeClient {
+	return &fakeClient{read: records(graph.Record{
+		"transactionId": "TX-1",
+		"senderId":      "U1",
+		"receiverId":    "U2",
+		"amount":        10.0,
+	})}
+}
+
+func TestUpsertTransactionRejectsConflictingDuplicate(t *testing.T) {
+	client := storedTransaction()
+	repo := New(client).WithDuplicateTransactions(DuplicateTransactionOptions{Mode: DuplicateTransactionsReject})
+
+	tx := domain.Transaction{ID: "TX-1", SenderUserID: "U1", ReceiverUserID: "U3", Amount: 10, Monetary: true}
+	err := repo.UpsertTransaction(context.Background(), tx, nil)
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || !errors.Is(err, ErrConflict) {
+		t.Fatalf("UpsertTransaction() error = %v, want a *ConflictError", err)
+	}
+	if conflict.Value != "TX-1" {
+		t.Errorf("conflict value = %q, want TX-1", conflict.Value)
+	}
+	if len(client.writes) != 0 {
+		t.Errorf("rejected duplicate was written %d times", len(client.writes))
+	}
+
+	// The same details are not a conflict.
+	tx.ReceiverUserID = "U2"
+	if err := repo.UpsertTransaction(context.Background(), tx, nil); err != nil {
+		t.Errorf("UpsertTransaction() of matching details error = %v", err)
+	}
+}
+
+func TestUpsertTransactionVersionsConflictingDuplicate(t *testing.T) {
+	client := storedTransaction()
+	repo := New(client).WithDuplicateTransactions(DuplicateTransactionOptions{Mode: DuplicateTransactionsVersion})
+
+	tx := domain.Transaction{ID: "TX-1", SenderUserID: "U1", ReceiverUserID: "U3", Amount: 10, Monetary: true}
+	if err := repo.UpsertTransaction(context.Background(), tx, nil); err != nil {
+		t.Fatalf("UpsertTransaction() error = %v", err)
+	}
+	if len(client.writes) != 1 {
+		t.Fatalf("writes = %d, want 1", len(client.writes))
+	}
+	row := client.writes[0].params["transaction"].(map[string]any)
+	if row["transactionId"] != "TX-1~v2" {
+		t.Errorf("stored transactionId = %v, want TX-1~v2", row["transactionId"])
+	}
+	if props := row["props"].(map[string]any); props["originalTransactionId"] != "TX-1" {
+		t.Errorf("originalTransactionId = %v, want TX-1", props["originalTransactionId"])
+	}
+}
+
+func TestUpsertTransactionOverwritesByDefault(t *testing.T) {
+	client := storedTransaction()
+	repo := New(client)
+
+	tx := domain.Transaction{ID: "TX-1", SenderUserID: "U1", ReceiverUserID: "U3", Amount: 10, Monetary: true}
+	if err := repo.UpsertTransaction(context.Background(), tx, nil); err != nil {
+		t.Fatalf("UpsertTransaction() error = %v", err)
+	}
+	if len(client.reads) != 0 {
+		t.Errorf("overwrite mode without a logger ran %d duplicate checks", len(client.reads))
+	}
+	if row := client.writes[0].params["transaction"].(map[string]any); row["transactionId"] != "TX-1" {
+		t.Errorf("stored transactionId = %v, want TX-1", row["transactionId"])
+	}
+}
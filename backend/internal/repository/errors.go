@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// ErrNotFound indicates the requested entity does not exist in the graph.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict indicates a write was rejected because it collides with existing data.
+var ErrConflict = errors.New("conflict with existing record")
+
+const constraintValidationFailedCode = "Neo.ClientError.Schema.ConstraintValidationFailed"
+
+// constraintMessageRegex extracts the label, property and value from messages such as
+// "Node(42) already exists with label `User` and property `userId` = 'USR-1'".
+var constraintMessageRegex = regexp.MustCompile("label `([^`]+)` and property `([^`]+)` = '([^']*)'")
+
+// ConflictError describes a constraint violation reported by the graph database.
+type ConflictError struct {
+	Label    string
+	Property string
+	Value    string
+	Err      error
+}
+
+func (e *ConflictError) Error() string {
+	if e.Property == "" {
+		return ErrConflict.Error()
+	}
+	return fmt.Sprintf("%s: %s", ErrConflict.Error(), e.Key())
+}
+
+// Key returns a human readable description of the conflicting key, e.g. "User.userId=USR-1".
+func (e *ConflictError) Key() string {
+	if e.Property == "" {
+		return ""
+	}
+	key := e.Property
+	if e.Label != "" {
+		key = e.Label + "." + key
+	}
+	if e.Value != "" {
+		key += "=" + e.Value
+	}
+	return key
+}
+
+// Is reports whether the target is ErrConflict so callers can use errors.Is.
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError converts well-known driver errors into repository error types.
+// Errors that are not recognised are returned unchanged.
+func classifyError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var neoErr *neo4j.Neo4jError
+	if !errors.As(err, &neoErr) {
+		return err
+	}
+	if neoErr.Code != constraintValidationFailedCode {
+		return err
+	}
+
+	conflict := &ConflictError{Err: err}
+	if match := constraintMessageRegex.FindStringSubmatch(neoErr.Msg); match != nil {
+		conflict.Label = match[1]
+		conflict.Property = match[2]
+		conflict.Value = match[3]
+	}
+	return conflict
+}
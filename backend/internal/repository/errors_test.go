@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+func TestClassifyError(t *testing.T) {
+	constraint := &neo4j.Neo4jError{
+		Code: constraintValidationFailedCode,
+		Msg:  "Node(42) already exists with label `User` and property `userId` = 'USR-1'",
+	}
+	unrelated := errors.New("connection reset")
+	tests := []struct {
+		name         string
+		err          error
+		wantConflict bool
+		wantKey      string
+	}{
+		{name: "constraint violation", err: constraint, wantConflict: true, wantKey: "User.userId=USR-1"},
+		{name: "wrapped constraint violation", err: fmt.Errorf("run query: %w", constraint), wantConflict: true, wantKey: "User.userId=USR-1"},
+		{
+			name:         "constraint violation with an unknown message",
+			err:          &neo4j.Neo4jError{Code: constraintValidationFailedCode, Msg: "constraint violated"},
+			wantConflict: true,
+		},
+		{name: "other driver error", err: &neo4j.Neo4jError{Code: "Neo.ClientError.Statement.SyntaxError", Msg: "bad query"}},
+		{name: "unrelated error", err: unrelated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := classifyError(tt.err)
+			var conflict *ConflictError
+			if isConflict := errors.As(got, &conflict); isConflict != tt.wantConflict {
+				t.Fatalf("classifyError() = %v, conflict %t, want %t", got, isConflict, tt.wantConflict)
+			}
+			if !tt.wantConflict {
+				if got != tt.err {
+					t.Errorf("classifyError() = %v, want the error unchanged", got)
+				}
+				return
+			}
+			if !errors.Is(got, ErrConflict) {
+				t.Errorf("classifyError() = %v, want errors.Is ErrConflict", got)
+			}
+			if key := conflict.Key(); key != tt.wantKey {
+				t.Errorf("Key() = %q, want %q", key, tt.wantKey)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("classifyError() = %v, want it to wrap the driver error", got)
+			}
+		})
+	}
+	if classifyError(nil) != nil {
+		t.Error("classifyError(nil) != nil")
+	}
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// maxExportBatchSize bounds a single export page; exports are expected to walk the
+// graph in many pages rather than pull everything in one round-trip.
+const maxExportBatchSize = 5000
+
+// ExportUsers returns one page of users matching the filters in opts, ordered by the
+// unique userId so that consecutive Offset values walk the full result set exactly once. Sort fields
+// are ignored and no total count is computed.
+func (r *Repository) ExportUsers(ctx context.Context, opts ListUsersOptions) ([]domain.UserSummary, error) {
+	params := userFilterParams(opts)
+	params["skip"] = exportOffset(opts.Offset)
+	params["limit"] = exportLimit(opts.Limit)
+	filter := userFilterClause
+	if opts.UpdatedSince != nil && !opts.UpdatedSince.IsZero() {
+		filter += updatedSincePredicate("u", *opts.UpdatedSince, params)
+	}
+
+	res, err := r.client.ExecuteRead(ctx, fmt.Sprintf(exportUsersCypherTemplate, filter), params)
+	if err != nil {
+		return nil, fmt.Errorf("export users: %w", err)
+	}
+
+	users := make([]domain.UserSummary, 0, len(res.Records))
+	for _, record := range res.Records {
+		users = append(users, decodeUserSummary(record))
+	}
+	return users, nil
+}
+
+// ExportTransactions returns one page of transactions matching the filters in opts,
+// newest first with transactionId breaking timestamp ties, so the order is total and
+// stable across runs. Sort fields are ignored and no total count is computed.
+func (r *Repository) ExportTransactions(ctx context.Context, opts ListTransactionsOptions) ([]domain.TransactionSummary, error) {
+	params := transactionFilterParams(opts)
+	params["skip"] = exportOffset(opts.Offset)
+	params["limit"] = exportLimit(opts.Limit)
+	filter := transactionFilterClause
+	if opts.UpdatedSince != nil && !opts.UpdatedSince.IsZero() {
+		filter += updatedSincePredicate("t", *opts.UpdatedSince, params)
+	}
+
+	res, err := r.client.ExecuteRead(ctx, fmt.Sprintf(exportTransactionsCypherTemplate, filter), params)
+	if err != nil {
+		return nil, fmt.Errorf("export transactions: %w", err)
+	}
+
+	txs := make([]domain.TransactionSummary, 0, len(res.Records))
+	for _, record := range res.Records {
+		txs = append(txs, decodeTransactionSummary(record))
+	}
+	return txs, nil
+}
+
+// updatedSincePredicate returns the condition, appended to a filter clause, that keeps
+// the nodes bound to v updated at or after since, and sets its parameters. updatedAt is
+// stored as an RFC3339 string in UTC, whose fractional seconds do not sort as strings,
+// so the string bound on whole seconds lets a range index on updatedAt narrow the scan
+// and the datetime comparison settles the rest.
+func updatedSincePredicate(v string, since time.Time, params map[string]any) string {
+	since = since.UTC()
+	params["updatedSinceFloor"] = since.Truncate(time.Second).Format("2006-01-02T15:04:05")
+	params["updatedSince"] = since.Format(time.RFC3339Nano)
+	return fmt.Sprintf("  AND %[1]s.updatedAt >= $updatedSinceFloor AND datetime(%[1]s.updatedAt) >= datetime($updatedSince)\n", v)
+}
+
+func exportOffset(offset int) int {
+	if offset < 0 {
+		return 0
+	}
+	return offset
+}
+
+func exportLimit(limit int) int {
+	if limit <= 0 {
+		return defaultMaintenanceBatchSize
+	}
+	if limit > maxExportBatchSize {
+		return maxExportBatchSize
+	}
+	return limit
+}
+
+const exportUsersCypherTemplate = `
+MATCH (u:User)%sRETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt
+ORDER BY u.userId
+SKIP $skip LIMIT $limit
+`
+
+const exportTransactionsCypherTemplate = `
+MATCH (t:Transaction)%sRETURN t.transactionId AS transactionId,
+       t.amount AS amount,
+       t.currency AS currency,
+       t.type AS type,
+       t.status AS status,
+       t.channel AS channel,
+       t.timestamp AS timestamp,
+       t.createdAt AS createdAt,
+       t.updatedAt AS updatedAt,
+       head([(sender:User)-[:PARTICIPATED_IN {role: "SENDER"}]->(t) | sender.userId]) AS senderId,
+       head([(receiver:User)-[:PARTICIPATED_IN {role: "RECEIVER"}]->(t) | receiver.userId]) AS receiverId
+ORDER BY datetime(t.timestamp) DESC, t.transactionId
+SKIP $skip LIMIT $limit
+`
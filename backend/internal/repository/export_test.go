@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// updatedSinceClient holds nodes keyed by ID with their updatedAt, and answers export
+// queries with those updated at or after $updatedSince, as the predicate does.
+func updatedSinceClient(idKey string, updated map[string]string) *fakeClient {
+	return &fakeClient{read: func(_ string, params map[string]any) (graph.Result, error) {
+		var since time.Time
+		if raw, ok := params["updatedSince"].(string); ok {
+			parsed, err := time.Parse(time.RFC3339Nano, raw)
+			if err != nil {
+				return graph.Result{}, err
+			}
+			since = parsed
+		}
+		var recs []graph.Record
+		for id, at := range updated {
+			stamp, err := time.Parse(time.RFC3339Nano, at)
+			if err != nil {
+				return graph.Result{}, err
+			}
+			if !stamp.Before(since) {
+				recs = append(recs, graph.Record{idKey: id, "updatedAt": at})
+			}
+		}
+		return graph.Result{Records: recs}, nil
+	}}
+}
+
+func TestExportUsersSinceReturnsOnlyChanged(t *testing.T) {
+	client := updatedSinceClient("userId", map[string]string{
+		"USR-old":     "2024-03-01T09:59:59Z",
+		"USR-earlier": "2024-03-01T10:00:00.25Z",
+		"USR-at":      "2024-03-01T10:00:00.5Z",
+		"USR-new":     "2024-03-02T08:00:00Z",
+	})
+	since := time.Date(2024, 3, 1, 10, 0, 0, 500_000_000, time.UTC)
+	users, err := New(client).ExportUsers(context.Background(), ListUsersOptions{UpdatedSince: &since})
+	if err != nil {
+		t.Fatalf("ExportUsers() error = %v", err)
+	}
+	got := map[string]bool{}
+	for _, u := range users {
+		got[u.ID] = true
+	}
+	if len(got) != 2 || !got["USR-at"] || !got["USR-new"] {
+		t.Errorf("exported %v, want only USR-at and USR-new", got)
+	}
+
+	call := client.reads[0]
+	if !strings.Contains(call.cypher, "u.updatedAt >= $updatedSinceFloor") || !strings.Contains(call.cypher, "datetime(u.updatedAt) >= datetime($updatedSince)") {
+		t.Errorf("query does not filter on updatedAt:\n%s", call.cypher)
+	}
+	if got := call.params["updatedSinceFloor"]; got != "2024-03-01T10:00:00" {
+		t.Errorf("updatedSinceFloor = %v, want the whole second 2024-03-01T10:00:00", got)
+	}
+}
+
+func TestExportTransactionsSinceReturnsOnlyChanged(t *testing.T) {
+	client := updatedSinceClient("transactionId", map[string]string{
+		"TX-old": "2024-03-01T09:00:00Z",
+		"TX-new": "2024-03-01T11:00:00Z",
+	})
+	since := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	txs, err := New(client).ExportTransactions(context.Background(), ListTransactionsOptions{UpdatedSince: &since})
+	if err != nil {
+		t.Fatalf("ExportTransactions() error = %v", err)
+	}
+	if len(txs) != 1 || txs[0].ID != "TX-new" {
+		t.Errorf("exported %+v, want only TX-new", txs)
+	}
+	if !strings.Contains(client.reads[0].cypher, "datetime(t.updatedAt) >= datetime($updatedSince)") {
+		t.Errorf("query does not filter on updatedAt:\n%s", client.reads[0].cypher)
+	}
+}
+
+func TestExportUsersWithoutSinceIsUnfiltered(t *testing.T) {
+	client := updatedSinceClient("userId", map[string]string{"USR-1": "2020-01-01T00:00:00Z"})
+	users, err := New(client).ExportUsers(context.Background(), ListUsersOptions{})
+	if err != nil {
+		t.Fatalf("ExportUsers() error = %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("exported %d users, want 1", len(users))
+	}
+	if strings.Contains(client.reads[0].cypher, "updatedSince") {
+		t.Errorf("query filters on updatedAt without since:\n%s", client.reads[0].cypher)
+	}
+	if _, ok := client.reads[0].params["updatedSince"]; ok {
+		t.Error("updatedSince parameter set without since")
+	}
+}
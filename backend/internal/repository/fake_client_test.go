@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// fakeCall is one query a fakeClient received.
+type fakeCall struct {
+	cypher string
+	params map[string]any
+}
+
+// fakeClient is a graph.Client that records every query and answers from read and write,
+// or with an empty result when they are nil.
+type fakeClient struct {
+	read   func(cypher string, params map[string]any) (graph.Result, error)
+	write  func(cypher string, params map[string]any) (graph.Result, error)
+	reads  []fakeCall
+	writes []fakeCall
+}
+
+func (c *fakeClient) ExecuteRead(_ context.Context, cypher string, params map[string]any) (graph.Result, error) {
+	c.reads = append(c.reads, fakeCall{cypher: cypher, params: params})
+	if c.read == nil {
+		return graph.Result{}, nil
+	}
+	return c.read(cypher, params)
+}
+
+func (c *fakeClient) ExecuteWrite(_ context.Context, cypher string, params map[string]any) (graph.Result, error) {
+	c.writes = append(c.writes, fakeCall{cypher: cypher, params: params})
+	if c.write == nil {
+		return graph.Result{}, nil
+	}
+	return c.write(cypher, params)
+}
+
+func (c *fakeClient) VerifyConnectivity(context.Context) error { return nil }
+
+func (c *fakeClient) Close(context.Context) error { return nil }
+
+// records answers every query with recs.
+func records(recs ...graph.Record) func(string, map[string]any) (graph.Result, error) {
+	return func(string, map[string]any) (graph.Result, error) {
+		return graph.Result{Records: recs}, nil
+	}
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// ErrGDSUnavailable reports an analysis that needs a Graph Data Science procedure the
+// database does not provide.
+var ErrGDSUnavailable = errors.New("graph data science plugin not available")
+
+// gdsAvailable reports whether the database exposes the GDS procedure.
+func (r *Repository) gdsAvailable(ctx context.Context, procedure string) (bool, error) {
+	res, err := r.client.ExecuteRead(ctx, gdsAvailableCypher, map[string]any{"procedure": procedure})
+	if err != nil {
+		if ctx.Err() != nil {
+			return false, fmt.Errorf("gds availability query: %w", err)
+		}
+		// Databases that cannot list procedures have no GDS to offer either.
+		return false, nil
+	}
+	return len(res.Records) > 0 && toInt64(res.Records[0]["available"]) > 0, nil
+}
+
+// gdsGraphName returns a projection name starting with prefix that no concurrent call
+// shares.
+func gdsGraphName(prefix string) (string, error) {
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("name %s projection: %w", prefix, err)
+	}
+	return "fintrace-" + prefix + "-" + hex.EncodeToString(suffix), nil
+}
+
+// runGDSProjection projects a graph with projectCypher under a fresh name starting with
+// prefix, runs streamCypher on it and drops it afterwards. Both queries get params plus
+// $graphName. A projection lives in the memory of the cluster member that created it, so
+// every step goes through ExecuteWrite, which is routed to the leader; ExecuteRead could
+// send each step to a different reader.
+func (r *Repository) runGDSProjection(ctx context.Context, prefix, projectCypher, streamCypher string, params map[string]any) ([]graph.Record, error) {
+	graphName, err := gdsGraphName(prefix)
+	if err != nil {
+		return nil, err
+	}
+	withName := make(map[string]any, len(params)+1)
+	for key, value := range params {
+		withName[key] = value
+	}
+	withName["graphName"] = graphName
+
+	if _, err := r.client.ExecuteWrite(ctx, projectCypher, withName); err != nil {
+		return nil, fmt.Errorf("project %s graph: %w", prefix, err)
+	}
+	defer func() {
+		_, _ = r.client.ExecuteWrite(context.WithoutCancel(ctx), gdsDropGraphCypher, map[string]any{"graphName": graphName})
+	}()
+
+	res, err := r.client.ExecuteWrite(ctx, streamCypher, withName)
+	if err != nil {
+		return nil, fmt.Errorf("stream %s: %w", prefix, err)
+	}
+	return res.Records, nil
+}
+
+const gdsAvailableCypher = `
+SHOW PROCEDURES YIELD name
+WHERE name = $procedure
+RETURN count(*) AS available
+`
+
+const gdsDropGraphCypher = `
+CALL gds.graph.drop($graphName, false) YIELD graphName
+RETURN graphName
+`
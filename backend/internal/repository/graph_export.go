@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// ExportEdgeTypes are the relationship types edge and node exports cover. Bookkeeping
+// nodes such as Stats are never exported.
+var ExportEdgeTypes = ComponentEdgeTypes
+
+// GraphExportOptions selects a page of an edge or node export. Empty EdgeTypes means
+// every type in ExportEdgeTypes; for nodes it also keeps nodes without any edge.
+type GraphExportOptions struct {
+	EdgeTypes []string
+	Offset    int
+	Limit     int
+}
+
+// ExportEdges returns one page of relationships of opts.EdgeTypes as an edge list
+// between exportNodeID identifiers. LINKED_TO edges weigh their decayed score when set,
+// or their raw score; every other edge weighs 1. Pages are ordered by element ID, which
+// is stable while the graph is not being written.
+func (r *Repository) ExportEdges(ctx context.Context, opts GraphExportOptions) ([]domain.GraphEdge, error) {
+	params := graphExportParams(opts)
+	res, err := r.client.ExecuteRead(ctx, exportEdgesCypher, params)
+	if err != nil {
+		return nil, fmt.Errorf("export edges: %w", err)
+	}
+
+	edges := make([]domain.GraphEdge, 0, len(res.Records))
+	for _, record := range res.Records {
+		edges = append(edges, domain.GraphEdge{
+			Source: toString(record["source"]),
+			Target: toString(record["target"]),
+			Type:   toString(record["type"]),
+			Weight: toFloat64(record["weight"]),
+		})
+	}
+	return edges, nil
+}
+
+// ExportNodes returns one page of the nodes ExportEdges refers to with the same
+// options: with EdgeTypes, only nodes touching at least one such edge, without it,
+// every user, transaction, attribute and payment method. The node IDs match the edge
+// endpoints, so the two exports together reconstruct the graph.
+func (r *Repository) ExportNodes(ctx context.Context, opts GraphExportOptions) ([]domain.GraphNode, error) {
+	params := graphExportParams(opts)
+	params["filtered"] = len(opts.EdgeTypes) > 0
+	res, err := r.client.ExecuteRead(ctx, exportNodesCypher, params)
+	if err != nil {
+		return nil, fmt.Errorf("export nodes: %w", err)
+	}
+
+	nodes := make([]domain.GraphNode, 0, len(res.Records))
+	for _, record := range res.Records {
+		nodes = append(nodes, domain.GraphNode{
+			ID:            toString(record["id"]),
+			Label:         toString(record["label"]),
+			Key:           toString(record["key"]),
+			AttributeType: toString(record["attributeType"]),
+		})
+	}
+	return nodes, nil
+}
+
+func graphExportParams(opts GraphExportOptions) map[string]any {
+	edgeTypes := opts.EdgeTypes
+	if len(edgeTypes) == 0 {
+		edgeTypes = ExportEdgeTypes
+	}
+	return map[string]any{
+		"edgeTypes": edgeTypes,
+		"skip":      exportOffset(opts.Offset),
+		"limit":     exportLimit(opts.Limit),
+	}
+}
+
+// exportNodeKey and exportNodeID give the business key of node %[1]s and that key
+// prefixed with its label; attribute keys carry their type, since values of different
+// types may collide.
+const (
+	exportNodeKey = `CASE
+    WHEN %[1]s:User THEN %[1]s.userId
+    WHEN %[1]s:Transaction THEN %[1]s.transactionId
+    WHEN %[1]s:PaymentMethod THEN %[1]s.paymentMethodId
+    WHEN %[1]s:Attribute THEN %[1]s.attributeType + ":" + %[1]s.value
+    ELSE elementId(%[1]s)
+  END`
+	exportNodeID = `head(labels(%[1]s)) + ":" + ` + exportNodeKey
+)
+
+var exportEdgesCypher = fmt.Sprintf(`
+MATCH (a)-[rel]->(b)
+WHERE type(rel) IN $edgeTypes
+RETURN %s AS source,
+       %s AS target,
+       type(rel) AS type,
+       CASE type(rel) WHEN "LINKED_TO" THEN coalesce(rel.decayedScore, rel.score, 1.0) ELSE 1.0 END AS weight
+ORDER BY elementId(rel)
+SKIP $skip LIMIT $limit
+`, fmt.Sprintf(exportNodeID, "a"), fmt.Sprintf(exportNodeID, "b"))
+
+var exportNodesCypher = fmt.Sprintf(`
+MATCH (n)
+WHERE (n:User OR n:Transaction OR n:Attribute OR n:PaymentMethod)
+  AND (NOT $filtered OR EXISTS { MATCH (n)-[rel]-() WHERE type(rel) IN $edgeTypes })
+RETURN %s AS id,
+       head(labels(n)) AS label,
+       %s AS key,
+       n.attributeType AS attributeType
+ORDER BY elementId(n)
+SKIP $skip LIMIT $limit
+`, fmt.Sprintf(exportNodeID, "n"), fmt.Sprintf(exportNodeKey, "n"))
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// DefaultHubLimit is how many users TopConnectedUsers returns when the caller does not
+	// choose.
+	DefaultHubLimit = 20
+	// MaxHubLimit caps the users one TopConnectedUsers call returns.
+	MaxHubLimit = 500
+)
+
+// TopConnectedUsers returns the limit users with the most distinct payment
+// counterparties, highest degree first with the user ID breaking ties. Counterparties
+// are read from SENT_TO and from RECEIVED_FROM, so either edge alone is enough, and a
+// counterparty both paid and paid by the user counts once towards the degree. InDegree
+// counts the users paying this one and OutDegree those it pays, telling collectors from
+// distributors. Users without any payment are left out. limit is clamped to
+// [1, MaxHubLimit]; zero or less means DefaultHubLimit.
+func (r *Repository) TopConnectedUsers(ctx context.Context, limit int) ([]domain.UserHub, error) {
+	if limit <= 0 {
+		limit = DefaultHubLimit
+	}
+	limit = min(limit, MaxHubLimit)
+
+	res, err := r.client.ExecuteRead(ctx, topConnectedUsersCypher, map[string]any{"limit": limit})
+	if err != nil {
+		return nil, fmt.Errorf("top connected users query: %w", err)
+	}
+	hubs := make([]domain.UserHub, 0, len(res.Records))
+	for _, record := range res.Records {
+		hubs = append(hubs, domain.UserHub{
+			User:      decodeUserSummary(record),
+			Degree:    int(toInt64(record["degree"])),
+			InDegree:  int(toInt64(record["inDegree"])),
+			OutDegree: int(toInt64(record["outDegree"])),
+		})
+	}
+	return hubs, nil
+}
+
+const topConnectedUsersCypher = `
+MATCH (u:User)
+WITH u,
+     COUNT {
+       MATCH (u)-[:SENT_TO|RECEIVED_FROM]-(peer:User)
+       RETURN DISTINCT peer
+     } AS degree,
+     COUNT {
+       MATCH (peer:User)-[:SENT_TO]->(u) RETURN peer
+       UNION
+       MATCH (u)-[:RECEIVED_FROM]->(peer:User) RETURN peer
+     } AS inDegree,
+     COUNT {
+       MATCH (u)-[:SENT_TO]->(peer:User) RETURN peer
+       UNION
+       MATCH (peer:User)-[:RECEIVED_FROM]->(u) RETURN peer
+     } AS outDegree
+WHERE degree > 0
+ORDER BY degree DESC, u.userId
+LIMIT $limit
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt,
+       degree,
+       inDegree,
+       outDegree
+`
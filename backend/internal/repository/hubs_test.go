@@ -0,0 +1,53 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestTopConnectedUsersDecodesAggregation(t *testing.T) {
+	client := &fakeClient{read: records(
+		graph.Record{"userId": "USR-1", "fullName": "Ada Collector", "riskScore": 0.8, "degree": int64(5), "inDegree": int64(5), "outDegree": int64(1)},
+		graph.Record{"userId": "USR-2", "fullName": "Bo Distributor", "degree": int64(3), "inDegree": int64(0), "outDegree": int64(3)},
+	)}
+	hubs, err := New(client).TopConnectedUsers(context.Background(), 2)
+	if err != nil {
+		t.Fatalf("TopConnectedUsers() error = %v", err)
+	}
+	if len(hubs) != 2 {
+		t.Fatalf("got %d hubs, want 2", len(hubs))
+	}
+	collector, distributor := hubs[0], hubs[1]
+	if collector.User.ID != "USR-1" || collector.User.FullName != "Ada Collector" || collector.User.RiskScore != 0.8 {
+		t.Errorf("first hub user = %+v", collector.User)
+	}
+	if collector.Degree != 5 || collector.InDegree != 5 || collector.OutDegree != 1 {
+		t.Errorf("collector degrees = %d/%d/%d, want 5/5/1", collector.Degree, collector.InDegree, collector.OutDegree)
+	}
+	if distributor.Degree != 3 || distributor.InDegree != 0 || distributor.OutDegree != 3 {
+		t.Errorf("distributor degrees = %d/%d/%d, want 3/0/3", distributor.Degree, distributor.InDegree, distributor.OutDegree)
+	}
+}
+
+func TestTopConnectedUsersLimit(t *testing.T) {
+	tests := []struct {
+		limit int
+		want  int
+	}{
+		{limit: 0, want: DefaultHubLimit},
+		{limit: -1, want: DefaultHubLimit},
+		{limit: 7, want: 7},
+		{limit: MaxHubLimit + 1, want: MaxHubLimit},
+	}
+	for _, tt := range tests {
+		client := &fakeClient{}
+		if _, err := New(client).TopConnectedUsers(context.Background(), tt.limit); err != nil {
+			t.Fatalf("TopConnectedUsers(%d) error = %v", tt.limit, err)
+		}
+		if got := client.reads[0].params["limit"]; got != tt.want {
+			t.Errorf("TopConnectedUsers(%d) queried limit %v, want %d", tt.limit, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// IsolatedUsersOptions paginates IsolatedUsers.
+type IsolatedUsersOptions struct {
+	Offset int
+	Limit  int
+}
+
+// IsolatedUsers returns users that took part in no transaction and share no attribute
+// with any other node, ordered by userId. Such accounts are typically dormant, abandoned
+// or left over from testing.
+func (r *Repository) IsolatedUsers(ctx context.Context, opts IsolatedUsersOptions) (domain.UserListResult, error) {
+	limit := opts.Limit
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 200 {
+		limit = 200
+	}
+	offset := opts.Offset
+	if offset < 0 {
+		offset = 0
+	}
+
+	res, err := r.client.ExecuteRead(ctx, fmt.Sprintf(isolatedUsersCypherTemplate, isolatedUserClause), map[string]any{
+		"skip":  offset,
+		"limit": limit,
+	})
+	if err != nil {
+		return domain.UserListResult{}, fmt.Errorf("isolated users query: %w", err)
+	}
+	users := make([]domain.UserSummary, 0, len(res.Records))
+	for _, record := range res.Records {
+		users = append(users, decodeUserSummary(record))
+	}
+
+	countRes, err := r.client.ExecuteRead(ctx, fmt.Sprintf(countIsolatedUsersCypherTemplate, isolatedUserClause), nil)
+	if err != nil {
+		return domain.UserListResult{}, fmt.Errorf("count isolated users query: %w", err)
+	}
+	var total int64
+	if len(countRes.Records) > 0 {
+		total = toInt64(countRes.Records[0]["total"])
+	}
+
+	return domain.UserListResult{Items: users, Total: total}, nil
+}
+
+const isolatedUserClause = `
+WHERE NOT EXISTS { (u)-[:PARTICIPATED_IN]->(:Transaction) }
+  AND NOT EXISTS {
+	(u)-[:HAS_ATTRIBUTE]->(:Attribute)<-[:HAS_ATTRIBUTE]-(other)
+	WHERE other <> u
+  }
+`
+
+const isolatedUsersCypherTemplate = `
+MATCH (u:User)
+%s
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt
+ORDER BY u.userId
+SKIP $skip LIMIT $limit
+`
+
+const countIsolatedUsersCypherTemplate = `
+MATCH (u:User)
+%s
+RETURN count(u) AS total
+`
@@ -0,0 +1,46 @@
+package repository
+
+import "time"
+
+// LinkDecay configures time decay of LINKED_TO scores. A link's decayed score is
+//
+//	decayedScore = score × 0.5^(|Δt| / HalfLife)
+//
+// where Δt is the gap between the two transactions' timestamps, so a link between
+// transactions one half-life apart counts half as much as one between simultaneous
+// transactions. The raw score is always kept alongside it.
+type LinkDecay struct {
+	// HalfLife is the timestamp gap that halves a link's score; zero disables decay.
+	HalfLife time.Duration
+	// OnWrite also decays links created while ingesting transactions. Without it only
+	// recomputed links carry a decayed score.
+	OnWrite bool
+}
+
+// WithLinkDecay sets how LINKED_TO scores decay with the timestamp gap between the
+// linked transactions and returns the repository for chaining.
+func (r *Repository) WithLinkDecay(decay LinkDecay) *Repository {
+	if decay.HalfLife < 0 {
+		decay.HalfLife = 0
+	}
+	r.linkDecay = decay
+	return r
+}
+
+// linkDecayHalfLife returns the half-life in seconds for the $linkDecayHalfLife
+// parameter, or zero when links written at this point are not decayed.
+func (r *Repository) linkDecayHalfLife(onWrite bool) float64 {
+	if onWrite && !r.linkDecay.OnWrite {
+		return 0
+	}
+	return r.linkDecay.HalfLife.Seconds()
+}
+
+// decayedLinkScore is the Cypher expression for a link's decayed score, given t, other
+// and attr in scope. It is null when decay is off, and the raw score when either
+// transaction has no timestamp.
+const decayedLinkScore = `CASE
+    WHEN $linkDecayHalfLife <= 0 THEN null
+    WHEN coalesce(t.timestamp, "") = "" OR coalesce(other.timestamp, "") = "" THEN attr.score
+    ELSE attr.score * 0.5 ^ (abs(duration.inSeconds(datetime(t.timestamp), datetime(other.timestamp)).seconds) / $linkDecayHalfLife)
+  END`
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// LookupUsers resolves ids to user summaries in one query. The result has one entry per
+// requested ID, in request order, with Found false for IDs that do not exist.
+func (r *Repository) LookupUsers(ctx context.Context, ids []string) ([]domain.UserLookup, error) {
+	res, err := r.client.ExecuteRead(ctx, lookupUsersCypher, map[string]any{"ids": uniqueIDs(ids)})
+	if err != nil {
+		return nil, fmt.Errorf("lookup users: %w", err)
+	}
+	found := make(map[string]domain.UserSummary, len(res.Records))
+	for _, record := range res.Records {
+		user := decodeUserSummary(record)
+		found[user.ID] = user
+	}
+
+	lookups := make([]domain.UserLookup, 0, len(ids))
+	for _, id := range ids {
+		user, ok := found[id]
+		lookups = append(lookups, domain.UserLookup{ID: id, Found: ok, User: user})
+	}
+	return lookups, nil
+}
+
+// LookupTransactions resolves ids to transaction summaries in one query, like LookupUsers.
+func (r *Repository) LookupTransactions(ctx context.Context, ids []string) ([]domain.TransactionLookup, error) {
+	res, err := r.client.ExecuteRead(ctx, lookupTransactionsCypher, map[string]any{"ids": uniqueIDs(ids)})
+	if err != nil {
+		return nil, fmt.Errorf("lookup transactions: %w", err)
+	}
+	found := make(map[string]domain.TransactionSummary, len(res.Records))
+	for _, record := range res.Records {
+		tx := decodeTransactionSummary(record)
+		found[tx.ID] = tx
+	}
+
+	lookups := make([]domain.TransactionLookup, 0, len(ids))
+	for _, id := range ids {
+		tx, ok := found[id]
+		lookups = append(lookups, domain.TransactionLookup{ID: id, Found: ok, Transaction: tx})
+	}
+	return lookups, nil
+}
+
+// uniqueIDs drops repeated IDs so each node is matched once.
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]struct{}, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		unique = append(unique, id)
+	}
+	return unique
+}
+
+const lookupUsersCypher = `
+UNWIND $ids AS id
+MATCH (u:User {userId: id})
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt
+`
+
+const lookupTransactionsCypher = `
+UNWIND $ids AS id
+MATCH (t:Transaction {transactionId: id})
+RETURN t.transactionId AS transactionId,
+       t.amount AS amount,
+       t.currency AS currency,
+       t.type AS type,
+       t.status AS status,
+       t.channel AS channel,
+       t.timestamp AS timestamp,
+       t.createdAt AS createdAt,
+       t.updatedAt AS updatedAt,
+       head([(sender:User)-[:PARTICIPATED_IN {role: "SENDER"}]->(t) | sender.userId]) AS senderId,
+       head([(receiver:User)-[:PARTICIPATED_IN {role: "RECEIVER"}]->(t) | receiver.userId]) AS receiverId
+`
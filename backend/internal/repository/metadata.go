@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// promotedMetadataPrefix namespaces promoted metadata keys among transaction properties,
+// so "merchantCategory" is stored as t.meta_merchantCategory.
+const promotedMetadataPrefix = "meta_"
+
+// WithPromotedMetadataKeys stores the listed transaction metadata keys as native node
+// properties instead of inside metadataJson, so they can be filtered and indexed and keep
+// their type on read. Values that are not a primitive or a list of primitives stay in
+// metadataJson. Returns the repository for chaining.
+func (r *Repository) WithPromotedMetadataKeys(keys ...string) *Repository {
+	r.promotedMetadata = make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		r.promotedMetadata[key] = struct{}{}
+	}
+	return r
+}
+
+// promotedMetadataProperty returns the node property a promoted metadata key is stored in.
+func promotedMetadataProperty(key string) string {
+	return promotedMetadataPrefix + key
+}
+
+// metadataProperties splits metadata into node properties: promoted keys as native values
+// and everything else as metadataJson. Promoted keys that are absent, and metadataJson
+// when nothing is left for it, are set to nil so a rewrite clears stale values.
+func metadataProperties(metadata map[string]any, promoted map[string]struct{}) map[string]any {
+	props := make(map[string]any, len(promoted)+1)
+	for key := range promoted {
+		props[promotedMetadataProperty(key)] = nil
+	}
+
+	rest := make(map[string]any, len(metadata))
+	for key, value := range metadata {
+		if _, ok := promoted[key]; ok {
+			if native, ok := nativeProperty(value); ok {
+				props[promotedMetadataProperty(key)] = native
+				continue
+			}
+		}
+		rest[key] = value
+	}
+
+	props["metadataJson"] = nil
+	if len(rest) > 0 {
+		if serialized, err := serializeMetadata(rest); err == nil {
+			props["metadataJson"] = serialized
+		}
+	}
+	return props
+}
+
+// nativeProperty converts value to a type Neo4j stores as a property: a string, bool,
+// int64, float64 or a homogeneous list of one of those. Integral json.Number values
+// become int64 so integers survive a round-trip.
+func nativeProperty(value any) (any, bool) {
+	switch v := value.(type) {
+	case string, bool, int64, float64:
+		return v, true
+	case int:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case float32:
+		return float64(v), true
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, true
+		}
+		if f, err := v.Float64(); err == nil {
+			return f, true
+		}
+	case []any:
+		list := make([]any, 0, len(v))
+		kind := ""
+		for _, item := range v {
+			native, ok := nativeProperty(item)
+			if !ok {
+				return nil, false
+			}
+			if _, nested := native.([]any); nested {
+				return nil, false
+			}
+			itemKind := jsonKind(native)
+			if kind != "" && itemKind != kind {
+				return nil, false
+			}
+			kind = itemKind
+			list = append(list, native)
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+func jsonKind(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "bool"
+	case int64:
+		return "int"
+	default:
+		return "float"
+	}
+}
+
+// decodeMetadata rebuilds transaction metadata from metadataJson and the promoted
+// properties read back from the node.
+func decodeMetadata(metadataJSON string, properties map[string]any) map[string]any {
+	metadata := map[string]any{}
+	if metadataJSON != "" {
+		_ = json.Unmarshal([]byte(metadataJSON), &metadata)
+	}
+	for name, value := range properties {
+		key, ok := strings.CutPrefix(name, promotedMetadataPrefix)
+		if !ok || value == nil {
+			continue
+		}
+		metadata[key] = value
+	}
+	if len(metadata) == 0 {
+		return nil
+	}
+	return metadata
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// readBackTransaction answers getTransactionCypher with the properties the last
+// transaction upsert on client wrote, as the node would hold them.
+func readBackTransaction(client *fakeClient) func(string, map[string]any) (graph.Result, error) {
+	return func(string, map[string]any) (graph.Result, error) {
+		row := client.writes[len(client.writes)-1].params["transaction"].(map[string]any)
+		properties := map[string]any{}
+		for key, value := range row["props"].(map[string]any) {
+			if value != nil {
+				properties[key] = value
+			}
+		}
+		record := graph.Record{
+			"senderId":     row["senderId"],
+			"receiverId":   row["receiverId"],
+			"monetary":     row["monetary"],
+			"metadataJson": properties["metadataJson"],
+			"properties":   properties,
+		}
+		return graph.Result{Records: []graph.Record{record}}, nil
+	}
+}
+
+func TestTransactionMetadataRoundTrip(t *testing.T) {
+	// Values as encoding/json decodes them from a request body.
+	metadata := map[string]any{
+		"merchant": "ACME Ltd",
+		"mcc":      float64(5411),
+		"flagged":  true,
+		"tags":     []any{"card-present", "contactless"},
+		"terminal": map[string]any{"id": "T-9", "lat": 51.5},
+	}
+	tests := []struct {
+		name     string
+		promoted []string
+	}{
+		{name: "metadataJson only"},
+		{name: "promoted keys", promoted: []string{"merchant", "mcc", "tags"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{}
+			client.read = readBackTransaction(client)
+			repo := New(client).WithPromotedMetadataKeys(tt.promoted...)
+			tx := domain.Transaction{ID: "TX-1", SenderUserID: "USR-1", ReceiverUserID: "USR-2", Monetary: true, Metadata: metadata}
+			if err := repo.UpsertTransaction(context.Background(), tx, nil); err != nil {
+				t.Fatalf("UpsertTransaction() error = %v", err)
+			}
+
+			got, err := repo.GetTransaction(context.Background(), "TX-1")
+			if err != nil {
+				t.Fatalf("GetTransaction() error = %v", err)
+			}
+			if !reflect.DeepEqual(got.Metadata, metadata) {
+				t.Errorf("metadata = %#v, want %#v", got.Metadata, metadata)
+			}
+			if got.SenderUserID != "USR-1" || got.ReceiverUserID != "USR-2" {
+				t.Errorf("participants = %s -> %s, want USR-1 -> USR-2", got.SenderUserID, got.ReceiverUserID)
+			}
+		})
+	}
+}
+
+func TestGetTransactionWithoutMetadata(t *testing.T) {
+	client := &fakeClient{read: records(graph.Record{"metadataJson": nil, "properties": map[string]any{}})}
+	tx, err := New(client).GetTransaction(context.Background(), "TX-1")
+	if err != nil {
+		t.Fatalf("GetTransaction() error = %v", err)
+	}
+	if tx.Metadata != nil {
+		t.Errorf("metadata = %#v, want nil", tx.Metadata)
+	}
+}
@@ -0,0 +1,202 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// DefaultNeighborhoodDepth is the expansion depth used when the caller does not
+	// choose one.
+	DefaultNeighborhoodDepth = 2
+	// MaxNeighborhoodDepth is the deepest expansion allowed. Each hop through a shared
+	// attribute can multiply the node count, so deeper requests are rejected.
+	MaxNeighborhoodDepth = 4
+	// neighborhoodMaxNodes caps the nodes of one neighborhood, the user included.
+	neighborhoodMaxNodes = 1000
+)
+
+// NeighborhoodNodeProperties and NeighborhoodEdgeProperties are the stored properties
+// ExpandUserNeighborhood can project onto nodes and edges. Personal data is left out so
+// neighborhoods need no PII redaction.
+var (
+	NeighborhoodNodeProperties = []string{"riskScore", "kycStatus", "createdAt", "updatedAt"}
+	NeighborhoodEdgeProperties = []string{"amount", "currency", "timestamp", "transactionId", "confidenceScore", "origin"}
+)
+
+// PropertyProjection names the properties to return on subgraph nodes and edges. A
+// property a node or edge does not have is left out of its map.
+type PropertyProjection struct {
+	Node []string
+	Edge []string
+}
+
+// neighborhoodEdgeTypes connect users through payments, in either stored direction, and
+// shared attributes.
+var neighborhoodEdgeTypes = []string{"SENT_TO", "RECEIVED_FROM", "HAS_ATTRIBUTE"}
+
+// ExpandUserNeighborhood returns the nodes within depth hops of a user over payments and
+// shared attributes, and every such relationship between them. It expands breadth-first
+// like ComponentSize and stops once neighborhoodMaxNodes nodes are reached, marking the
+// result truncated. depth must be between 1 and MaxNeighborhoodDepth. Nodes and edges
+// carry only their keys unless props asks for more, from NeighborhoodNodeProperties and
+// NeighborhoodEdgeProperties. It returns ErrNotFound when the user does not exist.
+func (r *Repository) ExpandUserNeighborhood(ctx context.Context, userID string, depth int, props PropertyProjection) (domain.Neighborhood, error) {
+	if userID == "" {
+		return domain.Neighborhood{}, errors.New("user id is required")
+	}
+	if depth < 1 || depth > MaxNeighborhoodDepth {
+		return domain.Neighborhood{}, fmt.Errorf("depth must be between 1 and %d", MaxNeighborhoodDepth)
+	}
+	if err := checkProperties("node", props.Node, NeighborhoodNodeProperties); err != nil {
+		return domain.Neighborhood{}, err
+	}
+	if err := checkProperties("edge", props.Edge, NeighborhoodEdgeProperties); err != nil {
+		return domain.Neighborhood{}, err
+	}
+
+	res, err := r.client.ExecuteRead(ctx, componentStartCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return domain.Neighborhood{}, fmt.Errorf("neighborhood start query: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.Neighborhood{}, fmt.Errorf("neighborhood of %s: %w", userID, ErrNotFound)
+	}
+
+	start := toString(res.Records[0]["id"])
+	visited := []string{start}
+	seen := map[string]struct{}{start: {}}
+	frontier := []string{start}
+	truncated := false
+	for hop := 0; hop < depth && len(frontier) > 0 && !truncated; hop++ {
+		res, err := r.client.ExecuteRead(ctx, componentFrontierCypher, map[string]any{
+			"frontier":  frontier,
+			"visited":   visited,
+			"edgeTypes": neighborhoodEdgeTypes,
+			"limit":     neighborhoodMaxNodes - len(visited) + 1,
+		})
+		if err != nil {
+			return domain.Neighborhood{}, fmt.Errorf("neighborhood traversal query: %w", err)
+		}
+
+		frontier = frontier[:0]
+		for _, record := range res.Records {
+			id := toString(record["id"])
+			if _, ok := seen[id]; ok {
+				continue
+			}
+			if len(visited) == neighborhoodMaxNodes {
+				truncated = true
+				break
+			}
+			seen[id] = struct{}{}
+			visited = append(visited, id)
+			frontier = append(frontier, id)
+		}
+	}
+
+	res, err = r.client.ExecuteRead(ctx, neighborhoodSubgraphCypher, map[string]any{
+		"ids":       visited,
+		"edgeTypes": neighborhoodEdgeTypes,
+		"nodeProps": nonNilStrings(props.Node),
+		"edgeProps": nonNilStrings(props.Edge),
+	})
+	if err != nil {
+		return domain.Neighborhood{}, fmt.Errorf("neighborhood subgraph query: %w", err)
+	}
+
+	neighborhood := domain.Neighborhood{UserID: userID, Depth: depth, Truncated: truncated}
+	for _, record := range res.Records {
+		node, _ := record["node"].(map[string]any)
+		neighborhood.Nodes = append(neighborhood.Nodes, domain.PathNode{
+			ID:            toString(node["id"]),
+			Label:         toString(node["label"]),
+			AttributeType: toString(node["attributeType"]),
+			Properties:    decodeProjection(node["props"]),
+		})
+		edges, _ := record["edges"].([]any)
+		for _, entry := range edges {
+			edge, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			neighborhood.Edges = append(neighborhood.Edges, domain.PathEdge{
+				Type:       toString(edge["type"]),
+				Source:     toString(edge["source"]),
+				Target:     toString(edge["target"]),
+				Weight:     toFloat64(edge["weight"]),
+				Properties: decodeProjection(edge["props"]),
+			})
+		}
+	}
+	return neighborhood, nil
+}
+
+// neighborhoodSubgraphCypher returns each node in $ids with the relationships it starts
+// that end inside $ids, so every relationship is reported once. The user comes first.
+var neighborhoodSubgraphCypher = fmt.Sprintf(`
+UNWIND range(0, size($ids) - 1) AS position
+MATCH (n)
+WHERE elementId(n) = $ids[position]
+CALL {
+	WITH n
+	MATCH (n)-[rel]->(m)
+	WHERE type(rel) IN $edgeTypes
+	  AND elementId(m) IN $ids
+	RETURN collect({
+	         type: type(rel),
+	         source: %s,
+	         target: %s,
+	         weight: 1.0,
+	         props: [key IN $edgeProps WHERE rel[key] IS NOT NULL | [key, rel[key]]]
+	       }) AS edges
+}
+RETURN {
+         id: %s,
+         label: head(labels(n)),
+         attributeType: n.attributeType,
+         props: [key IN $nodeProps WHERE n[key] IS NOT NULL | [key, n[key]]]
+       } AS node,
+       edges
+ORDER BY position
+`, fmt.Sprintf(pathNodeKey, "n"), fmt.Sprintf(pathNodeKey, "m"), fmt.Sprintf(pathNodeKey, "n"))
+
+// checkProperties rejects requested properties that are not in known.
+func checkProperties(kind string, requested, known []string) error {
+	for _, key := range requested {
+		if !slices.Contains(known, key) {
+			return fmt.Errorf("unknown %s property %q; expected one of %s", kind, key, strings.Join(known, ", "))
+		}
+	}
+	return nil
+}
+
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// decodeProjection turns the [key, value] pairs of a props column into a map, or nil
+// when no property was requested or present.
+func decodeProjection(value any) map[string]any {
+	pairs, _ := value.([]any)
+	if len(pairs) == 0 {
+		return nil
+	}
+	props := make(map[string]any, len(pairs))
+	for _, entry := range pairs {
+		pair, ok := entry.([]any)
+		if !ok || len(pair) != 2 {
+			continue
+		}
+		props[toString(pair[0])] = pair[1]
+	}
+	return props
+}
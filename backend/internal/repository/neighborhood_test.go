@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// neighborhoodClient answers the neighborhood queries for USR-1 sending to USR-2,
+// projecting from the stored properties only the keys the subgraph query asks for, the
+// way the props list comprehension does.
+func neighborhoodClient() *fakeClient {
+	nodes := map[string]map[string]any{
+		"USR-1": {"riskScore": 0.9, "kycStatus": "VERIFIED", "createdAt": "2024-01-01T00:00:00Z"},
+		"USR-2": {"riskScore": 0.1, "kycStatus": "PENDING"},
+	}
+	edge := map[string]any{"amount": 120.5, "currency": "USD", "transactionId": "TX-1"}
+
+	return &fakeClient{read: func(cypher string, params map[string]any) (graph.Result, error) {
+		switch cypher {
+		case componentStartCypher:
+			return graph.Result{Records: []graph.Record{{"id": "USR-1"}}}, nil
+		case componentFrontierCypher:
+			if params["frontier"].([]string)[0] == "USR-1" {
+				return graph.Result{Records: []graph.Record{{"id": "USR-2"}}}, nil
+			}
+			return graph.Result{}, nil
+		}
+		nodeProps, edgeProps := params["nodeProps"].([]string), params["edgeProps"].([]string)
+		var recs []graph.Record
+		for _, id := range params["ids"].([]string) {
+			var edges []any
+			if id == "USR-1" {
+				edges = append(edges, map[string]any{
+					"type": "SENT_TO", "source": "USR-1", "target": "USR-2", "weight": 1.0,
+					"props": project(edge, edgeProps),
+				})
+			}
+			recs = append(recs, graph.Record{
+				"node":  map[string]any{"id": id, "label": "User", "props": project(nodes[id], nodeProps)},
+				"edges": edges,
+			})
+		}
+		return graph.Result{Records: recs}, nil
+	}}
+}
+
+// project returns the [key, value] pairs of stored for the requested keys it has.
+func project(stored map[string]any, keys []string) []any {
+	pairs := []any{}
+	for _, key := range keys {
+		if value, ok := stored[key]; ok {
+			pairs = append(pairs, []any{key, value})
+		}
+	}
+	return pairs
+}
+
+func TestExpandUserNeighborhoodProjectsRequestedProperties(t *testing.T) {
+	client := neighborhoodClient()
+	props := PropertyProjection{Node: []string{"riskScore"}, Edge: []string{"amount", "currency"}}
+	neighborhood, err := New(client).ExpandUserNeighborhood(context.Background(), "USR-1", 1, props)
+	if err != nil {
+		t.Fatalf("ExpandUserNeighborhood() error = %v", err)
+	}
+	if len(neighborhood.Nodes) != 2 || len(neighborhood.Edges) != 1 {
+		t.Fatalf("got %d nodes and %d edges, want 2 and 1", len(neighborhood.Nodes), len(neighborhood.Edges))
+	}
+
+	for _, node := range neighborhood.Nodes {
+		if _, ok := node.Properties["riskScore"]; !ok {
+			t.Errorf("node %s properties = %v, want riskScore", node.ID, node.Properties)
+		}
+		if len(node.Properties) != 1 {
+			t.Errorf("node %s properties = %v, want only riskScore", node.ID, node.Properties)
+		}
+	}
+	if got := neighborhood.Nodes[0].Properties["riskScore"]; got != 0.9 {
+		t.Errorf("USR-1 riskScore = %v, want 0.9", got)
+	}
+
+	edge := neighborhood.Edges[0]
+	if edge.Properties["amount"] != 120.5 || edge.Properties["currency"] != "USD" {
+		t.Errorf("edge properties = %v, want amount and currency", edge.Properties)
+	}
+	if _, ok := edge.Properties["transactionId"]; ok {
+		t.Errorf("edge properties = %v, want no unrequested transactionId", edge.Properties)
+	}
+
+	subgraph := client.reads[len(client.reads)-1].params
+	if got := subgraph["nodeProps"].([]string); len(got) != 1 || got[0] != "riskScore" {
+		t.Errorf("queried nodeProps %v, want [riskScore]", got)
+	}
+}
+
+func TestExpandUserNeighborhoodWithoutPropertiesReturnsKeysOnly(t *testing.T) {
+	client := neighborhoodClient()
+	neighborhood, err := New(client).ExpandUserNeighborhood(context.Background(), "USR-1", 1, PropertyProjection{})
+	if err != nil {
+		t.Fatalf("ExpandUserNeighborhood() error = %v", err)
+	}
+	for _, node := range neighborhood.Nodes {
+		if node.Properties != nil {
+			t.Errorf("node %s properties = %v, want none", node.ID, node.Properties)
+		}
+	}
+	for _, edge := range neighborhood.Edges {
+		if edge.Properties != nil {
+			t.Errorf("edge %s->%s properties = %v, want none", edge.Source, edge.Target, edge.Properties)
+		}
+	}
+
+	// The lists are empty rather than null so the comprehension yields no pairs.
+	subgraph := client.reads[len(client.reads)-1].params
+	if got, ok := subgraph["nodeProps"].([]string); !ok || got == nil {
+		t.Errorf("queried nodeProps %#v, want an empty list", subgraph["nodeProps"])
+	}
+	if got, ok := subgraph["edgeProps"].([]string); !ok || got == nil {
+		t.Errorf("queried edgeProps %#v, want an empty list", subgraph["edgeProps"])
+	}
+}
+
+func TestExpandUserNeighborhoodRejectsUnknownProperties(t *testing.T) {
+	tests := []struct {
+		name  string
+		props PropertyProjection
+		want  string
+	}{
+		{name: "node", props: PropertyProjection{Node: []string{"fullName"}}, want: "node property"},
+		{name: "edge", props: PropertyProjection{Edge: []string{"ipAddress"}}, want: "edge property"},
+	}
+	for _, tt := range tests {
+		client := neighborhoodClient()
+		_, err := New(client).ExpandUserNeighborhood(context.Background(), "USR-1", 1, tt.props)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: ExpandUserNeighborhood() error = %v, want one naming the %s", tt.name, err, tt.want)
+		}
+		if len(client.reads) != 0 {
+			t.Errorf("%s: ran %d queries, want none", tt.name, len(client.reads))
+		}
+	}
+}
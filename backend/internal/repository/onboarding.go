@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// Onboarding velocity groupings for NewUserVelocityOptions.GroupBy.
+const (
+	OnboardingGroupNone        = ""
+	OnboardingGroupEmailDomain = "emailDomain"
+	OnboardingGroupIPSubnet    = "ipSubnet"
+)
+
+const (
+	defaultOnboardingWindow      = time.Hour
+	defaultOnboardingLookback    = 7 * 24 * time.Hour
+	defaultOnboardingSpikeFactor = 3
+	defaultOnboardingMinUsers    = 5
+	maxOnboardingBuckets         = 10000
+)
+
+// NewUserVelocityOptions controls NewUserVelocity.
+type NewUserVelocityOptions struct {
+	// Window is the bucket size; defaults to one hour.
+	Window time.Duration
+	// GroupBy is OnboardingGroupNone, OnboardingGroupEmailDomain or OnboardingGroupIPSubnet.
+	GroupBy string
+	// Since and Until bound user creation times; the default range is the last 7 days.
+	Since *time.Time
+	Until *time.Time
+	// SpikeFactor flags buckets holding at least this multiple of the group's average
+	// per-window count; defaults to 3.
+	SpikeFactor float64
+	// MinUsers is the smallest count flagged as a spike; defaults to 5.
+	MinUsers int
+}
+
+// NewUserVelocity buckets user creations by window (and optional group) and flags windows
+// whose count spikes above the group's baseline, listing the users behind each spike.
+// Users without createdAt are ignored. With GroupBy ipSubnet a user counts once for each
+// /24 (IPv4) or /64 (IPv6) subnet seen on its attributes or sent transactions.
+func (r *Repository) NewUserVelocity(ctx context.Context, opts NewUserVelocityOptions) (domain.OnboardingVelocity, error) {
+	switch opts.GroupBy {
+	case OnboardingGroupNone, OnboardingGroupEmailDomain, OnboardingGroupIPSubnet:
+	default:
+		return domain.OnboardingVelocity{}, fmt.Errorf("unsupported onboarding grouping %q", opts.GroupBy)
+	}
+	window := opts.Window
+	if window <= 0 {
+		window = defaultOnboardingWindow
+	}
+	until := time.Now().UTC()
+	if opts.Until != nil {
+		until = opts.Until.UTC()
+	}
+	since := until.Add(-defaultOnboardingLookback)
+	if opts.Since != nil {
+		since = opts.Since.UTC()
+	}
+	if !since.Before(until) {
+		return domain.OnboardingVelocity{}, errors.New("since must be before until")
+	}
+	since = since.Truncate(window)
+	windows := int((until.Sub(since) + window - 1) / window)
+	if windows > maxOnboardingBuckets {
+		return domain.OnboardingVelocity{}, fmt.Errorf("range spans %d windows; at most %d are allowed", windows, maxOnboardingBuckets)
+	}
+	factor := opts.SpikeFactor
+	if factor <= 0 {
+		factor = defaultOnboardingSpikeFactor
+	}
+	minUsers := opts.MinUsers
+	if minUsers <= 0 {
+		minUsers = defaultOnboardingMinUsers
+	}
+
+	res, err := r.client.ExecuteRead(ctx, newUserVelocityCypher, map[string]any{
+		"since":   formatTime(since),
+		"until":   formatTime(until),
+		"withIPs": opts.GroupBy == OnboardingGroupIPSubnet,
+	})
+	if err != nil {
+		return domain.OnboardingVelocity{}, fmt.Errorf("new user velocity query: %w", err)
+	}
+
+	type bucketKey struct {
+		start time.Time
+		group string
+	}
+	buckets := make(map[bucketKey]*domain.OnboardingBucket)
+	groupTotals := make(map[string]int)
+	for _, record := range res.Records {
+		created := toTimePtr(record["createdAt"])
+		if created == nil {
+			continue
+		}
+		userID := toString(record["userId"])
+		for _, group := range onboardingGroups(opts.GroupBy, record) {
+			key := bucketKey{start: created.UTC().Truncate(window), group: group}
+			bucket, ok := buckets[key]
+			if !ok {
+				bucket = &domain.OnboardingBucket{Start: key.start, Group: group}
+				buckets[key] = bucket
+			}
+			bucket.Count++
+			bucket.UserIDs = append(bucket.UserIDs, userID)
+			groupTotals[group]++
+		}
+	}
+
+	velocity := domain.OnboardingVelocity{
+		Window:  window,
+		GroupBy: opts.GroupBy,
+		Since:   since,
+		Until:   until,
+		Buckets: make([]domain.OnboardingBucket, 0, len(buckets)),
+	}
+	for _, bucket := range buckets {
+		bucket.Baseline = float64(groupTotals[bucket.Group]) / float64(windows)
+		bucket.Spike = bucket.Count >= minUsers && float64(bucket.Count) >= factor*bucket.Baseline
+		if bucket.Spike {
+			sort.Strings(bucket.UserIDs)
+		} else {
+			bucket.UserIDs = nil
+		}
+		velocity.Buckets = append(velocity.Buckets, *bucket)
+	}
+	sort.Slice(velocity.Buckets, func(i, j int) bool {
+		a, b := velocity.Buckets[i], velocity.Buckets[j]
+		if !a.Start.Equal(b.Start) {
+			return a.Start.Before(b.Start)
+		}
+		return a.Group < b.Group
+	})
+	return velocity, nil
+}
+
+// onboardingGroups returns the groups a user record belongs to. Users with no value for
+// the grouping are not counted.
+func onboardingGroups(groupBy string, record map[string]any) []string {
+	switch groupBy {
+	case OnboardingGroupEmailDomain:
+		email := strings.ToLower(strings.TrimSpace(toString(record["email"])))
+		if at := strings.LastIndex(email, "@"); at >= 0 && at < len(email)-1 {
+			return []string{email[at+1:]}
+		}
+		return nil
+	case OnboardingGroupIPSubnet:
+		seen := make(map[string]struct{})
+		var groups []string
+		values, _ := record["ips"].([]any)
+		for _, value := range values {
+			subnet := ipSubnet(toString(value))
+			if _, dup := seen[subnet]; subnet == "" || dup {
+				continue
+			}
+			seen[subnet] = struct{}{}
+			groups = append(groups, subnet)
+		}
+		return groups
+	default:
+		return []string{""}
+	}
+}
+
+func ipSubnet(raw string) string {
+	ip := net.ParseIP(strings.TrimSpace(raw))
+	if ip == nil {
+		return ""
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(64, 128)), Mask: net.CIDRMask(64, 128)}).String()
+}
+
+const newUserVelocityCypher = `
+MATCH (u:User)
+WHERE u.createdAt IS NOT NULL AND u.createdAt <> ""
+  AND datetime(u.createdAt) >= datetime($since)
+  AND datetime(u.createdAt) < datetime($until)
+RETURN u.userId AS userId,
+       u.createdAt AS createdAt,
+       u.email AS email,
+       CASE WHEN $withIPs THEN COLLECT {
+         MATCH (u)-[:HAS_ATTRIBUTE]->(a:Attribute {attributeType: "IP_ADDRESS"})
+         RETURN a.rawValue AS ip
+         UNION
+         MATCH (u)-[:PARTICIPATED_IN {role: "SENDER"}]->(t:Transaction)
+         WHERE coalesce(t.ipAddress, "") <> ""
+         RETURN t.ipAddress AS ip
+       } ELSE [] END AS ips
+`
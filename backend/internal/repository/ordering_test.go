@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestExportTransactionsBreaksTimestampTies(t *testing.T) {
+	// Two transactions at the same instant, as the database returns them under the
+	// transactionId tie-break.
+	client := &fakeClient{read: records(
+		graph.Record{"transactionId": "TX-A", "timestamp": "2024-05-01T12:00:00Z"},
+		graph.Record{"transactionId": "TX-B", "timestamp": "2024-05-01T12:00:00Z"},
+	)}
+	repo := New(client)
+	for run := 0; run < 2; run++ {
+		txs, err := repo.ExportTransactions(context.Background(), ListTransactionsOptions{})
+		if err != nil {
+			t.Fatalf("ExportTransactions() error = %v", err)
+		}
+		if len(txs) != 2 || txs[0].ID != "TX-A" || txs[1].ID != "TX-B" {
+			t.Fatalf("run %d exported %+v, want TX-A then TX-B", run, txs)
+		}
+	}
+	for _, call := range client.reads {
+		if !strings.Contains(call.cypher, "ORDER BY datetime(t.timestamp) DESC, t.transactionId\n") {
+			t.Errorf("export query does not break timestamp ties on transactionId:\n%s", call.cypher)
+		}
+	}
+}
+
+func TestExportUsersOrderedByUserID(t *testing.T) {
+	client := &fakeClient{}
+	if _, err := New(client).ExportUsers(context.Background(), ListUsersOptions{}); err != nil {
+		t.Fatalf("ExportUsers() error = %v", err)
+	}
+	if !strings.Contains(client.reads[0].cypher, "ORDER BY u.userId\n") {
+		t.Errorf("export query is not ordered by the unique userId:\n%s", client.reads[0].cypher)
+	}
+}
+
+func TestListOrderClausesEndWithID(t *testing.T) {
+	for _, field := range []string{"", "fullName", "riskScore", "createdAt", "updatedAt", "unknown"} {
+		for _, order := range []string{"ASC", "DESC"} {
+			if clause := userOrderClause(field, order); !strings.HasSuffix(clause, "u.userId "+order) {
+				t.Errorf("userOrderClause(%q, %q) = %q, want userId as the last key", field, order, clause)
+			}
+		}
+	}
+	for _, field := range []string{"", "amount", "status", "type", "channel", "timestamp", "createdAt", "updatedAt", "transactionId"} {
+		for _, order := range []string{"ASC", "DESC"} {
+			if clause := transactionOrderClause(field, order); !strings.HasSuffix(clause, "t.transactionId "+order) {
+				t.Errorf("transactionOrderClause(%q, %q) = %q, want transactionId as the last key", field, order, clause)
+			}
+		}
+	}
+}
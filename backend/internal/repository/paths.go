@@ -0,0 +1,357 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+const (
+	// DefaultPathHops is the path length used when the caller does not choose one.
+	DefaultPathHops = 4
+	// MaxPathHops is the longest path searched for. Shortest-path search cost grows
+	// quickly with length through dense attribute hubs.
+	MaxPathHops = 6
+)
+
+const (
+	// DefaultAllPathsLimit is how many paths AllPathsBetweenUsers returns when the caller
+	// does not choose.
+	DefaultAllPathsLimit = 10
+	// MaxAllPathsLimit caps the paths one AllPathsBetweenUsers call returns.
+	MaxAllPathsLimit = 100
+	// MaxShortestPathsK caps k for ShortestPathsBetweenUsers.
+	MaxShortestPathsK = 10
+)
+
+// pathRelationshipTypes are the relationships paths between users may follow. Paths are
+// undirected, so SENT_TO already covers money received.
+const pathRelationshipTypes = "SENT_TO|HAS_ATTRIBUTE"
+
+// userTransactionPathTypes add PARTICIPATED_IN to pathRelationshipTypes, so a path can
+// reach a transaction through its participants as well as its attributes.
+const userTransactionPathTypes = pathRelationshipTypes + "|PARTICIPATED_IN"
+
+// WeightStrategy selects how ShortestPathBetweenUsers weighs edges.
+type WeightStrategy string
+
+const (
+	// WeightHops weighs every edge 1.0, so the shortest path has the fewest hops.
+	WeightHops WeightStrategy = "hops"
+	// WeightAmount weighs a payment edge (SENT_TO or RECEIVED_FROM) carrying amount a at
+	// 1 / (1 + ln(1 + a)), so paths along larger flows of money cost less. A payment
+	// without a positive amount, and every other edge, weighs 1.0.
+	WeightAmount WeightStrategy = "amount"
+)
+
+// WeightStrategies lists the supported weight strategies, WeightHops first.
+var WeightStrategies = []WeightStrategy{WeightHops, WeightAmount}
+
+// weightedPathCandidates caps the simple paths a WeightAmount search compares. Cypher
+// has no weighted shortest path without the GDS plugin, so the search costs candidate
+// paths up to maxHops long and keeps the cheapest.
+const weightedPathCandidates = 10000
+
+// PathPair names the two users a shortest path is computed between.
+type PathPair struct {
+	SourceUserID string
+	TargetUserID string
+}
+
+// ShortestPathBetweenUsers returns a shortest path between two users over payments and
+// shared attributes, at most maxHops relationships long, weighing edges by weighting;
+// empty means WeightHops. With WeightAmount the path minimises the summed edge weight
+// among the first weightedPathCandidates simple paths, which through dense attribute
+// hubs may miss a cheaper one. maxHops is clamped to MaxPathHops, and zero or less means
+// DefaultPathHops. The path is empty when the users are not connected within maxHops.
+// It returns ErrNotFound when either user does not exist.
+func (r *Repository) ShortestPathBetweenUsers(ctx context.Context, sourceID, targetID string, maxHops int, weighting WeightStrategy) (domain.ShortestPath, error) {
+	if sourceID == "" || targetID == "" {
+		return domain.ShortestPath{}, errors.New("source and target user ids are required")
+	}
+	if sourceID == targetID {
+		return domain.ShortestPath{}, errors.New("source and target must be different users")
+	}
+
+	target := "(target:User {userId: $targetId})"
+	params := map[string]any{"sourceUserId": sourceID, "targetId": targetID}
+	var query string
+	switch weighting {
+	case "", WeightHops:
+		query = shortestPathCypher(target, pathRelationshipTypes, clampPathHops(maxHops))
+	case WeightAmount:
+		query = cheapestPathCypher(target, pathRelationshipTypes, clampPathHops(maxHops), amountWeight)
+		params["candidates"] = weightedPathCandidates
+	default:
+		return domain.ShortestPath{}, fmt.Errorf("unsupported weight strategy %q", weighting)
+	}
+	res, err := r.client.ExecuteRead(ctx, query, params)
+	if err != nil {
+		return domain.ShortestPath{}, fmt.Errorf("shortest path query: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.ShortestPath{}, fmt.Errorf("shortest path from %s to %s: %w", sourceID, targetID, ErrNotFound)
+	}
+	return decodePath(sourceID, targetID, res.Records[0]), nil
+}
+
+// PathBetweenUserAndTransaction returns a shortest path from a user to a transaction, at
+// most maxHops relationships long, which may pass through other users, transactions and
+// shared attributes. maxHops is treated as in ShortestPathBetweenUsers. The path is empty
+// when they are not connected within maxHops. It returns ErrNotFound when the user or
+// the transaction does not exist.
+func (r *Repository) PathBetweenUserAndTransaction(ctx context.Context, userID, txID string, maxHops int) (domain.ShortestPath, error) {
+	if userID == "" || txID == "" {
+		return domain.ShortestPath{}, errors.New("user and transaction ids are required")
+	}
+
+	query := shortestPathCypher("(target:Transaction {transactionId: $targetId})", userTransactionPathTypes, clampPathHops(maxHops))
+	res, err := r.client.ExecuteRead(ctx, query, map[string]any{
+		"sourceUserId": userID,
+		"targetId":     txID,
+	})
+	if err != nil {
+		return domain.ShortestPath{}, fmt.Errorf("user to transaction path query: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.ShortestPath{}, fmt.Errorf("path from user %s to transaction %s: %w", userID, txID, ErrNotFound)
+	}
+	return decodePath(userID, txID, res.Records[0]), nil
+}
+
+// ShortestPathsBatch computes ShortestPathBetweenUsers for every pair, returning results
+// in input order. All pairs run on one graph session, repeated pairs are computed once,
+// and once ctx is done the remaining pairs fail with its error instead of running. A
+// failing pair sets Err on its own result; the returned error is only for failing to
+// open the session.
+func (r *Repository) ShortestPathsBatch(ctx context.Context, pairs []PathPair, maxHops int, weighting WeightStrategy) ([]domain.ShortestPathResult, error) {
+	bound, release, err := r.WithSession(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = release(context.Background()) }()
+
+	results := make([]domain.ShortestPathResult, len(pairs))
+	computed := make(map[PathPair]domain.ShortestPathResult, len(pairs))
+	for i, pair := range pairs {
+		result, ok := computed[pair]
+		if !ok {
+			result = domain.ShortestPathResult{SourceUserID: pair.SourceUserID, TargetUserID: pair.TargetUserID}
+			if err := ctx.Err(); err != nil {
+				result.Err = err
+			} else {
+				result.Path, result.Err = bound.ShortestPathBetweenUsers(ctx, pair.SourceUserID, pair.TargetUserID, maxHops, weighting)
+			}
+			computed[pair] = result
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// ShortestPathsBetweenUsers returns the k shortest distinct paths between two users, at
+// most DefaultPathHops long, ordered by hop count; fewer when fewer exist. It is
+// AllPathsBetweenUsers with limit k, so paths of equal length come in no particular
+// order. k is clamped to MaxShortestPathsK, and zero or less means 1.
+func (r *Repository) ShortestPathsBetweenUsers(ctx context.Context, sourceID, targetID string, k int) ([]domain.ShortestPath, error) {
+	if k <= 0 {
+		k = 1
+	}
+	if k > MaxShortestPathsK {
+		k = MaxShortestPathsK
+	}
+	return r.AllPathsBetweenUsers(ctx, sourceID, targetID, DefaultPathHops, k)
+}
+
+// AllPathsBetweenUsers returns up to limit distinct paths between two users over the
+// same relationships as ShortestPathBetweenUsers, shortest first. Paths never visit a
+// node twice, and paths through the same nodes and relationship types are returned
+// once. Paths are searched one length at a time, from 1 to maxHops, and the search
+// stops once limit paths are found, so the cost is bounded by limit rather than by the
+// number of routes through dense attribute hubs. maxHops is treated as in
+// ShortestPathBetweenUsers; limit is clamped to MaxAllPathsLimit, and zero or less means
+// DefaultAllPathsLimit. It returns ErrNotFound when either user does not exist.
+func (r *Repository) AllPathsBetweenUsers(ctx context.Context, sourceID, targetID string, maxHops, limit int) ([]domain.ShortestPath, error) {
+	if sourceID == "" || targetID == "" {
+		return nil, errors.New("source and target user ids are required")
+	}
+	if sourceID == targetID {
+		return nil, errors.New("source and target must be different users")
+	}
+	if limit <= 0 {
+		limit = DefaultAllPathsLimit
+	}
+	if limit > MaxAllPathsLimit {
+		limit = MaxAllPathsLimit
+	}
+
+	params := map[string]any{"sourceUserId": sourceID, "targetId": targetID}
+	res, err := r.client.ExecuteRead(ctx, pathEndpointsCypher, params)
+	if err != nil {
+		return nil, fmt.Errorf("path endpoints query: %w", err)
+	}
+	if len(res.Records) == 0 || toInt64(res.Records[0]["found"]) == 0 {
+		return nil, fmt.Errorf("paths from %s to %s: %w", sourceID, targetID, ErrNotFound)
+	}
+
+	var paths []domain.ShortestPath
+	for hops := 1; hops <= clampPathHops(maxHops) && len(paths) < limit; hops++ {
+		params["limit"] = limit - len(paths)
+		res, err := r.client.ExecuteRead(ctx, pathsOfLengthCypher(hops), params)
+		if err != nil {
+			return nil, fmt.Errorf("paths of length %d query: %w", hops, err)
+		}
+		for _, record := range res.Records {
+			paths = append(paths, decodePath(sourceID, targetID, record))
+		}
+	}
+	return paths, nil
+}
+
+const pathEndpointsCypher = `
+OPTIONAL MATCH (source:User {userId: $sourceUserId})
+OPTIONAL MATCH (target:User {userId: $targetId})
+RETURN CASE WHEN source IS NULL OR target IS NULL THEN 0 ELSE 1 END AS found
+`
+
+// pathsOfLengthCypher returns up to $limit distinct simple paths of exactly hops
+// relationships between the two users, in the columns decodePath reads. DISTINCT and
+// LIMIT are applied as rows stream, so matching stops once enough paths are found.
+func pathsOfLengthCypher(hops int) string {
+	return fmt.Sprintf(`
+MATCH (source:User {userId: $sourceUserId})
+MATCH (target:User {userId: $targetId})
+MATCH path = (source)-[:%s*%d]-(target)
+WHERE all(n IN nodes(path) WHERE single(m IN nodes(path) WHERE m = n))
+RETURN DISTINCT [n IN nodes(path) | {
+         id: %s,
+         label: head(labels(n)),
+         attributeType: n.attributeType
+       }] AS nodes,
+       [rel IN relationships(path) | {
+         type: type(rel),
+         source: %s,
+         target: %s,
+         weight: 1.0
+       }] AS edges
+LIMIT $limit
+`, pathRelationshipTypes, hops,
+		fmt.Sprintf(pathNodeKey, "n"),
+		fmt.Sprintf(pathNodeKey, "startNode(rel)"),
+		fmt.Sprintf(pathNodeKey, "endNode(rel)"))
+}
+
+func clampPathHops(maxHops int) int {
+	if maxHops <= 0 {
+		return DefaultPathHops
+	}
+	if maxHops > MaxPathHops {
+		return MaxPathHops
+	}
+	return maxHops
+}
+
+// pathNodeKey picks the business key of any node a path can pass through.
+const pathNodeKey = "coalesce(%[1]s.userId, %[1]s.transactionId, %[1]s.paymentMethodId, %[1]s.value)"
+
+// shortestPathCypher builds the query for a shortest path from the user $sourceUserId to
+// the node matched by target, over relTypes and at most maxHops long. maxHops must
+// already be clamped; variable-length bounds cannot be parameters. Every edge weighs
+// 1.0, so the shortest path is the one with the fewest hops.
+func shortestPathCypher(target, relTypes string, maxHops int) string {
+	return fmt.Sprintf(`
+MATCH (source:User {userId: $sourceUserId})
+MATCH %s
+OPTIONAL MATCH path = shortestPath((source)-[:%s*..%d]-(target))
+RETURN [n IN coalesce(nodes(path), []) | {
+         id: %s,
+         label: head(labels(n)),
+         attributeType: n.attributeType
+       }] AS nodes,
+       [rel IN coalesce(relationships(path), []) | {
+         type: type(rel),
+         source: %s,
+         target: %s,
+         weight: 1.0
+       }] AS edges
+`, target, relTypes, maxHops,
+		fmt.Sprintf(pathNodeKey, "n"),
+		fmt.Sprintf(pathNodeKey, "startNode(rel)"),
+		fmt.Sprintf(pathNodeKey, "endNode(rel)"))
+}
+
+// amountWeight is the Cypher expression for the WeightAmount weight of rel.
+const amountWeight = `CASE
+           WHEN type(rel) IN ["SENT_TO", "RECEIVED_FROM"] AND coalesce(rel.amount, 0.0) > 0
+           THEN 1.0 / (1.0 + log(1.0 + rel.amount))
+           ELSE 1.0
+         END`
+
+// cheapestPathCypher is shortestPathCypher for edges weighing weight, a Cypher expression
+// over rel, instead of 1.0: among at most $candidates simple paths it keeps the one with
+// the least total weight, the fewest hops breaking ties.
+func cheapestPathCypher(target, relTypes string, maxHops int, weight string) string {
+	return fmt.Sprintf(`
+MATCH (source:User {userId: $sourceUserId})
+MATCH %s
+CALL {
+	WITH source, target
+	OPTIONAL MATCH candidate = (source)-[:%s*..%d]-(target)
+	WHERE all(n IN nodes(candidate) WHERE single(m IN nodes(candidate) WHERE m = n))
+	WITH candidate LIMIT $candidates
+	WITH candidate, reduce(cost = 0.0, rel IN coalesce(relationships(candidate), []) | cost + %s) AS cost
+	ORDER BY cost, length(candidate)
+	LIMIT 1
+	RETURN candidate AS path
+}
+RETURN [n IN coalesce(nodes(path), []) | {
+         id: %s,
+         label: head(labels(n)),
+         attributeType: n.attributeType
+       }] AS nodes,
+       [rel IN coalesce(relationships(path), []) | {
+         type: type(rel),
+         source: %s,
+         target: %s,
+         weight: %s
+       }] AS edges
+`, target, relTypes, maxHops, weight,
+		fmt.Sprintf(pathNodeKey, "n"),
+		fmt.Sprintf(pathNodeKey, "startNode(rel)"),
+		fmt.Sprintf(pathNodeKey, "endNode(rel)"),
+		weight)
+}
+
+// decodePath reads the nodes and edges columns produced by shortestPathCypher.
+func decodePath(sourceID, targetID string, record graph.Record) domain.ShortestPath {
+	path := domain.ShortestPath{SourceID: sourceID, TargetID: targetID}
+	nodes, _ := record["nodes"].([]any)
+	for _, entry := range nodes {
+		node, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		path.Nodes = append(path.Nodes, domain.PathNode{
+			ID:            toString(node["id"]),
+			Label:         toString(node["label"]),
+			AttributeType: toString(node["attributeType"]),
+		})
+	}
+	edges, _ := record["edges"].([]any)
+	for _, entry := range edges {
+		edge, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		path.Edges = append(path.Edges, domain.PathEdge{
+			Type:   toString(edge["type"]),
+			Source: toString(edge["source"]),
+			Target: toString(edge["target"]),
+			Weight: toFloat64(edge["weight"]),
+		})
+	}
+	return path
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// maxFingerprintUsers caps the users returned for one fingerprint. Genuinely shared cards
+// reach a handful of users; anything near the cap is itself the finding.
+const maxFingerprintUsers = 500
+
+// UsersByPaymentFingerprint returns the users linked through USES_PAYMENT_METHOD to a
+// PaymentMethod with the given fingerprint, earliest first use first. Each user's first
+// and last use are the bounds across all of their payment methods with that fingerprint.
+// An unknown fingerprint yields no users rather than ErrNotFound.
+func (r *Repository) UsersByPaymentFingerprint(ctx context.Context, fingerprint string) (domain.SharedPaymentMethod, error) {
+	if fingerprint == "" {
+		return domain.SharedPaymentMethod{}, errors.New("fingerprint is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, usersByPaymentFingerprintCypher, map[string]any{
+		"fingerprint": fingerprint,
+		"limit":       maxFingerprintUsers + 1,
+	})
+	if err != nil {
+		return domain.SharedPaymentMethod{}, fmt.Errorf("users by payment fingerprint: %w", err)
+	}
+
+	result := domain.SharedPaymentMethod{Fingerprint: fingerprint}
+	records := res.Records
+	if len(records) > maxFingerprintUsers {
+		records = records[:maxFingerprintUsers]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		var paymentMethodIDs []string
+		ids, _ := record["paymentMethodIds"].([]any)
+		for _, id := range ids {
+			if s := toString(id); s != "" {
+				paymentMethodIDs = append(paymentMethodIDs, s)
+			}
+		}
+		result.Users = append(result.Users, domain.PaymentFingerprintUser{
+			User:             decodeUserSummary(record),
+			PaymentMethodIDs: paymentMethodIDs,
+			FirstUsedAt:      toTimePtr(record["firstUsedAt"]),
+			LastUsedAt:       toTimePtr(record["lastUsedAt"]),
+		})
+	}
+	return result, nil
+}
+
+const usersByPaymentFingerprintCypher = `
+MATCH (pm:PaymentMethod {fingerprint: $fingerprint})<-[use:USES_PAYMENT_METHOD]-(u:User)
+WITH u,
+     collect(DISTINCT pm.paymentMethodId) AS paymentMethodIds,
+     min(CASE WHEN use.firstUsedAt = "" THEN null ELSE use.firstUsedAt END) AS firstUsedAt,
+     max(CASE WHEN use.lastUsedAt = "" THEN null ELSE use.lastUsedAt END) AS lastUsedAt
+RETURN u.userId AS userId,
+       u.fullName AS fullName,
+       u.email AS email,
+       u.phone AS phone,
+       u.kycStatus AS kycStatus,
+       u.riskScore AS riskScore,
+       u.createdAt AS createdAt,
+       u.updatedAt AS updatedAt,
+       paymentMethodIds,
+       firstUsedAt,
+       lastUsedAt
+ORDER BY firstUsedAt IS NULL, firstUsedAt, u.userId
+LIMIT $limit
+`
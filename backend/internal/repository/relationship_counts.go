@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// UserRelationshipCounts returns per-type relationship counts for a user in a single
+// query. It returns ErrNotFound when the user does not exist.
+func (r *Repository) UserRelationshipCounts(ctx context.Context, userID string) (domain.UserRelationshipCounts, error) {
+	if userID == "" {
+		return domain.UserRelationshipCounts{}, errors.New("user id is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, userRelationshipCountsCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return domain.UserRelationshipCounts{}, fmt.Errorf("count user relationships: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.UserRelationshipCounts{}, fmt.Errorf("count relationships for %s: %w", userID, ErrNotFound)
+	}
+
+	record := res.Records[0]
+	counts := domain.UserRelationshipCounts{
+		UserID:       userID,
+		Outbound:     toInt64(record["outbound"]),
+		Inbound:      toInt64(record["inbound"]),
+		Transactions: toInt64(record["transactions"]),
+		Attributes:   toInt64(record["attributes"]),
+		SharedByType: make(map[string]int64),
+	}
+	if entries, ok := record["shared"].([]any); ok {
+		for _, entry := range entries {
+			item, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			if attrType := toString(item["attributeType"]); attrType != "" {
+				counts.SharedByType[attrType] = toInt64(item["users"])
+			}
+		}
+	}
+	return counts, nil
+}
+
+const userRelationshipCountsCypher = `
+MATCH (u:User {userId: $userId})
+RETURN count { (u)-[:SENT_TO]->(:User) } AS outbound,
+       count { (u)<-[:SENT_TO]-(:User) } AS inbound,
+       count { (u)-[:PARTICIPATED_IN]->(:Transaction) } AS transactions,
+       count { (u)-[:HAS_ATTRIBUTE]->(:Attribute) } AS attributes,
+       COLLECT {
+         MATCH (u)-[:HAS_ATTRIBUTE]->(a:Attribute)<-[:HAS_ATTRIBUTE]-(other:User)
+         WHERE other <> u
+         WITH a.attributeType AS attributeType, count(DISTINCT other) AS users
+         RETURN {attributeType: attributeType, users: users}
+       } AS shared
+`
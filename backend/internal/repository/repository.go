@@ -25,6 +25,16 @@ type ListUsersOptions struct {
 	EmailDomain string
 	SortField   string
 	SortOrder   string
+	// CreatedAfter and CreatedBefore bound createdAt, inclusively; either may be nil.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Cursor pages by keyset instead of Offset: the listing resumes after the opaque
+	// After token returned as NextCursor by the previous page, or starts at the top
+	// when After is empty. Only the userId and riskScore sorts support it.
+	Cursor bool
+	After  string
+	// UpdatedSince keeps users updated at or after it. Only ExportUsers applies it.
+	UpdatedSince *time.Time
 }
 
 // ListTransactionsOptions defines filters and pagination for transaction listing.
@@ -40,13 +50,89 @@ type ListTransactionsOptions struct {
 	StartTs   *time.Time
 	EndTs     *time.Time
 	Channel   string
-	SortField string
-	SortOrder string
+	Currency  string
+	// DeviceID and IPAddress match the transaction's own property exactly. With
+	// IncludeLinked, transactions sharing the DEVICE or IP_ADDRESS attribute node of a
+	// direct match are returned too.
+	DeviceID      string
+	IPAddress     string
+	IncludeLinked bool
+	// SenderRiskMin, ReceiverRiskMin and AnyParticipantRiskMin keep transactions whose
+	// sender, receiver or either participant has at least that risk score; zero disables
+	// them. Users without a risk score count as 0.
+	SenderRiskMin         float64
+	ReceiverRiskMin       float64
+	AnyParticipantRiskMin float64
+	// ParticipantKYC is ParticipantKYCNoneVerified or ParticipantKYCAnyVerified; empty disables it.
+	ParticipantKYC string
+	SortField      string
+	SortOrder      string
+	// UpdatedSince keeps transactions updated at or after it. Only ExportTransactions
+	// applies it.
+	UpdatedSince *time.Time
 }
 
+// Participant KYC filters for ListTransactionsOptions.ParticipantKYC. Participants
+// without a kycStatus count as not verified.
+const (
+	ParticipantKYCNoneVerified = "NONE_VERIFIED"
+	ParticipantKYCAnyVerified  = "ANY_VERIFIED"
+)
+
+// UserRelationshipOptions filters the relationship views returned for a user.
+type UserRelationshipOptions struct {
+	// Origin restricts shared attributes to HAS_ATTRIBUTE edges with the given origin
+	// (USER, TRANSACTION or DERIVED). Empty matches every origin.
+	Origin string
+	// Limit caps the rows returned per section; zero or values above the repository's
+	// section cap use the cap.
+	Limit int
+	// Direction restricts direct links to DirectionOutbound (SENT_TO from the user) or
+	// DirectionInbound (SENT_TO towards it, reported as RECEIVED_FROM). Empty or
+	// DirectionBoth returns both.
+	Direction string
+}
+
+// Direct link directions for UserRelationshipOptions.Direction.
+const (
+	DirectionBoth     = "both"
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// directLinkTypes maps a direction filter to the link types it matches, as reported by
+// userDirectLinksCypher.
+func directLinkTypes(direction string) []string {
+	switch strings.ToLower(direction) {
+	case DirectionOutbound:
+		return []string{"SENT_TO"}
+	case DirectionInbound:
+		return []string{"RECEIVED_FROM"}
+	default:
+		return []string{"SENT_TO", "RECEIVED_FROM"}
+	}
+}
+
+// TransactionRelationshipOptions filters the relationship views returned for a transaction.
+type TransactionRelationshipOptions struct {
+	// Limit caps the rows returned per section, as in UserRelationshipOptions.
+	Limit int
+}
+
+// DefaultRelationshipSectionCap bounds every relationship section when no cap is configured.
+const DefaultRelationshipSectionCap = 1000
+
 // Repository encapsulates graph persistence operations.
 type Repository struct {
-	client graph.Client
+	client         graph.Client
+	duplicates     DuplicateTransactionOptions
+	sectionCap     int
+	contentHashing bool
+	// promotedMetadata lists transaction metadata keys stored as native properties.
+	promotedMetadata map[string]struct{}
+	linkDecay        LinkDecay
+	// receivedFromEdges keeps writing the redundant RECEIVED_FROM edges.
+	receivedFromEdges bool
 }
 
 // New instantiates a Repository backed by the supplied graph client.
@@ -54,53 +140,182 @@ func New(client graph.Client) *Repository {
 	return &Repository{client: client}
 }
 
+// WithSession returns a Repository whose queries all run on one graph session, and a
+// function that releases it. Bulk writers use it to avoid opening a session per write.
+// When the client cannot open sessions, r itself is returned with a no-op release.
+// The returned Repository must not be shared between goroutines.
+func (r *Repository) WithSession(ctx context.Context) (*Repository, func(context.Context) error, error) {
+	opener, ok := r.client.(graph.SessionOpener)
+	if !ok {
+		return r, func(context.Context) error { return nil }, nil
+	}
+	session, err := opener.OpenSession(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("open graph session: %w", err)
+	}
+	client := graph.NewSessionClient(r.client, session)
+	bound := New(client).
+		WithDuplicateTransactions(r.duplicates).
+		WithRelationshipSectionCap(r.sectionCap).
+		WithContentHashing(r.contentHashing).
+		WithLinkDecay(r.linkDecay).
+		WithReceivedFromEdges(r.receivedFromEdges)
+	bound.promotedMetadata = r.promotedMetadata
+	return bound, client.Close, nil
+}
+
+// WithReceivedFromEdges makes transaction upserts also write a RECEIVED_FROM edge from
+// receiver to sender beside every SENT_TO edge, as they did before reads began deriving
+// the inbound direction by traversing SENT_TO backwards. Reads accept either edge, so
+// graphs written with or without the flag give the same answers; the flag only keeps
+// the legacy edge for external tools that still read it. Returns the repository for
+// chaining.
+func (r *Repository) WithReceivedFromEdges(enabled bool) *Repository {
+	r.receivedFromEdges = enabled
+	return r
+}
+
+// WithRelationshipSectionCap sets the hard per-section row cap for relationship views and
+// returns the repository for chaining. Zero or less uses DefaultRelationshipSectionCap.
+func (r *Repository) WithRelationshipSectionCap(limit int) *Repository {
+	r.sectionCap = limit
+	return r
+}
+
+// sectionLimits resolves the requested per-section limit against the cap.
+func (r *Repository) sectionLimits(requested int) domain.SectionLimits {
+	limitCap := r.sectionCap
+	if limitCap <= 0 {
+		limitCap = DefaultRelationshipSectionCap
+	}
+	limit := requested
+	if limit <= 0 || limit > limitCap {
+		limit = limitCap
+	}
+	return domain.SectionLimits{Limit: limit, Cap: limitCap}
+}
+
+// sectionParams adds the limit parameters consumed by the relationship section queries.
+// One extra row is fetched so truncation can be detected.
+func sectionParams(params map[string]any, limits domain.SectionLimits) map[string]any {
+	params["limit"] = limits.Limit
+	params["fetch"] = limits.Limit + 1
+	return params
+}
+
 // UpsertUser ensures a user node exists with the latest metadata and attribute edges.
 func (r *Repository) UpsertUser(ctx context.Context, user domain.User) error {
 	if user.ID == "" {
 		return errors.New("user id is required")
 	}
 
-	params := map[string]any{
-		"userId":         user.ID,
-		"props":          userProperties(user),
-		"attributes":     attributeParams(user.Attributes),
-		"paymentMethods": paymentMethodParams(user.PaymentMethods),
+	// Writes made without hashing clear any stored hash, so a later hashed re-ingest of
+	// the old content is not mistaken for unchanged.
+	params := userWriteParams(user)
+	props := params["props"].(map[string]any)
+	if r.contentHashing {
+		hash, err := userContentHash(user, params)
+		if err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.ID, err)
+		}
+		same, err := r.unchanged(ctx, userContentHashCypher, map[string]any{"userId": user.ID}, hash)
+		if err != nil {
+			return fmt.Errorf("upsert user %s: %w", user.ID, err)
+		}
+		if same {
+			return ErrUnchanged
+		}
+		props[contentHashProperty] = hash
 	}
 
 	_, err := r.client.ExecuteWrite(ctx, upsertUserCypher, params)
 	if err != nil {
-		return fmt.Errorf("upsert user %s: %w", user.ID, err)
+		return fmt.Errorf("upsert user %s: %w", user.ID, classifyError(err))
 	}
 	return nil
 }
 
 // UpsertTransaction ensures a transaction node exists and all relationships are refreshed.
 func (r *Repository) UpsertTransaction(ctx context.Context, tx domain.Transaction, attributes []domain.Attribute) error {
+	row, err := r.transactionWriteRow(ctx, tx, attributes)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.ExecuteWrite(ctx, upsertTransactionCypher, map[string]any{
+		"transaction":       row,
+		"linkDecayHalfLife": r.linkDecayHalfLife(true),
+		"receivedFromEdges": r.receivedFromEdges,
+	})
+	if err != nil {
+		return fmt.Errorf("upsert transaction %s: %w", tx.ID, classifyError(err))
+	}
+
+	return nil
+}
+
+// transactionWriteRow validates tx and resolves content hashing and duplicate handling
+// for it, returning the values upsertTransactionBody reads from its row. It returns
+// ErrUnchanged when the stored content hash matches.
+func (r *Repository) transactionWriteRow(ctx context.Context, tx domain.Transaction, attributes []domain.Attribute) (map[string]any, error) {
 	if tx.ID == "" {
-		return errors.New("transaction id is required")
+		return nil, errors.New("transaction id is required")
+	}
+	if tx.SenderUserID == "" && !tx.Monetary {
+		return nil, errors.New("sender user ID is required")
 	}
-	if tx.SenderUserID == "" || tx.ReceiverUserID == "" {
-		return errors.New("both sender and receiver user IDs are required")
+	if tx.SenderUserID == "" || (tx.Monetary && tx.ReceiverUserID == "") {
+		return nil, errors.New("both sender and receiver user IDs are required")
 	}
 
-	params := map[string]any{
+	// The hash is taken before duplicate handling so a replayed transaction is skipped
+	// rather than rejected or stored as a new version.
+	var hash string
+	if r.contentHashing {
+		var err error
+		hash, err = r.transactionContentHash(tx, attributes)
+		if err != nil {
+			return nil, fmt.Errorf("upsert transaction %s: %w", tx.ID, err)
+		}
+		same, err := r.unchanged(ctx, transactionContentHashCypher, map[string]any{"transactionId": tx.ID}, hash)
+		if err != nil {
+			return nil, fmt.Errorf("upsert transaction %s: %w", tx.ID, err)
+		}
+		if same {
+			return nil, ErrUnchanged
+		}
+	}
+
+	originalID, err := r.resolveDuplicate(ctx, &tx)
+	if err != nil {
+		return nil, fmt.Errorf("upsert transaction %s: %w", tx.ID, err)
+	}
+	props := transactionProperties(tx, r.promotedMetadata)
+	if originalID != "" {
+		props["originalTransactionId"] = originalID
+	}
+	props[contentHashProperty] = nil
+	if hash != "" {
+		props[contentHashProperty] = hash
+	}
+
+	var amount, currency any
+	if tx.Monetary {
+		amount, currency = tx.Amount, tx.Currency
+	}
+	return map[string]any{
 		"transactionId":   tx.ID,
 		"senderId":        tx.SenderUserID,
 		"receiverId":      tx.ReceiverUserID,
-		"amount":          tx.Amount,
-		"currency":        tx.Currency,
+		"monetary":        tx.Monetary,
+		"amount":          amount,
+		"currency":        currency,
 		"timestamp":       formatTime(tx.Timestamp),
-		"props":           transactionProperties(tx),
+		"props":           props,
 		"attributes":      attributeParams(attributes),
 		"paymentMethodId": tx.PaymentMethodID,
-	}
-
-	_, err := r.client.ExecuteWrite(ctx, upsertTransactionCypher, params)
-	if err != nil {
-		return fmt.Errorf("upsert transaction %s: %w", tx.ID, err)
-	}
-
-	return nil
+		"reversalOf":      tx.ReversalOf,
+	}, nil
 }
 
 // ListUsers returns paginated users matching provided filters.
@@ -117,28 +332,33 @@ func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) (doma
 		offset = 0
 	}
 
-	search := strings.ToLower(strings.TrimSpace(opts.Search))
-	country := strings.ToLower(strings.TrimSpace(opts.Country))
-	city := strings.ToLower(strings.TrimSpace(opts.City))
-	emailDomain := strings.TrimSpace(opts.EmailDomain)
-	if emailDomain != "" {
-		emailDomain = strings.ToLower(strings.TrimPrefix(emailDomain, "@"))
-		emailDomain = "@" + emailDomain
-	}
+	params := userFilterParams(opts)
+	params["skip"] = offset
+	params["limit"] = limit
 
-	params := map[string]any{
-		"kycStatus":   strings.ToUpper(strings.TrimSpace(opts.KYCStatus)),
-		"riskMin":     opts.RiskMin,
-		"riskMax":     opts.RiskMax,
-		"search":      search,
-		"country":     country,
-		"city":        city,
-		"emailDomain": emailDomain,
-		"skip":        offset,
-		"limit":       limit,
+	filter := userFilterClause
+	var sortField, sortOrder string
+	if opts.Cursor {
+		var ok bool
+		if sortField, ok = cursorSortField(opts.SortField); !ok {
+			return domain.UserListResult{}, fmt.Errorf("%w: sortField %s does not support cursors", ErrInvalidCursor, opts.SortField)
+		}
+		sortOrder = cursorSortOrder(opts.SortOrder)
+		params["skip"] = 0
+		if opts.After != "" {
+			cursor, err := decodeUserCursor(opts.After, sortField, sortOrder)
+			if err != nil {
+				return domain.UserListResult{}, err
+			}
+			params["afterId"] = cursor.UserID
+			if cursor.SortKey != nil {
+				params["afterKey"] = *cursor.SortKey
+			}
+			filter += userCursorPredicate(sortField, sortOrder)
+		}
 	}
 
-	query := fmt.Sprintf(listUsersCypherTemplate, userFilterClause, userOrderClause(opts.SortField, opts.SortOrder))
+	query := fmt.Sprintf(listUsersCypherTemplate, filter, userOrderClause(opts.SortField, opts.SortOrder))
 	res, err := r.client.ExecuteRead(ctx, query, params)
 	if err != nil {
 		return domain.UserListResult{}, fmt.Errorf("list users query: %w", err)
@@ -146,21 +366,7 @@ func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) (doma
 
 	var users []domain.UserSummary
 	for _, record := range res.Records {
-		item := domain.UserSummary{
-			ID:        toString(record["userId"]),
-			FullName:  toString(record["fullName"]),
-			Email:     toString(record["email"]),
-			Phone:     toString(record["phone"]),
-			KYCStatus: toString(record["kycStatus"]),
-			RiskScore: toFloat64(record["riskScore"]),
-		}
-		if created := toTimePtr(record["createdAt"]); created != nil {
-			item.CreatedAt = *created
-		}
-		if updated := toTimePtr(record["updatedAt"]); updated != nil {
-			item.UpdatedAt = *updated
-		}
-		users = append(users, item)
+		users = append(users, decodeUserSummary(record))
 	}
 
 	countQuery := fmt.Sprintf(countUsersCypherTemplate, userFilterClause)
@@ -181,10 +387,18 @@ func (r *Repository) ListUsers(ctx context.Context, opts ListUsersOptions) (doma
 		}
 	}
 
-	return domain.UserListResult{
+	result := domain.UserListResult{
 		Items: users,
 		Total: total,
-	}, nil
+	}
+	if opts.Cursor && len(users) == limit {
+		next, err := encodeUserCursor(sortField, sortOrder, users[len(users)-1])
+		if err != nil {
+			return domain.UserListResult{}, err
+		}
+		result.NextCursor = next
+	}
+	return result, nil
 }
 
 // ListTransactions returns paginated transactions matching provided filters.
@@ -201,29 +415,9 @@ func (r *Repository) ListTransactions(ctx context.Context, opts ListTransactions
 		offset = 0
 	}
 
-	search := strings.ToLower(strings.TrimSpace(opts.Search))
-	start := ""
-	end := ""
-	if opts.StartTs != nil && !opts.StartTs.IsZero() {
-		start = opts.StartTs.UTC().Format(time.RFC3339)
-	}
-	if opts.EndTs != nil && !opts.EndTs.IsZero() {
-		end = opts.EndTs.UTC().Format(time.RFC3339)
-	}
-
-	params := map[string]any{
-		"userId":    strings.TrimSpace(opts.UserID),
-		"status":    strings.ToUpper(strings.TrimSpace(opts.Status)),
-		"type":      strings.ToUpper(strings.TrimSpace(opts.Type)),
-		"minAmount": opts.MinAmount,
-		"maxAmount": opts.MaxAmount,
-		"search":    search,
-		"skip":      offset,
-		"limit":     limit,
-		"startTs":   start,
-		"endTs":     end,
-		"channel":   strings.ToUpper(strings.TrimSpace(opts.Channel)),
-	}
+	params := transactionFilterParams(opts)
+	params["skip"] = offset
+	params["limit"] = limit
 
 	orderClause := transactionOrderClause(opts.SortField, opts.SortOrder)
 	query := fmt.Sprintf(listTransactionsCypherTemplate, transactionFilterClause, orderClause)
@@ -234,26 +428,7 @@ func (r *Repository) ListTransactions(ctx context.Context, opts ListTransactions
 
 	var txs []domain.TransactionSummary
 	for _, record := range res.Records {
-		item := domain.TransactionSummary{
-			ID:             toString(record["transactionId"]),
-			SenderUserID:   toString(record["senderId"]),
-			ReceiverUserID: toString(record["receiverId"]),
-			Amount:         toFloat64(record["amount"]),
-			Currency:       toString(record["currency"]),
-			Type:           toString(record["type"]),
-			Status:         toString(record["status"]),
-			Channel:        toString(record["channel"]),
-		}
-		if ts := toTimePtr(record["timestamp"]); ts != nil {
-			item.Timestamp = *ts
-		}
-		if created := toTimePtr(record["createdAt"]); created != nil {
-			item.CreatedAt = *created
-		}
-		if updated := toTimePtr(record["updatedAt"]); updated != nil {
-			item.UpdatedAt = *updated
-		}
-		txs = append(txs, item)
+		txs = append(txs, decodeTransactionSummary(record))
 	}
 
 	countQuery := fmt.Sprintf(countTransactionsCypherTemplate, transactionFilterClause)
@@ -281,36 +456,48 @@ func (r *Repository) ListTransactions(ctx context.Context, opts ListTransactions
 }
 
 // FetchUserRelationships returns a consolidated view of a user's relationships.
-func (r *Repository) FetchUserRelationships(ctx context.Context, userID string) (domain.UserRelationships, error) {
+func (r *Repository) FetchUserRelationships(ctx context.Context, userID string, opts UserRelationshipOptions) (domain.UserRelationships, error) {
 	if userID == "" {
 		return domain.UserRelationships{}, errors.New("user id is required")
 	}
 
 	relationships := domain.UserRelationships{
 		UserID: userID,
+		Limits: r.sectionLimits(opts.Limit),
 	}
 
-	if err := r.fetchUserDirectLinks(ctx, userID, &relationships); err != nil {
+	if err := r.fetchUserDirectLinks(ctx, userID, opts.Direction, &relationships); err != nil {
 		return domain.UserRelationships{}, err
 	}
 	if err := r.fetchUserTransactions(ctx, userID, &relationships); err != nil {
 		return domain.UserRelationships{}, err
 	}
-	if err := r.fetchUserSharedAttributes(ctx, userID, &relationships); err != nil {
+	if err := r.fetchUserSharedAttributes(ctx, userID, opts.Origin, &relationships); err != nil {
 		return domain.UserRelationships{}, err
 	}
 
 	return relationships, nil
 }
 
-// FetchTransactionRelationships returns relationships for a given transaction.
-func (r *Repository) FetchTransactionRelationships(ctx context.Context, txID string) (domain.TransactionRelationships, error) {
+// FetchTransactionRelationships returns relationships for a given transaction. It returns
+// ErrNotFound when the transaction does not exist; an existing transaction without links
+// yields empty sections.
+func (r *Repository) FetchTransactionRelationships(ctx context.Context, txID string, opts TransactionRelationshipOptions) (domain.TransactionRelationships, error) {
 	if txID == "" {
 		return domain.TransactionRelationships{}, errors.New("transaction id is required")
 	}
 
 	result := domain.TransactionRelationships{
 		TransactionID: txID,
+		Limits:        r.sectionLimits(opts.Limit),
+	}
+
+	exists, err := r.client.ExecuteRead(ctx, transactionExistsCypher, map[string]any{"transactionId": txID})
+	if err != nil {
+		return domain.TransactionRelationships{}, fmt.Errorf("check transaction exists: %w", err)
+	}
+	if len(exists.Records) == 0 {
+		return domain.TransactionRelationships{}, fmt.Errorf("transaction %s: %w", txID, ErrNotFound)
 	}
 
 	if err := r.fetchTransactionUsers(ctx, txID, &result); err != nil {
@@ -319,19 +506,101 @@ func (r *Repository) FetchTransactionRelationships(ctx context.Context, txID str
 	if err := r.fetchLinkedTransactions(ctx, txID, &result); err != nil {
 		return domain.TransactionRelationships{}, err
 	}
+	if err := r.fetchReversalChain(ctx, txID, &result); err != nil {
+		return domain.TransactionRelationships{}, err
+	}
 
 	return result, nil
 }
 
-func (r *Repository) fetchUserDirectLinks(ctx context.Context, userID string, rel *domain.UserRelationships) error {
-	res, err := r.client.ExecuteRead(ctx, userDirectLinksCypher, map[string]any{
-		"userId": userID,
-	})
+// BatchOptions controls batched maintenance operations.
+type BatchOptions struct {
+	// BatchSize bounds how many nodes are deleted per write transaction.
+	BatchSize int
+	// OnBatch, when set, is invoked after each batch with the label processed and the number affected.
+	OnBatch func(label string, removed int)
+}
+
+// PruneOrphans detach-deletes Attribute and PaymentMethod nodes that no longer have relationships.
+func (r *Repository) PruneOrphans(ctx context.Context) (int, error) {
+	return r.PruneOrphansWithOptions(ctx, BatchOptions{})
+}
+
+// PruneOrphansWithOptions removes orphaned nodes in bounded batches and returns the total removed.
+func (r *Repository) PruneOrphansWithOptions(ctx context.Context, opts BatchOptions) (int, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMaintenanceBatchSize
+	}
+
+	total := 0
+	for _, label := range []string{"Attribute", "PaymentMethod"} {
+		query := fmt.Sprintf(pruneOrphansCypherTemplate, label)
+		for {
+			if err := ctx.Err(); err != nil {
+				return total, err
+			}
+			res, err := r.client.ExecuteWrite(ctx, query, map[string]any{"batchSize": batchSize})
+			if err != nil {
+				return total, fmt.Errorf("prune orphaned %s nodes: %w", label, err)
+			}
+			removed := 0
+			if len(res.Records) > 0 {
+				removed = int(toInt64(res.Records[0]["removed"]))
+			}
+			total += removed
+			if opts.OnBatch != nil && removed > 0 {
+				opts.OnBatch(label, removed)
+			}
+			if removed < batchSize {
+				break
+			}
+		}
+	}
+	return total, nil
+}
+
+// BackfillAttributeOrigins sets origin on HAS_ATTRIBUTE edges written before origins were tracked.
+// Edges from users become USER, TX_DAY_BUCKET edges become DERIVED and other transaction edges TRANSACTION.
+func (r *Repository) BackfillAttributeOrigins(ctx context.Context, opts BatchOptions) (int, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMaintenanceBatchSize
+	}
+
+	total := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		res, err := r.client.ExecuteWrite(ctx, backfillAttributeOriginsCypher, map[string]any{"batchSize": batchSize})
+		if err != nil {
+			return total, fmt.Errorf("backfill attribute origins: %w", err)
+		}
+		updated := 0
+		if len(res.Records) > 0 {
+			updated = int(toInt64(res.Records[0]["updated"]))
+		}
+		total += updated
+		if opts.OnBatch != nil && updated > 0 {
+			opts.OnBatch("HAS_ATTRIBUTE", updated)
+		}
+		if updated < batchSize {
+			return total, nil
+		}
+	}
+}
+
+func (r *Repository) fetchUserDirectLinks(ctx context.Context, userID, direction string, rel *domain.UserRelationships) error {
+	res, err := r.client.ExecuteRead(ctx, userDirectLinksCypher, sectionParams(map[string]any{
+		"userId":    userID,
+		"linkTypes": directLinkTypes(direction),
+	}, rel.Limits))
 	if err != nil {
 		return fmt.Errorf("fetch user direct links: %w", err)
 	}
 
-	for _, record := range res.Records {
+	for _, record := range truncateSection(res.Records, "directConnections", &rel.Limits) {
 		link := domain.DirectUserLink{
 			UserID:        toString(record["peerId"]),
 			LinkType:      toString(record["linkType"]),
@@ -350,14 +619,14 @@ func (r *Repository) fetchUserDirectLinks(ctx context.Context, userID string, re
 }
 
 func (r *Repository) fetchUserTransactions(ctx context.Context, userID string, rel *domain.UserRelationships) error {
-	res, err := r.client.ExecuteRead(ctx, userTransactionsCypher, map[string]any{
+	res, err := r.client.ExecuteRead(ctx, userTransactionsCypher, sectionParams(map[string]any{
 		"userId": userID,
-	})
+	}, rel.Limits))
 	if err != nil {
 		return fmt.Errorf("fetch user transactions: %w", err)
 	}
 
-	for _, record := range res.Records {
+	for _, record := range truncateSection(res.Records, "transactions", &rel.Limits) {
 		link := domain.UserTransactionLink{
 			TransactionID: toString(record["transactionId"]),
 			Role:          toString(record["role"]),
@@ -372,19 +641,24 @@ func (r *Repository) fetchUserTransactions(ctx context.Context, userID string, r
 	return nil
 }
 
-func (r *Repository) fetchUserSharedAttributes(ctx context.Context, userID string, rel *domain.UserRelationships) error {
-	res, err := r.client.ExecuteRead(ctx, userSharedAttributesCypher, map[string]any{
+func (r *Repository) fetchUserSharedAttributes(ctx context.Context, userID, origin string, rel *domain.UserRelationships) error {
+	res, err := r.client.ExecuteRead(ctx, userSharedAttributesCypher, sectionParams(map[string]any{
 		"userId": userID,
-	})
+		"origin": strings.ToUpper(strings.TrimSpace(origin)),
+	}, rel.Limits))
 	if err != nil {
 		return fmt.Errorf("fetch shared attributes: %w", err)
 	}
 
-	for _, record := range res.Records {
+	usersTruncated := false
+	for _, record := range truncateSection(res.Records, "sharedAttributes", &rel.Limits) {
 		usersRaw, ok := record["userIds"].([]any)
 		if !ok {
 			continue
 		}
+		if record["usersTruncated"] == true {
+			usersTruncated = true
+		}
 		var userIDs []string
 		for _, u := range usersRaw {
 			if s := toString(u); s != "" {
@@ -397,18 +671,21 @@ func (r *Repository) fetchUserSharedAttributes(ctx context.Context, userID strin
 			UserIDs:       userIDs,
 		})
 	}
+	if usersTruncated {
+		rel.Limits.TruncatedSections = append(rel.Limits.TruncatedSections, "connectedUsers")
+	}
 	return nil
 }
 
 func (r *Repository) fetchTransactionUsers(ctx context.Context, txID string, rel *domain.TransactionRelationships) error {
-	res, err := r.client.ExecuteRead(ctx, transactionUsersCypher, map[string]any{
+	res, err := r.client.ExecuteRead(ctx, transactionUsersCypher, sectionParams(map[string]any{
 		"transactionId": txID,
-	})
+	}, rel.Limits))
 	if err != nil {
 		return fmt.Errorf("fetch transaction users: %w", err)
 	}
 
-	for _, record := range res.Records {
+	for _, record := range truncateSection(res.Records, "users", &rel.Limits) {
 		rel.Users = append(rel.Users, domain.TransactionUserLink{
 			UserID:    toString(record["userId"]),
 			Role:      toString(record["role"]),
@@ -421,19 +698,20 @@ func (r *Repository) fetchTransactionUsers(ctx context.Context, txID string, rel
 }
 
 func (r *Repository) fetchLinkedTransactions(ctx context.Context, txID string, rel *domain.TransactionRelationships) error {
-	res, err := r.client.ExecuteRead(ctx, transactionLinkedCypher, map[string]any{
+	res, err := r.client.ExecuteRead(ctx, transactionLinkedCypher, sectionParams(map[string]any{
 		"transactionId": txID,
-	})
+	}, rel.Limits))
 	if err != nil {
 		return fmt.Errorf("fetch linked transactions: %w", err)
 	}
 
-	for _, record := range res.Records {
+	for _, record := range truncateSection(res.Records, "linkedTransactions", &rel.Limits) {
 		link := domain.LinkedTransaction{
 			TransactionID: toString(record["otherTransactionId"]),
 			LinkType:      toString(record["linkType"]),
 			AttributeHash: toString(record["attributeHash"]),
 			Score:         toFloat64(record["score"]),
+			DecayedScore:  toFloat64Ptr(record["decayedScore"]),
 		}
 		if ts := toTimePtr(record["updatedAt"]); ts != nil {
 			link.LastUpdated = ts
@@ -443,6 +721,123 @@ func (r *Repository) fetchLinkedTransactions(ctx context.Context, txID string, r
 	return nil
 }
 
+// truncateSection drops the probe row fetched beyond the limit and records the section
+// as truncated when it was present.
+func truncateSection(records []graph.Record, section string, limits *domain.SectionLimits) []graph.Record {
+	if len(records) <= limits.Limit {
+		return records
+	}
+	limits.TruncatedSections = append(limits.TruncatedSections, section)
+	return records[:limits.Limit]
+}
+
+// userFilterParams builds the query parameters consumed by userFilterClause.
+func userFilterParams(opts ListUsersOptions) map[string]any {
+	search := strings.ToLower(strings.TrimSpace(opts.Search))
+	country := strings.ToLower(strings.TrimSpace(opts.Country))
+	city := strings.ToLower(strings.TrimSpace(opts.City))
+	emailDomain := strings.TrimSpace(opts.EmailDomain)
+	if emailDomain != "" {
+		emailDomain = strings.ToLower(strings.TrimPrefix(emailDomain, "@"))
+		emailDomain = "@" + emailDomain
+	}
+
+	createdAfter := ""
+	createdBefore := ""
+	if opts.CreatedAfter != nil && !opts.CreatedAfter.IsZero() {
+		createdAfter = opts.CreatedAfter.UTC().Format(time.RFC3339)
+	}
+	if opts.CreatedBefore != nil && !opts.CreatedBefore.IsZero() {
+		createdBefore = opts.CreatedBefore.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"kycStatus":     strings.ToUpper(strings.TrimSpace(opts.KYCStatus)),
+		"riskMin":       opts.RiskMin,
+		"riskMax":       opts.RiskMax,
+		"search":        search,
+		"country":       country,
+		"city":          city,
+		"emailDomain":   emailDomain,
+		"createdAfter":  createdAfter,
+		"createdBefore": createdBefore,
+	}
+}
+
+// transactionFilterParams builds the query parameters consumed by transactionFilterClause.
+func transactionFilterParams(opts ListTransactionsOptions) map[string]any {
+	search := strings.ToLower(strings.TrimSpace(opts.Search))
+	start := ""
+	end := ""
+	if opts.StartTs != nil && !opts.StartTs.IsZero() {
+		start = opts.StartTs.UTC().Format(time.RFC3339)
+	}
+	if opts.EndTs != nil && !opts.EndTs.IsZero() {
+		end = opts.EndTs.UTC().Format(time.RFC3339)
+	}
+
+	return map[string]any{
+		"userId":                strings.TrimSpace(opts.UserID),
+		"status":                strings.ToUpper(strings.TrimSpace(opts.Status)),
+		"type":                  strings.ToUpper(strings.TrimSpace(opts.Type)),
+		"minAmount":             opts.MinAmount,
+		"maxAmount":             opts.MaxAmount,
+		"search":                search,
+		"startTs":               start,
+		"endTs":                 end,
+		"channel":               strings.ToUpper(strings.TrimSpace(opts.Channel)),
+		"currency":              strings.ToUpper(strings.TrimSpace(opts.Currency)),
+		"deviceId":              strings.TrimSpace(opts.DeviceID),
+		"ipAddress":             strings.TrimSpace(opts.IPAddress),
+		"includeLinked":         opts.IncludeLinked,
+		"senderRiskMin":         opts.SenderRiskMin,
+		"receiverRiskMin":       opts.ReceiverRiskMin,
+		"anyParticipantRiskMin": opts.AnyParticipantRiskMin,
+		"participantKyc":        strings.ToUpper(strings.TrimSpace(opts.ParticipantKYC)),
+	}
+}
+
+func decodeUserSummary(record graph.Record) domain.UserSummary {
+	item := domain.UserSummary{
+		ID:        toString(record["userId"]),
+		FullName:  toString(record["fullName"]),
+		Email:     toString(record["email"]),
+		Phone:     toString(record["phone"]),
+		KYCStatus: toString(record["kycStatus"]),
+		RiskScore: toFloat64(record["riskScore"]),
+	}
+	if created := toTimePtr(record["createdAt"]); created != nil {
+		item.CreatedAt = *created
+	}
+	if updated := toTimePtr(record["updatedAt"]); updated != nil {
+		item.UpdatedAt = *updated
+	}
+	return item
+}
+
+func decodeTransactionSummary(record graph.Record) domain.TransactionSummary {
+	item := domain.TransactionSummary{
+		ID:             toString(record["transactionId"]),
+		SenderUserID:   toString(record["senderId"]),
+		ReceiverUserID: toString(record["receiverId"]),
+		Amount:         toFloat64(record["amount"]),
+		Currency:       toString(record["currency"]),
+		Type:           toString(record["type"]),
+		Status:         toString(record["status"]),
+		Channel:        toString(record["channel"]),
+	}
+	if ts := toTimePtr(record["timestamp"]); ts != nil {
+		item.Timestamp = *ts
+	}
+	if created := toTimePtr(record["createdAt"]); created != nil {
+		item.CreatedAt = *created
+	}
+	if updated := toTimePtr(record["updatedAt"]); updated != nil {
+		item.UpdatedAt = *updated
+	}
+	return item
+}
+
 func userProperties(u domain.User) map[string]any {
 	props := map[string]any{
 		"fullName":  u.FullName,
@@ -460,6 +855,8 @@ func userProperties(u domain.User) map[string]any {
 		props["dateOfBirth"] = formatTime(*u.DateOfBirth)
 	}
 
+	// The address is stored flat, as node properties cannot hold maps; userFilterClause
+	// and the user reads use the same addressX names.
 	props["addressLine1"] = u.Address.Line1
 	props["addressLine2"] = u.Address.Line2
 	props["addressCity"] = u.Address.City
@@ -497,29 +894,41 @@ func attributeParams(attrs []domain.Attribute) []map[string]any {
 			"rawValue":   attr.RawValue,
 			"confidence": attr.ConfidenceScore,
 			"score":      attr.ConfidenceScore,
+			"origin":     attr.Origin,
 		})
 	}
 	return result
 }
 
-func transactionProperties(tx domain.Transaction) map[string]any {
+func transactionProperties(tx domain.Transaction, promotedMetadata map[string]struct{}) map[string]any {
 	props := map[string]any{
 		"amount":          tx.Amount,
 		"currency":        tx.Currency,
+		"monetary":        tx.Monetary,
 		"type":            tx.Type,
 		"status":          tx.Status,
 		"channel":         tx.Channel,
 		"ipAddress":       tx.IPAddress,
 		"deviceId":        tx.DeviceID,
 		"paymentMethodId": tx.PaymentMethodID,
+		"originatorBic":   tx.OriginatorBIC,
+		"beneficiaryBic":  tx.BeneficiaryBIC,
 		"timestamp":       formatTime(tx.Timestamp),
 		"updatedAt":       formatTime(tx.UpdatedAt),
 	}
 
-	if len(tx.Metadata) > 0 {
-		if serialized, err := serializeMetadata(tx.Metadata); err == nil {
-			props["metadataJson"] = serialized
-		}
+	if !tx.Monetary {
+		// Setting null through += removes any amount left by an earlier monetary write.
+		props["amount"] = nil
+		props["currency"] = nil
+	}
+
+	props["reversalOfTransactionId"] = nil
+	if tx.ReversalOf != "" {
+		props["reversalOfTransactionId"] = tx.ReversalOf
+	}
+	for key, value := range metadataProperties(tx.Metadata, promotedMetadata) {
+		props[key] = value
 	}
 	if !tx.CreatedAt.IsZero() {
 		props["createdAt"] = formatTime(tx.CreatedAt)
@@ -557,7 +966,10 @@ func toString(val any) string {
 		return v.String()
 	case []byte:
 		return string(v)
+	case nil:
+		return ""
 	default:
+		reportDecodeMismatch("string", val)
 		return ""
 	}
 }
@@ -572,7 +984,35 @@ func toFloat64(val any) float64 {
 		return float64(v)
 	case int:
 		return float64(v)
+	case nil:
+		return 0
 	default:
+		reportDecodeMismatch("float", val)
+		return 0
+	}
+}
+
+// toFloat64Ptr is toFloat64 for optional properties, returning nil when val is unset.
+func toFloat64Ptr(val any) *float64 {
+	if val == nil {
+		return nil
+	}
+	f := toFloat64(val)
+	return &f
+}
+
+func toInt64(val any) int64 {
+	switch v := val.(type) {
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case float64:
+		return int64(v)
+	case nil:
+		return 0
+	default:
+		reportDecodeMismatch("integer", val)
 		return 0
 	}
 }
@@ -591,19 +1031,48 @@ func toTimePtr(val any) *time.Time {
 		if parsed, err := time.Parse(time.RFC3339, v); err == nil {
 			return &parsed
 		}
+		reportDecodeMismatch("timestamp", val)
+	case nil:
+	default:
+		reportDecodeMismatch("timestamp", val)
 	}
 	return nil
 }
 
+const defaultMaintenanceBatchSize = 1000
+
+const pruneOrphansCypherTemplate = `
+MATCH (n:%s)
+WHERE NOT (n)--()
+WITH n LIMIT $batchSize
+DETACH DELETE n
+RETURN count(*) AS removed
+`
+
+const backfillAttributeOriginsCypher = `
+MATCH (n)-[r:HAS_ATTRIBUTE]->(a:Attribute)
+WHERE r.origin IS NULL
+WITH n, r, a LIMIT $batchSize
+SET r.origin = CASE
+	WHEN n:User THEN "USER"
+	WHEN a.attributeType = "TX_DAY_BUCKET" THEN "DERIVED"
+	ELSE "TRANSACTION"
+END
+RETURN count(r) AS updated
+`
+
 const upsertUserCypher = `
+OPTIONAL MATCH (existing:User {userId: $userId})
+WITH existing IS NULL AS created
 MERGE (u:User {userId: $userId})
 SET u += $props
-WITH u
+WITH u, created
 FOREACH (attr IN $attributes |
 	MERGE (a:Attribute {attributeType: attr.type, value: attr.value})
 	SET a.rawValue = attr.rawValue
 	MERGE (u)-[ha:HAS_ATTRIBUTE]->(a)
-	SET ha.confidenceScore = attr.confidence
+	SET ha.confidenceScore = attr.confidence,
+		ha.origin = CASE WHEN attr.origin = "" THEN "USER" ELSE attr.origin END
 )
 FOREACH (pm IN $paymentMethods |
 	MERGE (p:PaymentMethod {paymentMethodId: pm.id})
@@ -612,55 +1081,121 @@ FOREACH (pm IN $paymentMethods |
 	SET upm.firstUsedAt = pm.firstUsedAt
 	SET upm.lastUsedAt = pm.lastUsedAt
 )
+FOREACH (_ IN CASE WHEN created THEN [1] ELSE [] END |
+	MERGE (s:Stats {id: "global"})
+	SET s._lock = true
+	SET s.users = coalesce(s.users, 0) + 1
+	REMOVE s._lock
+)
 RETURN u.userId AS userId
 `
 
-const upsertTransactionCypher = `
-MATCH (sender:User {userId: $senderId})
-MATCH (receiver:User {userId: $receiverId})
-MERGE (t:Transaction {transactionId: $transactionId})
-SET t += $props
-MERGE (sender)-[ps:PARTICIPATED_IN {transactionId: $transactionId, role: "SENDER"}]->(t)
-SET ps.amount = $amount,
-	ps.currency = $currency,
-	ps.timestamp = $timestamp
-MERGE (receiver)-[pr:PARTICIPATED_IN {transactionId: $transactionId, role: "RECEIVER"}]->(t)
-SET pr.amount = $amount,
-	pr.currency = $currency,
-	pr.timestamp = $timestamp
-MERGE (sender)-[st:SENT_TO {transactionId: $transactionId}]->(receiver)
-SET st.amount = $amount,
-	st.currency = $currency,
-	st.timestamp = $timestamp
-MERGE (receiver)-[rt:RECEIVED_FROM {transactionId: $transactionId}]->(sender)
-SET rt.amount = $amount,
-	rt.currency = $currency,
-	rt.timestamp = $timestamp
-FOREACH (attr IN $attributes |
+// upsertTransactionBody writes the transaction described by row (see
+// transactionWriteRow) with its participants, attributes, links and counters. It is the
+// body of both the single and the batched upsert, so every WITH carries row along.
+const upsertTransactionBody = `
+OPTIONAL MATCH (existing:Transaction {transactionId: row.transactionId})
+WITH row, existing IS NULL AS created,
+     CASE WHEN coalesce(existing.status, "") = "" THEN "UNKNOWN" ELSE toUpper(existing.status) END AS previousStatus
+MATCH (sender:User {userId: row.senderId})
+OPTIONAL MATCH (receiver:User {userId: row.receiverId})
+WITH row, created, previousStatus, sender, receiver
+WHERE receiver IS NOT NULL OR NOT row.monetary
+MERGE (t:Transaction {transactionId: row.transactionId})
+SET t += row.props
+MERGE (sender)-[ps:PARTICIPATED_IN {transactionId: row.transactionId, role: "SENDER"}]->(t)
+SET ps.amount = row.amount,
+	ps.currency = row.currency,
+	ps.timestamp = row.timestamp
+FOREACH (_ IN CASE WHEN receiver IS NULL THEN [] ELSE [1] END |
+	MERGE (receiver)-[pr:PARTICIPATED_IN {transactionId: row.transactionId, role: "RECEIVER"}]->(t)
+	SET pr.amount = row.amount,
+		pr.currency = row.currency,
+		pr.timestamp = row.timestamp
+)
+FOREACH (_ IN CASE WHEN row.monetary THEN [1] ELSE [] END |
+	MERGE (sender)-[st:SENT_TO {transactionId: row.transactionId}]->(receiver)
+	SET st.amount = row.amount,
+		st.currency = row.currency,
+		st.timestamp = row.timestamp
+	FOREACH (_rf IN CASE WHEN $receivedFromEdges THEN [1] ELSE [] END |
+		MERGE (receiver)-[rt:RECEIVED_FROM {transactionId: row.transactionId}]->(sender)
+		SET rt.amount = row.amount,
+			rt.currency = row.currency,
+			rt.timestamp = row.timestamp
+	)
+)
+FOREACH (attr IN row.attributes |
 	MERGE (a:Attribute {attributeType: attr.type, value: attr.value})
 	SET a.rawValue = attr.rawValue
 	MERGE (t)-[hta:HAS_ATTRIBUTE]->(a)
-	SET hta.origin = "TRANSACTION"
+	SET hta.origin = CASE WHEN attr.origin = "" THEN "TRANSACTION" ELSE attr.origin END
+)
+WITH row, t, created, previousStatus,
+     CASE WHEN coalesce(t.status, "") = "" THEN "UNKNOWN" ELSE toUpper(t.status) END AS currentStatus
+FOREACH (_ IN CASE WHEN created THEN [1] ELSE [] END |
+	MERGE (s:Stats {id: "global"})
+	SET s._lock = true
+	SET s.transactions = coalesce(s.transactions, 0) + 1
+	REMOVE s._lock
+)
+FOREACH (_ IN CASE WHEN NOT created AND previousStatus <> currentStatus THEN [1] ELSE [] END |
+	MERGE (prev:StatusCount {status: previousStatus})
+	SET prev._lock = true
+	SET prev.count = coalesce(prev.count, 0) - 1
+	REMOVE prev._lock
+)
+FOREACH (_ IN CASE WHEN created OR previousStatus <> currentStatus THEN [1] ELSE [] END |
+	MERGE (cur:StatusCount {status: currentStatus})
+	SET cur._lock = true
+	SET cur.count = coalesce(cur.count, 0) + 1
+	REMOVE cur._lock
 )
-WITH t, $attributes AS attrs
-UNWIND attrs AS attr
-MATCH (a:Attribute {attributeType: attr.type, value: attr.value})
-OPTIONAL MATCH (other:Transaction)-[:HAS_ATTRIBUTE]->(a)
-WHERE other.transactionId <> $transactionId
-WITH t, attr, collect(DISTINCT other) AS others
-UNWIND others AS otherTx
-MERGE (t)-[lt:LINKED_TO {attributeHash: attr.value, linkType: attr.type}]->(otherTx)
-SET lt.score = attr.score,
-    lt.updatedAt = datetime()
-WITH DISTINCT t, $paymentMethodId AS pmId
-OPTIONAL MATCH (pm:PaymentMethod {paymentMethodId: pmId})
-FOREACH (_ IN CASE WHEN pmId = "" OR pm IS NULL THEN [] ELSE [1] END |
+WITH row, t
+CALL {
+	WITH row, t
+	MATCH (t)-[stale:REVERSES]->(old:Transaction)
+	WHERE old.transactionId <> row.reversalOf
+	DELETE stale
+}
+CALL {
+	WITH row, t
+	MATCH (original:Transaction {transactionId: row.reversalOf})
+	WHERE original <> t
+	MERGE (t)-[:REVERSES]->(original)
+}
+CALL {
+	// Reversals ingested before their original are linked once it arrives.
+	WITH row, t
+	MATCH (reversal:Transaction {reversalOfTransactionId: row.transactionId})
+	WHERE reversal <> t
+	MERGE (reversal)-[:REVERSES]->(t)
+}
+CALL {
+	WITH row, t
+	UNWIND row.attributes AS attr
+	MATCH (a:Attribute {attributeType: attr.type, value: attr.value})
+	MATCH (other:Transaction)-[:HAS_ATTRIBUTE]->(a)
+	WHERE other.transactionId <> row.transactionId
+	WITH t, attr, collect(DISTINCT other) AS others
+	UNWIND others AS other
+	MERGE (t)-[lt:LINKED_TO {attributeHash: attr.value, linkType: attr.type}]->(other)
+	SET lt.score = attr.score,
+	    lt.decayedScore = ` + decayedLinkScore + `,
+	    lt.updatedAt = datetime()
+}
+WITH row, t
+OPTIONAL MATCH (pm:PaymentMethod {paymentMethodId: row.paymentMethodId})
+FOREACH (_ IN CASE WHEN row.paymentMethodId = "" OR pm IS NULL THEN [] ELSE [1] END |
 	MERGE (t)-[pmr:PAYMENT_METHOD_RELATES]->(pm)
 	SET pmr.role = "SENDER"
 )
 RETURN t.transactionId AS transactionId
 `
 
+const upsertTransactionCypher = `
+WITH $transaction AS row` + upsertTransactionBody
+
 const listUsersCypherTemplate = `
 MATCH (u:User)
 %s
@@ -711,9 +1246,11 @@ WHERE ($kycStatus = "" OR toUpper(u.kycStatus) = $kycStatus)
   AND ($riskMin <= 0 OR coalesce(u.riskScore, 0.0) >= $riskMin)
   AND ($riskMax <= 0 OR coalesce(u.riskScore, 0.0) <= $riskMax)
   AND ($search = "" OR toLower(u.fullName) CONTAINS $search OR toLower(u.email) CONTAINS $search OR toLower(u.userId) CONTAINS $search)
-  AND ($country = "" OR toLower(coalesce(u.address.country, "")) = $country)
-  AND ($city = "" OR toLower(coalesce(u.address.city, "")) = $city)
+  AND ($country = "" OR toLower(trim(coalesce(u.addressCountry, ""))) = $country)
+  AND ($city = "" OR toLower(trim(coalesce(u.addressCity, ""))) = $city)
   AND ($emailDomain = "" OR toLower(u.email) ENDS WITH $emailDomain)
+  AND ($createdAfter = "" OR datetime(u.createdAt) >= datetime($createdAfter))
+  AND ($createdBefore = "" OR datetime(u.createdAt) <= datetime($createdBefore))
 `
 
 const transactionFilterClause = `
@@ -735,8 +1272,55 @@ WHERE ($status = "" OR toUpper(t.status) = $status)
   AND ($startTs = "" OR t.timestamp >= datetime($startTs))
   AND ($endTs = "" OR t.timestamp <= datetime($endTs))
   AND ($channel = "" OR toUpper(t.channel) = $channel)
+  AND ($senderRiskMin <= 0 OR EXISTS {
+    MATCH (p:User)-[:PARTICIPATED_IN {role: "SENDER"}]->(t)
+    WHERE coalesce(p.riskScore, 0.0) >= $senderRiskMin
+  })
+  AND ($receiverRiskMin <= 0 OR EXISTS {
+    MATCH (p:User)-[:PARTICIPATED_IN {role: "RECEIVER"}]->(t)
+    WHERE coalesce(p.riskScore, 0.0) >= $receiverRiskMin
+  })
+  AND ($anyParticipantRiskMin <= 0 OR EXISTS {
+    MATCH (p:User)-[:PARTICIPATED_IN]->(t)
+    WHERE coalesce(p.riskScore, 0.0) >= $anyParticipantRiskMin
+  })
+  AND ($currency = "" OR toUpper(t.currency) = $currency)
+  AND (
+    $deviceId = ""
+    OR t.deviceId = $deviceId
+    OR ($includeLinked AND EXISTS {
+      MATCH (t)-[:HAS_ATTRIBUTE]->(:Attribute {attributeType: "DEVICE"})<-[:HAS_ATTRIBUTE]-(:Transaction {deviceId: $deviceId})
+    })
+  )
+  AND (
+    $ipAddress = ""
+    OR t.ipAddress = $ipAddress
+    OR ($includeLinked AND EXISTS {
+      MATCH (t)-[:HAS_ATTRIBUTE]->(:Attribute {attributeType: "IP_ADDRESS"})<-[:HAS_ATTRIBUTE]-(:Transaction {ipAddress: $ipAddress})
+    })
+  )
+  AND (
+    $participantKyc = ""
+    OR ($participantKyc = "NONE_VERIFIED" AND NOT EXISTS {
+      MATCH (p:User)-[:PARTICIPATED_IN]->(t)
+      WHERE toUpper(coalesce(p.kycStatus, "")) = "VERIFIED"
+    })
+    OR ($participantKyc = "ANY_VERIFIED" AND EXISTS {
+      MATCH (p:User)-[:PARTICIPATED_IN]->(t)
+      WHERE toUpper(coalesce(p.kycStatus, "")) = "VERIFIED"
+    })
+  )
 `
 
+// UserSortFields and TransactionSortFields are the sortField values user and transaction
+// listings accept, default first. Other values sort as the default.
+var (
+	UserSortFields        = []string{"userId", "fullName", "riskScore", "createdAt", "updatedAt"}
+	TransactionSortFields = []string{"timestamp", "amount", "status", "type", "channel", "createdAt", "updatedAt", "transactionId"}
+)
+
+// userOrderClause returns the ORDER BY expression for a user listing. userId is always
+// the final key so rows with equal sort values keep a stable order across pages.
 func userOrderClause(field, order string) string {
 	dir := "ASC"
 	if strings.EqualFold(order, "DESC") {
@@ -744,18 +1328,20 @@ func userOrderClause(field, order string) string {
 	}
 	switch strings.ToLower(field) {
 	case "fullname":
-		return fmt.Sprintf("toLower(u.fullName) %s", dir)
+		return fmt.Sprintf("toLower(u.fullName) %s, u.userId %s", dir, dir)
 	case "riskscore":
-		return fmt.Sprintf("coalesce(u.riskScore, 0.0) %s", dir)
+		return fmt.Sprintf("coalesce(u.riskScore, 0.0) %s, u.userId %s", dir, dir)
 	case "createdat":
-		return fmt.Sprintf("datetime(u.createdAt) %s", dir)
+		return fmt.Sprintf("datetime(u.createdAt) %s, u.userId %s", dir, dir)
 	case "updatedat":
-		return fmt.Sprintf("datetime(u.updatedAt) %s", dir)
+		return fmt.Sprintf("datetime(u.updatedAt) %s, u.userId %s", dir, dir)
 	default:
 		return fmt.Sprintf("u.userId %s", dir)
 	}
 }
 
+// transactionOrderClause returns the ORDER BY expression for a transaction listing, with
+// transactionId as the final key for a stable order.
 func transactionOrderClause(field, order string) string {
 	dir := "DESC"
 	if strings.EqualFold(order, "ASC") {
@@ -763,35 +1349,42 @@ func transactionOrderClause(field, order string) string {
 	}
 	switch strings.ToLower(field) {
 	case "amount":
-		return fmt.Sprintf("coalesce(t.amount, 0.0) %s", dir)
+		return fmt.Sprintf("coalesce(t.amount, 0.0) %s, t.transactionId %s", dir, dir)
 	case "status":
-		return fmt.Sprintf("toUpper(t.status) %s", dir)
+		return fmt.Sprintf("toUpper(t.status) %s, t.transactionId %s", dir, dir)
 	case "type":
-		return fmt.Sprintf("toUpper(t.type) %s", dir)
+		return fmt.Sprintf("toUpper(t.type) %s, t.transactionId %s", dir, dir)
 	case "channel":
-		return fmt.Sprintf("toUpper(t.channel) %s", dir)
+		return fmt.Sprintf("toUpper(t.channel) %s, t.transactionId %s", dir, dir)
 	case "timestamp":
-		return fmt.Sprintf("datetime(t.timestamp) %s", dir)
+		return fmt.Sprintf("datetime(t.timestamp) %s, t.transactionId %s", dir, dir)
 	case "createdat":
-		return fmt.Sprintf("datetime(t.createdAt) %s", dir)
+		return fmt.Sprintf("datetime(t.createdAt) %s, t.transactionId %s", dir, dir)
 	case "updatedat":
-		return fmt.Sprintf("datetime(t.updatedAt) %s", dir)
+		return fmt.Sprintf("datetime(t.updatedAt) %s, t.transactionId %s", dir, dir)
 	case "transactionid":
 		return fmt.Sprintf("t.transactionId %s", dir)
 	default:
-		return fmt.Sprintf("datetime(t.timestamp) %s", dir)
+		return fmt.Sprintf("datetime(t.timestamp) %s, t.transactionId %s", dir, dir)
 	}
 }
 
+// userDirectLinksCypher reads both directions from SENT_TO alone, reporting inbound
+// edges as RECEIVED_FROM, so it gives the same rows whether or not RECEIVED_FROM edges
+// are written.
 const userDirectLinksCypher = `
-MATCH (u:User {userId: $userId})-[r:SENT_TO|RECEIVED_FROM]->(peer:User)
+MATCH (u:User {userId: $userId})-[r:SENT_TO]-(peer:User)
+WITH peer, r, CASE WHEN startNode(r) = u THEN "SENT_TO" ELSE "RECEIVED_FROM" END AS linkType
+WHERE linkType IN $linkTypes
 RETURN peer.userId AS peerId,
-       type(r) AS linkType,
-       CASE WHEN type(r) = "SENT_TO" THEN "OUTBOUND" ELSE "INBOUND" END AS direction,
+       linkType,
+       CASE WHEN linkType = "SENT_TO" THEN "OUTBOUND" ELSE "INBOUND" END AS direction,
        r.transactionId AS transactionId,
        r.amount AS amount,
        r.currency AS currency,
        r.timestamp AS timestamp
+ORDER BY timestamp DESC, transactionId, linkType
+LIMIT $fetch
 `
 
 const userTransactionsCypher = `
@@ -801,14 +1394,27 @@ RETURN t.transactionId AS transactionId,
        t.amount AS amount,
        t.currency AS currency,
        t.timestamp AS timestamp
+ORDER BY timestamp DESC, transactionId, role
+LIMIT $fetch
 `
 
 const userSharedAttributesCypher = `
-MATCH (u:User {userId: $userId})-[:HAS_ATTRIBUTE]->(a:Attribute)<-[:HAS_ATTRIBUTE]-(other:User)
+MATCH (u:User {userId: $userId})-[ha:HAS_ATTRIBUTE]->(a:Attribute)<-[hb:HAS_ATTRIBUTE]-(other:User)
 WHERE other.userId <> $userId
+  AND ($origin = "" OR (coalesce(ha.origin, "USER") = $origin AND coalesce(hb.origin, "USER") = $origin))
+WITH a, collect(DISTINCT other.userId) AS userIds
 RETURN a.attributeType AS attributeType,
        a.value AS attributeHash,
-       collect(DISTINCT other.userId) AS userIds
+       userIds[0..$limit] AS userIds,
+       size(userIds) > $limit AS usersTruncated
+ORDER BY attributeType, attributeHash
+LIMIT $fetch
+`
+
+const transactionExistsCypher = `
+MATCH (t:Transaction {transactionId: $transactionId})
+RETURN t.transactionId AS transactionId
+LIMIT 1
 `
 
 const transactionUsersCypher = `
@@ -818,6 +1424,8 @@ RETURN user.userId AS userId,
        rel.amount AS amount,
        rel.currency AS currency,
        CASE WHEN rel.role = "SENDER" THEN "OUTBOUND" ELSE "INBOUND" END AS direction
+ORDER BY role DESC, userId
+LIMIT $fetch
 `
 
 const transactionLinkedCypher = `
@@ -826,5 +1434,8 @@ RETURN other.transactionId AS otherTransactionId,
        link.linkType AS linkType,
        link.attributeHash AS attributeHash,
        link.score AS score,
+       link.decayedScore AS decayedScore,
        link.updatedAt AS updatedAt
+ORDER BY coalesce(decayedScore, score) DESC, otherTransactionId, linkType
+LIMIT $fetch
 `
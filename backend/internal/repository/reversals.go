@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// maxReversalDepth bounds how many REVERSES hops are followed from a transaction. Real
+// chains are an original, its reversal and occasionally a reversal of that reversal.
+const maxReversalDepth = 10
+
+// fetchReversalChain loads every transaction reachable from txID through REVERSES in
+// either direction, oldest first. A transaction outside any chain gets an empty section.
+func (r *Repository) fetchReversalChain(ctx context.Context, txID string, rel *domain.TransactionRelationships) error {
+	res, err := r.client.ExecuteRead(ctx, transactionReversalChainCypher, sectionParams(map[string]any{
+		"transactionId": txID,
+	}, rel.Limits))
+	if err != nil {
+		return fmt.Errorf("fetch reversal chain: %w", err)
+	}
+
+	for _, record := range truncateSection(res.Records, "reversalChain", &rel.Limits) {
+		rel.ReversalChain = append(rel.ReversalChain, domain.ReversalLink{
+			TransactionID:         toString(record["transactionId"]),
+			ReversesTransactionID: toString(record["reversesTransactionId"]),
+			Amount:                toFloat64(record["amount"]),
+			Currency:              toString(record["currency"]),
+			Timestamp:             toTimePtr(record["timestamp"]),
+		})
+	}
+	return nil
+}
+
+var transactionReversalChainCypher = fmt.Sprintf(`
+MATCH (t:Transaction {transactionId: $transactionId})
+MATCH (t)-[:REVERSES*0..%d]-(member:Transaction)
+WHERE EXISTS { (member)-[:REVERSES]-(:Transaction) }
+WITH DISTINCT member
+OPTIONAL MATCH (member)-[:REVERSES]->(reversed:Transaction)
+RETURN member.transactionId AS transactionId,
+       reversed.transactionId AS reversesTransactionId,
+       member.amount AS amount,
+       member.currency AS currency,
+       member.timestamp AS timestamp
+ORDER BY member.timestamp, member.transactionId
+LIMIT $fetch
+`, maxReversalDepth)
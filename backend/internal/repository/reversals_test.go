@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestFetchTransactionRelationshipsReversalChain(t *testing.T) {
+	client := &fakeClient{read: func(cypher string, _ map[string]any) (graph.Result, error) {
+		switch cypher {
+		case transactionExistsCypher:
+			return graph.Result{Records: []graph.Record{{"transactionId": "TX-1"}}}, nil
+		case transactionReversalChainCypher:
+			return graph.Result{Records: []graph.Record{
+				{"transactionId": "TX-1", "reversesTransactionId": nil, "amount": 100.0, "currency": "USD", "timestamp": "2024-05-01T12:00:00Z"},
+				{"transactionId": "TX-2", "reversesTransactionId": "TX-1", "amount": 100.0, "currency": "USD", "timestamp": "2024-05-02T08:00:00Z"},
+			}}, nil
+		}
+		return graph.Result{}, nil
+	}}
+	rel, err := New(client).FetchTransactionRelationships(context.Background(), "TX-1", TransactionRelationshipOptions{})
+	if err != nil {
+		t.Fatalf("FetchTransactionRelationships() error = %v", err)
+	}
+	if len(rel.ReversalChain) != 2 {
+		t.Fatalf("chain has %d links, want 2", len(rel.ReversalChain))
+	}
+	original, reversal := rel.ReversalChain[0], rel.ReversalChain[1]
+	if original.TransactionID != "TX-1" || original.ReversesTransactionID != "" {
+		t.Errorf("original = %+v, want TX-1 reversing nothing", original)
+	}
+	if reversal.TransactionID != "TX-2" || reversal.ReversesTransactionID != "TX-1" {
+		t.Errorf("reversal = %+v, want TX-2 reversing TX-1", reversal)
+	}
+	wantTime := time.Date(2024, 5, 2, 8, 0, 0, 0, time.UTC)
+	if reversal.Timestamp == nil || !reversal.Timestamp.Equal(wantTime) {
+		t.Errorf("reversal timestamp = %v, want %v", reversal.Timestamp, wantTime)
+	}
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// RiskFeatureOptions sets the windows and thresholds used when collecting risk features.
+type RiskFeatureOptions struct {
+	Now time.Time
+	// StructuringFloor and StructuringCeiling bound the amounts counted as structuring.
+	StructuringFloor   float64
+	StructuringCeiling float64
+	// StructuringWindow is how far back structuring transactions are counted.
+	StructuringWindow time.Duration
+	// NetReversals leaves out transactions that reverse, or were reversed by, another
+	// transaction, so a payment and its refund do not count towards velocity or structuring.
+	NetReversals bool
+}
+
+// FetchUserRiskFeatures collects the graph features a risk model needs for a user. It
+// returns ErrNotFound when the user does not exist.
+func (r *Repository) FetchUserRiskFeatures(ctx context.Context, userID string, opts RiskFeatureOptions) (domain.UserRiskFeatures, error) {
+	if userID == "" {
+		return domain.UserRiskFeatures{}, errors.New("user id is required")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	now = now.UTC()
+
+	res, err := r.client.ExecuteRead(ctx, userRiskFeaturesCypher, map[string]any{
+		"userId":             userID,
+		"since24h":           formatTime(now.Add(-24 * time.Hour)),
+		"since7d":            formatTime(now.Add(-7 * 24 * time.Hour)),
+		"structuringSince":   formatTime(now.Add(-opts.StructuringWindow)),
+		"structuringFloor":   opts.StructuringFloor,
+		"structuringCeiling": opts.StructuringCeiling,
+		"netReversals":       opts.NetReversals,
+	})
+	if err != nil {
+		return domain.UserRiskFeatures{}, fmt.Errorf("fetch risk features: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.UserRiskFeatures{}, fmt.Errorf("fetch risk features for %s: %w", userID, ErrNotFound)
+	}
+
+	record := res.Records[0]
+	return domain.UserRiskFeatures{
+		UserID:                  toString(record["userId"]),
+		StoredScore:             toFloat64(record["riskScore"]),
+		Transactions24h:         toInt64(record["transactions24h"]),
+		Transactions7d:          toInt64(record["transactions7d"]),
+		Counterparties:          toInt64(record["counterparties"]),
+		AvgCounterpartyRisk:     toFloat64(record["avgCounterpartyRisk"]),
+		MaxCounterpartyRisk:     toFloat64(record["maxCounterpartyRisk"]),
+		SharedAttributeUsers:    toInt64(record["sharedAttributeUsers"]),
+		SharedAttributes:        toInt64(record["sharedAttributes"]),
+		StructuringTransactions: toInt64(record["structuringTransactions"]),
+	}, nil
+}
+
+const userRiskFeaturesCypher = `
+MATCH (u:User {userId: $userId})
+CALL {
+  WITH u
+  OPTIONAL MATCH (u)-[:PARTICIPATED_IN]->(t:Transaction)
+  WHERE coalesce(t.monetary, true)
+    AND (NOT $netReversals OR NOT EXISTS { (t)-[:REVERSES]-(:Transaction) })
+  RETURN count(CASE WHEN t.timestamp >= datetime($since24h) THEN 1 END) AS transactions24h,
+         count(CASE WHEN t.timestamp >= datetime($since7d) THEN 1 END) AS transactions7d
+}
+CALL {
+  WITH u
+  OPTIONAL MATCH (u)-[:SENT_TO]-(peer:User)
+  WITH DISTINCT peer
+  RETURN count(peer) AS counterparties,
+         coalesce(avg(peer.riskScore), 0.0) AS avgCounterpartyRisk,
+         coalesce(max(peer.riskScore), 0.0) AS maxCounterpartyRisk
+}
+CALL {
+  WITH u
+  OPTIONAL MATCH (u)-[:HAS_ATTRIBUTE]->(a:Attribute)<-[:HAS_ATTRIBUTE]-(other:User)
+  WHERE other <> u
+  RETURN count(DISTINCT other) AS sharedAttributeUsers,
+         count(DISTINCT a) AS sharedAttributes
+}
+CALL {
+  WITH u
+  OPTIONAL MATCH (u)-[:PARTICIPATED_IN {role: "SENDER"}]->(t:Transaction)
+  WHERE coalesce(t.monetary, true)
+    AND (NOT $netReversals OR NOT EXISTS { (t)-[:REVERSES]-(:Transaction) })
+    AND t.timestamp >= datetime($structuringSince)
+    AND coalesce(t.amount, 0.0) >= $structuringFloor
+    AND coalesce(t.amount, 0.0) < $structuringCeiling
+  RETURN count(t) AS structuringTransactions
+}
+RETURN u.userId AS userId,
+       u.riskScore AS riskScore,
+       transactions24h,
+       transactions7d,
+       counterparties,
+       avgCounterpartyRisk,
+       maxCounterpartyRisk,
+       sharedAttributeUsers,
+       sharedAttributes,
+       structuringTransactions
+`
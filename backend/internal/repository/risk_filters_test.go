@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestListTransactionsParticipantRiskPredicates(t *testing.T) {
+	client := &fakeClient{}
+	opts := ListTransactionsOptions{SenderRiskMin: 0.8, ReceiverRiskMin: 0.5, AnyParticipantRiskMin: 0.9}
+	if _, err := New(client).ListTransactions(context.Background(), opts); err != nil {
+		t.Fatalf("ListTransactions() error = %v", err)
+	}
+	if len(client.reads) == 0 {
+		t.Fatal("no read ran")
+	}
+	for _, call := range client.reads {
+		for param, want := range map[string]float64{"senderRiskMin": 0.8, "receiverRiskMin": 0.5, "anyParticipantRiskMin": 0.9} {
+			if got := call.params[param]; got != want {
+				t.Errorf("%s = %v, want %v", param, got, want)
+			}
+		}
+		cypher := normalizeSpace(call.cypher)
+		for _, predicate := range []string{
+			// A zero or negative threshold disables the filter.
+			`($senderRiskMin <= 0 OR EXISTS { MATCH (p:User)-[:PARTICIPATED_IN {role: "SENDER"}]->(t) WHERE coalesce(p.riskScore, 0.0) >= $senderRiskMin })`,
+			`($receiverRiskMin <= 0 OR EXISTS { MATCH (p:User)-[:PARTICIPATED_IN {role: "RECEIVER"}]->(t) WHERE coalesce(p.riskScore, 0.0) >= $receiverRiskMin })`,
+			// A user without a risk score counts as 0, so it never satisfies a positive threshold.
+			`($anyParticipantRiskMin <= 0 OR EXISTS { MATCH (p:User)-[:PARTICIPATED_IN]->(t) WHERE coalesce(p.riskScore, 0.0) >= $anyParticipantRiskMin })`,
+		} {
+			if !strings.Contains(cypher, predicate) {
+				t.Errorf("query lacks %s", predicate)
+			}
+		}
+	}
+}
+
+func TestListTransactionsRiskFiltersOffByDefault(t *testing.T) {
+	client := &fakeClient{}
+	if _, err := New(client).ListTransactions(context.Background(), ListTransactionsOptions{}); err != nil {
+		t.Fatalf("ListTransactions() error = %v", err)
+	}
+	for _, param := range []string{"senderRiskMin", "receiverRiskMin", "anyParticipantRiskMin"} {
+		if got := client.reads[0].params[param]; got != 0.0 {
+			t.Errorf("%s = %v, want 0 so the predicate passes every transaction", param, got)
+		}
+	}
+}
+
+// normalizeSpace collapses every run of whitespace in s to one space.
+func normalizeSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+const (
+	// DefaultSharedPaymentMinUsers is the fewest users a payment method needs to be
+	// listed by UsersSharingPaymentMethods when the caller does not choose.
+	DefaultSharedPaymentMinUsers = 2
+	// maxSharedPaymentMethods caps the clusters one UsersSharingPaymentMethods call
+	// returns.
+	maxSharedPaymentMethods = 500
+	// maxSharedPaymentUsers caps the user IDs listed per cluster.
+	maxSharedPaymentUsers = 1000
+)
+
+// UsersSharingPaymentMethods returns the PaymentMethod nodes linked through
+// USES_PAYMENT_METHOD to at least minUsers distinct users, each with its users, most
+// shared first with the payment method ID breaking ties. Zero or less means
+// DefaultSharedPaymentMinUsers. Unlike UsersByPaymentFingerprint this groups by the
+// payment method itself, so it needs no fingerprint and scans every payment method.
+func (r *Repository) UsersSharingPaymentMethods(ctx context.Context, minUsers int) (domain.PaymentMethodClusters, error) {
+	if minUsers <= 0 {
+		minUsers = DefaultSharedPaymentMinUsers
+	}
+
+	res, err := r.client.ExecuteRead(ctx, usersSharingPaymentMethodsCypher, map[string]any{
+		"minUsers": minUsers,
+		"maxUsers": maxSharedPaymentUsers,
+		"limit":    maxSharedPaymentMethods + 1,
+	})
+	if err != nil {
+		return domain.PaymentMethodClusters{}, fmt.Errorf("users sharing payment methods: %w", err)
+	}
+
+	result := domain.PaymentMethodClusters{MinUsers: minUsers, Clusters: []domain.PaymentMethodCluster{}}
+	records := res.Records
+	if len(records) > maxSharedPaymentMethods {
+		records = records[:maxSharedPaymentMethods]
+		result.Truncated = true
+	}
+	for _, record := range records {
+		values, _ := record["userIds"].([]any)
+		userIDs := make([]string, 0, len(values))
+		for _, value := range values {
+			userIDs = append(userIDs, toString(value))
+		}
+		result.Clusters = append(result.Clusters, domain.PaymentMethodCluster{
+			PaymentMethodID: toString(record["paymentMethodId"]),
+			MethodType:      toString(record["methodType"]),
+			Provider:        toString(record["provider"]),
+			Fingerprint:     toString(record["fingerprint"]),
+			UserCount:       int(toInt64(record["userCount"])),
+			UserIDs:         userIDs,
+		})
+	}
+	return result, nil
+}
+
+const usersSharingPaymentMethodsCypher = `
+MATCH (pm:PaymentMethod)<-[:USES_PAYMENT_METHOD]-(u:User)
+WITH pm, u.userId AS userId
+ORDER BY userId
+WITH pm, collect(DISTINCT userId) AS userIds
+WHERE size(userIds) >= $minUsers
+RETURN pm.paymentMethodId AS paymentMethodId,
+       pm.methodType AS methodType,
+       pm.provider AS provider,
+       pm.fingerprint AS fingerprint,
+       size(userIds) AS userCount,
+       userIds[..$maxUsers] AS userIds
+ORDER BY userCount DESC, paymentMethodId
+LIMIT $limit
+`
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// The upsert statements maintain denormalised counters on a singleton (:Stats {id: "global"})
+// node and one (:StatusCount {status}) node per transaction status, inside the same write
+// transaction as the entity change. Concurrent first-time writes of the same ID can still
+// double count, so RebuildStats exists to recompute the counters from scratch.
+
+// GetStats returns the maintained graph counters with a single read.
+func (r *Repository) GetStats(ctx context.Context) (domain.GraphStats, error) {
+	res, err := r.client.ExecuteRead(ctx, getStatsCypher, nil)
+	if err != nil {
+		return domain.GraphStats{}, fmt.Errorf("get stats: %w", err)
+	}
+	return decodeStats(res.Records), nil
+}
+
+// RebuildStats recomputes every counter by scanning the graph, correcting any drift.
+func (r *Repository) RebuildStats(ctx context.Context) (domain.GraphStats, error) {
+	res, err := r.client.ExecuteWrite(ctx, rebuildStatsCypher, nil)
+	if err != nil {
+		return domain.GraphStats{}, fmt.Errorf("rebuild stats: %w", err)
+	}
+	return decodeStats(res.Records), nil
+}
+
+func decodeStats(records []graph.Record) domain.GraphStats {
+	stats := domain.GraphStats{
+		TransactionsByStatus: map[string]int64{},
+	}
+	if len(records) == 0 {
+		return stats
+	}
+	record := records[0]
+	stats.TotalUsers = toInt64(record["users"])
+	stats.TotalTransactions = toInt64(record["transactions"])
+	if statuses, ok := record["statuses"].([]any); ok {
+		for _, entry := range statuses {
+			row, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			status := toString(row["status"])
+			if status == "" {
+				continue
+			}
+			stats.TransactionsByStatus[status] = toInt64(row["count"])
+		}
+	}
+	return stats
+}
+
+const getStatsCypher = `
+OPTIONAL MATCH (s:Stats {id: "global"})
+OPTIONAL MATCH (sc:StatusCount)
+WHERE sc.count > 0
+RETURN coalesce(s.users, 0) AS users,
+       coalesce(s.transactions, 0) AS transactions,
+       collect({status: sc.status, count: sc.count}) AS statuses
+`
+
+const rebuildStatsCypher = `
+CALL { MATCH (u:User) RETURN count(u) AS users }
+CALL { MATCH (t:Transaction) RETURN count(t) AS transactions }
+CALL { MATCH (old:StatusCount) DETACH DELETE old }
+MERGE (s:Stats {id: "global"})
+SET s.users = users,
+    s.transactions = transactions
+WITH s
+CALL {
+	MATCH (t:Transaction)
+	WITH CASE WHEN coalesce(t.status, "") = "" THEN "UNKNOWN" ELSE toUpper(t.status) END AS status, count(*) AS total
+	CREATE (sc:StatusCount {status: status, count: total})
+	RETURN collect({status: status, count: total}) AS statuses
+}
+RETURN s.users AS users,
+       s.transactions AS transactions,
+       statuses
+`
@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// UpsertTransactions writes txs, where attributes[i] belongs to txs[i], in one statement
+// instead of one round-trip per transaction. Rows are applied in order, so a transaction
+// links to earlier ones in the same batch exactly as if they had been written one by
+// one. Content hashing and duplicate handling still read once per transaction, and
+// duplicates within one batch are only compared when the earlier one was already
+// stored. A transaction that fails validation or duplicate handling is left out and
+// reported in the returned error, joined with the others, after the rest are written;
+// unchanged ones are counted rather than reported.
+func (r *Repository) UpsertTransactions(ctx context.Context, txs []domain.Transaction, attributes [][]domain.Attribute) (BatchWriteResult, error) {
+	if len(attributes) != len(txs) {
+		return BatchWriteResult{}, fmt.Errorf("upsert transactions: %d attribute lists for %d transactions", len(attributes), len(txs))
+	}
+
+	var result BatchWriteResult
+	var failed []error
+	rows := make([]map[string]any, 0, len(txs))
+	for i, tx := range txs {
+		row, err := r.transactionWriteRow(ctx, tx, attributes[i])
+		switch {
+		case errors.Is(err, ErrUnchanged):
+			result.Unchanged++
+		case err != nil:
+			failed = append(failed, err)
+		default:
+			rows = append(rows, row)
+		}
+	}
+	if len(rows) == 0 {
+		return result, errors.Join(failed...)
+	}
+
+	res, err := r.client.ExecuteWrite(ctx, upsertTransactionsCypher, map[string]any{
+		"transactions":      rows,
+		"linkDecayHalfLife": r.linkDecayHalfLife(true),
+		"receivedFromEdges": r.receivedFromEdges,
+	})
+	if err != nil {
+		return result, fmt.Errorf("upsert %d transactions: %w", len(rows), classifyError(err))
+	}
+	if len(res.Records) > 0 {
+		result.Written = int(toInt64(res.Records[0]["written"]))
+	}
+	return result, errors.Join(failed...)
+}
+
+// upsertTransactionsCypher runs upsertTransactionBody once per row. Rows whose sender or
+// receiver does not exist write nothing and are not counted in written.
+const upsertTransactionsCypher = `
+UNWIND $transactions AS row
+CALL {
+	WITH row` + upsertTransactionBody + `}
+RETURN count(transactionId) AS written
+`
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DeleteTransaction detach-deletes a transaction, together with the SENT_TO and
+// RECEIVED_FROM edges its participants hold for it, and keeps the transaction and status
+// counters in step. It returns how many LINKED_TO edges were removed, counting both the
+// transaction's own links and those other transactions held pointing at it, or
+// ErrNotFound when the transaction does not exist.
+func (r *Repository) DeleteTransaction(ctx context.Context, txID string) (int, error) {
+	if txID == "" {
+		return 0, errors.New("transaction id is required")
+	}
+
+	res, err := r.client.ExecuteWrite(ctx, deleteTransactionCypher, map[string]any{"transactionId": txID})
+	if err != nil {
+		return 0, fmt.Errorf("delete transaction %s: %w", txID, classifyError(err))
+	}
+	if len(res.Records) == 0 {
+		return 0, fmt.Errorf("transaction %s: %w", txID, ErrNotFound)
+	}
+	return int(toInt64(res.Records[0]["removedLinks"])), nil
+}
+
+const deleteTransactionCypher = `
+MATCH (t:Transaction {transactionId: $transactionId})
+WITH t, CASE WHEN coalesce(t.status, "") = "" THEN "UNKNOWN" ELSE toUpper(t.status) END AS status
+CALL {
+	WITH t
+	OPTIONAL MATCH (t)-[link:LINKED_TO]-(:Transaction)
+	RETURN count(DISTINCT link) AS removedLinks
+}
+CALL {
+	WITH t
+	OPTIONAL MATCH (:User)-[payment:SENT_TO|RECEIVED_FROM {transactionId: t.transactionId}]->(:User)
+	DELETE payment
+	RETURN count(payment) AS removedPayments
+}
+DETACH DELETE t
+WITH status, removedLinks
+MERGE (s:Stats {id: "global"})
+SET s._lock = true
+SET s.transactions = CASE WHEN coalesce(s.transactions, 0) > 0 THEN s.transactions - 1 ELSE 0 END
+REMOVE s._lock
+MERGE (sc:StatusCount {status: status})
+SET sc._lock = true
+SET sc.count = CASE WHEN coalesce(sc.count, 0) > 0 THEN sc.count - 1 ELSE 0 END
+REMOVE sc._lock
+RETURN removedLinks
+`
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestDeleteTransactionQueryShape(t *testing.T) {
+	client := &fakeClient{write: records(graph.Record{"removedLinks": int64(3)})}
+	removed, err := New(client).DeleteTransaction(context.Background(), "tx-1")
+	if err != nil {
+		t.Fatalf("DeleteTransaction() error = %v", err)
+	}
+	if removed != 3 {
+		t.Errorf("removed links = %d, want 3", removed)
+	}
+	if len(client.writes) != 1 {
+		t.Fatalf("ran %d writes, want 1", len(client.writes))
+	}
+	call := client.writes[0]
+	if call.params["transactionId"] != "tx-1" {
+		t.Errorf("transactionId = %v, want tx-1", call.params["transactionId"])
+	}
+	for _, fragment := range []string{
+		"MATCH (t:Transaction {transactionId: $transactionId})",
+		// Undirected, so links other transactions hold pointing at t are counted too;
+		// DETACH DELETE removes them with the rest of t's edges.
+		"(t)-[link:LINKED_TO]-(:Transaction)",
+		"SENT_TO|RECEIVED_FROM {transactionId: t.transactionId}",
+		"DETACH DELETE t",
+		"RETURN removedLinks",
+	} {
+		if !strings.Contains(call.cypher, fragment) {
+			t.Errorf("query lacks %q:\n%s", fragment, call.cypher)
+		}
+	}
+}
+
+func TestDeleteTransactionNotFound(t *testing.T) {
+	_, err := New(&fakeClient{}).DeleteTransaction(context.Background(), "tx-missing")
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+}
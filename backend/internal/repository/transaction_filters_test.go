@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestListTransactionsCurrencyFilter(t *testing.T) {
+	client := &fakeClient{}
+	if _, err := New(client).ListTransactions(context.Background(), ListTransactionsOptions{Currency: " gbp"}); err != nil {
+		t.Fatalf("ListTransactions() error = %v", err)
+	}
+	for _, call := range client.reads {
+		if got := call.params["currency"]; got != "GBP" {
+			t.Errorf("currency = %v, want GBP", got)
+		}
+		if !strings.Contains(call.cypher, `($currency = "" OR toUpper(t.currency) = $currency)`) {
+			t.Errorf("query lacks the currency predicate:\n%s", call.cypher)
+		}
+	}
+}
+
+func TestListTransactionsDeviceAndIPDirectMatch(t *testing.T) {
+	client := &fakeClient{}
+	opts := ListTransactionsOptions{DeviceID: " Dev-AbC ", IPAddress: "2001:DB8::1"}
+	if _, err := New(client).ListTransactions(context.Background(), opts); err != nil {
+		t.Fatalf("ListTransactions() error = %v", err)
+	}
+	for _, call := range client.reads {
+		// Exact matches: trimmed, but not case-folded like the enum filters.
+		if call.params["deviceId"] != "Dev-AbC" || call.params["ipAddress"] != "2001:DB8::1" {
+			t.Errorf("deviceId, ipAddress = %q, %q, want Dev-AbC, 2001:DB8::1", call.params["deviceId"], call.params["ipAddress"])
+		}
+		if call.params["includeLinked"] != false {
+			t.Errorf("includeLinked = %v, want false by default", call.params["includeLinked"])
+		}
+		for _, predicate := range []string{"OR t.deviceId = $deviceId", "OR t.ipAddress = $ipAddress"} {
+			if !strings.Contains(call.cypher, predicate) {
+				t.Errorf("query lacks %q", predicate)
+			}
+		}
+	}
+}
+
+func TestUpsertTransactionStoresDeviceAndIP(t *testing.T) {
+	client := &fakeClient{}
+	tx := domain.Transaction{ID: "TX-1", SenderUserID: "USR-1", ReceiverUserID: "USR-2", Monetary: true, DeviceID: "Dev-AbC", IPAddress: "203.0.113.7"}
+	if err := New(client).UpsertTransaction(context.Background(), tx, nil); err != nil {
+		t.Fatalf("UpsertTransaction() error = %v", err)
+	}
+	// The direct filters match these node properties, not the hashed attributes.
+	props := client.writes[0].params["transaction"].(map[string]any)["props"].(map[string]any)
+	if props["deviceId"] != "Dev-AbC" || props["ipAddress"] != "203.0.113.7" {
+		t.Errorf("stored deviceId, ipAddress = %v, %v", props["deviceId"], props["ipAddress"])
+	}
+}
@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// GetTransaction loads a stored transaction with its participants, resolved from the
+// PARTICIPATED_IN edges, and its metadata decoded from metadataJson. It returns
+// ErrNotFound when the transaction does not exist.
+func (r *Repository) GetTransaction(ctx context.Context, txID string) (domain.Transaction, error) {
+	if txID == "" {
+		return domain.Transaction{}, errors.New("transaction id is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, getTransactionCypher, map[string]any{"transactionId": txID})
+	if err != nil {
+		return domain.Transaction{}, fmt.Errorf("fetch transaction: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.Transaction{}, fmt.Errorf("transaction %s: %w", txID, ErrNotFound)
+	}
+
+	record := res.Records[0]
+	monetary, _ := record["monetary"].(bool)
+	tx := domain.Transaction{
+		ID:              txID,
+		SenderUserID:    toString(record["senderId"]),
+		ReceiverUserID:  toString(record["receiverId"]),
+		Amount:          toFloat64(record["amount"]),
+		Currency:        toString(record["currency"]),
+		Type:            toString(record["type"]),
+		Status:          toString(record["status"]),
+		Channel:         toString(record["channel"]),
+		IPAddress:       toString(record["ipAddress"]),
+		DeviceID:        toString(record["deviceId"]),
+		PaymentMethodID: toString(record["paymentMethodId"]),
+		OriginatorBIC:   toString(record["originatorBic"]),
+		BeneficiaryBIC:  toString(record["beneficiaryBic"]),
+		Monetary:        monetary,
+		ReversalOf:      toString(record["reversalOf"]),
+	}
+	properties, _ := record["properties"].(map[string]any)
+	tx.Metadata = decodeMetadata(toString(record["metadataJson"]), properties)
+	if ts := toTimePtr(record["timestamp"]); ts != nil {
+		tx.Timestamp = *ts
+	}
+	if created := toTimePtr(record["createdAt"]); created != nil {
+		tx.CreatedAt = *created
+	}
+	if updated := toTimePtr(record["updatedAt"]); updated != nil {
+		tx.UpdatedAt = *updated
+	}
+	return tx, nil
+}
+
+// RecomputeTransactionLinks replaces the transaction's attribute edges with attributes
+// and re-derives its outgoing LINKED_TO edges from them, dropping stale links. Links other
+// transactions hold towards this one are left to their own recompute. It returns the new
+// link set, or ErrNotFound when the transaction does not exist.
+func (r *Repository) RecomputeTransactionLinks(ctx context.Context, txID string, attributes []domain.Attribute) ([]domain.LinkedTransaction, error) {
+	if txID == "" {
+		return nil, errors.New("transaction id is required")
+	}
+
+	res, err := r.client.ExecuteWrite(ctx, recomputeTransactionLinksCypher, map[string]any{
+		"transactionId":     txID,
+		"attributes":        attributeParams(attributes),
+		"linkDecayHalfLife": r.linkDecayHalfLife(false),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("recompute links for %s: %w", txID, err)
+	}
+	if len(res.Records) == 0 {
+		return nil, fmt.Errorf("recompute links for %s: %w", txID, ErrNotFound)
+	}
+
+	entries, _ := res.Records[0]["links"].([]any)
+	links := make([]domain.LinkedTransaction, 0, len(entries))
+	for _, entry := range entries {
+		item, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		link := domain.LinkedTransaction{
+			TransactionID: toString(item["otherTransactionId"]),
+			LinkType:      toString(item["linkType"]),
+			AttributeHash: toString(item["attributeHash"]),
+			Score:         toFloat64(item["score"]),
+			DecayedScore:  toFloat64Ptr(item["decayedScore"]),
+		}
+		if ts := toTimePtr(item["updatedAt"]); ts != nil {
+			link.LastUpdated = ts
+		}
+		links = append(links, link)
+	}
+	return links, nil
+}
+
+const getTransactionCypher = `
+MATCH (t:Transaction {transactionId: $transactionId})
+OPTIONAL MATCH (s:User)-[:PARTICIPATED_IN {transactionId: $transactionId, role: "SENDER"}]->(t)
+OPTIONAL MATCH (rcv:User)-[:PARTICIPATED_IN {transactionId: $transactionId, role: "RECEIVER"}]->(t)
+RETURN s.userId AS senderId,
+       rcv.userId AS receiverId,
+       t.amount AS amount,
+       t.currency AS currency,
+       t.type AS type,
+       t.status AS status,
+       t.channel AS channel,
+       t.ipAddress AS ipAddress,
+       t.deviceId AS deviceId,
+       t.paymentMethodId AS paymentMethodId,
+       t.originatorBic AS originatorBic,
+       t.beneficiaryBic AS beneficiaryBic,
+       coalesce(t.monetary, true) AS monetary,
+       t.timestamp AS timestamp,
+       t.createdAt AS createdAt,
+       t.updatedAt AS updatedAt,
+       t.metadataJson AS metadataJson,
+       t.reversalOfTransactionId AS reversalOf,
+       properties(t) AS properties
+LIMIT 1
+`
+
+const recomputeTransactionLinksCypher = `
+MATCH (t:Transaction {transactionId: $transactionId})
+CALL {
+  WITH t
+  OPTIONAL MATCH (t)-[stale:LINKED_TO|HAS_ATTRIBUTE]->()
+  DELETE stale
+}
+CALL {
+  WITH t
+  UNWIND $attributes AS attr
+  MERGE (a:Attribute {attributeType: attr.type, value: attr.value})
+  SET a.rawValue = attr.rawValue
+  MERGE (t)-[hta:HAS_ATTRIBUTE]->(a)
+  SET hta.origin = CASE WHEN attr.origin = "" THEN "TRANSACTION" ELSE attr.origin END
+  WITH t, attr, a
+  MATCH (other:Transaction)-[:HAS_ATTRIBUTE]->(a)
+  WHERE other <> t
+  MERGE (t)-[lt:LINKED_TO {attributeHash: attr.value, linkType: attr.type}]->(other)
+  SET lt.score = attr.score,
+      lt.decayedScore = ` + decayedLinkScore + `,
+      lt.updatedAt = datetime()
+}
+RETURN COLLECT {
+  MATCH (t)-[link:LINKED_TO]->(other:Transaction)
+  RETURN {
+    otherTransactionId: other.transactionId,
+    linkType: link.linkType,
+    attributeHash: link.attributeHash,
+    score: link.score,
+    decayedScore: link.decayedScore,
+    updatedAt: link.updatedAt
+  } AS link
+  ORDER BY link.linkType, link.otherTransactionId
+} AS links
+`
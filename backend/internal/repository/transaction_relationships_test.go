@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestFetchTransactionRelationshipsNotFound(t *testing.T) {
+	client := &fakeClient{}
+	_, err := New(client).FetchTransactionRelationships(context.Background(), "tx-missing", TransactionRelationshipOptions{})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+	if len(client.reads) != 1 {
+		t.Errorf("ran %d reads, want only the existence check", len(client.reads))
+	}
+}
+
+func TestFetchTransactionRelationshipsFoundWithoutLinks(t *testing.T) {
+	client := &fakeClient{read: func(cypher string, _ map[string]any) (graph.Result, error) {
+		if cypher == transactionExistsCypher {
+			return graph.Result{Records: []graph.Record{{"transactionId": "tx-1"}}}, nil
+		}
+		return graph.Result{}, nil
+	}}
+	rel, err := New(client).FetchTransactionRelationships(context.Background(), "tx-1", TransactionRelationshipOptions{})
+	if err != nil {
+		t.Fatalf("FetchTransactionRelationships() error = %v", err)
+	}
+	if rel.TransactionID != "tx-1" {
+		t.Errorf("TransactionID = %q, want tx-1", rel.TransactionID)
+	}
+	if len(rel.Users) != 0 || len(rel.LinkedTransactions) != 0 || len(rel.ReversalChain) != 0 {
+		t.Errorf("relationships = %+v, want every section empty", rel)
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// UserUniqueAttributes returns the attributes linked to the user and to nothing else,
+// i.e. whose only HAS_ATTRIBUTE edge comes from this user. It returns ErrNotFound when
+// the user does not exist.
+func (r *Repository) UserUniqueAttributes(ctx context.Context, userID string) ([]domain.Attribute, error) {
+	if userID == "" {
+		return nil, errors.New("user id is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, userUniqueAttributesCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return nil, fmt.Errorf("fetch unique attributes: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return nil, fmt.Errorf("unique attributes for %s: %w", userID, ErrNotFound)
+	}
+
+	entries, _ := res.Records[0]["attributes"].([]any)
+	attrs := make([]domain.Attribute, 0, len(entries))
+	for _, entry := range entries {
+		item, ok := entry.(map[string]any)
+		if !ok {
+			continue
+		}
+		attrs = append(attrs, domain.Attribute{
+			Type:            toString(item["attributeType"]),
+			Value:           toString(item["value"]),
+			RawValue:        toString(item["rawValue"]),
+			ConfidenceScore: toFloat64(item["confidence"]),
+			Origin:          toString(item["origin"]),
+		})
+	}
+	return attrs, nil
+}
+
+const userUniqueAttributesCypher = `
+MATCH (u:User {userId: $userId})
+RETURN COLLECT {
+  MATCH (u)-[ha:HAS_ATTRIBUTE]->(a:Attribute)
+  WHERE NOT EXISTS { (a)<-[:HAS_ATTRIBUTE]-(other) WHERE other <> u }
+  RETURN {
+    attributeType: a.attributeType,
+    value: a.value,
+    rawValue: a.rawValue,
+    confidence: ha.confidenceScore,
+    origin: ha.origin
+  } AS attr
+  ORDER BY attr.attributeType, attr.value
+} AS attributes
+`
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// addressPredicate matches the user filter comparing a stored property to $country or
+// $city, capturing the property name and the parameter.
+var addressPredicate = regexp.MustCompile(`coalesce\(u\.(\w+), ""\)\)\) = \$(country|city)\b`)
+
+// addressGraph keeps the properties UpsertUser writes for each user. It answers list and
+// count queries by applying the country and city predicates the query itself names to
+// those properties, so a filter on a property ingest does not write matches nobody.
+type addressGraph struct {
+	fakeClient
+	users map[string]map[string]any
+	order []string
+}
+
+func newAddressGraph(t *testing.T) *addressGraph {
+	g := &addressGraph{users: map[string]map[string]any{}}
+	g.write = func(_ string, params map[string]any) (graph.Result, error) {
+		id := toString(params["userId"])
+		if _, ok := g.users[id]; !ok {
+			g.order = append(g.order, id)
+		}
+		g.users[id] = params["props"].(map[string]any)
+		return graph.Result{}, nil
+	}
+	g.read = func(cypher string, params map[string]any) (graph.Result, error) {
+		if cypher == getUserCypher {
+			props, ok := g.users[toString(params["userId"])]
+			if !ok {
+				return graph.Result{}, nil
+			}
+			return graph.Result{Records: []graph.Record{{"user": props}}}, nil
+		}
+		predicates := addressPredicate.FindAllStringSubmatch(cypher, -1)
+		if len(predicates) != 2 {
+			t.Fatalf("user filter compares %d address properties, want country and city:\n%s", len(predicates), cypher)
+		}
+		var recs []graph.Record
+		for _, id := range g.order {
+			props := g.users[id]
+			matched := true
+			for _, p := range predicates {
+				want := toString(params[p[2]])
+				if want != "" && strings.ToLower(strings.TrimSpace(toString(props[p[1]]))) != want {
+					matched = false
+				}
+			}
+			if matched {
+				recs = append(recs, graph.Record{"userId": id})
+			}
+		}
+		if strings.Contains(cypher, "count(u) AS total") {
+			return graph.Result{Records: []graph.Record{{"total": int64(len(recs))}}}, nil
+		}
+		return graph.Result{Records: recs}, nil
+	}
+	return g
+}
+
+func TestUserAddressFiltersMatchIngestedUsers(t *testing.T) {
+	g := newAddressGraph(t)
+	repo := New(g)
+	for _, user := range []domain.User{
+		{ID: "USR-berlin", Address: domain.Address{City: "Berlin", Country: "DE"}},
+		{ID: "USR-munich", Address: domain.Address{City: "Munich", Country: "de"}},
+		{ID: "USR-paris", Address: domain.Address{City: " Paris ", Country: "FR"}},
+		{ID: "USR-none"},
+	} {
+		if err := repo.UpsertUser(context.Background(), user); err != nil {
+			t.Fatalf("UpsertUser(%s) error = %v", user.ID, err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts ListUsersOptions
+		want []string
+	}{
+		{name: "country", opts: ListUsersOptions{Country: "DE"}, want: []string{"USR-berlin", "USR-munich"}},
+		{name: "city", opts: ListUsersOptions{City: "paris"}, want: []string{"USR-paris"}},
+		{name: "country and city", opts: ListUsersOptions{Country: "de", City: "Munich"}, want: []string{"USR-munich"}},
+		{name: "no match", opts: ListUsersOptions{Country: "US"}},
+		{name: "unfiltered", opts: ListUsersOptions{}, want: []string{"USR-berlin", "USR-munich", "USR-paris", "USR-none"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := repo.ListUsers(context.Background(), tt.opts)
+			if err != nil {
+				t.Fatalf("ListUsers() error = %v", err)
+			}
+			var got []string
+			for _, u := range result.Items {
+				got = append(got, u.ID)
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("listed %v, want %v", got, tt.want)
+			}
+			if result.Total != int64(len(tt.want)) {
+				t.Errorf("Total = %d, want %d", result.Total, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestUserAddressReadBackMatchesIngest(t *testing.T) {
+	g := newAddressGraph(t)
+	repo := New(g)
+	address := domain.Address{Line1: "1 Unter den Linden", City: "Berlin", State: "BE", PostalCode: "10117", Country: "DE"}
+	if err := repo.UpsertUser(context.Background(), domain.User{ID: "USR-1", Address: address}); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	// Node properties cannot hold maps, so nothing may be written under a nested address.
+	if _, ok := g.users["USR-1"]["address"]; ok {
+		t.Error("ingest wrote a nested address property")
+	}
+	user, err := repo.GetUser(context.Background(), "USR-1")
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if user.Address != address {
+		t.Errorf("read back address %+v, want %+v", user.Address, address)
+	}
+}
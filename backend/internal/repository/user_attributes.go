@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// AddUserAttributes links attributes to an existing user without rewriting any of its
+// properties, so a newly observed device or address does not need a full profile
+// upsert. Attributes the user already has keep their edge, with its confidence and
+// origin refreshed. It returns how many attributes were newly linked, or ErrNotFound
+// when the user does not exist.
+func (r *Repository) AddUserAttributes(ctx context.Context, userID string, attributes []domain.Attribute) (int, error) {
+	if userID == "" {
+		return 0, errors.New("user id is required")
+	}
+	if len(attributes) == 0 {
+		return 0, errors.New("at least one attribute is required")
+	}
+
+	type attributeKey struct{ attrType, value string }
+	seen := make(map[attributeKey]struct{}, len(attributes))
+	unique := make([]domain.Attribute, 0, len(attributes))
+	for _, attr := range attributes {
+		key := attributeKey{attr.Type, attr.Value}
+		if _, dup := seen[key]; dup {
+			continue
+		}
+		seen[key] = struct{}{}
+		unique = append(unique, attr)
+	}
+
+	res, err := r.client.ExecuteWrite(ctx, addUserAttributesCypher, map[string]any{
+		"userId":     userID,
+		"attributes": attributeParams(unique),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("add attributes to user %s: %w", userID, classifyError(err))
+	}
+	if len(res.Records) == 0 {
+		return 0, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+	return int(toInt64(res.Records[0]["added"])), nil
+}
+
+const addUserAttributesCypher = `
+MATCH (u:User {userId: $userId})
+CALL {
+	WITH u
+	UNWIND $attributes AS attr
+	MERGE (a:Attribute {attributeType: attr.type, value: attr.value})
+	SET a.rawValue = attr.rawValue
+	WITH u, attr, a, EXISTS { (u)-[:HAS_ATTRIBUTE]->(a) } AS existed
+	MERGE (u)-[ha:HAS_ATTRIBUTE]->(a)
+	SET ha.confidenceScore = attr.confidence,
+		ha.origin = CASE WHEN attr.origin = "" THEN "USER" ELSE attr.origin END
+	RETURN sum(CASE WHEN existed THEN 0 ELSE 1 END) AS added
+}
+RETURN added
+`
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// linkedAttributes answers addUserAttributesCypher as the graph would for a user already
+// linked to the attributes in linked, linking the new ones.
+func linkedAttributes(linked map[string]bool) func(string, map[string]any) (graph.Result, error) {
+	return func(_ string, params map[string]any) (graph.Result, error) {
+		added := int64(0)
+		for _, attr := range params["attributes"].([]map[string]any) {
+			key := attr["type"].(string) + "|" + attr["value"].(string)
+			if !linked[key] {
+				linked[key] = true
+				added++
+			}
+		}
+		return graph.Result{Records: []graph.Record{{"added": added}}}, nil
+	}
+}
+
+func TestAddUserAttributesNewAndDuplicate(t *testing.T) {
+	client := &fakeClient{write: linkedAttributes(map[string]bool{"DEVICE|dev-old": true})}
+	repo := New(client)
+	device := domain.Attribute{Type: "DEVICE", Value: "dev-new"}
+
+	added, err := repo.AddUserAttributes(context.Background(), "USR-1", []domain.Attribute{device})
+	if err != nil {
+		t.Fatalf("AddUserAttributes() error = %v", err)
+	}
+	if added != 1 {
+		t.Errorf("new attribute: added = %d, want 1", added)
+	}
+
+	added, err = repo.AddUserAttributes(context.Background(), "USR-1", []domain.Attribute{device, {Type: "DEVICE", Value: "dev-old"}})
+	if err != nil {
+		t.Fatalf("AddUserAttributes() error = %v", err)
+	}
+	if added != 0 {
+		t.Errorf("already linked attributes: added = %d, want 0", added)
+	}
+}
+
+func TestAddUserAttributesCollapsesRepeats(t *testing.T) {
+	client := &fakeClient{write: linkedAttributes(map[string]bool{})}
+	device := domain.Attribute{Type: "DEVICE", Value: "dev-1"}
+	added, err := New(client).AddUserAttributes(context.Background(), "USR-1", []domain.Attribute{device, device})
+	if err != nil {
+		t.Fatalf("AddUserAttributes() error = %v", err)
+	}
+	if added != 1 {
+		t.Errorf("added = %d, want 1", added)
+	}
+	if got := len(client.writes[0].params["attributes"].([]map[string]any)); got != 1 {
+		t.Errorf("wrote %d attributes, want the repeat dropped", got)
+	}
+	if _, ok := client.writes[0].params["props"]; ok {
+		t.Error("attribute update writes user properties")
+	}
+}
+
+func TestAddUserAttributesUnknownUser(t *testing.T) {
+	_, err := New(&fakeClient{}).AddUserAttributes(context.Background(), "USR-missing", []domain.Attribute{{Type: "DEVICE", Value: "dev-1"}})
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// BatchWriteResult counts the outcome of a batched upsert.
+type BatchWriteResult struct {
+	// Written is the number of distinct records written.
+	Written int
+	// Unchanged is the number of records skipped because their content hash matched.
+	Unchanged int
+}
+
+// UpsertUsers writes users, with their attributes and payment methods, in one statement
+// instead of one round-trip per user. When a user ID repeats, the last entry wins. With
+// content hashing, users whose stored hash matches are left out, at the cost of one
+// extra read for the whole batch.
+func (r *Repository) UpsertUsers(ctx context.Context, users []domain.User) (BatchWriteResult, error) {
+	rows := make([]map[string]any, 0, len(users))
+	index := make(map[string]int, len(users))
+	hashes := make(map[string]string, len(users))
+	for _, user := range users {
+		if user.ID == "" {
+			return BatchWriteResult{}, errors.New("user id is required")
+		}
+		row := userWriteParams(user)
+		if r.contentHashing {
+			hash, err := userContentHash(user, row)
+			if err != nil {
+				return BatchWriteResult{}, fmt.Errorf("upsert user %s: %w", user.ID, err)
+			}
+			row["props"].(map[string]any)[contentHashProperty] = hash
+			hashes[user.ID] = hash
+		}
+		if i, ok := index[user.ID]; ok {
+			rows[i] = row
+			continue
+		}
+		index[user.ID] = len(rows)
+		rows = append(rows, row)
+	}
+
+	var result BatchWriteResult
+	if r.contentHashing && len(rows) > 0 {
+		ids := make([]string, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row["userId"].(string))
+		}
+		res, err := r.client.ExecuteRead(ctx, usersContentHashCypher, map[string]any{"userIds": ids})
+		if err != nil {
+			return BatchWriteResult{}, fmt.Errorf("upsert users: read content hashes: %w", err)
+		}
+		unchanged := make(map[string]bool, len(res.Records))
+		for _, record := range res.Records {
+			id := toString(record["userId"])
+			unchanged[id] = toString(record[contentHashProperty]) == hashes[id]
+		}
+		changed := rows[:0]
+		for _, row := range rows {
+			if !unchanged[row["userId"].(string)] {
+				changed = append(changed, row)
+			}
+		}
+		result.Unchanged = len(rows) - len(changed)
+		rows = changed
+	}
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	if _, err := r.client.ExecuteWrite(ctx, upsertUsersCypher, map[string]any{"users": rows}); err != nil {
+		return result, fmt.Errorf("upsert %d users: %w", len(rows), classifyError(err))
+	}
+	result.Written = len(rows)
+	return result, nil
+}
+
+// userWriteParams holds every value written for user. The content hash covers exactly
+// these, with the stored hash itself unset.
+func userWriteParams(user domain.User) map[string]any {
+	props := userProperties(user)
+	props[contentHashProperty] = nil
+	return map[string]any{
+		"userId":         user.ID,
+		"props":          props,
+		"attributes":     attributeParams(user.Attributes),
+		"paymentMethods": paymentMethodParams(user.PaymentMethods),
+	}
+}
+
+const usersContentHashCypher = `
+UNWIND $userIds AS userId
+MATCH (u:User {userId: userId})
+RETURN userId, u.contentHash AS contentHash
+`
+
+// upsertUsersCypher is upsertUserCypher over a list of rows. Rows must have distinct user
+// IDs so the created count, applied to Stats once per batch, is exact.
+const upsertUsersCypher = `
+UNWIND $users AS row
+OPTIONAL MATCH (existing:User {userId: row.userId})
+WITH row, existing IS NULL AS created
+MERGE (u:User {userId: row.userId})
+SET u += row.props
+WITH u, row, created
+FOREACH (attr IN row.attributes |
+	MERGE (a:Attribute {attributeType: attr.type, value: attr.value})
+	SET a.rawValue = attr.rawValue
+	MERGE (u)-[ha:HAS_ATTRIBUTE]->(a)
+	SET ha.confidenceScore = attr.confidence,
+		ha.origin = CASE WHEN attr.origin = "" THEN "USER" ELSE attr.origin END
+)
+FOREACH (pm IN row.paymentMethods |
+	MERGE (p:PaymentMethod {paymentMethodId: pm.id})
+	SET p += pm.props
+	MERGE (u)-[upm:USES_PAYMENT_METHOD]->(p)
+	SET upm.firstUsedAt = pm.firstUsedAt
+	SET upm.lastUsedAt = pm.lastUsedAt
+)
+WITH sum(CASE WHEN created THEN 1 ELSE 0 END) AS createdUsers, count(*) AS written
+FOREACH (_ IN CASE WHEN createdUsers > 0 THEN [1] ELSE [] END |
+	MERGE (s:Stats {id: "global"})
+	SET s._lock = true
+	SET s.users = coalesce(s.users, 0) + createdUsers
+	REMOVE s._lock
+)
+RETURN written
+`
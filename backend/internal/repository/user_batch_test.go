@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestUpsertUsersWritesOneStatement(t *testing.T) {
+	var users []domain.User
+	for i := 0; i < 5; i++ {
+		users = append(users, domain.User{ID: fmt.Sprintf("USR-%d", i), Email: fmt.Sprintf("user%d@example.com", i)})
+	}
+	// A repeated ID is written once, with its last payload.
+	users = append(users, domain.User{ID: "USR-0", Email: "latest@example.com"})
+
+	client := &fakeClient{}
+	result, err := New(client).UpsertUsers(context.Background(), users)
+	if err != nil {
+		t.Fatalf("UpsertUsers() error = %v", err)
+	}
+	if len(client.writes) != 1 {
+		t.Fatalf("ran %d writes, want 1 for the whole batch", len(client.writes))
+	}
+	if result.Written != 5 {
+		t.Errorf("Written = %d, want 5", result.Written)
+	}
+	rows := client.writes[0].params["users"].([]map[string]any)
+	if len(rows) != 5 {
+		t.Fatalf("statement carries %d rows, want 5", len(rows))
+	}
+	if got := rows[0]["props"].(map[string]any)["email"]; got != "latest@example.com" {
+		t.Errorf("USR-0 email = %v, want the last payload", got)
+	}
+}
+
+func TestUpsertUsersEmptyBatchWritesNothing(t *testing.T) {
+	client := &fakeClient{}
+	if _, err := New(client).UpsertUsers(context.Background(), nil); err != nil {
+		t.Fatalf("UpsertUsers() error = %v", err)
+	}
+	if len(client.writes) != 0 {
+		t.Errorf("ran %d writes for an empty batch, want 0", len(client.writes))
+	}
+}
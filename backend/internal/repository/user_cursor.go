@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// ErrInvalidCursor reports a user list cursor that cannot be decoded, or that was issued
+// for a different sort than the one requested.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// userCursor is the decoded form of the opaque After token of ListUsersOptions. SortKey
+// is only set for sorts other than userId.
+type userCursor struct {
+	SortField string   `json:"s"`
+	SortOrder string   `json:"o"`
+	SortKey   *float64 `json:"k,omitempty"`
+	UserID    string   `json:"id"`
+}
+
+// cursorSortField returns the normalized sort field of a cursor listing, or false when
+// field cannot be paged by cursor. Only sort keys that are never null can be compared
+// with the keyset predicate, so fullName, createdAt and updatedAt need page numbers.
+func cursorSortField(field string) (string, bool) {
+	switch strings.ToLower(field) {
+	case "", "userid":
+		return "userid", true
+	case "riskscore":
+		return "riskscore", true
+	default:
+		return "", false
+	}
+}
+
+func cursorSortOrder(order string) string {
+	if strings.EqualFold(order, "DESC") {
+		return "DESC"
+	}
+	return "ASC"
+}
+
+// encodeUserCursor returns the token that resumes a listing sorted by field and order
+// after last.
+func encodeUserCursor(field, order string, last domain.UserSummary) (string, error) {
+	cursor := userCursor{SortField: field, SortOrder: order, UserID: last.ID}
+	if field == "riskscore" {
+		key := last.RiskScore
+		cursor.SortKey = &key
+	}
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", fmt.Errorf("encode cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeUserCursor decodes token and checks it was issued for field and order.
+func decodeUserCursor(token, field, order string) (userCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return userCursor{}, ErrInvalidCursor
+	}
+	var cursor userCursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.UserID == "" {
+		return userCursor{}, ErrInvalidCursor
+	}
+	if cursor.SortField != field || cursor.SortOrder != order {
+		return userCursor{}, fmt.Errorf("%w: issued for sort %s %s", ErrInvalidCursor, cursor.SortField, cursor.SortOrder)
+	}
+	if field == "riskscore" && cursor.SortKey == nil {
+		return userCursor{}, ErrInvalidCursor
+	}
+	return cursor, nil
+}
+
+// userCursorPredicate returns the condition, appended to userFilterClause, that keeps
+// only users after the cursor in the order userOrderClause gives field and order.
+func userCursorPredicate(field, order string) string {
+	cmp := ">"
+	if order == "DESC" {
+		cmp = "<"
+	}
+	if field == "riskscore" {
+		return fmt.Sprintf("  AND (coalesce(u.riskScore, 0.0) %[1]s $afterKey OR (coalesce(u.riskScore, 0.0) = $afterKey AND u.userId %[1]s $afterId))\n", cmp)
+	}
+	return fmt.Sprintf("  AND u.userId %s $afterId\n", cmp)
+}
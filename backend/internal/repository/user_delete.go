@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// DeleteUser detach-deletes a user, dropping every relationship it has, and returns
+// ErrNotFound when the user does not exist. Transactions the user took part in are kept.
+// With purgeOrphans, the user's former Attribute and PaymentMethod nodes that no longer
+// have any relationship are deleted too, and their number is returned; other orphans
+// are left for PruneOrphans.
+func (r *Repository) DeleteUser(ctx context.Context, userID string, purgeOrphans bool) (int, error) {
+	if userID == "" {
+		return 0, errors.New("user id is required")
+	}
+
+	res, err := r.client.ExecuteWrite(ctx, deleteUserCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return 0, fmt.Errorf("delete user %s: %w", userID, classifyError(err))
+	}
+	if len(res.Records) == 0 {
+		return 0, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+	if !purgeOrphans {
+		return 0, nil
+	}
+
+	record := res.Records[0]
+	res, err = r.client.ExecuteWrite(ctx, purgeUserOrphansCypher, map[string]any{
+		"attributes":     record["attributes"],
+		"paymentMethods": record["paymentMethods"],
+	})
+	if err != nil {
+		return 0, fmt.Errorf("purge orphans of user %s: %w", userID, classifyError(err))
+	}
+	if len(res.Records) == 0 {
+		return 0, nil
+	}
+	return int(toInt64(res.Records[0]["removed"])), nil
+}
+
+// deleteUserCypher returns the keys of the user's attributes and payment methods so
+// purgeUserOrphansCypher can find them once the user is gone.
+const deleteUserCypher = `
+MATCH (u:User {userId: $userId})
+CALL {
+	WITH u
+	OPTIONAL MATCH (u)-[:HAS_ATTRIBUTE]->(a:Attribute)
+	RETURN collect(DISTINCT {type: a.attributeType, value: a.value}) AS attributes
+}
+CALL {
+	WITH u
+	OPTIONAL MATCH (u)-[:USES_PAYMENT_METHOD]->(p:PaymentMethod)
+	RETURN collect(DISTINCT p.paymentMethodId) AS paymentMethods
+}
+DETACH DELETE u
+WITH attributes, paymentMethods
+MERGE (s:Stats {id: "global"})
+SET s._lock = true
+SET s.users = CASE WHEN coalesce(s.users, 0) > 0 THEN s.users - 1 ELSE 0 END
+REMOVE s._lock
+RETURN [attr IN attributes WHERE attr.type IS NOT NULL] AS attributes, paymentMethods
+`
+
+const purgeUserOrphansCypher = `
+CALL {
+	UNWIND $attributes AS key
+	MATCH (a:Attribute {attributeType: key.type, value: key.value})
+	WHERE NOT (a)--()
+	DELETE a
+	RETURN count(a) AS attributes
+}
+CALL {
+	UNWIND $paymentMethods AS id
+	MATCH (p:PaymentMethod {paymentMethodId: id})
+	WHERE NOT (p)--()
+	DELETE p
+	RETURN count(p) AS paymentMethods
+}
+RETURN attributes + paymentMethods AS removed
+`
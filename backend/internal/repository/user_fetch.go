@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// GetUser returns a user's full profile, as UpsertUser stores it, with every attribute
+// and payment method linked to it, including derived attributes. Attributes are ordered
+// by type and value and payment methods by ID. It returns ErrNotFound when the user does
+// not exist.
+func (r *Repository) GetUser(ctx context.Context, userID string) (domain.User, error) {
+	if userID == "" {
+		return domain.User{}, errors.New("user id is required")
+	}
+
+	res, err := r.client.ExecuteRead(ctx, getUserCypher, map[string]any{"userId": userID})
+	if err != nil {
+		return domain.User{}, fmt.Errorf("get user: %w", err)
+	}
+	if len(res.Records) == 0 {
+		return domain.User{}, fmt.Errorf("user %s: %w", userID, ErrNotFound)
+	}
+
+	record := res.Records[0]
+	props, _ := record["user"].(map[string]any)
+	user := domain.User{
+		ID:        userID,
+		FullName:  toString(props["fullName"]),
+		Email:     toString(props["email"]),
+		Phone:     toString(props["phone"]),
+		KYCStatus: toString(props["kycStatus"]),
+		RiskScore: toFloat64(props["riskScore"]),
+		Address: domain.Address{
+			Line1:      toString(props["addressLine1"]),
+			Line2:      toString(props["addressLine2"]),
+			City:       toString(props["addressCity"]),
+			State:      toString(props["addressState"]),
+			PostalCode: toString(props["addressPostalCode"]),
+			Country:    toString(props["addressCountry"]),
+		},
+		DateOfBirth:    toTimePtr(props["dateOfBirth"]),
+		Attributes:     []domain.Attribute{},
+		PaymentMethods: []domain.PaymentMethod{},
+	}
+	if created := toTimePtr(props["createdAt"]); created != nil {
+		user.CreatedAt = *created
+	}
+	if updated := toTimePtr(props["updatedAt"]); updated != nil {
+		user.UpdatedAt = *updated
+	}
+	if attrs, ok := record["attributes"].([]any); ok {
+		for _, entry := range attrs {
+			row, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			user.Attributes = append(user.Attributes, domain.Attribute{
+				Type:            toString(row["type"]),
+				Value:           toString(row["value"]),
+				RawValue:        toString(row["rawValue"]),
+				ConfidenceScore: toFloat64(row["confidence"]),
+				Origin:          toString(row["origin"]),
+			})
+		}
+	}
+	if methods, ok := record["paymentMethods"].([]any); ok {
+		for _, entry := range methods {
+			row, ok := entry.(map[string]any)
+			if !ok {
+				continue
+			}
+			user.PaymentMethods = append(user.PaymentMethods, domain.PaymentMethod{
+				ID:          toString(row["id"]),
+				MethodType:  toString(row["methodType"]),
+				Provider:    toString(row["provider"]),
+				Masked:      toString(row["masked"]),
+				Fingerprint: toString(row["fingerprint"]),
+				FirstUsedAt: toTimePtr(row["firstUsedAt"]),
+				LastUsedAt:  toTimePtr(row["lastUsedAt"]),
+			})
+		}
+	}
+	return user, nil
+}
+
+const getUserCypher = `
+MATCH (u:User {userId: $userId})
+CALL {
+	WITH u
+	OPTIONAL MATCH (u)-[ha:HAS_ATTRIBUTE]->(a:Attribute)
+	WITH ha, a
+	ORDER BY a.attributeType, a.value
+	RETURN collect(CASE WHEN a IS NULL THEN null ELSE {
+		type: a.attributeType,
+		value: a.value,
+		rawValue: a.rawValue,
+		confidence: ha.confidenceScore,
+		origin: ha.origin
+	} END) AS attributes
+}
+CALL {
+	WITH u
+	OPTIONAL MATCH (u)-[upm:USES_PAYMENT_METHOD]->(p:PaymentMethod)
+	WITH upm, p
+	ORDER BY p.paymentMethodId
+	RETURN collect(CASE WHEN p IS NULL THEN null ELSE {
+		id: p.paymentMethodId,
+		methodType: p.methodType,
+		provider: p.provider,
+		masked: p.masked,
+		fingerprint: p.fingerprint,
+		firstUsedAt: upm.firstUsedAt,
+		lastUsedAt: upm.lastUsedAt
+	} END) AS paymentMethods
+}
+RETURN properties(u) AS user, attributes, paymentMethods
+`
@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserFilterParamsCreatedRange(t *testing.T) {
+	after := time.Date(2024, 1, 1, 2, 0, 0, 0, time.FixedZone("CET", 3600))
+	before := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		opts       ListUsersOptions
+		wantAfter  string
+		wantBefore string
+	}{
+		{name: "open range", opts: ListUsersOptions{}},
+		{name: "only after", opts: ListUsersOptions{CreatedAfter: &after}, wantAfter: "2024-01-01T01:00:00Z"},
+		{name: "only before", opts: ListUsersOptions{CreatedBefore: &before}, wantBefore: "2024-06-30T00:00:00Z"},
+		{name: "zero time is no bound", opts: ListUsersOptions{CreatedAfter: &time.Time{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := userFilterParams(tt.opts)
+			// userFilterClause skips a bound whose parameter is "".
+			if params["createdAfter"] != tt.wantAfter || params["createdBefore"] != tt.wantBefore {
+				t.Errorf("createdAfter, createdBefore = %q, %q, want %q, %q", params["createdAfter"], params["createdBefore"], tt.wantAfter, tt.wantBefore)
+			}
+		})
+	}
+}
@@ -0,0 +1,772 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+	"github.com/vanshika/fintrace/backend/internal/service"
+)
+
+func (h *APIHandlers) handleCompareUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	userA := strings.TrimSpace(query.Get("a"))
+	userB := strings.TrimSpace(query.Get("b"))
+	if userA == "" || userB == "" {
+		writeError(w, http.StatusBadRequest, "query parameters a and b are required")
+		return
+	}
+	if userA == userB {
+		writeError(w, http.StatusBadRequest, "a and b must be different users")
+		return
+	}
+
+	comparison, err := h.service.CompareUsers(r.Context(), userA, userB)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "one or both users not found")
+			return
+		}
+		h.logger.Error("failed to compare users", "error", err, "userA", userA, "userB", userB)
+		writeServerError(w, err, "failed to compare users")
+		return
+	}
+
+	response := userComparisonResponse{
+		UserA:                userA,
+		UserB:                userB,
+		Similarity:           comparison.Similarity,
+		FieldMatches:         []string{},
+		SharedAttributes:     []attributeRefResponse{},
+		CommonCounterparties: []string{},
+		DirectTransactions:   []userDirectConnection{},
+	}
+	response.FieldMatches = append(response.FieldMatches, comparison.FieldMatches...)
+	response.CommonCounterparties = append(response.CommonCounterparties, comparison.CommonCounterparties...)
+	for _, attr := range comparison.SharedAttributes {
+		response.SharedAttributes = append(response.SharedAttributes, attributeRefResponse{
+			AttributeType: attr.AttributeType,
+			AttributeHash: attr.AttributeHash,
+		})
+	}
+	for _, link := range comparison.DirectTransactions {
+		response.DirectTransactions = append(response.DirectTransactions, userDirectConnection{
+			UserID:        link.UserID,
+			LinkType:      link.LinkType,
+			Direction:     link.Direction,
+			TransactionID: link.TransactionID,
+			Amount:        link.Amount,
+			Currency:      link.Currency,
+			Timestamp:     formatTimePtr(link.Timestamp),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *APIHandlers) handleIsolatedUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	pagination, err := parsePaginationParams(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	result, err := h.service.IsolatedUsers(r.Context(), pagination.Page, pagination.PageSize)
+	if err != nil {
+		h.logger.Error("failed to list isolated users", "error", err)
+		writeServerError(w, err, "failed to list isolated users")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, h.newListUsersResponse(r, result))
+}
+
+func (h *APIHandlers) handleComponents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	minSize := repository.DefaultComponentMinSize
+	if raw := r.URL.Query().Get("minSize"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "minSize must be a positive integer")
+			return
+		}
+		minSize = parsed
+	}
+
+	result, err := h.service.ConnectedComponents(r.Context(), minSize)
+	if err != nil {
+		h.logger.Error("failed to compute connected components", "error", err, "minSize", minSize)
+		writeServerError(w, err, "failed to compute connected components")
+		return
+	}
+
+	resp := componentsResponse{
+		MinSize:    result.MinSize,
+		Algorithm:  result.Algorithm,
+		Truncated:  result.Truncated,
+		Components: make([]componentResponse, 0, len(result.Components)),
+	}
+	for _, component := range result.Components {
+		resp.Components = append(resp.Components, componentResponse{
+			ComponentID: component.ID,
+			Size:        component.Size,
+			UserIDs:     component.UserIDs,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleCentrality(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	metric := strings.ToLower(strings.TrimSpace(query.Get("metric")))
+	if metric == "" {
+		metric = repository.CentralityPageRank
+	}
+	if !slices.Contains(repository.CentralityMetrics, metric) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported metric %q; expected one of %s", metric, strings.Join(repository.CentralityMetrics, ", ")))
+		return
+	}
+	limit := repository.DefaultCentralityLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxCentralityLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxCentralityLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	result, err := h.service.UserCentrality(r.Context(), repository.CentralityOptions{Metric: metric, Limit: limit})
+	if err != nil {
+		if errors.Is(err, repository.ErrGDSUnavailable) {
+			writeError(w, http.StatusNotImplemented, metric+" requires the Graph Data Science plugin")
+			return
+		}
+		h.logger.Error("failed to compute user centrality", "error", err, "metric", metric)
+		writeServerError(w, err, "failed to compute user centrality")
+		return
+	}
+
+	resp := centralityResponse{
+		Metric: result.Metric,
+		Users:  make([]centralUserResponse, 0, len(result.Users)),
+	}
+	redact := h.shouldRedactPII(r)
+	for _, user := range result.Users {
+		summary := user.User
+		if redact {
+			summary = summary.Redacted()
+		}
+		resp.Users = append(resp.Users, centralUserResponse{
+			userSummaryResponse: newUserSummaryResponse(summary),
+			Score:               user.Score,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleHubs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	limit := repository.DefaultHubLimit
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxHubLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxHubLimit))
+			return
+		}
+		limit = parsed
+	}
+
+	hubs, err := h.service.TopConnectedUsers(r.Context(), limit)
+	if err != nil {
+		h.logger.Error("failed to fetch top connected users", "error", err, "limit", limit)
+		writeServerError(w, err, "failed to fetch top connected users")
+		return
+	}
+
+	resp := hubsResponse{Users: make([]hubUserResponse, 0, len(hubs))}
+	redact := h.shouldRedactPII(r)
+	for _, hub := range hubs {
+		summary := hub.User
+		if redact {
+			summary = summary.Redacted()
+		}
+		resp.Users = append(resp.Users, hubUserResponse{
+			userSummaryResponse: newUserSummaryResponse(summary),
+			Degree:              hub.Degree,
+			InDegree:            hub.InDegree,
+			OutDegree:           hub.OutDegree,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+type hubsResponse struct {
+	Users []hubUserResponse `json:"users"`
+}
+
+type hubUserResponse struct {
+	userSummaryResponse
+	Degree    int `json:"degree"`
+	InDegree  int `json:"inDegree"`
+	OutDegree int `json:"outDegree"`
+}
+
+type centralityResponse struct {
+	Metric string                `json:"metric"`
+	Users  []centralUserResponse `json:"users"`
+}
+
+type centralUserResponse struct {
+	userSummaryResponse
+	Score float64 `json:"score"`
+}
+
+func (h *APIHandlers) handleClusterProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req clusterProfileRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.UserIDs) == 0 {
+		writeError(w, http.StatusBadRequest, "userIds must contain at least one ID")
+		return
+	}
+	if len(req.UserIDs) > repository.MaxClusterProfileUsers {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("userIds must contain at most %d IDs", repository.MaxClusterProfileUsers))
+		return
+	}
+	for i, id := range req.UserIDs {
+		req.UserIDs[i] = strings.TrimSpace(id)
+		if req.UserIDs[i] == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("userIds[%d] is empty", i))
+			return
+		}
+	}
+
+	profile, err := h.service.ClusterProfile(r.Context(), req.UserIDs)
+	if err != nil {
+		h.logger.Error("failed to profile cluster", "error", err, "users", len(req.UserIDs))
+		writeServerError(w, err, "failed to profile cluster")
+		return
+	}
+
+	resp := clusterProfileResponse{
+		Users:      profile.Users,
+		FoundUsers: profile.FoundUsers,
+		Types:      make([]clusterAttributeShareResponse, 0, len(profile.Types)),
+	}
+	for _, share := range profile.Types {
+		resp.Types = append(resp.Types, clusterAttributeShareResponse{
+			AttributeType: share.AttributeType,
+			Attributes:    share.Attributes,
+			Pairs:         share.Pairs,
+			Users:         share.Users,
+			Share:         share.Share,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleAttributeClusters(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	attributeType := strings.ToUpper(strings.TrimSpace(query.Get("type")))
+	minUsers := repository.DefaultAttributeClusterMinUsers
+	if raw := query.Get("minUsers"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 2 {
+			writeError(w, http.StatusBadRequest, "minUsers must be an integer of at least 2")
+			return
+		}
+		minUsers = parsed
+	}
+	maxUsers := repository.DefaultAttributeClusterMaxUsers
+	if raw := query.Get("maxUsers"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxAttributeClusterUsers {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("maxUsers must be between 1 and %d", repository.MaxAttributeClusterUsers))
+			return
+		}
+		maxUsers = parsed
+	}
+	limit := repository.DefaultAttributeClusterLimit
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxAttributeClusterLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxAttributeClusterLimit))
+			return
+		}
+		limit = parsed
+	}
+	order := strings.ToLower(strings.TrimSpace(query.Get("order")))
+	if order == "" {
+		order = repository.AttributeClusterOrderSize
+	}
+	if !slices.Contains(repository.AttributeClusterOrders, order) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported order %q; expected one of %s", order, strings.Join(repository.AttributeClusterOrders, ", ")))
+		return
+	}
+
+	result, err := h.service.AttributeClusters(r.Context(), repository.AttributeClusterOptions{
+		Type:     attributeType,
+		MinUsers: minUsers,
+		MaxUsers: maxUsers,
+		Limit:    limit,
+		Order:    order,
+	})
+	if err != nil {
+		h.logger.Error("failed to fetch attribute clusters", "error", err, "type", attributeType, "minUsers", minUsers, "limit", limit)
+		writeServerError(w, err, "failed to fetch attribute clusters")
+		return
+	}
+
+	resp := attributeClustersResponse{
+		AttributeType: result.AttributeType,
+		MinUsers:      result.MinUsers,
+		Order:         result.Order,
+		Limit:         limit,
+		Truncated:     result.Truncated,
+		Clusters:      make([]attributeClusterResponse, 0, len(result.Clusters)),
+	}
+	// Raw values of emails, phones and addresses are personal data; the hash still
+	// identifies the attribute.
+	redact := h.shouldRedactPII(r)
+	for _, cluster := range result.Clusters {
+		item := attributeClusterResponse{
+			AttributeType: cluster.AttributeType,
+			AttributeHash: cluster.AttributeHash,
+			RawValue:      cluster.RawValue,
+			UserCount:     cluster.UserCount,
+			UserIDs:       cluster.UserIDs,
+		}
+		if redact {
+			item.RawValue = domain.RedactRawValue(item.RawValue)
+		}
+		resp.Clusters = append(resp.Clusters, item)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+type attributeClustersResponse struct {
+	AttributeType string                     `json:"attributeType,omitempty"`
+	MinUsers      int                        `json:"minUsers"`
+	Order         string                     `json:"order"`
+	Limit         int                        `json:"limit"`
+	Truncated     bool                       `json:"truncated"`
+	Clusters      []attributeClusterResponse `json:"clusters"`
+}
+
+type attributeClusterResponse struct {
+	AttributeType string   `json:"attributeType"`
+	AttributeHash string   `json:"attributeHash"`
+	RawValue      string   `json:"rawValue,omitempty"`
+	UserCount     int      `json:"userCount"`
+	UserIDs       []string `json:"userIds"`
+}
+
+func (h *APIHandlers) handleCorrelatedUsers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	minShared := repository.DefaultCorrelatedMinTypes
+	if raw := r.URL.Query().Get("minShared"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, "minShared must be a positive integer")
+			return
+		}
+		minShared = parsed
+	}
+
+	result, err := h.service.UsersWithSharedAttributeCount(r.Context(), minShared)
+	if err != nil {
+		h.logger.Error("failed to fetch correlated users", "error", err, "minShared", minShared)
+		writeServerError(w, err, "failed to fetch correlated users")
+		return
+	}
+
+	resp := correlatedUsersResponse{
+		MinShared: result.MinSharedTypes,
+		Truncated: result.Truncated,
+		Pairs:     make([]correlatedUserPairResponse, 0, len(result.Pairs)),
+	}
+	for _, pair := range result.Pairs {
+		resp.Pairs = append(resp.Pairs, correlatedUserPairResponse{
+			UserA:       pair.UserA,
+			UserB:       pair.UserB,
+			SharedCount: len(pair.SharedTypes),
+			SharedTypes: pair.SharedTypes,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+type correlatedUsersResponse struct {
+	MinShared int                          `json:"minShared"`
+	Truncated bool                         `json:"truncated"`
+	Pairs     []correlatedUserPairResponse `json:"pairs"`
+}
+
+type correlatedUserPairResponse struct {
+	UserA       string   `json:"userA"`
+	UserB       string   `json:"userB"`
+	SharedCount int      `json:"sharedCount"`
+	SharedTypes []string `json:"sharedTypes"`
+}
+
+type clusterProfileRequest struct {
+	UserIDs []string `json:"userIds"`
+}
+
+type clusterProfileResponse struct {
+	Users      int                             `json:"users"`
+	FoundUsers int                             `json:"foundUsers"`
+	Types      []clusterAttributeShareResponse `json:"types"`
+}
+
+type clusterAttributeShareResponse struct {
+	AttributeType string  `json:"attributeType"`
+	Attributes    int     `json:"attributes"`
+	Pairs         int     `json:"pairs"`
+	Users         int     `json:"users"`
+	Share         float64 `json:"share"`
+}
+
+type componentsResponse struct {
+	MinSize    int                 `json:"minSize"`
+	Algorithm  string              `json:"algorithm"`
+	Truncated  bool                `json:"truncated"`
+	Components []componentResponse `json:"components"`
+}
+
+type componentResponse struct {
+	ComponentID string   `json:"componentId"`
+	Size        int      `json:"size"`
+	UserIDs     []string `json:"userIds"`
+}
+
+func (h *APIHandlers) handleSharedPayment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	fingerprint := strings.TrimSpace(r.URL.Query().Get("fingerprint"))
+	if fingerprint == "" {
+		writeError(w, http.StatusBadRequest, "fingerprint is required")
+		return
+	}
+
+	shared, err := h.service.UsersByPaymentFingerprint(r.Context(), fingerprint)
+	if err != nil {
+		h.logger.Error("failed to fetch users by payment fingerprint", "error", err)
+		writeServerError(w, err, "failed to fetch users by payment fingerprint")
+		return
+	}
+
+	response := sharedPaymentResponse{
+		Fingerprint: shared.Fingerprint,
+		Users:       []sharedPaymentUserResponse{},
+		Truncated:   shared.Truncated,
+	}
+	redact := h.shouldRedactPII(r)
+	for _, user := range shared.Users {
+		summary := user.User
+		if redact {
+			summary = summary.Redacted()
+		}
+		paymentMethodIDs := user.PaymentMethodIDs
+		if paymentMethodIDs == nil {
+			paymentMethodIDs = []string{}
+		}
+		response.Users = append(response.Users, sharedPaymentUserResponse{
+			userSummaryResponse: newUserSummaryResponse(summary),
+			PaymentMethodIDs:    paymentMethodIDs,
+			FirstUsedAt:         formatTimePtr(user.FirstUsedAt),
+			LastUsedAt:          formatTimePtr(user.LastUsedAt),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+type sharedPaymentUserResponse struct {
+	userSummaryResponse
+	PaymentMethodIDs []string `json:"paymentMethodIds"`
+	FirstUsedAt      string   `json:"firstUsedAt,omitempty"`
+	LastUsedAt       string   `json:"lastUsedAt,omitempty"`
+}
+
+type sharedPaymentResponse struct {
+	Fingerprint string                      `json:"fingerprint"`
+	Users       []sharedPaymentUserResponse `json:"users"`
+	Truncated   bool                        `json:"truncated"`
+}
+
+func (h *APIHandlers) handleSharedPaymentMethods(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	minUsers := repository.DefaultSharedPaymentMinUsers
+	if raw := r.URL.Query().Get("minUsers"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 2 {
+			writeError(w, http.StatusBadRequest, "minUsers must be an integer of at least 2")
+			return
+		}
+		minUsers = parsed
+	}
+
+	result, err := h.service.UsersSharingPaymentMethods(r.Context(), minUsers)
+	if err != nil {
+		h.logger.Error("failed to fetch shared payment methods", "error", err, "minUsers", minUsers)
+		writeServerError(w, err, "failed to fetch shared payment methods")
+		return
+	}
+
+	resp := sharedPaymentMethodsResponse{
+		MinUsers:       result.MinUsers,
+		Truncated:      result.Truncated,
+		PaymentMethods: make([]sharedPaymentMethodResponse, 0, len(result.Clusters)),
+	}
+	for _, cluster := range result.Clusters {
+		resp.PaymentMethods = append(resp.PaymentMethods, sharedPaymentMethodResponse{
+			PaymentMethodID: cluster.PaymentMethodID,
+			MethodType:      cluster.MethodType,
+			Provider:        cluster.Provider,
+			Fingerprint:     cluster.Fingerprint,
+			UserCount:       cluster.UserCount,
+			UserIDs:         cluster.UserIDs,
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+type sharedPaymentMethodsResponse struct {
+	MinUsers       int                           `json:"minUsers"`
+	Truncated      bool                          `json:"truncated"`
+	PaymentMethods []sharedPaymentMethodResponse `json:"paymentMethods"`
+}
+
+type sharedPaymentMethodResponse struct {
+	PaymentMethodID string   `json:"paymentMethodId"`
+	MethodType      string   `json:"methodType,omitempty"`
+	Provider        string   `json:"provider,omitempty"`
+	Fingerprint     string   `json:"fingerprint,omitempty"`
+	UserCount       int      `json:"userCount"`
+	UserIDs         []string `json:"userIds"`
+}
+
+func (h *APIHandlers) handleBankTransactions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	bic := strings.TrimSpace(r.URL.Query().Get("bic"))
+	if bic == "" {
+		writeError(w, http.StatusBadRequest, "bic is required")
+		return
+	}
+	if _, ok := service.NormalizeBIC(bic); !ok {
+		writeError(w, http.StatusBadRequest, "bic must be an 8 or 11 character BIC")
+		return
+	}
+
+	routed, err := h.service.TransactionsThroughBank(r.Context(), bic)
+	if err != nil {
+		h.logger.Error("failed to fetch transactions through bank", "error", err, "bic", bic)
+		writeServerError(w, err, "failed to fetch transactions through bank")
+		return
+	}
+
+	response := bankTransactionsResponse{
+		BIC:          routed.BIC,
+		Transactions: []bankTransactionResponse{},
+		Truncated:    routed.Truncated,
+	}
+	for _, item := range routed.Transactions {
+		response.Transactions = append(response.Transactions, bankTransactionResponse{
+			transactionSummaryResponse: newTransactionSummaryResponse(item.Transaction),
+			OriginatorBIC:              item.OriginatorBIC,
+			BeneficiaryBIC:             item.BeneficiaryBIC,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+type bankTransactionResponse struct {
+	transactionSummaryResponse
+	OriginatorBIC  string `json:"originatorBic,omitempty"`
+	BeneficiaryBIC string `json:"beneficiaryBic,omitempty"`
+}
+
+type bankTransactionsResponse struct {
+	BIC          string                    `json:"bic"`
+	Transactions []bankTransactionResponse `json:"transactions"`
+	Truncated    bool                      `json:"truncated"`
+}
+
+type attributeRefResponse struct {
+	AttributeType string `json:"attributeType"`
+	AttributeHash string `json:"attributeHash"`
+}
+
+type userComparisonResponse struct {
+	UserA                string                 `json:"userA"`
+	UserB                string                 `json:"userB"`
+	Similarity           float64                `json:"similarity"`
+	FieldMatches         []string               `json:"fieldMatches"`
+	SharedAttributes     []attributeRefResponse `json:"sharedAttributes"`
+	CommonCounterparties []string               `json:"commonCounterparties"`
+	DirectTransactions   []userDirectConnection `json:"directTransactions"`
+}
+
+func (h *APIHandlers) handleOnboardingVelocity(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	opts := repository.NewUserVelocityOptions{GroupBy: query.Get("groupBy")}
+	switch opts.GroupBy {
+	case repository.OnboardingGroupNone, repository.OnboardingGroupEmailDomain, repository.OnboardingGroupIPSubnet:
+	default:
+		writeError(w, http.StatusBadRequest, "groupBy must be emailDomain or ipSubnet")
+		return
+	}
+	if v := query.Get("window"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil || window < time.Minute {
+			writeError(w, http.StatusBadRequest, "window must be a duration of at least 1m")
+			return
+		}
+		opts.Window = window
+	}
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{{"since", &opts.Since}, {"until", &opts.Until}} {
+		if v := query.Get(param.name); v != "" {
+			ts, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid "+param.name+" timestamp")
+				return
+			}
+			*param.dst = &ts
+		}
+	}
+	if err := validateTimeRange("since", opts.Since, "until", opts.Until); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if v := query.Get("spikeFactor"); v != "" {
+		factor, err := strconv.ParseFloat(v, 64)
+		if err != nil || factor <= 0 {
+			writeError(w, http.StatusBadRequest, "spikeFactor must be a positive number")
+			return
+		}
+		opts.SpikeFactor = factor
+	}
+	if v := query.Get("minUsers"); v != "" {
+		minUsers, err := strconv.Atoi(v)
+		if err != nil || minUsers <= 0 {
+			writeError(w, http.StatusBadRequest, "minUsers must be a positive integer")
+			return
+		}
+		opts.MinUsers = minUsers
+	}
+
+	velocity, err := h.service.OnboardingVelocity(r.Context(), opts)
+	if err != nil {
+		h.logger.Error("failed to compute onboarding velocity", "error", err)
+		writeServerError(w, err, "failed to compute onboarding velocity")
+		return
+	}
+
+	response := onboardingVelocityResponse{
+		Window:  velocity.Window.String(),
+		GroupBy: velocity.GroupBy,
+		Since:   formatTime(velocity.Since),
+		Until:   formatTime(velocity.Until),
+		Buckets: make([]onboardingBucketResponse, 0, len(velocity.Buckets)),
+	}
+	for _, bucket := range velocity.Buckets {
+		response.Buckets = append(response.Buckets, onboardingBucketResponse{
+			Start:    formatTime(bucket.Start),
+			Group:    bucket.Group,
+			Count:    bucket.Count,
+			Baseline: bucket.Baseline,
+			Spike:    bucket.Spike,
+			UserIDs:  bucket.UserIDs,
+		})
+		if bucket.Spike {
+			response.Spikes++
+		}
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+type onboardingBucketResponse struct {
+	Start    string   `json:"start"`
+	Group    string   `json:"group,omitempty"`
+	Count    int      `json:"count"`
+	Baseline float64  `json:"baseline"`
+	Spike    bool     `json:"spike"`
+	UserIDs  []string `json:"userIds,omitempty"`
+}
+
+type onboardingVelocityResponse struct {
+	Window  string                     `json:"window"`
+	GroupBy string                     `json:"groupBy,omitempty"`
+	Since   string                     `json:"since"`
+	Until   string                     `json:"until"`
+	Spikes  int                        `json:"spikes"`
+	Buckets []onboardingBucketResponse `json:"buckets"`
+}
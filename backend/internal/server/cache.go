@@ -0,0 +1,70 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CachePolicy sets how long clients and proxies may reuse responses of the
+// low-volatility endpoint classes. A zero max-age sends no-store for the class.
+type CachePolicy struct {
+	// Stats covers the aggregate graph counts of GET /stats.
+	Stats time.Duration
+}
+
+// maxAge returns how long a successful read of path may be reused, or zero when it must
+// not be stored.
+func (p CachePolicy) maxAge(path string) time.Duration {
+	switch path {
+	case "/stats":
+		return p.Stats
+	default:
+		return 0
+	}
+}
+
+// cacheControlMiddleware sets Cache-Control on every response. Successful GET and HEAD
+// requests to an endpoint class with a max-age may be reused for that long, privately,
+// as responses depend on the caller's key. Everything else, writes, errors, health
+// probes and the user and transaction lists carrying personal data among them, is sent
+// with no-store. It runs inside versionMiddleware so /v1/stats matches /stats.
+func cacheControlMiddleware(policy CachePolicy, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var maxAge time.Duration
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			maxAge = policy.maxAge(r.URL.Path)
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		if maxAge <= 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		next.ServeHTTP(&cacheResponseWriter{ResponseWriter: w, maxAge: maxAge}, r)
+	})
+}
+
+// cacheResponseWriter allows caching once the status is known to be 200 OK, so an error
+// is never reused.
+type cacheResponseWriter struct {
+	http.ResponseWriter
+	maxAge      time.Duration
+	wroteHeader bool
+}
+
+func (w *cacheResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		if status == http.StatusOK {
+			w.Header().Set("Cache-Control", "private, max-age="+strconv.Itoa(int(w.maxAge/time.Second)))
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *cacheResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCacheControlMiddleware(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy CachePolicy
+		method string
+		path   string
+		status int
+		want   string
+	}{
+		{name: "stats is cached", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodGet, path: "/stats", status: http.StatusOK, want: "private, max-age=30"},
+		{name: "stats HEAD is cached", policy: CachePolicy{Stats: time.Minute}, method: http.MethodHead, path: "/stats", status: http.StatusOK, want: "private, max-age=60"},
+		{name: "stats error is not cached", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodGet, path: "/stats", status: http.StatusServiceUnavailable, want: "no-store"},
+		{name: "zero max-age disables caching", method: http.MethodGet, path: "/stats", status: http.StatusOK, want: "no-store"},
+		{name: "user write", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodPost, path: "/users", status: http.StatusCreated, want: "no-store"},
+		{name: "transaction delete", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodDelete, path: "/transactions/TX-1", status: http.StatusOK, want: "no-store"},
+		{name: "user list carries PII", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodGet, path: "/users", status: http.StatusOK, want: "no-store"},
+		{name: "health probe", policy: CachePolicy{Stats: 30 * time.Second}, method: http.MethodGet, path: "/healthz", status: http.StatusOK, want: "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := cacheControlMiddleware(tt.policy, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				respondJSON(w, tt.status, map[string]string{"status": "ok"})
+			}))
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+			if got := rec.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheControlAppliesToVersionedPaths(t *testing.T) {
+	stats := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]int{"totalUsers": 1})
+	})
+	handler := versionMiddleware(cacheControlMiddleware(CachePolicy{Stats: 30 * time.Second}, stats))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/stats", nil))
+	if got := rec.Header().Get("Cache-Control"); got != "private, max-age=30" {
+		t.Errorf("Cache-Control = %q, want private, max-age=30", got)
+	}
+}
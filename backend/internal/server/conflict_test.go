@@ -0,0 +1,33 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestCreateUserConstraintViolationIsConflict(t *testing.T) {
+	driverErr := fmt.Errorf("run query: %w", &neo4j.Neo4jError{
+		Code: "Neo.ClientError.Schema.ConstraintValidationFailed",
+		Msg:  "Node(7) already exists with label `User` and property `email` = 'a@example.com'",
+	})
+	handlers := newStubHandlers(repository.New(failingClient{err: driverErr}))
+
+	rec := serve(t, handlers.handleUsers, http.MethodPost, "/users", strings.NewReader(`{"userId":"USR-1","email":"a@example.com"}`))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	var body map[string]string
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if msg := body["error"]; !strings.Contains(msg, "User.email=a@example.com") {
+		t.Errorf("error = %q, want it to name the conflicting key", msg)
+	}
+}
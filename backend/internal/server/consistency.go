@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// bookmarkHeader carries graph bookmarks between client and server for read-after-write
+// consistency. Responses to consistent requests include the latest bookmarks; clients
+// send them back on later requests to read their own writes.
+const bookmarkHeader = "X-Graph-Bookmark"
+
+// consistencyMiddleware enables causally consistent graph access for requests that ask
+// for it with ?consistent=true or by presenting bookmarks. Other requests are untouched
+// and their reads may be served by a replica that lags behind recent writes.
+func consistencyMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		initial := r.Header.Values(bookmarkHeader)
+		consistent, _ := strconv.ParseBool(r.URL.Query().Get("consistent"))
+		if !consistent && len(initial) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, tracker := graph.WithBookmarkTracker(r.Context(), initial)
+		next.ServeHTTP(&bookmarkResponseWriter{ResponseWriter: w, tracker: tracker}, r.WithContext(ctx))
+	})
+}
+
+// bookmarkResponseWriter adds the tracker's bookmarks to the response headers just
+// before they are sent.
+type bookmarkResponseWriter struct {
+	http.ResponseWriter
+	tracker     *graph.BookmarkTracker
+	wroteHeader bool
+}
+
+func (w *bookmarkResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		for _, bm := range w.tracker.Bookmarks() {
+			w.Header().Add(bookmarkHeader, bm)
+		}
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *bookmarkResponseWriter) Write(p []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(p)
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+func TestConsistencyMiddlewarePropagatesBookmarks(t *testing.T) {
+	tests := []struct {
+		name        string
+		target      string
+		sent        []string
+		wantTracked bool
+	}{
+		{name: "default reads may be stale", target: "/users/U1"},
+		{name: "consistent flag", target: "/users/U1?consistent=true", wantTracked: true},
+		{name: "presented bookmarks", target: "/users/U1", sent: []string{"bm:1"}, wantTracked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The handler stands in for a write: it sees the client's bookmarks and
+			// records the one its write produced.
+			var seen []string
+			var tracked bool
+			handler := consistencyMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tracker := graph.BookmarkTrackerFrom(r.Context())
+				tracked = tracker != nil
+				seen = tracker.Bookmarks()
+				tracker.Set([]string{"bm:write"})
+				respondJSON(w, http.StatusCreated, map[string]string{"status": "ok"})
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, tt.target, nil)
+			for _, bm := range tt.sent {
+				req.Header.Add(bookmarkHeader, bm)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if tracked != tt.wantTracked {
+				t.Fatalf("tracked = %t, want %t", tracked, tt.wantTracked)
+			}
+			if !slices.Equal(seen, tt.sent) {
+				t.Errorf("handler saw bookmarks %v, want %v", seen, tt.sent)
+			}
+			wantHeader := []string(nil)
+			if tt.wantTracked {
+				wantHeader = []string{"bm:write"}
+			}
+			if got := rec.Header().Values(bookmarkHeader); !slices.Equal(got, wantHeader) {
+				t.Errorf("response bookmarks = %v, want %v", got, wantHeader)
+			}
+			if rec.Code != http.StatusCreated {
+				t.Errorf("status = %d, want 201", rec.Code)
+			}
+		})
+	}
+}
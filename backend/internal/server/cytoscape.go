@@ -0,0 +1,128 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// graphFormat is the response shape a graph endpoint answers with.
+type graphFormat string
+
+const (
+	// graphFormatDefault is the endpoint's own nodes and edges shape.
+	graphFormatDefault graphFormat = ""
+	// graphFormatCytoscape is the elements shape Cytoscape.js loads directly.
+	graphFormatCytoscape graphFormat = "cytoscape"
+)
+
+// parseGraphFormat reads the format query parameter of a graph endpoint. Empty and
+// "json" select the default shape.
+func parseGraphFormat(query url.Values) (graphFormat, error) {
+	switch format := strings.ToLower(strings.TrimSpace(query.Get("format"))); format {
+	case "", "json":
+		return graphFormatDefault, nil
+	case string(graphFormatCytoscape):
+		return graphFormatCytoscape, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q; expected json or cytoscape", format)
+	}
+}
+
+// cytoscapeBuilder collects nodes and edges into Cytoscape elements, listing each node
+// and each edge once however many paths or subgraphs contain it.
+type cytoscapeBuilder struct {
+	elements cytoscapeElements
+	nodes    map[string]struct{}
+	edges    map[string]struct{}
+}
+
+func newCytoscapeBuilder() *cytoscapeBuilder {
+	return &cytoscapeBuilder{
+		elements: cytoscapeElements{Nodes: []cytoscapeNode{}, Edges: []cytoscapeEdge{}},
+		nodes:    make(map[string]struct{}),
+		edges:    make(map[string]struct{}),
+	}
+}
+
+// add appends the nodes and edges not already collected, in order. Labels are what a
+// graph view displays: the node's ID and the edge's type. An edge's ID is its type and
+// ends, since Cytoscape needs element IDs unique across the whole graph.
+func (b *cytoscapeBuilder) add(nodes []domain.PathNode, edges []domain.PathEdge) {
+	for _, node := range nodes {
+		if _, ok := b.nodes[node.ID]; ok {
+			continue
+		}
+		b.nodes[node.ID] = struct{}{}
+		b.elements.Nodes = append(b.elements.Nodes, cytoscapeNode{Data: cytoscapeNodeData{
+			ID:            node.ID,
+			Label:         node.ID,
+			Type:          node.Label,
+			AttributeType: node.AttributeType,
+			Properties:    node.Properties,
+		}})
+	}
+	for _, edge := range edges {
+		id := edge.Source + "-" + edge.Type + "->" + edge.Target
+		if _, ok := b.edges[id]; ok {
+			continue
+		}
+		b.edges[id] = struct{}{}
+		b.elements.Edges = append(b.elements.Edges, cytoscapeEdge{Data: cytoscapeEdgeData{
+			ID:         id,
+			Source:     edge.Source,
+			Target:     edge.Target,
+			Label:      edge.Type,
+			Type:       edge.Type,
+			Weight:     edge.Weight,
+			Properties: edge.Properties,
+		}})
+	}
+}
+
+type cytoscapeElements struct {
+	Nodes []cytoscapeNode `json:"nodes"`
+	Edges []cytoscapeEdge `json:"edges"`
+}
+
+type cytoscapeNode struct {
+	Data cytoscapeNodeData `json:"data"`
+}
+
+type cytoscapeNodeData struct {
+	ID            string         `json:"id"`
+	Label         string         `json:"label"`
+	Type          string         `json:"type"`
+	AttributeType string         `json:"attributeType,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
+}
+
+type cytoscapeEdge struct {
+	Data cytoscapeEdgeData `json:"data"`
+}
+
+type cytoscapeEdgeData struct {
+	ID         string         `json:"id"`
+	Source     string         `json:"source"`
+	Target     string         `json:"target"`
+	Label      string         `json:"label"`
+	Type       string         `json:"type"`
+	Weight     float64        `json:"weight"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
+
+type cytoscapeNeighborhoodResponse struct {
+	UserID    string            `json:"userId"`
+	Depth     int               `json:"depth"`
+	Truncated bool              `json:"truncated"`
+	Elements  cytoscapeElements `json:"elements"`
+}
+
+type cytoscapePathsResponse struct {
+	SourceUserID string            `json:"sourceUserId"`
+	TargetUserID string            `json:"targetUserId"`
+	Truncated    bool              `json:"truncated"`
+	Elements     cytoscapeElements `json:"elements"`
+}
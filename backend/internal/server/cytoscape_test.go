@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// cytoscapeBody is the Cytoscape.js elements shape, decoded without the server's types so
+// the test pins the wire format.
+type cytoscapeBody struct {
+	Elements struct {
+		Nodes []struct {
+			Data map[string]any `json:"data"`
+		} `json:"nodes"`
+		Edges []struct {
+			Data map[string]any `json:"data"`
+		} `json:"edges"`
+	} `json:"elements"`
+}
+
+func TestNeighborhoodCytoscapeFormat(t *testing.T) {
+	repo := &stubRepository{neighborhood: domain.Neighborhood{
+		UserID: "U1",
+		Depth:  1,
+		Nodes: []domain.PathNode{
+			{ID: "U1", Label: "User"},
+			{ID: "U2", Label: "User"},
+			{ID: "h1", Label: "Attribute", AttributeType: "EMAIL"},
+		},
+		Edges: []domain.PathEdge{
+			{Type: "SENT_TO", Source: "U1", Target: "U2", Weight: 12.5},
+			{Type: "HAS_ATTRIBUTE", Source: "U2", Target: "h1", Weight: 1},
+		},
+	}}
+	rec := serve(t, newStubHandlers(repo).handleNeighborhood, http.MethodGet, "/analytics/neighborhood?userId=U1&depth=1&format=cytoscape", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body cytoscapeBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+
+	if got := len(body.Elements.Nodes); got != 3 {
+		t.Fatalf("nodes = %d, want 3", got)
+	}
+	user := body.Elements.Nodes[0].Data
+	if user["id"] != "U1" || user["label"] != "U1" || user["type"] != "User" {
+		t.Errorf("first node data = %v, want id and label U1, type User", user)
+	}
+	if attr := body.Elements.Nodes[2].Data; attr["type"] != "Attribute" || attr["attributeType"] != "EMAIL" {
+		t.Errorf("attribute node data = %v, want type Attribute and attributeType EMAIL", attr)
+	}
+
+	if got := len(body.Elements.Edges); got != 2 {
+		t.Fatalf("edges = %d, want 2", got)
+	}
+	edge := body.Elements.Edges[0].Data
+	for key, want := range map[string]any{"id": "U1-SENT_TO->U2", "source": "U1", "target": "U2", "label": "SENT_TO", "type": "SENT_TO", "weight": 12.5} {
+		if edge[key] != want {
+			t.Errorf("edge data[%q] = %v, want %v", key, edge[key], want)
+		}
+	}
+}
+
+func TestShortestPathCytoscapeFormatMergesPaths(t *testing.T) {
+	// Both paths start with the same edge, which must be listed once.
+	repo := &stubRepository{paths: []domain.ShortestPath{
+		{
+			Nodes: []domain.PathNode{{ID: "U1", Label: "User"}, {ID: "U2", Label: "User"}},
+			Edges: []domain.PathEdge{{Type: "SENT_TO", Source: "U1", Target: "U2", Weight: 1}},
+		},
+		{
+			Nodes: []domain.PathNode{{ID: "U1", Label: "User"}, {ID: "U3", Label: "User"}, {ID: "U2", Label: "User"}},
+			Edges: []domain.PathEdge{
+				{Type: "SENT_TO", Source: "U1", Target: "U3", Weight: 1},
+				{Type: "SENT_TO", Source: "U3", Target: "U2", Weight: 1},
+			},
+		},
+		{
+			Nodes: []domain.PathNode{{ID: "U1", Label: "User"}, {ID: "U2", Label: "User"}},
+			Edges: []domain.PathEdge{{Type: "SENT_TO", Source: "U1", Target: "U2", Weight: 1}},
+		},
+	}}
+	rec := serve(t, newStubHandlers(repo).handleKShortestPaths, http.MethodGet, "/analytics/shortest-path?sourceUserId=U1&targetUserId=U2&k=3&format=cytoscape", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body cytoscapeBody
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if got := len(body.Elements.Nodes); got != 3 {
+		t.Errorf("nodes = %d, want 3 distinct", got)
+	}
+	if got := len(body.Elements.Edges); got != 3 {
+		t.Errorf("edges = %d, want 3 distinct", got)
+	}
+	ids := make(map[any]bool)
+	for _, edge := range body.Elements.Edges {
+		if ids[edge.Data["id"]] {
+			t.Errorf("edge id %v listed twice", edge.Data["id"])
+		}
+		ids[edge.Data["id"]] = true
+	}
+}
+
+func TestGraphFormatDefaultAndInvalid(t *testing.T) {
+	repo := &stubRepository{neighborhood: domain.Neighborhood{UserID: "U1", Depth: 1, Nodes: []domain.PathNode{{ID: "U1", Label: "User"}}}}
+	handlers := newStubHandlers(repo)
+
+	rec := serve(t, handlers.handleNeighborhood, http.MethodGet, "/analytics/neighborhood?userId=U1", nil)
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if _, ok := body["nodes"]; !ok {
+		t.Errorf("default format body = %v, want top-level nodes", body)
+	}
+	if _, ok := body["elements"]; ok {
+		t.Errorf("default format body = %v, want no elements", body)
+	}
+
+	rec = serve(t, handlers.handleNeighborhood, http.MethodGet, "/analytics/neighborhood?userId=U1&format=graphml", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown format status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strconv"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+type explainResponse struct {
+	Queries []explainedQueryResponse `json:"queries"`
+	// Redacted reports whether PII parameter values were masked.
+	Redacted bool `json:"redacted"`
+}
+
+type explainedQueryResponse struct {
+	Access string         `json:"access"`
+	Cypher string         `json:"cypher"`
+	Params map[string]any `json:"params"`
+}
+
+// explainable lets admin callers add ?explain=true to a read endpoint to get the Cypher
+// and parameters it generates instead of running them. Parameter values under PII keys
+// are masked unless the key also holds ScopeDebug. With authentication disabled no
+// caller is an admin, so explain is never available. Requests that fail before issuing
+// a query, for example on invalid parameters, get their normal error response.
+func explainable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		explain, _ := strconv.ParseBool(r.URL.Query().Get("explain"))
+		if !explain {
+			next(w, r)
+			return
+		}
+		if !requestHasScope(r, ScopeAdmin) {
+			writeError(w, http.StatusForbidden, "explain requires an API key with the admin scope")
+			return
+		}
+
+		ctx, recorder := graph.WithQueryRecorder(r.Context())
+		buffered := &bufferedResponseWriter{header: http.Header{}}
+		next(buffered, r.WithContext(ctx))
+
+		redact := !requestHasExplicitScope(r, ScopeDebug)
+		queries := recorder.Queries(redact)
+		if len(queries) == 0 {
+			buffered.flushTo(w)
+			return
+		}
+		response := explainResponse{Queries: []explainedQueryResponse{}, Redacted: redact}
+		for _, q := range queries {
+			response.Queries = append(response.Queries, explainedQueryResponse{
+				Access: q.Access,
+				Cypher: q.Cypher,
+				Params: q.Params,
+			})
+		}
+		respondJSON(w, http.StatusOK, response)
+	}
+}
+
+// bufferedResponseWriter holds a response so it can be discarded or sent later.
+type bufferedResponseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *bufferedResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.body.Write(p)
+}
+
+func (w *bufferedResponseWriter) flushTo(dst http.ResponseWriter) {
+	for key, values := range w.header {
+		dst.Header()[key] = values
+	}
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	dst.WriteHeader(w.status)
+	_, _ = dst.Write(w.body.Bytes())
+}
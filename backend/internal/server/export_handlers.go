@@ -0,0 +1,237 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/export"
+)
+
+// WithExportJobs enables the /export/jobs endpoints backed by jobs.
+func (h *APIHandlers) WithExportJobs(jobs *export.JobManager) *APIHandlers {
+	h.exportJobs = jobs
+	return h
+}
+
+// WithExportSource enables the streaming /export/edges and /export/nodes endpoints,
+// which read from src.
+func (h *APIHandlers) WithExportSource(src export.Source) *APIHandlers {
+	h.exportSource = src
+	return h
+}
+
+// handleExportEdges streams the graph as an edge list in the response body.
+func (h *APIHandlers) handleExportEdges(w http.ResponseWriter, r *http.Request) {
+	h.streamGraphExport(w, r, export.EntityEdges)
+}
+
+// handleExportNodes streams the node list that complements /export/edges.
+func (h *APIHandlers) handleExportNodes(w http.ResponseWriter, r *http.Request) {
+	h.streamGraphExport(w, r, export.EntityNodes)
+}
+
+// streamGraphExport runs an edge or node export straight into the response body rather
+// than through a background job. Filters are the export query parameters, e.g. edgeTypes.
+func (h *APIHandlers) streamGraphExport(w http.ResponseWriter, r *http.Request, entity export.Entity) {
+	if h.exportSource == nil {
+		writeError(w, http.StatusNotFound, "graph exports are not enabled")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	values := r.URL.Query()
+	if values.Has("entity") {
+		writeError(w, http.StatusBadRequest, `unknown filter "entity"`)
+		return
+	}
+	values.Set("entity", string(entity))
+	req, err := export.RequestFromValues(values)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentType(req.Format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+string(entity)+"."+string(req.Format)+`"`)
+	// The status is sent with the first batch, so a failure part-way can only be
+	// logged; the truncated body lacks the format's closing bytes.
+	result, err := export.Run(r.Context(), h.exportSource, w, req)
+	if err != nil {
+		h.logger.Error("graph export failed", "error", err, "entity", entity, "records", result.Records)
+	}
+}
+
+// exportJobsRetryAfter is the Retry-After, in seconds, sent when every export job slot
+// is taken.
+const exportJobsRetryAfter = 30
+
+func (h *APIHandlers) handleExportJobs(w http.ResponseWriter, r *http.Request) {
+	if h.exportJobs == nil {
+		writeError(w, http.StatusNotFound, "export jobs are not enabled")
+		return
+	}
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	req, err := export.RequestFromValues(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	req.RedactPII = h.shouldRedactPII(r)
+
+	job, err := h.exportJobs.Start(r.Context(), req)
+	if errors.Is(err, export.ErrTooManyJobs) {
+		w.Header().Set("Retry-After", strconv.Itoa(exportJobsRetryAfter))
+		writeError(w, http.StatusTooManyRequests, err.Error())
+		return
+	}
+	if err != nil {
+		h.logger.Error("failed to start export job", "error", err)
+		writeServerError(w, err, "failed to start export job")
+		return
+	}
+
+	w.Header().Set("Location", exportJobPath(job.ID))
+	respondJSON(w, http.StatusAccepted, newExportJobResponse(job))
+}
+
+// handleExportJob serves /export/jobs/{id} and /export/jobs/{id}/download.
+func (h *APIHandlers) handleExportJob(w http.ResponseWriter, r *http.Request) {
+	if h.exportJobs == nil {
+		writeError(w, http.StatusNotFound, "export jobs are not enabled")
+		return
+	}
+
+	jobID, resource := resourcePath(r.URL.Path, "/export/jobs")
+	if jobID == "" {
+		writeError(w, http.StatusBadRequest, "job ID is required")
+		return
+	}
+
+	switch {
+	case resource == "download" && r.Method == http.MethodGet:
+		h.downloadExportJob(w, r, jobID)
+	case resource == "download":
+		methodNotAllowed(w, http.MethodGet)
+	case resource != "":
+		writeError(w, http.StatusNotFound, "not found")
+	case r.Method == http.MethodGet:
+		job, err := h.exportJobs.Get(r.Context(), jobID)
+		if err != nil {
+			h.writeExportJobError(w, err, jobID)
+			return
+		}
+		respondJSON(w, http.StatusOK, newExportJobResponse(job))
+	case r.Method == http.MethodDelete:
+		job, err := h.exportJobs.Cancel(r.Context(), jobID)
+		if err != nil {
+			h.writeExportJobError(w, err, jobID)
+			return
+		}
+		respondJSON(w, http.StatusOK, newExportJobResponse(job))
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+func (h *APIHandlers) downloadExportJob(w http.ResponseWriter, r *http.Request, jobID string) {
+	job, err := h.exportJobs.Get(r.Context(), jobID)
+	if err != nil {
+		h.writeExportJobError(w, err, jobID)
+		return
+	}
+	if job.Status != export.JobSucceeded {
+		writeError(w, http.StatusConflict, "export job is "+strings.ToLower(string(job.Status)))
+		return
+	}
+
+	file, err := os.Open(job.Path)
+	if err != nil {
+		h.writeExportJobError(w, err, jobID)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		h.writeExportJobError(w, err, jobID)
+		return
+	}
+
+	w.Header().Set("Content-Type", exportContentType(job.Format))
+	w.Header().Set("Content-Disposition", `attachment; filename="`+string(job.Entity)+"-"+job.ID+"."+string(job.Format)+`"`)
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+func (h *APIHandlers) writeExportJobError(w http.ResponseWriter, err error, jobID string) {
+	if errors.Is(err, export.ErrJobNotFound) || errors.Is(err, os.ErrNotExist) {
+		writeError(w, http.StatusNotFound, "export job not found")
+		return
+	}
+	h.logger.Error("export job request failed", "error", err, "jobId", jobID)
+	writeServerError(w, err, "export job request failed")
+}
+
+func exportJobPath(id string) string {
+	return "/export/jobs/" + id
+}
+
+func exportContentType(format export.Format) string {
+	switch format {
+	case export.FormatCSV:
+		return "text/csv"
+	case export.FormatNDJSON:
+		return "application/x-ndjson"
+	default:
+		return "application/json"
+	}
+}
+
+type exportJobResponse struct {
+	JobID       string `json:"jobId"`
+	Entity      string `json:"entity"`
+	Format      string `json:"format"`
+	Status      string `json:"status"`
+	Records     int    `json:"records"`
+	Error       string `json:"error,omitempty"`
+	StatusURL   string `json:"statusUrl"`
+	DownloadURL string `json:"downloadUrl,omitempty"`
+	CreatedAt   string `json:"createdAt,omitempty"`
+	StartedAt   string `json:"startedAt,omitempty"`
+	FinishedAt  string `json:"finishedAt,omitempty"`
+	// MaxUpdatedAt is the since value for the next incremental export of the entity.
+	MaxUpdatedAt string `json:"maxUpdatedAt,omitempty"`
+}
+
+func newExportJobResponse(job export.Job) exportJobResponse {
+	resp := exportJobResponse{
+		JobID:      job.ID,
+		Entity:     string(job.Entity),
+		Format:     string(job.Format),
+		Status:     string(job.Status),
+		Records:    job.Records,
+		Error:      job.Error,
+		StatusURL:  exportJobPath(job.ID),
+		CreatedAt:  formatTime(job.CreatedAt),
+		StartedAt:  formatTimePtr(job.StartedAt),
+		FinishedAt: formatTimePtr(job.FinishedAt),
+	}
+	if job.Status == export.JobSucceeded {
+		resp.DownloadURL = exportJobPath(job.ID) + "/download"
+		// Keep fractional seconds so the next since does not re-export the whole second.
+		if job.MaxUpdatedAt != nil {
+			resp.MaxUpdatedAt = job.MaxUpdatedAt.UTC().Format(time.RFC3339Nano)
+		}
+	}
+	return resp
+}
@@ -0,0 +1,43 @@
+package server
+
+import (
+	"context"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// failingClient is a graph.Client whose every query fails with err.
+type failingClient struct {
+	err error
+}
+
+func (c failingClient) ExecuteRead(context.Context, string, map[string]any) (graph.Result, error) {
+	return graph.Result{}, c.err
+}
+
+func (c failingClient) ExecuteWrite(context.Context, string, map[string]any) (graph.Result, error) {
+	return graph.Result{}, c.err
+}
+
+func (c failingClient) VerifyConnectivity(context.Context) error { return nil }
+
+func (c failingClient) Close(context.Context) error { return nil }
+
+// recordingClient is a graph.Client that answers every query with an empty result and
+// records the parameters of every read.
+type recordingClient struct {
+	reads []map[string]any
+}
+
+func (c *recordingClient) ExecuteRead(_ context.Context, _ string, params map[string]any) (graph.Result, error) {
+	c.reads = append(c.reads, params)
+	return graph.Result{}, nil
+}
+
+func (c *recordingClient) ExecuteWrite(context.Context, string, map[string]any) (graph.Result, error) {
+	return graph.Result{}, nil
+}
+
+func (c *recordingClient) VerifyConnectivity(context.Context) error { return nil }
+
+func (c *recordingClient) Close(context.Context) error { return nil }
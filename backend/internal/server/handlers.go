@@ -9,13 +9,24 @@ import (
 	"strings"
 	"time"
 
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/export"
+	"github.com/vanshika/fintrace/backend/internal/graph"
+	"github.com/vanshika/fintrace/backend/internal/repository"
 	"github.com/vanshika/fintrace/backend/internal/service"
 )
 
 // APIHandlers exposes HTTP handlers for the REST API.
 type APIHandlers struct {
-	logger  *slog.Logger
-	service *service.RelationshipService
+	logger       *slog.Logger
+	service      *service.RelationshipService
+	exportJobs   *export.JobManager
+	exportSource export.Source
+	redactPII    bool
+	lookupMax    int
+	pathBatchMax int
+	pathMaxItems int
+	queries      *graph.QueryRegistry
 }
 
 // NewAPIHandlers constructs an APIHandlers instance.
@@ -26,6 +37,18 @@ func NewAPIHandlers(logger *slog.Logger, svc *service.RelationshipService) *APIH
 	}
 }
 
+// WithPIIRedaction masks email, phone and address fields in user lists and export
+// jobs unless the caller's API key holds ScopePII. With authentication disabled no
+// caller holds the scope, so every response is masked.
+func (h *APIHandlers) WithPIIRedaction(enabled bool) *APIHandlers {
+	h.redactPII = enabled
+	return h
+}
+
+func (h *APIHandlers) shouldRedactPII(r *http.Request) bool {
+	return h.redactPII && !requestHasScope(r, ScopePII)
+}
+
 func (h *APIHandlers) handleUsers(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodPost:
@@ -54,17 +77,46 @@ func (h *APIHandlers) handleUserRelationships(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	userID := strings.TrimPrefix(r.URL.Path, "/relationships/user/")
-	userID = strings.Trim(userID, "/")
+	userID, extra := resourcePath(r.URL.Path, "/relationships/user")
 	if userID == "" {
 		writeError(w, http.StatusBadRequest, "user ID is required")
 		return
 	}
+	if extra != "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+
+	origin := strings.ToUpper(strings.TrimSpace(r.URL.Query().Get("origin")))
+	switch origin {
+	case "", domain.AttributeOriginUser, domain.AttributeOriginTransaction, domain.AttributeOriginDerived:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid origin")
+		return
+	}
+
+	direction := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("direction")))
+	switch direction {
+	case "", repository.DirectionBoth, repository.DirectionInbound, repository.DirectionOutbound:
+	default:
+		writeError(w, http.StatusBadRequest, "direction must be inbound, outbound or both")
+		return
+	}
 
-	relationships, err := h.service.GetUserRelationships(r.Context(), userID)
+	limit, err := parseSectionLimit(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	relationships, err := h.service.GetUserRelationships(r.Context(), userID, repository.UserRelationshipOptions{
+		Origin:    origin,
+		Limit:     limit,
+		Direction: direction,
+	})
 	if err != nil {
 		h.logger.Error("failed to fetch user relationships", "error", err, "userId", userID)
-		writeError(w, http.StatusInternalServerError, "failed to fetch user relationships")
+		writeServerError(w, err, "failed to fetch user relationships")
 		return
 	}
 
@@ -73,6 +125,7 @@ func (h *APIHandlers) handleUserRelationships(w http.ResponseWriter, r *http.Req
 		DirectConnections: []userDirectConnection{},
 		Transactions:      []userTransactionLink{},
 		SharedAttributes:  []sharedAttribute{},
+		sectionLimits:     toSectionLimits(relationships.Limits),
 	}
 
 	for _, link := range relationships.DirectLinks {
@@ -114,17 +167,32 @@ func (h *APIHandlers) handleTransactionRelationships(w http.ResponseWriter, r *h
 		return
 	}
 
-	txID := strings.TrimPrefix(r.URL.Path, "/relationships/transaction/")
-	txID = strings.Trim(txID, "/")
+	txID, extra := resourcePath(r.URL.Path, "/relationships/transaction")
 	if txID == "" {
 		writeError(w, http.StatusBadRequest, "transaction ID is required")
 		return
 	}
+	if extra != "" {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
 
-	relationships, err := h.service.GetTransactionRelationships(r.Context(), txID)
+	limit, err := parseSectionLimit(r.URL.Query())
 	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	relationships, err := h.service.GetTransactionRelationships(r.Context(), txID, repository.TransactionRelationshipOptions{
+		Limit: limit,
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "transaction not found")
+			return
+		}
 		h.logger.Error("failed to fetch transaction relationships", "error", err, "transactionId", txID)
-		writeError(w, http.StatusInternalServerError, "failed to fetch transaction relationships")
+		writeServerError(w, err, "failed to fetch transaction relationships")
 		return
 	}
 
@@ -132,6 +200,8 @@ func (h *APIHandlers) handleTransactionRelationships(w http.ResponseWriter, r *h
 		TransactionID:      txID,
 		Users:              []transactionUserLink{},
 		LinkedTransactions: []linkedTransaction{},
+		ReversalChain:      []reversalLink{},
+		sectionLimits:      toSectionLimits(relationships.Limits),
 	}
 	for _, user := range relationships.Users {
 		response.Users = append(response.Users, transactionUserLink{
@@ -148,16 +218,71 @@ func (h *APIHandlers) handleTransactionRelationships(w http.ResponseWriter, r *h
 			LinkType:      link.LinkType,
 			AttributeHash: link.AttributeHash,
 			Score:         link.Score,
+			DecayedScore:  link.DecayedScore,
 			UpdatedAt:     formatTimePtr(link.LastUpdated),
 		})
 	}
+	for _, link := range relationships.ReversalChain {
+		response.ReversalChain = append(response.ReversalChain, reversalLink{
+			TransactionID:         link.TransactionID,
+			ReversesTransactionID: link.ReversesTransactionID,
+			Amount:                link.Amount,
+			Currency:              link.Currency,
+			Timestamp:             formatTimePtr(link.Timestamp),
+		})
+	}
 
 	respondJSON(w, http.StatusOK, response)
 }
 
+func (h *APIHandlers) handleAdminPrune(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	batchSize := parseInt(r.URL.Query().Get("batchSize"), 0)
+	removed, err := h.service.PruneOrphans(r.Context(), repository.BatchOptions{
+		BatchSize: batchSize,
+		OnBatch: func(label string, count int) {
+			h.logger.Info("pruned orphaned nodes", "label", label, "removed", count)
+		},
+	})
+	if err != nil {
+		h.logger.Error("failed to prune orphaned nodes", "error", err, "removed", removed)
+		writeServerError(w, err, "failed to prune orphaned nodes")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pruneResponse{
+		Status:  "ok",
+		Removed: removed,
+	})
+}
+
+func (h *APIHandlers) handleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	stats, err := h.service.GetStats(r.Context())
+	if err != nil {
+		h.logger.Error("failed to fetch stats", "error", err)
+		writeServerError(w, err, "failed to fetch stats")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, statsResponse{
+		TotalUsers:           stats.TotalUsers,
+		TotalTransactions:    stats.TotalTransactions,
+		TransactionsByStatus: stats.TransactionsByStatus,
+	})
+}
+
 func (h *APIHandlers) createOrUpdateUser(w http.ResponseWriter, r *http.Request) {
-	var payload userRequest
-	if err := decodeJSON(r, &payload); err != nil {
+	payload, err := userRequestDecoders[requestAPIVersion(r)](r)
+	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -173,8 +298,16 @@ func (h *APIHandlers) createOrUpdateUser(w http.ResponseWriter, r *http.Request)
 	}
 
 	if err := h.service.UpsertUser(r.Context(), input); err != nil {
+		if writeConflict(w, err, "user") {
+			h.logger.Warn("user upsert conflicted", "error", err, "userId", input.ID)
+			return
+		}
+		if errors.Is(err, service.ErrLimitExceeded) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.logger.Error("failed to upsert user", "error", err, "userId", input.ID)
-		writeError(w, http.StatusInternalServerError, "failed to persist user")
+		writeServerError(w, err, "failed to persist user")
 		return
 	}
 
@@ -186,8 +319,11 @@ func (h *APIHandlers) createOrUpdateUser(w http.ResponseWriter, r *http.Request)
 
 func (h *APIHandlers) listUsers(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	page := parseInt(query.Get("page"), 1)
-	pageSize := parseInt(query.Get("pageSize"), 50)
+	pagination, err := parsePaginationParams(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	search := query.Get("search")
 	kycStatus := query.Get("kycStatus")
 	country := query.Get("country")
@@ -195,6 +331,12 @@ func (h *APIHandlers) listUsers(w http.ResponseWriter, r *http.Request) {
 	emailDomain := query.Get("emailDomain")
 	sortField := query.Get("sortField")
 	sortOrder := query.Get("sortOrder")
+	// Any cursor parameter, even an empty one for the first page, selects cursor mode.
+	cursorMode := query.Has("cursor")
+	if cursorMode && query.Get("page") != "" {
+		writeError(w, http.StatusBadRequest, "page and cursor cannot be combined")
+		return
+	}
 
 	var riskMinPtr *float64
 	if v := query.Get("riskMin"); v != "" {
@@ -214,53 +356,101 @@ func (h *APIHandlers) listUsers(w http.ResponseWriter, r *http.Request) {
 		}
 		riskMaxPtr = &val
 	}
+	if err := validateFloatRange("riskMin", riskMinPtr, "riskMax", riskMaxPtr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	var createdAfterPtr *time.Time
+	if v := query.Get("createdAfter"); v != "" {
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid createdAfter timestamp")
+			return
+		}
+		createdAfterPtr = &ts
+	}
+	var createdBeforePtr *time.Time
+	if v := query.Get("createdBefore"); v != "" {
+		ts, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid createdBefore timestamp")
+			return
+		}
+		createdBeforePtr = &ts
+	}
+	if err := validateTimeRange("createdAfter", createdAfterPtr, "createdBefore", createdBeforePtr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	result, err := h.service.ListUsers(r.Context(), service.ListUsersParams{
-		Page:        page,
-		PageSize:    pageSize,
-		Search:      search,
-		KYCStatus:   kycStatus,
-		RiskMin:     riskMinPtr,
-		RiskMax:     riskMaxPtr,
-		Country:     country,
-		City:        city,
-		EmailDomain: emailDomain,
-		SortField:   sortField,
-		SortOrder:   sortOrder,
+		Page:          pagination.Page,
+		PageSize:      pagination.PageSize,
+		Search:        search,
+		KYCStatus:     kycStatus,
+		RiskMin:       riskMinPtr,
+		RiskMax:       riskMaxPtr,
+		Country:       country,
+		City:          city,
+		EmailDomain:   emailDomain,
+		SortField:     sortField,
+		SortOrder:     sortOrder,
+		CreatedAfter:  createdAfterPtr,
+		CreatedBefore: createdBeforePtr,
+		Cursor:        cursorMode,
+		After:         query.Get("cursor"),
 	})
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		h.logger.Error("failed to list users", "error", err)
-		writeError(w, http.StatusInternalServerError, "failed to list users")
+		writeServerError(w, err, "failed to list users")
 		return
 	}
 
+	respondJSON(w, http.StatusOK, h.newListUsersResponse(r, result))
+}
+
+// newListUsersResponse converts a page of users, masking PII when the caller may not see it.
+func (h *APIHandlers) newListUsersResponse(r *http.Request, page service.UsersPage) listUsersResponse {
 	resp := listUsersResponse{
 		Pagination: paginationResponse{
-			Page:       result.Pagination.Page,
-			PageSize:   result.Pagination.PageSize,
-			TotalItems: result.Pagination.TotalItems,
-			TotalPages: result.Pagination.TotalPages,
+			Page:       page.Pagination.Page,
+			PageSize:   page.Pagination.PageSize,
+			TotalItems: page.Pagination.TotalItems,
+			TotalPages: page.Pagination.TotalPages,
+			NextCursor: page.Pagination.NextCursor,
 		},
 	}
-	for _, item := range result.Items {
-		resp.Items = append(resp.Items, userSummaryResponse{
-			UserID:    item.ID,
-			FullName:  item.FullName,
-			Email:     item.Email,
-			Phone:     item.Phone,
-			KYCStatus: item.KYCStatus,
-			RiskScore: item.RiskScore,
-			CreatedAt: formatTime(item.CreatedAt),
-			UpdatedAt: formatTime(item.UpdatedAt),
-		})
+	redact := h.shouldRedactPII(r)
+	for _, item := range page.Items {
+		if redact {
+			item = item.Redacted()
+		}
+		resp.Items = append(resp.Items, newUserSummaryResponse(item))
 	}
+	return resp
+}
 
-	respondJSON(w, http.StatusOK, resp)
+func newUserSummaryResponse(item domain.UserSummary) userSummaryResponse {
+	return userSummaryResponse{
+		UserID:    item.ID,
+		FullName:  item.FullName,
+		Email:     item.Email,
+		Phone:     item.Phone,
+		KYCStatus: item.KYCStatus,
+		RiskScore: item.RiskScore,
+		CreatedAt: formatTime(item.CreatedAt),
+		UpdatedAt: formatTime(item.UpdatedAt),
+	}
 }
 
 func (h *APIHandlers) createOrUpdateTransaction(w http.ResponseWriter, r *http.Request) {
-	var payload transactionRequest
-	if err := decodeJSON(r, &payload); err != nil {
+	payload, err := transactionRequestDecoders[requestAPIVersion(r)](r)
+	if err != nil {
 		writeError(w, http.StatusBadRequest, err.Error())
 		return
 	}
@@ -268,9 +458,20 @@ func (h *APIHandlers) createOrUpdateTransaction(w http.ResponseWriter, r *http.R
 		writeError(w, http.StatusBadRequest, "transactionId is required")
 		return
 	}
-	if payload.SenderUserID == "" || payload.ReceiverUserID == "" {
-		writeError(w, http.StatusBadRequest, "senderUserId and receiverUserId are required")
-		return
+	if domain.IsMonetaryType(payload.Type) {
+		if payload.SenderUserID == "" || payload.ReceiverUserID == "" {
+			writeError(w, http.StatusBadRequest, "senderUserId and receiverUserId are required")
+			return
+		}
+	} else {
+		if payload.SenderUserID == "" {
+			writeError(w, http.StatusBadRequest, "senderUserId is required")
+			return
+		}
+		if payload.Amount != 0 || payload.Currency != "" {
+			writeError(w, http.StatusBadRequest, "amount and currency are not allowed on account events")
+			return
+		}
 	}
 
 	input, err := payload.toServiceInput()
@@ -280,8 +481,16 @@ func (h *APIHandlers) createOrUpdateTransaction(w http.ResponseWriter, r *http.R
 	}
 
 	if err := h.service.UpsertTransaction(r.Context(), input); err != nil {
+		if writeConflict(w, err, "transaction") {
+			h.logger.Warn("transaction upsert conflicted", "error", err, "transactionId", input.ID)
+			return
+		}
+		if errors.Is(err, service.ErrLimitExceeded) {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		h.logger.Error("failed to upsert transaction", "error", err, "transactionId", input.ID)
-		writeError(w, http.StatusInternalServerError, "failed to persist transaction")
+		writeServerError(w, err, "failed to persist transaction")
 		return
 	}
 
@@ -293,13 +502,35 @@ func (h *APIHandlers) createOrUpdateTransaction(w http.ResponseWriter, r *http.R
 
 func (h *APIHandlers) listTransactions(w http.ResponseWriter, r *http.Request) {
 	query := r.URL.Query()
-	page := parseInt(query.Get("page"), 1)
-	pageSize := parseInt(query.Get("pageSize"), 50)
+	pagination, err := parsePaginationParams(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 	search := query.Get("search")
 	userID := query.Get("userId")
 	status := query.Get("status")
 	txType := query.Get("type")
 	channel := query.Get("channel")
+	currency := query.Get("currency")
+	deviceID := query.Get("deviceId")
+	ipAddress := query.Get("ipAddress")
+	includeLinked := false
+	if raw := query.Get("includeLinked"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "includeLinked must be a boolean")
+			return
+		}
+		includeLinked = parsed
+	}
+	participantKYC := strings.ToUpper(strings.TrimSpace(query.Get("participantKyc")))
+	switch participantKYC {
+	case "", repository.ParticipantKYCNoneVerified, repository.ParticipantKYCAnyVerified:
+	default:
+		writeError(w, http.StatusBadRequest, "invalid participantKyc")
+		return
+	}
 	sortField := query.Get("sortField")
 	sortOrder := query.Get("sortOrder")
 
@@ -321,6 +552,25 @@ func (h *APIHandlers) listTransactions(w http.ResponseWriter, r *http.Request) {
 		}
 		maxAmountPtr = &val
 	}
+	if err := validateFloatRange("minAmount", minAmountPtr, "maxAmount", maxAmountPtr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	senderRiskMin, err := parseFloatParam(query, "senderRiskMin")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	receiverRiskMin, err := parseFloatParam(query, "receiverRiskMin")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	anyParticipantRiskMin, err := parseFloatParam(query, "anyParticipantRiskMin")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	var startPtr *time.Time
 	if v := query.Get("start"); v != "" {
@@ -340,25 +590,37 @@ func (h *APIHandlers) listTransactions(w http.ResponseWriter, r *http.Request) {
 		}
 		endPtr = &ts
 	}
+	if err := validateTimeRange("start", startPtr, "end", endPtr); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
 
 	result, err := h.service.ListTransactions(r.Context(), service.ListTransactionsParams{
-		Page:      page,
-		PageSize:  pageSize,
-		Search:    search,
-		UserID:    userID,
-		Status:    status,
-		Type:      txType,
-		MinAmount: minAmountPtr,
-		MaxAmount: maxAmountPtr,
-		StartTime: startPtr,
-		EndTime:   endPtr,
-		Channel:   channel,
-		SortField: sortField,
-		SortOrder: sortOrder,
+		Page:                  pagination.Page,
+		PageSize:              pagination.PageSize,
+		Search:                search,
+		UserID:                userID,
+		Status:                status,
+		Type:                  txType,
+		MinAmount:             minAmountPtr,
+		MaxAmount:             maxAmountPtr,
+		StartTime:             startPtr,
+		EndTime:               endPtr,
+		Channel:               channel,
+		Currency:              currency,
+		DeviceID:              deviceID,
+		IPAddress:             ipAddress,
+		IncludeLinked:         includeLinked,
+		SenderRiskMin:         senderRiskMin,
+		ReceiverRiskMin:       receiverRiskMin,
+		AnyParticipantRiskMin: anyParticipantRiskMin,
+		ParticipantKYC:        participantKYC,
+		SortField:             sortField,
+		SortOrder:             sortOrder,
 	})
 	if err != nil {
 		h.logger.Error("failed to list transactions", "error", err)
-		writeError(w, http.StatusInternalServerError, "failed to list transactions")
+		writeServerError(w, err, "failed to list transactions")
 		return
 	}
 
@@ -371,24 +633,28 @@ func (h *APIHandlers) listTransactions(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 	for _, item := range result.Items {
-		resp.Items = append(resp.Items, transactionSummaryResponse{
-			TransactionID:  item.ID,
-			SenderUserID:   item.SenderUserID,
-			ReceiverUserID: item.ReceiverUserID,
-			Amount:         item.Amount,
-			Currency:       item.Currency,
-			Type:           item.Type,
-			Status:         item.Status,
-			Channel:        item.Channel,
-			Timestamp:      formatTime(item.Timestamp),
-			CreatedAt:      formatTime(item.CreatedAt),
-			UpdatedAt:      formatTime(item.UpdatedAt),
-		})
+		resp.Items = append(resp.Items, newTransactionSummaryResponse(item))
 	}
 
 	respondJSON(w, http.StatusOK, resp)
 }
 
+func newTransactionSummaryResponse(item domain.TransactionSummary) transactionSummaryResponse {
+	return transactionSummaryResponse{
+		TransactionID:  item.ID,
+		SenderUserID:   item.SenderUserID,
+		ReceiverUserID: item.ReceiverUserID,
+		Amount:         item.Amount,
+		Currency:       item.Currency,
+		Type:           item.Type,
+		Status:         item.Status,
+		Channel:        item.Channel,
+		Timestamp:      formatTime(item.Timestamp),
+		CreatedAt:      formatTime(item.CreatedAt),
+		UpdatedAt:      formatTime(item.UpdatedAt),
+	}
+}
+
 // --- Request & Response DTOs ---
 
 type userRequest struct {
@@ -444,17 +710,22 @@ type transactionRequest struct {
 	IPAddress       string         `json:"ipAddress"`
 	DeviceID        string         `json:"deviceId"`
 	PaymentMethodID string         `json:"paymentMethodId"`
+	OriginatorBIC   string         `json:"originatorBic,omitempty"`
+	BeneficiaryBIC  string         `json:"beneficiaryBic,omitempty"`
 	Timestamp       string         `json:"timestamp"`
 	Metadata        map[string]any `json:"metadata"`
 	CreatedAt       string         `json:"createdAt"`
 	UpdatedAt       string         `json:"updatedAt"`
+	// ReversalOfTransactionID marks this transaction as reversing an earlier one.
+	ReversalOfTransactionID string `json:"reversalOfTransactionId"`
 }
 
 type paginationResponse struct {
-	Page       int   `json:"page"`
-	PageSize   int   `json:"pageSize"`
-	TotalItems int64 `json:"totalItems"`
-	TotalPages int   `json:"totalPages"`
+	Page       int    `json:"page"`
+	PageSize   int    `json:"pageSize"`
+	TotalItems int64  `json:"totalItems"`
+	TotalPages int    `json:"totalPages"`
+	NextCursor string `json:"nextCursor,omitempty"`
 }
 
 type listUsersResponse struct {
@@ -474,8 +745,8 @@ type userSummaryResponse struct {
 	Phone     string  `json:"phone"`
 	KYCStatus string  `json:"kycStatus"`
 	RiskScore float64 `json:"riskScore"`
-	CreatedAt string  `json:"createdAt"`
-	UpdatedAt string  `json:"updatedAt"`
+	CreatedAt string  `json:"createdAt,omitempty"`
+	UpdatedAt string  `json:"updatedAt,omitempty"`
 }
 
 type transactionSummaryResponse struct {
@@ -487,9 +758,27 @@ type transactionSummaryResponse struct {
 	Type           string  `json:"type"`
 	Status         string  `json:"status"`
 	Channel        string  `json:"channel"`
-	Timestamp      string  `json:"timestamp"`
-	CreatedAt      string  `json:"createdAt"`
-	UpdatedAt      string  `json:"updatedAt"`
+	Timestamp      string  `json:"timestamp,omitempty"`
+	CreatedAt      string  `json:"createdAt,omitempty"`
+	UpdatedAt      string  `json:"updatedAt,omitempty"`
+}
+
+// sectionLimits is embedded in relationship responses so clients can tell when a
+// section holds more rows than were returned.
+type sectionLimits struct {
+	Limit             int      `json:"limit"`
+	Cap               int      `json:"cap"`
+	Truncated         bool     `json:"truncated"`
+	TruncatedSections []string `json:"truncatedSections,omitempty"`
+}
+
+func toSectionLimits(l domain.SectionLimits) sectionLimits {
+	return sectionLimits{
+		Limit:             l.Limit,
+		Cap:               l.Cap,
+		Truncated:         l.Truncated(),
+		TruncatedSections: l.TruncatedSections,
+	}
 }
 
 type userRelationshipsResponse struct {
@@ -497,6 +786,7 @@ type userRelationshipsResponse struct {
 	DirectConnections []userDirectConnection `json:"directConnections"`
 	Transactions      []userTransactionLink  `json:"transactions"`
 	SharedAttributes  []sharedAttribute      `json:"sharedAttributes"`
+	sectionLimits
 }
 
 type userDirectConnection struct {
@@ -506,7 +796,7 @@ type userDirectConnection struct {
 	TransactionID string  `json:"transactionId"`
 	Amount        float64 `json:"amount"`
 	Currency      string  `json:"currency"`
-	Timestamp     string  `json:"timestamp"`
+	Timestamp     string  `json:"timestamp,omitempty"`
 }
 
 type userTransactionLink struct {
@@ -514,7 +804,7 @@ type userTransactionLink struct {
 	Role          string  `json:"role"`
 	Amount        float64 `json:"amount"`
 	Currency      string  `json:"currency"`
-	Timestamp     string  `json:"timestamp"`
+	Timestamp     string  `json:"timestamp,omitempty"`
 }
 
 type sharedAttribute struct {
@@ -527,6 +817,8 @@ type transactionRelationshipsResponse struct {
 	TransactionID      string                `json:"transactionId"`
 	Users              []transactionUserLink `json:"users"`
 	LinkedTransactions []linkedTransaction   `json:"linkedTransactions"`
+	ReversalChain      []reversalLink        `json:"reversalChain"`
+	sectionLimits
 }
 
 type transactionUserLink struct {
@@ -538,11 +830,20 @@ type transactionUserLink struct {
 }
 
 type linkedTransaction struct {
-	TransactionID string  `json:"transactionId"`
-	LinkType      string  `json:"linkType"`
-	AttributeHash string  `json:"attributeHash"`
-	Score         float64 `json:"score"`
-	UpdatedAt     string  `json:"updatedAt"`
+	TransactionID string   `json:"transactionId"`
+	LinkType      string   `json:"linkType"`
+	AttributeHash string   `json:"attributeHash"`
+	Score         float64  `json:"score"`
+	DecayedScore  *float64 `json:"decayedScore,omitempty"`
+	UpdatedAt     string   `json:"updatedAt,omitempty"`
+}
+
+type reversalLink struct {
+	TransactionID         string  `json:"transactionId"`
+	ReversesTransactionID string  `json:"reversesTransactionId,omitempty"`
+	Amount                float64 `json:"amount"`
+	Currency              string  `json:"currency"`
+	Timestamp             string  `json:"timestamp,omitempty"`
 }
 
 type statusResponse struct {
@@ -550,6 +851,17 @@ type statusResponse struct {
 	ID     string `json:"id"`
 }
 
+type statsResponse struct {
+	TotalUsers           int64            `json:"totalUsers"`
+	TotalTransactions    int64            `json:"totalTransactions"`
+	TransactionsByStatus map[string]int64 `json:"transactionsByStatus"`
+}
+
+type pruneResponse struct {
+	Status  string `json:"status"`
+	Removed int    `json:"removed"`
+}
+
 // --- Helpers ---
 
 func (req userRequest) toServiceInput() (service.UserInput, error) {
@@ -666,6 +978,13 @@ func (req transactionRequest) toServiceInput() (service.TransactionInput, error)
 		updatedPtr = &ut
 	}
 
+	if _, ok := service.NormalizeBIC(req.OriginatorBIC); req.OriginatorBIC != "" && !ok {
+		return service.TransactionInput{}, fmtError("invalid originatorBic")
+	}
+	if _, ok := service.NormalizeBIC(req.BeneficiaryBIC); req.BeneficiaryBIC != "" && !ok {
+		return service.TransactionInput{}, fmtError("invalid beneficiaryBic")
+	}
+
 	return service.TransactionInput{
 		ID:              req.TransactionID,
 		SenderUserID:    req.SenderUserID,
@@ -678,10 +997,14 @@ func (req transactionRequest) toServiceInput() (service.TransactionInput, error)
 		IPAddress:       req.IPAddress,
 		DeviceID:        req.DeviceID,
 		PaymentMethodID: req.PaymentMethodID,
+		OriginatorBIC:   req.OriginatorBIC,
+		BeneficiaryBIC:  req.BeneficiaryBIC,
 		Timestamp:       ts,
 		Metadata:        req.Metadata,
 		CreatedAt:       createdPtr,
 		UpdatedAt:       updatedPtr,
+
+		ReversalOfTransactionID: strings.TrimSpace(req.ReversalOfTransactionID),
 	}, nil
 }
 
@@ -693,6 +1016,8 @@ func decodeJSON(r *http.Request, dst any) error {
 
 	decoder := json.NewDecoder(r.Body)
 	decoder.DisallowUnknownFields()
+	// Keep metadata numbers as written so integers are not stored as floats.
+	decoder.UseNumber()
 	if err := decoder.Decode(dst); err != nil {
 		return err
 	}
@@ -709,6 +1034,8 @@ func parseInt(value string, fallback int) int {
 	return fallback
 }
 
+// formatTime renders t as RFC3339 or "" when unset; response fields that carry optional
+// timestamps are tagged omitempty so an unset time is omitted rather than sent as "".
 func formatTime(t time.Time) string {
 	if t.IsZero() {
 		return ""
@@ -716,6 +1043,7 @@ func formatTime(t time.Time) string {
 	return t.UTC().Format(time.RFC3339)
 }
 
+// formatTimePtr is the pointer variant of formatTime.
 func formatTimePtr(ts *time.Time) string {
 	if ts == nil || ts.IsZero() {
 		return ""
@@ -729,6 +1057,36 @@ func writeError(w http.ResponseWriter, status int, msg string) {
 	})
 }
 
+// writeConflict responds with 409 when err is a repository conflict and reports whether it did so.
+func writeConflict(w http.ResponseWriter, err error, entity string) bool {
+	if !errors.Is(err, repository.ErrConflict) {
+		return false
+	}
+	msg := entity + " conflicts with an existing record"
+	var conflict *repository.ConflictError
+	if errors.As(err, &conflict) && conflict.Key() != "" {
+		msg += ": " + conflict.Key()
+	}
+	writeError(w, http.StatusConflict, msg)
+	return true
+}
+
+// operationLimitRetryAfter is the Retry-After, in seconds, sent when the graph client has
+// no free concurrency slot.
+const operationLimitRetryAfter = 1
+
+// writeServerError responds to a failure the client did not cause with 500 and msg, or
+// with 503 and Retry-After when a graph read or write found its concurrency class
+// saturated, so clients can back off and retry instead of treating it as a failure.
+func writeServerError(w http.ResponseWriter, err error, msg string) {
+	if errors.Is(err, graph.ErrOperationLimit) {
+		w.Header().Set("Retry-After", strconv.Itoa(operationLimitRetryAfter))
+		writeError(w, http.StatusServiceUnavailable, "graph is busy, retry later")
+		return
+	}
+	writeError(w, http.StatusInternalServerError, msg)
+}
+
 func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
 	w.Header().Set("Allow", strings.Join(allowed, ", "))
 	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
@@ -2,6 +2,9 @@ package server
 
 import (
 	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
 
 	"github.com/vanshika/fintrace/backend/internal/graph"
 )
@@ -11,15 +14,78 @@ type HealthService interface {
 	Probe(ctx context.Context) error
 }
 
-// GraphHealthService verifies graph connectivity as part of health checks.
+// HealthCheck is the outcome of one step of a readiness probe.
+type HealthCheck struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// HealthReporter is implemented by health services that can report each probe step
+// separately, so /readyz can show which one failed and how long each took.
+type HealthReporter interface {
+	Checks(ctx context.Context) []HealthCheck
+}
+
+// GraphHealthService verifies graph connectivity as part of health checks. When Query is
+// set it is also run as a read against the configured database, which catches problems
+// connectivity alone does not, such as a database name that does not exist.
 type GraphHealthService struct {
 	Client graph.Client
+	Query  string
 }
 
 // Probe implements the HealthService interface.
 func (s GraphHealthService) Probe(ctx context.Context) error {
+	for _, check := range s.Checks(ctx) {
+		if check.Err != nil {
+			return check.Err
+		}
+	}
+	return nil
+}
+
+// Checks implements the HealthReporter interface. The query is skipped when the
+// connectivity check fails.
+func (s GraphHealthService) Checks(ctx context.Context) []HealthCheck {
 	if s.Client == nil {
 		return nil
 	}
-	return s.Client.VerifyConnectivity(ctx)
+	connectivity := timeCheck("connectivity", func() error {
+		return s.Client.VerifyConnectivity(ctx)
+	})
+	checks := []HealthCheck{connectivity}
+	if s.Query == "" || connectivity.Err != nil {
+		return checks
+	}
+	return append(checks, timeCheck("query", func() error {
+		if _, err := s.Client.ExecuteRead(ctx, s.Query, nil); err != nil {
+			return fmt.Errorf("health query: %w", err)
+		}
+		return nil
+	}))
+}
+
+func timeCheck(name string, fn func() error) HealthCheck {
+	start := time.Now()
+	err := fn()
+	return HealthCheck{Name: name, Duration: time.Since(start), Err: err}
+}
+
+// Readiness tracks whether the server should still receive traffic. Once draining,
+// /healthz reports 503 so load balancers deregister the instance before shutdown.
+type Readiness struct {
+	draining atomic.Bool
+}
+
+// StartDraining marks the server as no longer ready.
+func (r *Readiness) StartDraining() {
+	if r != nil {
+		r.draining.Store(true)
+	}
+}
+
+// Draining reports whether StartDraining has been called.
+func (r *Readiness) Draining() bool {
+	return r != nil && r.draining.Load()
 }
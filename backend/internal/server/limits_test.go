@@ -0,0 +1,30 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/service"
+)
+
+func TestCreateUserOverStrictCapIsBadRequest(t *testing.T) {
+	repo := &stubRepository{}
+	svc := service.NewRelationshipService(repo, nil)
+	svc.WithEntityLimits(service.EntityLimits{MaxPaymentMethods: 1, Strict: true}, nil)
+	handlers := NewAPIHandlers(slog.New(slog.NewTextHandler(io.Discard, nil)), svc)
+
+	body := `{"userId":"USR-1","paymentMethods":[{"paymentMethodId":"PM-1"},{"paymentMethodId":"PM-2"}]}`
+	rec := serve(t, handlers.handleUsers, http.MethodPost, "/users", strings.NewReader(body))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), "payment methods") {
+		t.Errorf("body = %s, want it to name the capped field", rec.Body)
+	}
+	if len(repo.users) != 0 {
+		t.Error("a user over the cap was written")
+	}
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultLookupMaxIDs caps a batch lookup when WithLookupLimit is not set.
+const defaultLookupMaxIDs = 500
+
+// WithLookupLimit caps how many IDs one POST /users/lookup or /transactions/lookup
+// request may resolve. Zero or less uses defaultLookupMaxIDs.
+func (h *APIHandlers) WithLookupLimit(maxIDs int) *APIHandlers {
+	h.lookupMax = maxIDs
+	return h
+}
+
+func (h *APIHandlers) handleUserLookup(w http.ResponseWriter, r *http.Request) {
+	ids, ok := h.decodeLookupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	lookups, err := h.service.LookupUsers(r.Context(), ids)
+	if err != nil {
+		h.logger.Error("failed to look up users", "error", err, "count", len(ids))
+		writeServerError(w, err, "failed to look up users")
+		return
+	}
+
+	redact := h.shouldRedactPII(r)
+	resp := userLookupResponse{Items: make([]userLookupItem, 0, len(lookups))}
+	for _, lookup := range lookups {
+		item := userLookupItem{ID: lookup.ID, Found: lookup.Found}
+		if lookup.Found {
+			user := lookup.User
+			if redact {
+				user = user.Redacted()
+			}
+			summary := newUserSummaryResponse(user)
+			item.User = &summary
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleTransactionLookup(w http.ResponseWriter, r *http.Request) {
+	ids, ok := h.decodeLookupRequest(w, r)
+	if !ok {
+		return
+	}
+
+	lookups, err := h.service.LookupTransactions(r.Context(), ids)
+	if err != nil {
+		h.logger.Error("failed to look up transactions", "error", err, "count", len(ids))
+		writeServerError(w, err, "failed to look up transactions")
+		return
+	}
+
+	resp := transactionLookupResponse{Items: make([]transactionLookupItem, 0, len(lookups))}
+	for _, lookup := range lookups {
+		item := transactionLookupItem{ID: lookup.ID, Found: lookup.Found}
+		if lookup.Found {
+			summary := newTransactionSummaryResponse(lookup.Transaction)
+			item.Transaction = &summary
+		}
+		resp.Items = append(resp.Items, item)
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// decodeLookupRequest reads and validates a lookup body, writing the error response and
+// returning false when it is unusable.
+func (h *APIHandlers) decodeLookupRequest(w http.ResponseWriter, r *http.Request) ([]string, bool) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return nil, false
+	}
+
+	var req lookupRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return nil, false
+	}
+	if len(req.IDs) == 0 {
+		writeError(w, http.StatusBadRequest, "ids must contain at least one ID")
+		return nil, false
+	}
+	maxIDs := h.lookupMax
+	if maxIDs <= 0 {
+		maxIDs = defaultLookupMaxIDs
+	}
+	if len(req.IDs) > maxIDs {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("ids must contain at most %d IDs", maxIDs))
+		return nil, false
+	}
+	for i, id := range req.IDs {
+		req.IDs[i] = strings.TrimSpace(id)
+		if req.IDs[i] == "" {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("ids[%d] is empty", i))
+			return nil, false
+		}
+	}
+	return req.IDs, true
+}
+
+type lookupRequest struct {
+	IDs []string `json:"ids"`
+}
+
+type userLookupResponse struct {
+	Items []userLookupItem `json:"items"`
+}
+
+type userLookupItem struct {
+	ID    string               `json:"id"`
+	Found bool                 `json:"found"`
+	User  *userSummaryResponse `json:"user,omitempty"`
+}
+
+type transactionLookupResponse struct {
+	Items []transactionLookupItem `json:"items"`
+}
+
+type transactionLookupItem struct {
+	ID          string                      `json:"id"`
+	Found       bool                        `json:"found"`
+	Transaction *transactionSummaryResponse `json:"transaction,omitempty"`
+}
@@ -0,0 +1,230 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// routeTemplates are the label values metrics use for request paths. "{id}" matches any
+// single segment; literal segments are preferred, so /users/lookup is not /users/{id}.
+// Paths matching no template are labelled otherRoute, which keeps label cardinality
+// bounded no matter what clients request.
+var routeTemplates = [][]string{
+	splitRoute("/healthz"),
+	splitRoute("/readyz"),
+	splitRoute("/metrics"),
+	splitRoute("/users"),
+	splitRoute("/users/lookup"),
+	splitRoute("/users/{id}"),
+	splitRoute("/users/{id}/risk-explanation"),
+	splitRoute("/users/{id}/relationship-counts"),
+	splitRoute("/users/{id}/unique-attributes"),
+	splitRoute("/users/{id}/component-size"),
+	splitRoute("/users/{id}/attributes"),
+	splitRoute("/transactions"),
+	splitRoute("/transactions/lookup"),
+	splitRoute("/transactions/{id}"),
+	splitRoute("/transactions/{id}/recompute-links"),
+	splitRoute("/relationships/user"),
+	splitRoute("/relationships/user/{id}"),
+	splitRoute("/relationships/transaction"),
+	splitRoute("/relationships/transaction/{id}"),
+	splitRoute("/stats"),
+	splitRoute("/admin/prune"),
+	splitRoute("/admin/queries"),
+	splitRoute("/admin/queries/{id}"),
+	splitRoute("/analytics/compare"),
+	splitRoute("/analytics/onboarding-velocity"),
+	splitRoute("/analytics/isolated-users"),
+	splitRoute("/analytics/shared-payment"),
+	splitRoute("/analytics/shared-payment-methods"),
+	splitRoute("/analytics/bank-transactions"),
+	splitRoute("/analytics/shortest-paths"),
+	splitRoute("/analytics/shortest-path"),
+	splitRoute("/analytics/paths"),
+	splitRoute("/analytics/user-tx-path"),
+	splitRoute("/analytics/neighborhood"),
+	splitRoute("/analytics/cycles"),
+	splitRoute("/analytics/components"),
+	splitRoute("/analytics/cluster-profile"),
+	splitRoute("/analytics/attribute-clusters"),
+	splitRoute("/analytics/correlated-users"),
+	splitRoute("/analytics/centrality"),
+	splitRoute("/analytics/hubs"),
+	splitRoute("/export/edges"),
+	splitRoute("/export/nodes"),
+	splitRoute("/export/jobs"),
+	splitRoute("/export/jobs/{id}"),
+	splitRoute("/export/jobs/{id}/download"),
+}
+
+const (
+	idSegment  = "{id}"
+	otherRoute = "other"
+)
+
+func splitRoute(path string) []string {
+	return strings.Split(strings.Trim(path, "/"), "/")
+}
+
+// routeTemplate resolves a request path to its route template, e.g. /v1/users/USR-123/
+// to /users/{id}. The path is normalized and its /vN prefix dropped first, as the router
+// does before dispatching.
+func routeTemplate(path string) string {
+	path, ok := normalizePath(path)
+	if !ok {
+		return otherRoute
+	}
+	if _, rest, hasPrefix := splitVersionPrefix(path); hasPrefix {
+		path = rest
+	}
+	segments := splitRoute(path)
+
+	best, bestLiterals := -1, -1
+	for i, template := range routeTemplates {
+		if len(template) != len(segments) {
+			continue
+		}
+		literals := 0
+		matched := true
+		for j, part := range template {
+			if part == idSegment {
+				continue
+			}
+			if part != segments[j] {
+				matched = false
+				break
+			}
+			literals++
+		}
+		if matched && literals > bestLiterals {
+			best, bestLiterals = i, literals
+		}
+	}
+	if best < 0 {
+		return otherRoute
+	}
+	return "/" + strings.Join(routeTemplates[best], "/")
+}
+
+// metricMethods are the request methods used as label values; anything else is OTHER.
+var metricMethods = map[string]struct{}{
+	http.MethodGet: {}, http.MethodHead: {}, http.MethodPost: {}, http.MethodPut: {},
+	http.MethodPatch: {}, http.MethodDelete: {}, http.MethodOptions: {},
+}
+
+// requestDurationBuckets are the histogram bucket upper bounds, in seconds.
+var requestDurationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type requestKey struct {
+	method string
+	route  string
+}
+
+type requestStats struct {
+	statuses map[int]uint64
+	buckets  []uint64
+	count    uint64
+	sum      float64
+}
+
+// requestMetrics counts requests and their durations by method, route template and
+// status, and renders them in the Prometheus text format.
+type requestMetrics struct {
+	mu    sync.Mutex
+	stats map[requestKey]*requestStats
+}
+
+func newRequestMetrics() *requestMetrics {
+	return &requestMetrics{stats: make(map[requestKey]*requestStats)}
+}
+
+func (m *requestMetrics) observe(method, path string, status int, duration time.Duration) {
+	if _, ok := metricMethods[method]; !ok {
+		method = "OTHER"
+	}
+	key := requestKey{method: method, route: routeTemplate(path)}
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats, ok := m.stats[key]
+	if !ok {
+		stats = &requestStats{statuses: make(map[int]uint64), buckets: make([]uint64, len(requestDurationBuckets))}
+		m.stats[key] = stats
+	}
+	stats.statuses[status]++
+	stats.count++
+	stats.sum += seconds
+	for i, bound := range requestDurationBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+}
+
+// middleware records every request passing through next.
+func (m *requestMetrics) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		m.observe(r.Method, r.URL.Path, rec.status, time.Since(start))
+	})
+}
+
+// ServeHTTP writes the collected metrics in the Prometheus text exposition format.
+func (m *requestMetrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	m.mu.Lock()
+	keys := make([]requestKey, 0, len(m.stats))
+	for key := range m.stats {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].method < keys[j].method
+	})
+
+	var b strings.Builder
+	b.WriteString("# HELP fintrace_http_requests_total HTTP requests served, by route template.\n")
+	b.WriteString("# TYPE fintrace_http_requests_total counter\n")
+	for _, key := range keys {
+		stats := m.stats[key]
+		statuses := make([]int, 0, len(stats.statuses))
+		for status := range stats.statuses {
+			statuses = append(statuses, status)
+		}
+		sort.Ints(statuses)
+		for _, status := range statuses {
+			fmt.Fprintf(&b, "fintrace_http_requests_total{method=%q,route=%q,status=\"%d\"} %d\n", key.method, key.route, status, stats.statuses[status])
+		}
+	}
+	b.WriteString("# HELP fintrace_http_request_duration_seconds HTTP request latency, by route template.\n")
+	b.WriteString("# TYPE fintrace_http_request_duration_seconds histogram\n")
+	for _, key := range keys {
+		stats := m.stats[key]
+		labels := fmt.Sprintf("method=%q,route=%q", key.method, key.route)
+		for i, bound := range requestDurationBuckets {
+			fmt.Fprintf(&b, "fintrace_http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, strconv.FormatFloat(bound, 'g', -1, 64), stats.buckets[i])
+		}
+		fmt.Fprintf(&b, "fintrace_http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, stats.count)
+		fmt.Fprintf(&b, "fintrace_http_request_duration_seconds_sum{%s} %s\n", labels, strconv.FormatFloat(stats.sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "fintrace_http_request_duration_seconds_count{%s} %d\n", labels, stats.count)
+	}
+	m.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_, _ = w.Write([]byte(b.String()))
+}
@@ -0,0 +1,143 @@
+package server
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestRouteTemplate(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "/users/USR-123", want: "/users/{id}"},
+		{path: "/users/USR-123/", want: "/users/{id}"},
+		{path: "/users//USR-123", want: "/users/{id}"},
+		{path: "/users", want: "/users"},
+		{path: "/users/lookup", want: "/users/lookup"},
+		{path: "/users/USR-123/risk-explanation", want: "/users/{id}/risk-explanation"},
+		{path: "/transactions/lookup", want: "/transactions/lookup"},
+		{path: "/transactions/TX-9", want: "/transactions/{id}"},
+		{path: "/export/jobs/J1/download", want: "/export/jobs/{id}/download"},
+		{path: "/v1/users/USR-123", want: "/users/{id}"},
+		{path: "/v1/users/lookup", want: "/users/lookup"},
+		{path: "/v1/stats", want: "/stats"},
+		{path: "/", want: otherRoute},
+		{path: "/unknown", want: otherRoute},
+		{path: "/users/USR-123/unknown", want: otherRoute},
+		{path: "/users/USR-123/risk-explanation/extra", want: otherRoute},
+		{path: "/users/../admin/prune", want: otherRoute},
+	}
+	for _, tt := range tests {
+		if got := routeTemplate(tt.path); got != tt.want {
+			t.Errorf("routeTemplate(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+// TestRouteTemplatesMatchRouter keeps routeTemplates, a hand-maintained copy of the
+// routes, in step with the patterns router.go registers: every exact pattern needs its
+// own template, every subtree pattern at least one template below it, and every template
+// must be served by some pattern.
+func TestRouteTemplatesMatchRouter(t *testing.T) {
+	exact, subtrees := registeredPatterns(t)
+	if len(exact) == 0 {
+		t.Fatal("found no routes registered in router.go")
+	}
+
+	templates := make(map[string]bool, len(routeTemplates))
+	for _, template := range routeTemplates {
+		templates["/"+strings.Join(template, "/")] = true
+	}
+
+	for pattern := range exact {
+		if !templates[pattern] {
+			t.Errorf("route %s is registered in router.go but has no entry in routeTemplates", pattern)
+		}
+	}
+	for subtree := range subtrees {
+		found := false
+		for template := range templates {
+			if strings.HasPrefix(template, subtree) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("subtree %s is registered in router.go but no routeTemplates entry lies below it", subtree)
+		}
+	}
+	for template := range templates {
+		if exact[template] {
+			continue
+		}
+		served := false
+		for subtree := range subtrees {
+			if strings.HasPrefix(template, subtree) {
+				served = true
+				break
+			}
+		}
+		if !served {
+			t.Errorf("routeTemplates entry %s is not served by any route in router.go", template)
+		}
+	}
+}
+
+// registeredPatterns reads the mux patterns registered in router.go, split into exact
+// paths and subtrees, which end in a slash.
+func registeredPatterns(t *testing.T) (map[string]bool, map[string]bool) {
+	t.Helper()
+	file, err := parser.ParseFile(token.NewFileSet(), "router.go", nil, 0)
+	if err != nil {
+		t.Fatalf("parse router.go: %v", err)
+	}
+
+	exact, subtrees := map[string]bool{}, map[string]bool{}
+	add := func(pattern string) {
+		if strings.HasSuffix(pattern, "/") {
+			subtrees[pattern] = true
+		} else {
+			exact[pattern] = true
+		}
+	}
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		argIndex := -1
+		switch fun := call.Fun.(type) {
+		case *ast.SelectorExpr:
+			if recv, ok := fun.X.(*ast.Ident); ok && recv.Name == "mux" && (fun.Sel.Name == "HandleFunc" || fun.Sel.Name == "Handle") {
+				argIndex = 0
+			}
+		case *ast.Ident:
+			if fun.Name == "handleCollection" {
+				argIndex = 1
+			}
+		}
+		if argIndex < 0 || len(call.Args) <= argIndex {
+			return true
+		}
+		lit, ok := call.Args[argIndex].(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			t.Errorf("route registered with a non-literal pattern at offset %d", call.Pos())
+			return true
+		}
+		pattern, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			t.Fatalf("unquote pattern %s: %v", lit.Value, err)
+		}
+		add(pattern)
+		if argIndex == 1 {
+			add(pattern + "/")
+		}
+		return true
+	})
+	return exact, subtrees
+}
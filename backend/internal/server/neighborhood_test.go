@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// projectingRepository answers ExpandUserNeighborhood with a two user subgraph carrying
+// only the properties asked for, and records the projection it was given.
+type projectingRepository struct {
+	stubRepository
+	props repository.PropertyProjection
+}
+
+func (s *projectingRepository) ExpandUserNeighborhood(_ context.Context, userID string, depth int, props repository.PropertyProjection) (domain.Neighborhood, error) {
+	s.props = props
+	stored := map[string]any{"riskScore": 0.9, "kycStatus": "VERIFIED", "amount": 120.5, "currency": "USD", "transactionId": "TX-1"}
+	pick := func(keys []string) map[string]any {
+		if len(keys) == 0 {
+			return nil
+		}
+		out := make(map[string]any, len(keys))
+		for _, key := range keys {
+			out[key] = stored[key]
+		}
+		return out
+	}
+	return domain.Neighborhood{
+		UserID: userID,
+		Depth:  depth,
+		Nodes: []domain.PathNode{
+			{ID: userID, Label: "User", Properties: pick(props.Node)},
+			{ID: "USR-2", Label: "User", Properties: pick(props.Node)},
+		},
+		Edges: []domain.PathEdge{{Type: "SENT_TO", Source: userID, Target: "USR-2", Weight: 1, Properties: pick(props.Edge)}},
+	}, nil
+}
+
+func TestNeighborhoodReturnsOnlyRequestedProperties(t *testing.T) {
+	repo := &projectingRepository{}
+	rec := serve(t, newStubHandlers(repo).handleNeighborhood, http.MethodGet, "/graph/neighborhood?userId=USR-1&nodeProps=riskScore,riskScore&edgeProps=amount", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if !slices.Equal(repo.props.Node, []string{"riskScore"}) || !slices.Equal(repo.props.Edge, []string{"amount"}) {
+		t.Errorf("repository got projection %+v, want node [riskScore] and edge [amount]", repo.props)
+	}
+
+	var body struct {
+		Nodes []map[string]any `json:"nodes"`
+		Edges []map[string]any `json:"edges"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	for _, node := range body.Nodes {
+		props, _ := node["properties"].(map[string]any)
+		if _, ok := props["riskScore"]; !ok || len(props) != 1 {
+			t.Errorf("node %v properties = %v, want only riskScore", node["id"], props)
+		}
+	}
+	for _, edge := range body.Edges {
+		props, _ := edge["properties"].(map[string]any)
+		if _, ok := props["amount"]; !ok || len(props) != 1 {
+			t.Errorf("edge properties = %v, want only amount", props)
+		}
+	}
+}
+
+func TestNeighborhoodOmitsPropertiesByDefault(t *testing.T) {
+	rec := serve(t, newStubHandlers(&projectingRepository{}).handleNeighborhood, http.MethodGet, "/graph/neighborhood?userId=USR-1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Nodes []map[string]any `json:"nodes"`
+		Edges []map[string]any `json:"edges"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	for _, node := range body.Nodes {
+		assertAbsent(t, node, "properties")
+	}
+	for _, edge := range body.Edges {
+		assertAbsent(t, edge, "properties")
+	}
+}
+
+func TestNeighborhoodRejectsUnknownProperties(t *testing.T) {
+	for _, query := range []string{"nodeProps=fullName", "edgeProps=riskScore", "nodeProps=riskScore,bogus"} {
+		repo := &projectingRepository{}
+		rec := serve(t, newStubHandlers(repo).handleNeighborhood, http.MethodGet, "/graph/neighborhood?userId=USR-1&"+query, nil)
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400: %s", query, rec.Code, rec.Body)
+		}
+	}
+}
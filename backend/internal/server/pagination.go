@@ -0,0 +1,91 @@
+package server
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPage     = 1
+	defaultPageSize = 50
+	// maxPageSize caps pageSize for every list endpoint; larger requests are clamped.
+	maxPageSize = 200
+)
+
+// paginationParams is the validated page/pageSize pair of a list request.
+type paginationParams struct {
+	Page     int
+	PageSize int
+}
+
+// parsePaginationParams reads page and pageSize from query, applying defaults when they
+// are absent and clamping pageSize to maxPageSize. Non-numeric or non-positive values
+// are rejected so callers can answer 400 instead of silently serving page one.
+func parsePaginationParams(query url.Values) (paginationParams, error) {
+	params := paginationParams{Page: defaultPage, PageSize: defaultPageSize}
+
+	if v := query.Get("page"); v != "" {
+		page, err := strconv.Atoi(v)
+		if err != nil || page < 1 {
+			return paginationParams{}, fmt.Errorf("invalid page: must be a positive integer")
+		}
+		params.Page = page
+	}
+	if v := query.Get("pageSize"); v != "" {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 1 {
+			return paginationParams{}, fmt.Errorf("invalid pageSize: must be a positive integer")
+		}
+		params.PageSize = size
+	}
+	if params.PageSize > maxPageSize {
+		params.PageSize = maxPageSize
+	}
+	return params, nil
+}
+
+// parseFloatParam reads an optional float query parameter, returning nil when it is
+// absent.
+func parseFloatParam(query url.Values, key string) (*float64, error) {
+	raw := query.Get(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s", key)
+	}
+	return &v, nil
+}
+
+// validateFloatRange rejects a lower bound greater than its upper bound.
+func validateFloatRange(minName string, min *float64, maxName string, max *float64) error {
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("%s must not be greater than %s", minName, maxName)
+	}
+	return nil
+}
+
+// validateTimeRange rejects a start after its end.
+func validateTimeRange(startName string, start *time.Time, endName string, end *time.Time) error {
+	if start != nil && end != nil && start.After(*end) {
+		return fmt.Errorf("%s must not be after %s", startName, endName)
+	}
+	return nil
+}
+
+// parseSectionLimit reads the optional per-section ?limit; values above the server cap
+// are lowered to it by the repository.
+func parseSectionLimit(query url.Values) (int, error) {
+	v := query.Get("limit")
+	if v == "" {
+		return 0, nil
+	}
+	limit, err := strconv.Atoi(v)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("invalid limit: must be a positive integer")
+	}
+	return limit, nil
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestParsePaginationParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    paginationParams
+		wantErr bool
+	}{
+		{name: "defaults", query: "", want: paginationParams{Page: defaultPage, PageSize: defaultPageSize}},
+		{name: "page override", query: "page=3", want: paginationParams{Page: 3, PageSize: defaultPageSize}},
+		{name: "page size override", query: "pageSize=10", want: paginationParams{Page: defaultPage, PageSize: 10}},
+		{name: "both overrides", query: "page=2&pageSize=25", want: paginationParams{Page: 2, PageSize: 25}},
+		{name: "page size at cap", query: "pageSize=200", want: paginationParams{Page: defaultPage, PageSize: maxPageSize}},
+		{name: "page size clamped", query: "pageSize=5000", want: paginationParams{Page: defaultPage, PageSize: maxPageSize}},
+		{name: "empty values use defaults", query: "page=&pageSize=", want: paginationParams{Page: defaultPage, PageSize: defaultPageSize}},
+		{name: "zero page", query: "page=0", wantErr: true},
+		{name: "negative page", query: "page=-1", wantErr: true},
+		{name: "non-numeric page", query: "page=two", wantErr: true},
+		{name: "fractional page", query: "page=1.5", wantErr: true},
+		{name: "zero page size", query: "pageSize=0", wantErr: true},
+		{name: "negative page size", query: "pageSize=-10", wantErr: true},
+		{name: "non-numeric page size", query: "pageSize=all", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("parse query %q: %v", tt.query, err)
+			}
+			got, err := parsePaginationParams(query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parsePaginationParams(%q) = %+v, want an error", tt.query, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parsePaginationParams(%q): %v", tt.query, err)
+			}
+			if got != tt.want {
+				t.Errorf("parsePaginationParams(%q) = %+v, want %+v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// chainPath returns a path through n users, u0 to u(n-1), so it has n nodes and n-1 edges.
+func chainPath(n int) domain.ShortestPath {
+	var path domain.ShortestPath
+	for i := 0; i < n; i++ {
+		path.Nodes = append(path.Nodes, domain.PathNode{ID: fmt.Sprintf("u%d", i), Label: "User"})
+		if i > 0 {
+			path.Edges = append(path.Edges, domain.PathEdge{Type: "LINKED_TO", Source: fmt.Sprintf("u%d", i-1), Target: fmt.Sprintf("u%d", i)})
+		}
+	}
+	return path
+}
+
+// batchRepository answers ShortestPathsBatch with path for every pair.
+type batchRepository struct {
+	stubRepository
+	path domain.ShortestPath
+}
+
+func (s *batchRepository) ShortestPathsBatch(_ context.Context, pairs []repository.PathPair, _ int, _ repository.WeightStrategy) ([]domain.ShortestPathResult, error) {
+	results := make([]domain.ShortestPathResult, len(pairs))
+	for i := range results {
+		results[i] = domain.ShortestPathResult{Path: s.path}
+	}
+	return results, nil
+}
+
+func (s *batchRepository) PathBetweenUserAndTransaction(context.Context, string, string, int) (domain.ShortestPath, error) {
+	return s.path, nil
+}
+
+func TestPathBudgetCut(t *testing.T) {
+	path := chainPath(6) // 6 nodes, 5 edges
+	tests := []struct {
+		limit     int
+		wantNodes int
+		wantEdges int
+		truncated bool
+	}{
+		{limit: 11, wantNodes: 6, wantEdges: 5},
+		{limit: 10, wantNodes: 5, wantEdges: 4, truncated: true},
+		{limit: 5, wantNodes: 3, wantEdges: 2, truncated: true},
+		{limit: 1, wantNodes: 1, wantEdges: 0, truncated: true},
+	}
+	for _, tt := range tests {
+		budget := (&APIHandlers{}).WithPathPayloadLimit(tt.limit).newPathBudget()
+		nodes, edges := budget.cut(path.Nodes, path.Edges)
+		if len(nodes) != tt.wantNodes || len(edges) != tt.wantEdges || budget.truncated != tt.truncated {
+			t.Errorf("limit %d: kept %d nodes, %d edges, truncated %v, want %d, %d, %v",
+				tt.limit, len(nodes), len(edges), budget.truncated, tt.wantNodes, tt.wantEdges, tt.truncated)
+		}
+		kept := map[string]bool{}
+		for _, node := range nodes {
+			kept[node.ID] = true
+		}
+		for _, edge := range edges {
+			if !kept[edge.Source] || !kept[edge.Target] {
+				t.Errorf("limit %d: kept edge %s->%s to a cut node", tt.limit, edge.Source, edge.Target)
+			}
+		}
+	}
+}
+
+func TestPathBudgetDefault(t *testing.T) {
+	if got := (&APIHandlers{}).newPathBudget().remaining; got != defaultPathMaxElements {
+		t.Errorf("unset limit budgets %d, want %d", got, defaultPathMaxElements)
+	}
+}
+
+func TestAllPathsTruncatedByPayloadLimit(t *testing.T) {
+	// Each path is 3 nodes and 2 edges; a limit of 12 fits two of the three.
+	repo := &stubRepository{paths: []domain.ShortestPath{chainPath(3), chainPath(3), chainPath(3)}}
+	handlers := newStubHandlers(repo).WithPathPayloadLimit(12)
+	for _, target := range []string{
+		"/analytics/paths?sourceUserId=u0&targetUserId=u2",
+		"/analytics/shortest-path?sourceUserId=u0&targetUserId=u2&k=3",
+	} {
+		handler := handlers.handleAllPaths
+		if strings.Contains(target, "shortest-path") {
+			handler = handlers.handleKShortestPaths
+		}
+		rec := serve(t, handler, http.MethodGet, target, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200: %s", target, rec.Code, rec.Body)
+		}
+		var body struct {
+			Truncated bool `json:"truncated"`
+			Paths     []struct {
+				Nodes []json.RawMessage `json:"nodes"`
+				Edges []json.RawMessage `json:"edges"`
+			} `json:"paths"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if !body.Truncated || len(body.Paths) != 2 {
+			t.Errorf("%s: got %d paths, truncated %v, want 2 whole paths truncated", target, len(body.Paths), body.Truncated)
+		}
+		for _, path := range body.Paths {
+			if len(path.Nodes) != 3 || len(path.Edges) != 2 {
+				t.Errorf("%s: kept path has %d nodes and %d edges, want it whole", target, len(path.Nodes), len(path.Edges))
+			}
+		}
+	}
+}
+
+func TestPathsUnderPayloadLimitNotTruncated(t *testing.T) {
+	repo := &stubRepository{paths: []domain.ShortestPath{chainPath(3), chainPath(3)}}
+	rec := serve(t, newStubHandlers(repo).WithPathPayloadLimit(10).handleAllPaths, http.MethodGet, "/analytics/paths?sourceUserId=u0&targetUserId=u2", nil)
+	var body struct {
+		Truncated bool              `json:"truncated"`
+		Paths     []json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Truncated || len(body.Paths) != 2 {
+		t.Errorf("got %d paths, truncated %v, want both untruncated at exactly the limit", len(body.Paths), body.Truncated)
+	}
+}
+
+func TestShortestPathsBatchTruncatedByPayloadLimit(t *testing.T) {
+	repo := &batchRepository{path: chainPath(4)} // 7 elements per pair
+	handler := newStubHandlers(repo).WithPathPayloadLimit(10).handleShortestPathsBatch
+	body := `{"pairs":[{"sourceUserId":"u0","targetUserId":"u3"},{"sourceUserId":"u1","targetUserId":"u3"}]}`
+	rec := serve(t, handler, http.MethodPost, "/analytics/shortest-paths", strings.NewReader(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp shortestPathsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Truncated || len(resp.Items) != 2 {
+		t.Fatalf("got %d items, truncated %v, want 2 truncated", len(resp.Items), resp.Truncated)
+	}
+	first, second := resp.Items[0], resp.Items[1]
+	if first.Truncated || len(first.Nodes) != 4 || len(first.Edges) != 3 {
+		t.Errorf("first item = %d nodes, %d edges, truncated %v, want the whole path", len(first.Nodes), len(first.Edges), first.Truncated)
+	}
+	// A pair over the budget still reports what was found, just not the elements.
+	if !second.Truncated || len(second.Nodes) != 0 || len(second.Edges) != 0 || !second.Found || second.Hops != 3 {
+		t.Errorf("second item = %+v, want found with 3 hops and no elements", second)
+	}
+}
+
+func TestUserTransactionPathTruncatedByPayloadLimit(t *testing.T) {
+	repo := &batchRepository{path: chainPath(6)}
+	rec := serve(t, newStubHandlers(repo).WithPathPayloadLimit(5).handleUserTransactionPath, http.MethodGet, "/analytics/user-tx-path?userId=u0&transactionId=TX-1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Truncated bool              `json:"truncated"`
+		Hops      int               `json:"hops"`
+		Nodes     []json.RawMessage `json:"nodes"`
+		Edges     []json.RawMessage `json:"edges"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.Truncated || len(body.Nodes)+len(body.Edges) > 5 || body.Hops != 5 {
+		t.Errorf("got %d nodes, %d edges, hops %d, truncated %v, want at most 5 elements of a 5 hop path, truncated",
+			len(body.Nodes), len(body.Edges), body.Hops, body.Truncated)
+	}
+}
+
+func TestNeighborhoodTruncatedByPayloadLimit(t *testing.T) {
+	path := chainPath(8)
+	repo := &stubRepository{neighborhood: domain.Neighborhood{UserID: "u0", Depth: 2, Nodes: path.Nodes, Edges: path.Edges}}
+	rec := serve(t, newStubHandlers(repo).WithPathPayloadLimit(6).handleNeighborhood, http.MethodGet, "/analytics/neighborhood?userId=u0", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Truncated bool `json:"truncated"`
+		Nodes     []struct {
+			ID string `json:"id"`
+		} `json:"nodes"`
+		Edges []struct {
+			Source string `json:"source"`
+			Target string `json:"target"`
+		} `json:"edges"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !body.Truncated || len(body.Nodes) != 3 || len(body.Edges) != 2 {
+		t.Fatalf("got %d nodes, %d edges, truncated %v, want 3, 2, true", len(body.Nodes), len(body.Edges), body.Truncated)
+	}
+	kept := map[string]bool{}
+	for _, node := range body.Nodes {
+		kept[node.ID] = true
+	}
+	for _, edge := range body.Edges {
+		if !kept[edge.Source] || !kept[edge.Target] {
+			t.Errorf("kept edge %s->%s to a node that was cut", edge.Source, edge.Target)
+		}
+	}
+}
@@ -0,0 +1,610 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// defaultPathBatchMaxPairs caps a batch shortest-path request when WithPathBatchLimit is
+// not set.
+const defaultPathBatchMaxPairs = 100
+
+// WithPathBatchLimit caps how many pairs one POST /analytics/shortest-paths request may
+// contain. Zero or less uses defaultPathBatchMaxPairs.
+func (h *APIHandlers) WithPathBatchLimit(maxPairs int) *APIHandlers {
+	h.pathBatchMax = maxPairs
+	return h
+}
+
+// defaultPathMaxElements caps the nodes and edges of one path response when
+// WithPathPayloadLimit is not set.
+const defaultPathMaxElements = 5000
+
+// WithPathPayloadLimit caps the nodes plus edges one path, cycle or neighborhood response
+// carries in total, so a dense graph cannot produce an unbounded body. Responses over the
+// cap are cut and flagged as truncated. Zero or less uses defaultPathMaxElements.
+func (h *APIHandlers) WithPathPayloadLimit(maxElements int) *APIHandlers {
+	h.pathMaxItems = maxElements
+	return h
+}
+
+// pathBudget counts down the nodes and edges a path response may still carry.
+type pathBudget struct {
+	remaining int
+	truncated bool
+}
+
+func (h *APIHandlers) newPathBudget() *pathBudget {
+	limit := h.pathMaxItems
+	if limit <= 0 {
+		limit = defaultPathMaxElements
+	}
+	return &pathBudget{remaining: limit}
+}
+
+// fits reports whether a whole path of nodes and edges fits in what is left, taking it
+// from the budget when it does. A path that does not fit marks the response truncated.
+func (b *pathBudget) fits(nodes []domain.PathNode, edges []domain.PathEdge) bool {
+	size := len(nodes) + len(edges)
+	if size > b.remaining {
+		b.truncated = true
+		return false
+	}
+	b.remaining -= size
+	return true
+}
+
+// cut trims one graph to what is left, keeping nodes in order and then the edges whose
+// ends were both kept, so a cut path is a prefix and a cut neighborhood a subgraph. Nodes
+// get at most half the budget, leaving room for the edges between them.
+func (b *pathBudget) cut(nodes []domain.PathNode, edges []domain.PathEdge) ([]domain.PathNode, []domain.PathEdge) {
+	if b.fits(nodes, edges) {
+		return nodes, edges
+	}
+	nodes = nodes[:min(len(nodes), (b.remaining+1)/2)]
+	b.remaining -= len(nodes)
+	kept := make(map[string]struct{}, len(nodes))
+	for _, node := range nodes {
+		kept[node.ID] = struct{}{}
+	}
+	var keptEdges []domain.PathEdge
+	for _, edge := range edges {
+		if b.remaining == 0 {
+			break
+		}
+		_, source := kept[edge.Source]
+		_, target := kept[edge.Target]
+		if source && target {
+			keptEdges = append(keptEdges, edge)
+			b.remaining--
+		}
+	}
+	return nodes, keptEdges
+}
+
+func (h *APIHandlers) handleShortestPathsBatch(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req shortestPathsRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(req.Pairs) == 0 {
+		writeError(w, http.StatusBadRequest, "pairs must contain at least one pair")
+		return
+	}
+	maxPairs := h.pathBatchMax
+	if maxPairs <= 0 {
+		maxPairs = defaultPathBatchMaxPairs
+	}
+	if len(req.Pairs) > maxPairs {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("pairs must contain at most %d pairs", maxPairs))
+		return
+	}
+	if req.MaxHops < 0 || req.MaxHops > repository.MaxPathHops {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("maxHops must be between 1 and %d", repository.MaxPathHops))
+		return
+	}
+	weighting := repository.WeightStrategy(strings.ToLower(strings.TrimSpace(req.WeightStrategy)))
+	if weighting == "" {
+		weighting = repository.WeightHops
+	}
+	if !slices.Contains(repository.WeightStrategies, weighting) {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported weightStrategy %q; expected hops or amount", req.WeightStrategy))
+		return
+	}
+
+	// Pairs that are invalid on their own get an error in place and are not sent to the
+	// graph, so one bad pair does not fail the batch.
+	items := make([]shortestPathItem, len(req.Pairs))
+	var pairs []repository.PathPair
+	var positions []int
+	for i, p := range req.Pairs {
+		source, target := strings.TrimSpace(p.SourceUserID), strings.TrimSpace(p.TargetUserID)
+		items[i] = shortestPathItem{SourceUserID: source, TargetUserID: target, Nodes: []pathNodeResponse{}, Edges: []pathEdgeResponse{}}
+		switch {
+		case source == "" || target == "":
+			items[i].Error = "sourceUserId and targetUserId are required"
+		case source == target:
+			items[i].Error = "sourceUserId and targetUserId must differ"
+		default:
+			pairs = append(pairs, repository.PathPair{SourceUserID: source, TargetUserID: target})
+			positions = append(positions, i)
+		}
+	}
+
+	var truncated bool
+	if len(pairs) > 0 {
+		results, err := h.service.ShortestPathsBatch(r.Context(), pairs, req.MaxHops, weighting)
+		if err != nil {
+			h.logger.Error("failed to compute shortest paths", "error", err, "pairs", len(pairs))
+			writeServerError(w, err, "failed to compute shortest paths")
+			return
+		}
+		// Pairs share the payload budget in request order. A path that does not fit keeps
+		// its found, hops and cost but lists no nodes or edges.
+		budget := h.newPathBudget()
+		for j, result := range results {
+			item := &items[positions[j]]
+			if result.Err != nil {
+				item.Error = h.pathErrorMessage(result)
+				continue
+			}
+			item.Found = result.Path.Found()
+			item.Hops = result.Path.Hops()
+			item.Cost = result.Path.Cost()
+			if !budget.fits(result.Path.Nodes, result.Path.Edges) {
+				item.Truncated = true
+				continue
+			}
+			item.Nodes, item.Edges = newPathResponse(result.Path.Nodes, result.Path.Edges)
+		}
+		truncated = budget.truncated
+	}
+
+	respondJSON(w, http.StatusOK, shortestPathsResponse{Items: items, Truncated: truncated})
+}
+
+func (h *APIHandlers) handleAllPaths(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	source, target, ok := pathEndpoints(w, query)
+	if !ok {
+		return
+	}
+	maxHops := 0
+	if raw := query.Get("maxHops"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxPathHops {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("maxHops must be between 1 and %d", repository.MaxPathHops))
+			return
+		}
+		maxHops = parsed
+	}
+	limit := 0
+	if raw := query.Get("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxAllPathsLimit {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("limit must be between 1 and %d", repository.MaxAllPathsLimit))
+			return
+		}
+		limit = parsed
+	}
+	format, err := parseGraphFormat(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	paths, err := h.service.AllPathsBetweenUsers(r.Context(), source, target, maxHops, limit)
+	h.respondPaths(w, source, target, format, paths, err)
+}
+
+func (h *APIHandlers) handleKShortestPaths(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	source, target, ok := pathEndpoints(w, query)
+	if !ok {
+		return
+	}
+	k := 1
+	if raw := query.Get("k"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxShortestPathsK {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("k must be between 1 and %d", repository.MaxShortestPathsK))
+			return
+		}
+		k = parsed
+	}
+	format, err := parseGraphFormat(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	paths, err := h.service.ShortestPathsBetweenUsers(r.Context(), source, target, k)
+	h.respondPaths(w, source, target, format, paths, err)
+}
+
+// pathEndpoints reads the sourceUserId and targetUserId of a path request, answering 400
+// and returning false when they are missing or equal.
+func pathEndpoints(w http.ResponseWriter, query url.Values) (string, string, bool) {
+	source := strings.TrimSpace(query.Get("sourceUserId"))
+	target := strings.TrimSpace(query.Get("targetUserId"))
+	switch {
+	case source == "" || target == "":
+		writeError(w, http.StatusBadRequest, "sourceUserId and targetUserId are required")
+		return "", "", false
+	case source == target:
+		writeError(w, http.StatusBadRequest, "sourceUserId and targetUserId must differ")
+		return "", "", false
+	}
+	return source, target, true
+}
+
+// respondPaths writes the paths found between source and target in format, or the error
+// finding them. The Cytoscape format merges the paths into one graph.
+func (h *APIHandlers) respondPaths(w http.ResponseWriter, source, target string, format graphFormat, paths []domain.ShortestPath, err error) {
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "source or target user not found")
+			return
+		}
+		h.logger.Error("failed to find paths", "error", err, "sourceUserId", source, "targetUserId", target)
+		writeServerError(w, err, "failed to find paths")
+		return
+	}
+
+	// Paths are kept whole and in order until the next one no longer fits the budget.
+	budget := h.newPathBudget()
+	if format == graphFormatCytoscape {
+		elements := newCytoscapeBuilder()
+		for _, path := range paths {
+			if !budget.fits(path.Nodes, path.Edges) {
+				break
+			}
+			elements.add(path.Nodes, path.Edges)
+		}
+		respondJSON(w, http.StatusOK, cytoscapePathsResponse{
+			SourceUserID: source,
+			TargetUserID: target,
+			Truncated:    budget.truncated,
+			Elements:     elements.elements,
+		})
+		return
+	}
+	resp := allPathsResponse{SourceUserID: source, TargetUserID: target, Paths: make([]pathResponse, 0, len(paths))}
+	for _, path := range paths {
+		if !budget.fits(path.Nodes, path.Edges) {
+			break
+		}
+		item := pathResponse{Hops: path.Hops()}
+		item.Nodes, item.Edges = newPathResponse(path.Nodes, path.Edges)
+		resp.Paths = append(resp.Paths, item)
+	}
+	resp.Truncated = budget.truncated
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleUserTransactionPath(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	userID := strings.TrimSpace(query.Get("userId"))
+	txID := strings.TrimSpace(query.Get("transactionId"))
+	if userID == "" || txID == "" {
+		writeError(w, http.StatusBadRequest, "userId and transactionId are required")
+		return
+	}
+	maxHops := 0
+	if raw := query.Get("maxHops"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxPathHops {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("maxHops must be between 1 and %d", repository.MaxPathHops))
+			return
+		}
+		maxHops = parsed
+	}
+
+	path, err := h.service.PathBetweenUserAndTransaction(r.Context(), userID, txID, maxHops)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user or transaction not found")
+			return
+		}
+		h.logger.Error("failed to compute user to transaction path", "error", err, "userId", userID, "transactionId", txID)
+		writeServerError(w, err, "failed to compute path")
+		return
+	}
+
+	resp := userTransactionPathResponse{
+		UserID:        userID,
+		TransactionID: txID,
+		Found:         path.Found(),
+		Hops:          path.Hops(),
+	}
+	budget := h.newPathBudget()
+	resp.Nodes, resp.Edges = newPathResponse(budget.cut(path.Nodes, path.Edges))
+	resp.Truncated = budget.truncated
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleNeighborhood(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	userID := strings.TrimSpace(query.Get("userId"))
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "userId is required")
+		return
+	}
+	depth := repository.DefaultNeighborhoodDepth
+	if raw := query.Get("depth"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxNeighborhoodDepth {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("depth must be between 1 and %d", repository.MaxNeighborhoodDepth))
+			return
+		}
+		depth = parsed
+	}
+	var props repository.PropertyProjection
+	var err error
+	if props.Node, err = parsePropertyList(query.Get("nodeProps"), "node", repository.NeighborhoodNodeProperties); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if props.Edge, err = parsePropertyList(query.Get("edgeProps"), "edge", repository.NeighborhoodEdgeProperties); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	format, err := parseGraphFormat(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	neighborhood, err := h.service.UserNeighborhood(r.Context(), userID, depth, props)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to expand neighborhood", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to expand neighborhood")
+		return
+	}
+
+	budget := h.newPathBudget()
+	nodes, edges := budget.cut(neighborhood.Nodes, neighborhood.Edges)
+	if format == graphFormatCytoscape {
+		elements := newCytoscapeBuilder()
+		elements.add(nodes, edges)
+		respondJSON(w, http.StatusOK, cytoscapeNeighborhoodResponse{
+			UserID:    userID,
+			Depth:     neighborhood.Depth,
+			Truncated: neighborhood.Truncated || budget.truncated,
+			Elements:  elements.elements,
+		})
+		return
+	}
+	resp := neighborhoodResponse{
+		UserID:    userID,
+		Depth:     neighborhood.Depth,
+		Truncated: neighborhood.Truncated || budget.truncated,
+	}
+	resp.Nodes, resp.Edges = newPathResponse(nodes, edges)
+	respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *APIHandlers) handleCycles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	maxLength := repository.DefaultCycleLength
+	if raw := query.Get("maxLength"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < repository.MinCycleLength || parsed > repository.MaxCycleLength {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("maxLength must be between %d and %d", repository.MinCycleLength, repository.MaxCycleLength))
+			return
+		}
+		maxLength = parsed
+	}
+	minAmountParam, err := parseFloatParam(query, "minAmount")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	var minAmount float64
+	if minAmountParam != nil {
+		if *minAmountParam < 0 {
+			writeError(w, http.StatusBadRequest, "minAmount must not be negative")
+			return
+		}
+		minAmount = *minAmountParam
+	}
+
+	cycles, err := h.service.DetectCycles(r.Context(), maxLength, minAmount)
+	if err != nil {
+		h.logger.Error("failed to detect cycles", "error", err, "maxLength", maxLength, "minAmount", minAmount)
+		writeServerError(w, err, "failed to detect cycles")
+		return
+	}
+
+	resp := cyclesResponse{
+		MaxLength: cycles.MaxLength,
+		MinAmount: cycles.MinAmount,
+		Cycles:    make([]cycleResponse, 0, len(cycles.Cycles)),
+	}
+	budget := h.newPathBudget()
+	for _, cycle := range cycles.Cycles {
+		if !budget.fits(cycle.Nodes, cycle.Edges) {
+			break
+		}
+		item := cycleResponse{Length: cycle.Length(), TotalAmount: cycle.TotalAmount}
+		item.Nodes, item.Edges = newPathResponse(cycle.Nodes, cycle.Edges)
+		resp.Cycles = append(resp.Cycles, item)
+	}
+	resp.Truncated = cycles.Truncated || budget.truncated
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// parsePropertyList splits a comma-separated list of property names, rejecting names
+// not in known.
+func parsePropertyList(raw, kind string, known []string) ([]string, error) {
+	var props []string
+	for _, key := range strings.Split(raw, ",") {
+		key = strings.TrimSpace(key)
+		if key == "" {
+			continue
+		}
+		if !slices.Contains(known, key) {
+			return nil, fmt.Errorf("unsupported %s property %q; expected one of %s", kind, key, strings.Join(known, ", "))
+		}
+		if !slices.Contains(props, key) {
+			props = append(props, key)
+		}
+	}
+	return props, nil
+}
+
+// pathErrorMessage turns a per-pair failure into a message safe to return to clients.
+func (h *APIHandlers) pathErrorMessage(result domain.ShortestPathResult) string {
+	switch {
+	case errors.Is(result.Err, repository.ErrNotFound):
+		return "source or target user not found"
+	case errors.Is(result.Err, context.DeadlineExceeded), errors.Is(result.Err, context.Canceled):
+		return "not computed before the request deadline"
+	default:
+		h.logger.Error("failed to compute shortest path", "error", result.Err,
+			"sourceUserId", result.SourceUserID, "targetUserId", result.TargetUserID)
+		return "failed to compute shortest path"
+	}
+}
+
+func newPathResponse(pathNodes []domain.PathNode, pathEdges []domain.PathEdge) ([]pathNodeResponse, []pathEdgeResponse) {
+	nodes := make([]pathNodeResponse, 0, len(pathNodes))
+	for _, node := range pathNodes {
+		nodes = append(nodes, pathNodeResponse{ID: node.ID, Label: node.Label, AttributeType: node.AttributeType, Properties: node.Properties})
+	}
+	edges := make([]pathEdgeResponse, 0, len(pathEdges))
+	for _, edge := range pathEdges {
+		edges = append(edges, pathEdgeResponse{Type: edge.Type, Source: edge.Source, Target: edge.Target, Weight: edge.Weight, Properties: edge.Properties})
+	}
+	return nodes, edges
+}
+
+type shortestPathsRequest struct {
+	Pairs []struct {
+		SourceUserID string `json:"sourceUserId"`
+		TargetUserID string `json:"targetUserId"`
+	} `json:"pairs"`
+	MaxHops        int    `json:"maxHops"`
+	WeightStrategy string `json:"weightStrategy"`
+}
+
+type shortestPathsResponse struct {
+	Items     []shortestPathItem `json:"items"`
+	Truncated bool               `json:"truncated"`
+}
+
+type shortestPathItem struct {
+	SourceUserID string             `json:"sourceUserId"`
+	TargetUserID string             `json:"targetUserId"`
+	Found        bool               `json:"found"`
+	Hops         int                `json:"hops"`
+	Cost         float64            `json:"cost"`
+	Nodes        []pathNodeResponse `json:"nodes"`
+	Edges        []pathEdgeResponse `json:"edges"`
+	Truncated    bool               `json:"truncated,omitempty"`
+	Error        string             `json:"error,omitempty"`
+}
+
+type allPathsResponse struct {
+	SourceUserID string         `json:"sourceUserId"`
+	TargetUserID string         `json:"targetUserId"`
+	Truncated    bool           `json:"truncated"`
+	Paths        []pathResponse `json:"paths"`
+}
+
+type pathResponse struct {
+	Hops  int                `json:"hops"`
+	Nodes []pathNodeResponse `json:"nodes"`
+	Edges []pathEdgeResponse `json:"edges"`
+}
+
+type userTransactionPathResponse struct {
+	UserID        string             `json:"userId"`
+	TransactionID string             `json:"transactionId"`
+	Found         bool               `json:"found"`
+	Hops          int                `json:"hops"`
+	Truncated     bool               `json:"truncated"`
+	Nodes         []pathNodeResponse `json:"nodes"`
+	Edges         []pathEdgeResponse `json:"edges"`
+}
+
+type neighborhoodResponse struct {
+	UserID    string             `json:"userId"`
+	Depth     int                `json:"depth"`
+	Truncated bool               `json:"truncated"`
+	Nodes     []pathNodeResponse `json:"nodes"`
+	Edges     []pathEdgeResponse `json:"edges"`
+}
+
+type cyclesResponse struct {
+	MaxLength int             `json:"maxLength"`
+	MinAmount float64         `json:"minAmount"`
+	Truncated bool            `json:"truncated"`
+	Cycles    []cycleResponse `json:"cycles"`
+}
+
+type cycleResponse struct {
+	Length      int                `json:"length"`
+	TotalAmount float64            `json:"totalAmount"`
+	Nodes       []pathNodeResponse `json:"nodes"`
+	Edges       []pathEdgeResponse `json:"edges"`
+}
+
+type pathNodeResponse struct {
+	ID            string         `json:"id"`
+	Label         string         `json:"label"`
+	AttributeType string         `json:"attributeType,omitempty"`
+	Properties    map[string]any `json:"properties,omitempty"`
+}
+
+type pathEdgeResponse struct {
+	Type       string         `json:"type"`
+	Source     string         `json:"source"`
+	Target     string         `json:"target"`
+	Weight     float64        `json:"weight"`
+	Properties map[string]any `json:"properties,omitempty"`
+}
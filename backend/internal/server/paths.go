@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// normalizePathMiddleware rewrites the request path to its canonical form before routing:
+// repeated slashes are collapsed and a trailing slash is dropped, so /users//U1/ and
+// /users/U1 reach the same handler with the same path. Paths containing "." or ".."
+// segments get a 404 instead of being resolved, since no route uses them.
+func normalizePathMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, ok := normalizePath(r.URL.Path)
+		if !ok {
+			writeError(w, http.StatusNotFound, "not found")
+			return
+		}
+		if path != r.URL.Path {
+			u := *r.URL
+			u.Path = path
+			u.RawPath = ""
+			r2 := *r
+			r2.URL = &u
+			r = &r2
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// normalizePath collapses repeated slashes and strips the trailing slash from path. It
+// reports false for paths with dot segments.
+func normalizePath(path string) (string, bool) {
+	segments := strings.Split(path, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		switch segment {
+		case "":
+			continue
+		case ".", "..":
+			return "", false
+		}
+		kept = append(kept, segment)
+	}
+	return "/" + strings.Join(kept, "/"), true
+}
+
+// handleCollection registers handler for both pattern and its subtree, so a collection
+// path without an ID reaches the handler rather than ServeMux's redirect to pattern/.
+func handleCollection(mux *http.ServeMux, pattern string, handler http.HandlerFunc) {
+	mux.HandleFunc(pattern, handler)
+	mux.HandleFunc(pattern+"/", handler)
+}
+
+// resourcePath splits a normalized path under prefix (given without a trailing slash)
+// into the resource ID and whatever follows it, e.g. "/users/U1/risk-explanation" under
+// "/users" into "U1" and "risk-explanation". Both are empty when the path has no ID.
+func resourcePath(path, prefix string) (string, string) {
+	rest := strings.TrimPrefix(strings.TrimPrefix(path, prefix), "/")
+	id, sub, _ := strings.Cut(rest, "/")
+	return id, sub
+}
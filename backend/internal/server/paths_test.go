@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizePath(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   string
+		wantOK bool
+	}{
+		{path: "/users/U1", want: "/users/U1", wantOK: true},
+		{path: "/users/U1/", want: "/users/U1", wantOK: true},
+		{path: "/users//U1", want: "/users/U1", wantOK: true},
+		{path: "//users///U1//risk-explanation/", want: "/users/U1/risk-explanation", wantOK: true},
+		{path: "/users", want: "/users", wantOK: true},
+		{path: "/users/", want: "/users", wantOK: true},
+		{path: "/", want: "/", wantOK: true},
+		{path: "", want: "/", wantOK: true},
+		{path: "/users/./U1", wantOK: false},
+		{path: "/users/../admin", wantOK: false},
+		{path: "/users/U1/..", wantOK: false},
+		{path: "/users/U1/.", wantOK: false},
+		{path: "/users/U1.json", want: "/users/U1.json", wantOK: true},
+		{path: "/users/..U1", want: "/users/..U1", wantOK: true},
+	}
+	for _, tt := range tests {
+		got, ok := normalizePath(tt.path)
+		if ok != tt.wantOK {
+			t.Errorf("normalizePath(%q) ok = %v, want %v", tt.path, ok, tt.wantOK)
+			continue
+		}
+		if ok && got != tt.want {
+			t.Errorf("normalizePath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	tests := []struct {
+		path    string
+		prefix  string
+		wantID  string
+		wantSub string
+	}{
+		{path: "/users/U1", prefix: "/users", wantID: "U1"},
+		{path: "/users/U1/risk-explanation", prefix: "/users", wantID: "U1", wantSub: "risk-explanation"},
+		{path: "/users/U1/attributes/extra", prefix: "/users", wantID: "U1", wantSub: "attributes/extra"},
+		{path: "/users", prefix: "/users"},
+		{path: "/export/jobs/J1/download", prefix: "/export/jobs", wantID: "J1", wantSub: "download"},
+		{path: "/export/jobs", prefix: "/export/jobs"},
+	}
+	for _, tt := range tests {
+		id, sub := resourcePath(tt.path, tt.prefix)
+		if id != tt.wantID || sub != tt.wantSub {
+			t.Errorf("resourcePath(%q, %q) = %q, %q, want %q, %q", tt.path, tt.prefix, id, sub, tt.wantID, tt.wantSub)
+		}
+	}
+}
+
+// The edge cases go through the middleware and a collection handler, as requests do, so
+// a missing ID, a trailing slash and repeated slashes reach the handler normalized and
+// dot segments never reach it.
+func TestNormalizePathMiddleware(t *testing.T) {
+	var gotID string
+	mux := http.NewServeMux()
+	handleCollection(mux, "/users", func(w http.ResponseWriter, r *http.Request) {
+		id, _ := resourcePath(r.URL.Path, "/users")
+		if id == "" {
+			writeError(w, http.StatusBadRequest, "user ID is required")
+			return
+		}
+		gotID = id
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := normalizePathMiddleware(mux)
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+		wantID     string
+	}{
+		{name: "plain", path: "/users/U1", wantStatus: http.StatusOK, wantID: "U1"},
+		{name: "trailing slash", path: "/users/U1/", wantStatus: http.StatusOK, wantID: "U1"},
+		{name: "double slash", path: "/users//U1", wantStatus: http.StatusOK, wantID: "U1"},
+		{name: "missing id", path: "/users", wantStatus: http.StatusBadRequest},
+		{name: "missing id with trailing slash", path: "/users/", wantStatus: http.StatusBadRequest},
+		{name: "dot segment", path: "/users/./U1", wantStatus: http.StatusNotFound},
+		{name: "dot dot segment", path: "/users/../users/U1", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotID = ""
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.URL.Path = tt.path
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("GET %s status = %d, want %d", tt.path, rec.Code, tt.wantStatus)
+			}
+			if gotID != tt.wantID {
+				t.Errorf("GET %s reached the handler with ID %q, want %q", tt.path, gotID, tt.wantID)
+			}
+		})
+	}
+}
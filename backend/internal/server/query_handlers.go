@@ -0,0 +1,91 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+)
+
+// WithQueryRegistry enables the /admin/queries endpoints, which list and cancel the
+// queries running through clients registered with queries.
+func (h *APIHandlers) WithQueryRegistry(queries *graph.QueryRegistry) *APIHandlers {
+	h.queries = queries
+	return h
+}
+
+func (h *APIHandlers) handleAdminQueries(w http.ResponseWriter, r *http.Request) {
+	if h.queries == nil {
+		writeError(w, http.StatusNotFound, "query registry is not enabled")
+		return
+	}
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	now := time.Now()
+	running := h.queries.List()
+	resp := runningQueriesResponse{Queries: make([]runningQueryResponse, 0, len(running))}
+	for _, q := range running {
+		resp.Queries = append(resp.Queries, runningQueryResponse{
+			ID:        q.ID,
+			Access:    q.Access,
+			Query:     q.Cypher,
+			StartedAt: formatTime(q.StartedAt),
+			ElapsedMs: now.Sub(q.StartedAt).Milliseconds(),
+		})
+	}
+	respondJSON(w, http.StatusOK, resp)
+}
+
+// handleAdminQuery serves DELETE /admin/queries/{id}.
+func (h *APIHandlers) handleAdminQuery(w http.ResponseWriter, r *http.Request) {
+	if h.queries == nil {
+		writeError(w, http.StatusNotFound, "query registry is not enabled")
+		return
+	}
+
+	queryID, resource := resourcePath(r.URL.Path, "/admin/queries")
+	switch {
+	case queryID == "":
+		writeError(w, http.StatusBadRequest, "query ID is required")
+		return
+	case resource != "":
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	case r.Method != http.MethodDelete:
+		methodNotAllowed(w, http.MethodDelete)
+		return
+	}
+
+	if err := h.queries.Cancel(queryID); err != nil {
+		if errors.Is(err, graph.ErrQueryNotFound) {
+			writeError(w, http.StatusNotFound, "query not running")
+			return
+		}
+		h.logger.Error("failed to cancel query", "error", err, "queryId", queryID)
+		writeServerError(w, err, "failed to cancel query")
+		return
+	}
+	h.logger.Warn("query cancelled by operator", "queryId", queryID)
+	respondJSON(w, http.StatusOK, cancelQueryResponse{ID: queryID, Status: "cancelled"})
+}
+
+type runningQueriesResponse struct {
+	Queries []runningQueryResponse `json:"queries"`
+}
+
+type runningQueryResponse struct {
+	ID        string `json:"id"`
+	Access    string `json:"access"`
+	Query     string `json:"query"`
+	StartedAt string `json:"startedAt"`
+	ElapsedMs int64  `json:"elapsedMs"`
+}
+
+type cancelQueryResponse struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestPIIRedactionByScope(t *testing.T) {
+	auth := NewAuthorizer(map[string][]string{
+		"reader-key": {string(ScopeRead)},
+		"pii-key":    {string(ScopeRead), string(ScopePII)},
+		"admin-key":  {string(ScopeAdmin)},
+	})
+	tests := []struct {
+		name    string
+		redact  bool
+		key     string
+		wantRaw bool
+	}{
+		{name: "redaction off", redact: false, key: "reader-key", wantRaw: true},
+		{name: "read scope only", redact: true, key: "reader-key", wantRaw: false},
+		{name: "pii scope", redact: true, key: "pii-key", wantRaw: true},
+		{name: "admin implies pii", redact: true, key: "admin-key", wantRaw: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{
+				userList: domain.UserListResult{Total: 1, Items: []domain.UserSummary{
+					{ID: "USR-1", Email: "jane.doe@example.com", Phone: "+1 555-010-1234"},
+				}},
+				user: domain.User{
+					ID:      "USR-1",
+					Email:   "jane.doe@example.com",
+					Phone:   "+1 555-010-1234",
+					Address: domain.Address{Line1: "1 Main St", City: "Springfield", Country: "US"},
+				},
+			}
+			handlers := newStubHandlers(repo).WithPIIRedaction(tt.redact)
+			wantEmail, wantPhone, wantLine1 := "j***@example.com", "+* ***-***-1234", "***"
+			if tt.wantRaw {
+				wantEmail, wantPhone, wantLine1 = "jane.doe@example.com", "+1 555-010-1234", "1 Main St"
+			}
+
+			var list struct {
+				Items []struct {
+					Email string `json:"email"`
+					Phone string `json:"phone"`
+				} `json:"items"`
+			}
+			getJSON(t, auth.Require(ScopeRead, handlers.handleUsers), "/users", tt.key, &list)
+			if len(list.Items) != 1 {
+				t.Fatalf("list returned %d items, want 1", len(list.Items))
+			}
+			if list.Items[0].Email != wantEmail || list.Items[0].Phone != wantPhone {
+				t.Errorf("list email, phone = %q, %q, want %q, %q", list.Items[0].Email, list.Items[0].Phone, wantEmail, wantPhone)
+			}
+
+			var detail struct {
+				Email   string `json:"email"`
+				Phone   string `json:"phone"`
+				Address struct {
+					Line1   string `json:"line1"`
+					Country string `json:"country"`
+				} `json:"address"`
+			}
+			getJSON(t, auth.RequireByMethod(handlers.handleUserResource), "/users/USR-1", tt.key, &detail)
+			if detail.Email != wantEmail || detail.Phone != wantPhone || detail.Address.Line1 != wantLine1 {
+				t.Errorf("detail email, phone, line1 = %q, %q, %q, want %q, %q, %q", detail.Email, detail.Phone, detail.Address.Line1, wantEmail, wantPhone, wantLine1)
+			}
+			if detail.Address.Country != "US" {
+				t.Errorf("detail country = %q, want it kept unmasked", detail.Address.Country)
+			}
+		})
+	}
+}
+
+// getJSON sends a GET for target with key to handler and decodes the 200 response into v.
+func getJSON(t *testing.T, handler http.HandlerFunc, target, key string, v any) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set("X-API-Key", key)
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s status = %d, want 200: %s", target, rec.Code, rec.Body)
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %s: %v", target, err)
+	}
+}
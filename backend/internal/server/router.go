@@ -13,8 +13,15 @@ import (
 type RouterDependencies struct {
 	Health           HealthService
 	API              *APIHandlers
+	Auth             *Authorizer
+	Readiness        *Readiness
 	AllowedOrigins   []string
 	AllowCredentials bool
+	// MetricsEnabled serves request metrics on /metrics, labelled by route template.
+	MetricsEnabled bool
+	// Cache sets the Cache-Control max-age of low-volatility endpoints; every other
+	// response is sent with no-store.
+	Cache CachePolicy
 }
 
 // NewRouter wires the HTTP routes exposed by the backend API.
@@ -30,6 +37,11 @@ func NewRouter(logger *slog.Logger, deps RouterDependencies) http.Handler {
 			"status": "ok",
 		}
 
+		if deps.Readiness.Draining() {
+			respondJSON(w, http.StatusServiceUnavailable, map[string]any{"status": "draining"})
+			return
+		}
+
 		if deps.Health != nil {
 			if err := deps.Health.Probe(ctx); err != nil {
 				logger.Error("health probe failed", "error", err)
@@ -41,21 +53,110 @@ func NewRouter(logger *slog.Logger, deps RouterDependencies) http.Handler {
 
 		respondJSON(w, status, payload)
 	})
+	mux.HandleFunc("/readyz", readyzHandler(logger, deps.Health, deps.Readiness))
 
 	if deps.API != nil {
-		mux.HandleFunc("/users", deps.API.handleUsers)
-		mux.HandleFunc("/transactions", deps.API.handleTransactions)
-		mux.HandleFunc("/relationships/user/", deps.API.handleUserRelationships)
-		mux.HandleFunc("/relationships/transaction/", deps.API.handleTransactionRelationships)
+		auth := deps.Auth
+		mux.HandleFunc("/users", auth.RequireByMethod(explainable(deps.API.handleUsers)))
+		mux.HandleFunc("/users/", auth.RequireByMethod(deps.API.handleUserResource))
+		mux.HandleFunc("/transactions", auth.RequireByMethod(explainable(deps.API.handleTransactions)))
+		mux.HandleFunc("/transactions/", auth.RequireByMethod(deps.API.handleTransactionResource))
+		// Lookups only read, so they need ScopeRead despite being POSTs.
+		mux.HandleFunc("/users/lookup", auth.Require(ScopeRead, deps.API.handleUserLookup))
+		mux.HandleFunc("/transactions/lookup", auth.Require(ScopeRead, deps.API.handleTransactionLookup))
+		handleCollection(mux, "/relationships/user", auth.Require(ScopeRead, deps.API.handleUserRelationships))
+		handleCollection(mux, "/relationships/transaction", auth.Require(ScopeRead, deps.API.handleTransactionRelationships))
+		mux.HandleFunc("/stats", auth.Require(ScopeRead, deps.API.handleStats))
+		mux.HandleFunc("/admin/prune", auth.Require(ScopeAdmin, deps.API.handleAdminPrune))
+		mux.HandleFunc("/admin/queries", auth.Require(ScopeAdmin, deps.API.handleAdminQueries))
+		mux.HandleFunc("/admin/queries/", auth.Require(ScopeAdmin, deps.API.handleAdminQuery))
+		mux.HandleFunc("/analytics/compare", auth.Require(ScopeRead, explainable(deps.API.handleCompareUsers)))
+		mux.HandleFunc("/analytics/onboarding-velocity", auth.Require(ScopeRead, explainable(deps.API.handleOnboardingVelocity)))
+		mux.HandleFunc("/analytics/isolated-users", auth.Require(ScopeRead, explainable(deps.API.handleIsolatedUsers)))
+		mux.HandleFunc("/analytics/shared-payment", auth.Require(ScopeRead, explainable(deps.API.handleSharedPayment)))
+		mux.HandleFunc("/analytics/shared-payment-methods", auth.Require(ScopeRead, explainable(deps.API.handleSharedPaymentMethods)))
+		mux.HandleFunc("/analytics/bank-transactions", auth.Require(ScopeRead, explainable(deps.API.handleBankTransactions)))
+		mux.HandleFunc("/analytics/shortest-paths", auth.Require(ScopeRead, explainable(deps.API.handleShortestPathsBatch)))
+		mux.HandleFunc("/analytics/shortest-path", auth.Require(ScopeRead, explainable(deps.API.handleKShortestPaths)))
+		mux.HandleFunc("/analytics/paths", auth.Require(ScopeRead, explainable(deps.API.handleAllPaths)))
+		mux.HandleFunc("/analytics/user-tx-path", auth.Require(ScopeRead, explainable(deps.API.handleUserTransactionPath)))
+		mux.HandleFunc("/analytics/neighborhood", auth.Require(ScopeRead, explainable(deps.API.handleNeighborhood)))
+		mux.HandleFunc("/analytics/cycles", auth.Require(ScopeRead, explainable(deps.API.handleCycles)))
+		mux.HandleFunc("/analytics/components", auth.Require(ScopeRead, explainable(deps.API.handleComponents)))
+		mux.HandleFunc("/analytics/cluster-profile", auth.Require(ScopeRead, explainable(deps.API.handleClusterProfile)))
+		mux.HandleFunc("/analytics/attribute-clusters", auth.Require(ScopeRead, explainable(deps.API.handleAttributeClusters)))
+		mux.HandleFunc("/analytics/correlated-users", auth.Require(ScopeRead, explainable(deps.API.handleCorrelatedUsers)))
+		mux.HandleFunc("/analytics/centrality", auth.Require(ScopeRead, explainable(deps.API.handleCentrality)))
+		mux.HandleFunc("/analytics/hubs", auth.Require(ScopeRead, explainable(deps.API.handleHubs)))
+		mux.HandleFunc("/export/edges", auth.Require(ScopeExport, deps.API.handleExportEdges))
+		mux.HandleFunc("/export/nodes", auth.Require(ScopeExport, deps.API.handleExportNodes))
+		mux.HandleFunc("/export/jobs", auth.Require(ScopeExport, deps.API.handleExportJobs))
+		mux.HandleFunc("/export/jobs/", auth.Require(ScopeExport, deps.API.handleExportJob))
 	}
 
-	handler := http.Handler(loggingMiddleware(logger, mux))
+	handler := normalizePathMiddleware(consistencyMiddleware(versionMiddleware(cacheControlMiddleware(deps.Cache, mux))))
+	if deps.MetricsEnabled {
+		metrics := newRequestMetrics()
+		mux.Handle("/metrics", metrics)
+		handler = metrics.middleware(handler)
+	}
+	handler = loggingMiddleware(logger, handler)
 	if len(deps.AllowedOrigins) > 0 {
 		handler = corsMiddleware(deps.AllowedOrigins, deps.AllowCredentials)(handler)
 	}
 	return handler
 }
 
+// readyzHandler reports readiness like /healthz, with the result and latency of every
+// probe step when health supports HealthReporter.
+func readyzHandler(logger *slog.Logger, health HealthService, readiness *Readiness) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if readiness.Draining() {
+			respondJSON(w, http.StatusServiceUnavailable, readyzResponse{Status: "draining", Checks: []readyzCheck{}})
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+		defer cancel()
+
+		var checks []HealthCheck
+		switch h := health.(type) {
+		case nil:
+		case HealthReporter:
+			checks = h.Checks(ctx)
+		default:
+			checks = []HealthCheck{timeCheck("probe", func() error { return h.Probe(ctx) })}
+		}
+
+		status := http.StatusOK
+		response := readyzResponse{Status: "ok", Checks: make([]readyzCheck, 0, len(checks))}
+		for _, check := range checks {
+			entry := readyzCheck{Name: check.Name, Status: "ok", LatencyMs: float64(check.Duration.Microseconds()) / 1000}
+			if check.Err != nil {
+				logger.Error("readiness check failed", "check", check.Name, "error", check.Err)
+				entry.Status = "failed"
+				entry.Error = check.Err.Error()
+				status = http.StatusServiceUnavailable
+				response.Status = "degraded"
+			}
+			response.Checks = append(response.Checks, entry)
+		}
+		respondJSON(w, status, response)
+	}
+}
+
+type readyzResponse struct {
+	Status string        `json:"status"`
+	Checks []readyzCheck `json:"checks"`
+}
+
+type readyzCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
 func loggingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -117,7 +218,8 @@ func corsMiddleware(allowedOrigins []string, allowCredentials bool) func(http.Ha
 			if allowCredentials {
 				w.Header().Set("Access-Control-Allow-Credentials", "true")
 			}
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-API-Key, X-API-Version, X-Graph-Bookmark")
+			w.Header().Set("Access-Control-Expose-Headers", "X-API-Version, X-Graph-Bookmark")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
 
 			if r.Method == http.MethodOptions {
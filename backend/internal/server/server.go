@@ -2,11 +2,17 @@ package server
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net"
 	"net/http"
+	"os"
 	"time"
 
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
 	"github.com/vanshika/fintrace/backend/internal/config"
 )
 
@@ -19,7 +25,14 @@ type Server struct {
 
 // New constructs a Server instance using the provided router.
 func New(logger *slog.Logger, cfg config.HTTPConfig, handler http.Handler) *Server {
-	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	addr := cfg.ListenAddress
+	if addr == "" {
+		addr = fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	}
+	if cfg.H2CEnabled && cfg.TLSCertFile == "" {
+		// TLS listeners negotiate HTTP/2 via ALPN; cleartext needs the h2c upgrade handler.
+		handler = h2c.NewHandler(handler, &http2.Server{})
+	}
 	httpServer := &http.Server{
 		Addr:              addr,
 		Handler:           handler,
@@ -38,16 +51,91 @@ func New(logger *slog.Logger, cfg config.HTTPConfig, handler http.Handler) *Serv
 
 // Start begins listening for HTTP traffic.
 func (s *Server) Start() error {
-	s.logger.Info("starting http server", "addr", s.httpServer.Addr)
-	err := s.httpServer.ListenAndServe()
+	listener, err := s.listen()
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info("starting http server",
+		"network", s.network(),
+		"addr", s.httpServer.Addr,
+		"tls", s.cfg.TLSCertFile != "",
+		"h2c", s.cfg.H2CEnabled && s.cfg.TLSCertFile == "",
+	)
+	if s.cfg.TLSCertFile != "" {
+		err = s.httpServer.ServeTLS(listener, s.cfg.TLSCertFile, s.cfg.TLSKeyFile)
+	} else {
+		err = s.httpServer.Serve(listener)
+	}
 	if err != nil && err != http.ErrServerClosed {
 		return err
 	}
 	return nil
 }
 
+// Drain marks readiness as draining and waits for the configured DrainDelay so load
+// balancers stop sending traffic before Shutdown closes the listener. It returns early
+// when ctx is cancelled.
+func (s *Server) Drain(ctx context.Context, readiness *Readiness) {
+	if s.cfg.DrainDelay <= 0 {
+		return
+	}
+	readiness.StartDraining()
+	s.logger.Info("draining http server; reporting not ready", "delay", s.cfg.DrainDelay.String())
+
+	timer := time.NewTimer(s.cfg.DrainDelay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		s.logger.Info("drain delay elapsed")
+	case <-ctx.Done():
+		s.logger.Warn("drain interrupted", "error", ctx.Err())
+	}
+}
+
 // Shutdown gracefully terminates all active connections.
 func (s *Server) Shutdown(ctx context.Context) error {
 	s.logger.Info("shutting down http server")
 	return s.httpServer.Shutdown(ctx)
 }
+
+func (s *Server) network() string {
+	if s.cfg.ListenNetwork == "" {
+		return "tcp"
+	}
+	return s.cfg.ListenNetwork
+}
+
+func (s *Server) listen() (net.Listener, error) {
+	network := s.network()
+	if network == "unix" {
+		if err := removeStaleSocket(s.httpServer.Addr); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen(network, s.httpServer.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("listen on %s %s: %w", network, s.httpServer.Addr, err)
+	}
+	return listener, nil
+}
+
+// removeStaleSocket removes a socket file left behind by an unclean exit, which would
+// make Listen fail. Anything else at path is left alone and reported, so a mistyped
+// listen address cannot delete a regular file.
+func removeStaleSocket(path string) error {
+	info, err := os.Lstat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("stat listen socket %s: %w", path, err)
+	}
+	if info.Mode()&os.ModeSocket == 0 {
+		return fmt.Errorf("listen socket %s: file exists and is not a socket", path)
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("remove stale socket %s: %w", path, err)
+	}
+	return nil
+}
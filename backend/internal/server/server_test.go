@@ -0,0 +1,142 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+
+	"github.com/vanshika/fintrace/backend/internal/config"
+)
+
+// startUnixServer serves handler on a unix socket under t.TempDir and returns the
+// socket path. The server is shut down when the test ends.
+func startUnixServer(t *testing.T, cfg config.HTTPConfig, handler http.Handler) string {
+	t.Helper()
+	socket := filepath.Join(t.TempDir(), "api.sock")
+	cfg.ListenNetwork = "unix"
+	cfg.ListenAddress = socket
+	srv := New(slog.New(slog.NewTextHandler(io.Discard, nil)), cfg, handler)
+
+	errs := make(chan error, 1)
+	go func() { errs <- srv.Start() }()
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			t.Errorf("Shutdown() error = %v", err)
+		}
+		if err := <-errs; err != nil {
+			t.Errorf("Start() error = %v", err)
+		}
+	})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if info, err := os.Lstat(socket); err == nil && info.Mode()&os.ModeSocket != 0 {
+			return socket
+		}
+		select {
+		case err := <-errs:
+			t.Fatalf("Start() error = %v", err)
+		default:
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server did not create its socket")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func dialUnix(socket string) func(context.Context, string, string) (net.Conn, error) {
+	return func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", socket)
+	}
+}
+
+func TestServerServesOnUnixSocket(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]string{"status": "ok", "proto": r.Proto})
+	})
+	socket := startUnixServer(t, config.HTTPConfig{}, handler)
+
+	client := &http.Client{Transport: &http.Transport{DialContext: dialUnix(socket)}}
+	resp, err := client.Get("http://fintrace/healthz")
+	if err != nil {
+		t.Fatalf("GET over unix socket: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), `"status":"ok"`) {
+		t.Errorf("response = %d %s, want 200 with status ok", resp.StatusCode, body)
+	}
+}
+
+func TestServerServesH2COnUnixSocket(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]string{"proto": r.Proto})
+	})
+	socket := startUnixServer(t, config.HTTPConfig{H2CEnabled: true}, handler)
+
+	// Prior-knowledge h2c: HTTP/2 frames over the plain socket.
+	transport := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dialUnix(socket)(ctx, network, addr)
+		},
+	}
+	defer transport.CloseIdleConnections()
+	resp, err := (&http.Client{Transport: transport}).Get("http://fintrace/healthz")
+	if err != nil {
+		t.Fatalf("h2c GET over unix socket: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.ProtoMajor != 2 {
+		t.Errorf("response protocol = %s, want HTTP/2", resp.Proto)
+	}
+}
+
+func TestRemoveStaleSocket(t *testing.T) {
+	dir := t.TempDir()
+
+	if err := removeStaleSocket(filepath.Join(dir, "missing.sock")); err != nil {
+		t.Errorf("missing socket error = %v, want nil", err)
+	}
+
+	// A socket left behind by an unclean exit is removed.
+	stale := filepath.Join(dir, "stale.sock")
+	listener, err := net.Listen("unix", stale)
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	listener.(*net.UnixListener).SetUnlinkOnClose(false)
+	listener.Close()
+	if err := removeStaleSocket(stale); err != nil {
+		t.Fatalf("stale socket error = %v", err)
+	}
+	if _, err := os.Lstat(stale); !os.IsNotExist(err) {
+		t.Errorf("stale socket still exists: %v", err)
+	}
+
+	// A regular file at the listen address is refused and kept.
+	file := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(file, []byte("keep me"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := removeStaleSocket(file); err == nil || !strings.Contains(err.Error(), "not a socket") {
+		t.Errorf("regular file error = %v, want a not a socket error", err)
+	}
+	if data, err := os.ReadFile(file); err != nil || string(data) != "keep me" {
+		t.Errorf("regular file was changed: %q, %v", data, err)
+	}
+}
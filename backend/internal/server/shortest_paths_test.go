@@ -0,0 +1,44 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestKShortestPathsOrderedByHops(t *testing.T) {
+	repo := &stubRepository{paths: []domain.ShortestPath{
+		{
+			Nodes: []domain.PathNode{{ID: "u1", Label: "User"}, {ID: "h1", Label: "Attribute"}, {ID: "u2", Label: "User"}},
+			Edges: []domain.PathEdge{
+				{Type: "HAS_ATTRIBUTE", Source: "u1", Target: "h1"},
+				{Type: "HAS_ATTRIBUTE", Source: "u2", Target: "h1"},
+			},
+		},
+		{
+			Nodes: []domain.PathNode{{ID: "u1", Label: "User"}, {ID: "u2", Label: "User"}},
+			Edges: []domain.PathEdge{{Type: "LINKED_TO", Source: "u1", Target: "u2"}},
+		},
+	}}
+	rec := serve(t, newStubHandlers(repo).handleKShortestPaths, http.MethodGet, "/analytics/shortest-path?sourceUserId=u1&targetUserId=u2&k=3", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Paths []struct {
+			Hops int `json:"hops"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	// k=3 but only two paths exist, so both come back, the shorter one first.
+	if len(body.Paths) != 2 {
+		t.Fatalf("got %d paths, want 2: %s", len(body.Paths), rec.Body)
+	}
+	if body.Paths[0].Hops != 1 || body.Paths[1].Hops != 2 {
+		t.Errorf("hops = %d, %d, want 1, 2", body.Paths[0].Hops, body.Paths[1].Hops)
+	}
+}
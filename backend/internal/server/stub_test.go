@@ -0,0 +1,71 @@
+package server
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+	"github.com/vanshika/fintrace/backend/internal/service"
+)
+
+// stubRepository answers the repository calls a handler test needs with canned results.
+// Calls it does not override panic through the nil embedded interface.
+type stubRepository struct {
+	service.GraphRepository
+	paths        []domain.ShortestPath
+	neighborhood domain.Neighborhood
+	users        []domain.User
+	listUsers    []repository.ListUsersOptions
+	userList     domain.UserListResult
+	user         domain.User
+	transaction  domain.Transaction
+	err          error
+}
+
+func (s *stubRepository) GetTransaction(context.Context, string) (domain.Transaction, error) {
+	return s.transaction, s.err
+}
+
+func (s *stubRepository) GetUser(context.Context, string) (domain.User, error) {
+	return s.user, s.err
+}
+
+func (s *stubRepository) ListUsers(_ context.Context, opts repository.ListUsersOptions) (domain.UserListResult, error) {
+	s.listUsers = append(s.listUsers, opts)
+	return s.userList, s.err
+}
+
+func (s *stubRepository) UpsertUser(_ context.Context, user domain.User) error {
+	s.users = append(s.users, user)
+	return s.err
+}
+
+func (s *stubRepository) ShortestPathsBetweenUsers(context.Context, string, string, int) ([]domain.ShortestPath, error) {
+	return s.paths, s.err
+}
+
+func (s *stubRepository) AllPathsBetweenUsers(context.Context, string, string, int, int) ([]domain.ShortestPath, error) {
+	return s.paths, s.err
+}
+
+func (s *stubRepository) ExpandUserNeighborhood(context.Context, string, int, repository.PropertyProjection) (domain.Neighborhood, error) {
+	return s.neighborhood, s.err
+}
+
+// newStubHandlers builds handlers over repo that log nowhere.
+func newStubHandlers(repo service.GraphRepository) *APIHandlers {
+	return NewAPIHandlers(slog.New(slog.NewTextHandler(io.Discard, nil)), service.NewRelationshipService(repo, nil))
+}
+
+// serve runs one request through handler and returns the recorded response.
+func serve(t *testing.T, handler http.HandlerFunc, method, target string, body io.Reader) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(method, target, body))
+	return rec
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestListUsersOmitsMissingTimestamps(t *testing.T) {
+	created := time.Date(2024, 3, 1, 9, 30, 0, 0, time.UTC)
+	repo := &stubRepository{userList: domain.UserListResult{Total: 2, Items: []domain.UserSummary{
+		{ID: "USR-1"},
+		{ID: "USR-2", CreatedAt: created},
+	}}}
+	rec := serve(t, newStubHandlers(repo).handleUsers, http.MethodGet, "/users", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Items []map[string]any `json:"items"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(body.Items))
+	}
+	assertAbsent(t, body.Items[0], "createdAt", "updatedAt")
+	if got := body.Items[1]["createdAt"]; got != "2024-03-01T09:30:00Z" {
+		t.Errorf("createdAt = %v, want 2024-03-01T09:30:00Z", got)
+	}
+	assertAbsent(t, body.Items[1], "updatedAt")
+}
+
+func TestTransactionRelationshipsOmitMissingTimestamps(t *testing.T) {
+	repo := &relationshipsRepository{rel: domain.TransactionRelationships{
+		LinkedTransactions: []domain.LinkedTransaction{{TransactionID: "tx-2", LinkType: "IP_ADDRESS"}},
+		ReversalChain:      []domain.ReversalLink{{TransactionID: "tx-3", ReversesTransactionID: "tx-1"}},
+	}}
+	rec := serve(t, newStubHandlers(repo).handleTransactionRelationships, http.MethodGet, "/relationships/transaction/tx-1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		LinkedTransactions []map[string]any `json:"linkedTransactions"`
+		ReversalChain      []map[string]any `json:"reversalChain"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body.LinkedTransactions) != 1 || len(body.ReversalChain) != 1 {
+		t.Fatalf("body = %s, want one linked transaction and one reversal", rec.Body)
+	}
+	assertAbsent(t, body.LinkedTransactions[0], "updatedAt")
+	assertAbsent(t, body.ReversalChain[0], "timestamp")
+}
+
+// assertAbsent fails when object has any of keys, in particular as an empty string.
+func assertAbsent(t *testing.T, object map[string]any, keys ...string) {
+	t.Helper()
+	for _, key := range keys {
+		if value, ok := object[key]; ok {
+			t.Errorf("%s = %q, want it omitted", key, value)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestListTransactionsCurrencyReachesRead(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{query: "", want: ""},
+		{query: "currency=EUR", want: "EUR"},
+		{query: "currency=%20usd%20", want: "USD"},
+	}
+	for _, tt := range tests {
+		client := &recordingClient{}
+		handlers := newStubHandlers(repository.New(client))
+		rec := serve(t, handlers.handleTransactions, http.MethodGet, "/transactions?"+tt.query, nil)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("%q: status = %d, want 200: %s", tt.query, rec.Code, rec.Body)
+		}
+		if len(client.reads) == 0 {
+			t.Fatalf("%q: no read reached the graph", tt.query)
+		}
+		// The page and the count read the same filters.
+		for i, params := range client.reads {
+			if got := params["currency"]; got != tt.want {
+				t.Errorf("%q: read %d currency = %q, want %q", tt.query, i, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestListTransactionsDeviceAndIPReachRead(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantDevice string
+		wantIP     string
+		wantLinked bool
+	}{
+		{query: "deviceId=dev-1", wantStatus: http.StatusOK, wantDevice: "dev-1"},
+		{query: "ipAddress=203.0.113.7", wantStatus: http.StatusOK, wantIP: "203.0.113.7"},
+		{query: "ipAddress=203.0.113.7&includeLinked=true", wantStatus: http.StatusOK, wantIP: "203.0.113.7", wantLinked: true},
+		{query: "deviceId=dev-1&includeLinked=maybe", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		client := &recordingClient{}
+		rec := serve(t, newStubHandlers(repository.New(client)).handleTransactions, http.MethodGet, "/transactions?"+tt.query, nil)
+		if rec.Code != tt.wantStatus {
+			t.Fatalf("%q: status = %d, want %d: %s", tt.query, rec.Code, tt.wantStatus, rec.Body)
+		}
+		if tt.wantStatus != http.StatusOK {
+			continue
+		}
+		params := client.reads[0]
+		if params["deviceId"] != tt.wantDevice || params["ipAddress"] != tt.wantIP || params["includeLinked"] != tt.wantLinked {
+			t.Errorf("%q: deviceId, ipAddress, includeLinked = %v, %v, %v", tt.query, params["deviceId"], params["ipAddress"], params["includeLinked"])
+		}
+	}
+}
+
+func TestListTransactionsParticipantRiskParams(t *testing.T) {
+	client := &recordingClient{}
+	target := "/transactions?senderRiskMin=0.8&receiverRiskMin=0.5&anyParticipantRiskMin=0.9"
+	rec := serve(t, newStubHandlers(repository.New(client)).handleTransactions, http.MethodGet, target, nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	params := client.reads[0]
+	if params["senderRiskMin"] != 0.8 || params["receiverRiskMin"] != 0.5 || params["anyParticipantRiskMin"] != 0.9 {
+		t.Errorf("risk params = %v, %v, %v", params["senderRiskMin"], params["receiverRiskMin"], params["anyParticipantRiskMin"])
+	}
+
+	rec = serve(t, newStubHandlers(repository.New(&recordingClient{})).handleTransactions, http.MethodGet, "/transactions?senderRiskMin=high", nil)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid senderRiskMin: status = %d, want 400", rec.Code)
+	}
+}
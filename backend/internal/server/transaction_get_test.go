@@ -0,0 +1,48 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestGetTransactionMetadataJSON(t *testing.T) {
+	metadata := map[string]any{
+		"merchant": "ACME Ltd",
+		"mcc":      float64(5411),
+		"tags":     []any{"card-present"},
+		"terminal": map[string]any{"id": "T-9"},
+	}
+	repo := &stubRepository{transaction: domain.Transaction{ID: "TX-1", SenderUserID: "USR-1", ReceiverUserID: "USR-2", Metadata: metadata}}
+	rec := serve(t, newStubHandlers(repo).handleTransactionResource, http.MethodGet, "/transactions/TX-1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		SenderUserID   string         `json:"senderUserId"`
+		ReceiverUserID string         `json:"receiverUserId"`
+		Metadata       map[string]any `json:"metadata"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(body.Metadata, metadata) {
+		t.Errorf("metadata = %#v, want %#v", body.Metadata, metadata)
+	}
+	if body.SenderUserID != "USR-1" || body.ReceiverUserID != "USR-2" {
+		t.Errorf("participants = %s -> %s, want USR-1 -> USR-2", body.SenderUserID, body.ReceiverUserID)
+	}
+}
+
+func TestGetTransactionNotFound(t *testing.T) {
+	repo := &stubRepository{err: fmt.Errorf("transaction TX-9: %w", repository.ErrNotFound)}
+	rec := serve(t, newStubHandlers(repo).handleTransactionResource, http.MethodGet, "/transactions/TX-9", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,136 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// handleTransactionResource dispatches /transactions/{id}/{resource} requests.
+func (h *APIHandlers) handleTransactionResource(w http.ResponseWriter, r *http.Request) {
+	txID, resource := resourcePath(r.URL.Path, "/transactions")
+	if txID == "" {
+		writeError(w, http.StatusBadRequest, "transaction ID is required")
+		return
+	}
+
+	switch resource {
+	case "":
+		h.handleTransaction(w, r, txID)
+	case "recompute-links":
+		h.handleRecomputeTransactionLinks(w, r, txID)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *APIHandlers) handleRecomputeTransactionLinks(w http.ResponseWriter, r *http.Request, txID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	links, err := h.service.RecomputeTransactionLinks(r.Context(), txID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "transaction not found")
+			return
+		}
+		h.logger.Error("failed to recompute transaction links", "error", err, "transactionId", txID)
+		writeServerError(w, err, "failed to recompute transaction links")
+		return
+	}
+
+	response := recomputeLinksResponse{TransactionID: txID, LinkedTransactions: []linkedTransaction{}}
+	for _, link := range links {
+		response.LinkedTransactions = append(response.LinkedTransactions, linkedTransaction{
+			TransactionID: link.TransactionID,
+			LinkType:      link.LinkType,
+			AttributeHash: link.AttributeHash,
+			Score:         link.Score,
+			DecayedScore:  link.DecayedScore,
+			UpdatedAt:     formatTimePtr(link.LastUpdated),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+type recomputeLinksResponse struct {
+	TransactionID      string              `json:"transactionId"`
+	LinkedTransactions []linkedTransaction `json:"linkedTransactions"`
+}
+
+type deleteTransactionResponse struct {
+	TransactionID string `json:"transactionId"`
+	// RemovedLinks counts LINKED_TO edges removed in either direction.
+	RemovedLinks int `json:"removedLinks"`
+}
+
+// handleTransaction serves GET and DELETE on /transactions/{id}.
+func (h *APIHandlers) handleTransaction(w http.ResponseWriter, r *http.Request, txID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getTransaction(w, r, txID)
+	case http.MethodDelete:
+		h.deleteTransaction(w, r, txID)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+// getTransaction returns the stored transaction in the shape POST /transactions
+// accepts, with metadata decoded back into an object.
+func (h *APIHandlers) getTransaction(w http.ResponseWriter, r *http.Request, txID string) {
+	tx, err := h.service.GetTransaction(r.Context(), txID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "transaction not found")
+			return
+		}
+		h.logger.Error("failed to get transaction", "error", err, "transactionId", txID)
+		writeServerError(w, err, "failed to get transaction")
+		return
+	}
+
+	metadata := tx.Metadata
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	respondJSON(w, http.StatusOK, transactionRequest{
+		TransactionID:           tx.ID,
+		SenderUserID:            tx.SenderUserID,
+		ReceiverUserID:          tx.ReceiverUserID,
+		Amount:                  tx.Amount,
+		Currency:                tx.Currency,
+		Type:                    tx.Type,
+		Status:                  tx.Status,
+		Channel:                 tx.Channel,
+		IPAddress:               tx.IPAddress,
+		DeviceID:                tx.DeviceID,
+		PaymentMethodID:         tx.PaymentMethodID,
+		OriginatorBIC:           tx.OriginatorBIC,
+		BeneficiaryBIC:          tx.BeneficiaryBIC,
+		Timestamp:               formatTime(tx.Timestamp),
+		Metadata:                metadata,
+		CreatedAt:               formatTime(tx.CreatedAt),
+		UpdatedAt:               formatTime(tx.UpdatedAt),
+		ReversalOfTransactionID: tx.ReversalOf,
+	})
+}
+
+func (h *APIHandlers) deleteTransaction(w http.ResponseWriter, r *http.Request, txID string) {
+	removed, err := h.service.DeleteTransaction(r.Context(), txID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "transaction not found")
+			return
+		}
+		h.logger.Error("failed to delete transaction", "error", err, "transactionId", txID)
+		writeServerError(w, err, "failed to delete transaction")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, deleteTransactionResponse{TransactionID: txID, RemovedLinks: removed})
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// relationshipsRepository answers FetchTransactionRelationships with rel, or err when set.
+type relationshipsRepository struct {
+	stubRepository
+	rel domain.TransactionRelationships
+}
+
+func (s *relationshipsRepository) FetchTransactionRelationships(_ context.Context, txID string, _ repository.TransactionRelationshipOptions) (domain.TransactionRelationships, error) {
+	if s.err != nil {
+		return domain.TransactionRelationships{}, s.err
+	}
+	rel := s.rel
+	rel.TransactionID = txID
+	return rel, nil
+}
+
+func TestTransactionRelationshipsNotFound(t *testing.T) {
+	repo := &relationshipsRepository{stubRepository: stubRepository{err: fmt.Errorf("transaction tx-missing: %w", repository.ErrNotFound)}}
+	rec := serve(t, newStubHandlers(repo).handleTransactionRelationships, http.MethodGet, "/relationships/transaction/tx-missing", nil)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want 404: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestTransactionRelationshipsFoundWithoutLinks(t *testing.T) {
+	rec := serve(t, newStubHandlers(&relationshipsRepository{}).handleTransactionRelationships, http.MethodGet, "/relationships/transaction/tx-1", nil)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	// Empty sections are arrays, not null, so clients can iterate them unconditionally.
+	for _, section := range []string{"users", "linkedTransactions", "reversalChain"} {
+		if got := string(body[section]); got != "[]" {
+			t.Errorf("%s = %s, want []", section, got)
+		}
+	}
+}
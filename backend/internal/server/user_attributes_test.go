@@ -0,0 +1,56 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// attributeRepository links attributes to users in memory, counting those not linked
+// before as AddUserAttributes does.
+type attributeRepository struct {
+	stubRepository
+	linked map[string]bool
+}
+
+func (s *attributeRepository) AddUserAttributes(_ context.Context, userID string, attrs []domain.Attribute) (int, error) {
+	added := 0
+	for _, attr := range attrs {
+		key := userID + "|" + attr.Type + "|" + attr.Value
+		if !s.linked[key] {
+			s.linked[key] = true
+			added++
+		}
+	}
+	return added, nil
+}
+
+func TestAddUserAttributesNewThenDuplicate(t *testing.T) {
+	handlers := newStubHandlers(&attributeRepository{linked: map[string]bool{}})
+	body := `{"attributes":[{"type":"DEVICE","value":"dev-42"}]}`
+	for i, want := range []int{1, 0} {
+		rec := serve(t, handlers.handleUserResource, http.MethodPost, "/users/USR-1/attributes", strings.NewReader(body))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d status = %d, want 200: %s", i+1, rec.Code, rec.Body)
+		}
+		var resp addUserAttributesResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.UserID != "USR-1" || resp.Added != want {
+			t.Errorf("request %d = %+v, want USR-1 with %d added", i+1, resp, want)
+		}
+	}
+}
+
+func TestAddUserAttributesRequiresAnAttribute(t *testing.T) {
+	handlers := newStubHandlers(&attributeRepository{linked: map[string]bool{}})
+	rec := serve(t, handlers.handleUserResource, http.MethodPost, "/users/USR-1/attributes", strings.NewReader(`{"attributes":[]}`))
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,57 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestListUsersCreatedRange(t *testing.T) {
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantAfter  *time.Time
+		wantBefore *time.Time
+	}{
+		{name: "no range", query: "", wantStatus: http.StatusOK},
+		{name: "only after", query: "createdAfter=2024-01-01T00:00:00Z", wantStatus: http.StatusOK, wantAfter: &after},
+		{name: "only before", query: "createdBefore=2024-06-30T14:00:00%2B02:00", wantStatus: http.StatusOK, wantBefore: &before},
+		{name: "both", query: "createdAfter=2024-01-01T00:00:00Z&createdBefore=2024-06-30T12:00:00Z", wantStatus: http.StatusOK, wantAfter: &after, wantBefore: &before},
+		{name: "invalid after", query: "createdAfter=2024-01-01", wantStatus: http.StatusBadRequest},
+		{name: "invalid before", query: "createdBefore=yesterday", wantStatus: http.StatusBadRequest},
+		{name: "after later than before", query: "createdAfter=2024-06-30T12:00:01Z&createdBefore=2024-06-30T12:00:00Z", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{}
+			rec := serve(t, newStubHandlers(repo).handleUsers, http.MethodGet, "/users?"+tt.query, nil)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				if len(repo.listUsers) != 0 {
+					t.Error("rejected request reached the repository")
+				}
+				return
+			}
+			opts := repo.listUsers[0]
+			if !sameTime(opts.CreatedAfter, tt.wantAfter) {
+				t.Errorf("CreatedAfter = %v, want %v", opts.CreatedAfter, tt.wantAfter)
+			}
+			if !sameTime(opts.CreatedBefore, tt.wantBefore) {
+				t.Errorf("CreatedBefore = %v, want %v", opts.CreatedBefore, tt.wantBefore)
+			}
+		})
+	}
+}
+
+// sameTime reports whether two optional times are both unset or the same instant.
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
@@ -0,0 +1,391 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+	"github.com/vanshika/fintrace/backend/internal/service"
+)
+
+// handleUserResource dispatches /users/{id}/{resource} routes.
+func (h *APIHandlers) handleUserResource(w http.ResponseWriter, r *http.Request) {
+	userID, resource := resourcePath(r.URL.Path, "/users")
+	if userID == "" {
+		writeError(w, http.StatusBadRequest, "user ID is required")
+		return
+	}
+
+	switch resource {
+	case "":
+		h.handleUser(w, r, userID)
+	case "risk-explanation":
+		h.handleUserRiskExplanation(w, r, userID)
+	case "relationship-counts":
+		h.handleUserRelationshipCounts(w, r, userID)
+	case "unique-attributes":
+		h.handleUserUniqueAttributes(w, r, userID)
+	case "component-size":
+		h.handleUserComponentSize(w, r, userID)
+	case "attributes":
+		h.handleAddUserAttributes(w, r, userID)
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (h *APIHandlers) handleUserRiskExplanation(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	explanation, err := h.service.ExplainUserRisk(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to explain user risk", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to explain user risk")
+		return
+	}
+
+	response := riskExplanationResponse{
+		UserID:        explanation.UserID,
+		Model:         explanation.Model,
+		StoredScore:   explanation.StoredScore,
+		ComputedScore: explanation.ComputedScore,
+		Components:    []riskComponentResponse{},
+	}
+	for _, c := range explanation.Components {
+		response.Components = append(response.Components, riskComponentResponse{
+			Name:         c.Name,
+			Value:        c.Value,
+			Score:        c.Score,
+			Weight:       c.Weight,
+			Contribution: c.Contribution,
+			Detail:       c.Detail,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *APIHandlers) handleUserRelationshipCounts(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	counts, err := h.service.GetUserRelationshipCounts(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to count user relationships", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to count user relationships")
+		return
+	}
+
+	response := relationshipCountsResponse{
+		UserID:           counts.UserID,
+		Outbound:         counts.Outbound,
+		Inbound:          counts.Inbound,
+		Transactions:     counts.Transactions,
+		Attributes:       counts.Attributes,
+		SharedAttributes: map[string]int64{},
+	}
+	for attrType, users := range counts.SharedByType {
+		response.SharedAttributes[attrType] = users
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *APIHandlers) handleUserUniqueAttributes(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	attrs, err := h.service.GetUserUniqueAttributes(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to fetch unique attributes", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to fetch unique attributes")
+		return
+	}
+
+	redact := h.shouldRedactPII(r)
+	response := uniqueAttributesResponse{UserID: userID, Attributes: make([]uniqueAttributeResponse, 0, len(attrs))}
+	for _, attr := range attrs {
+		if redact {
+			attr = attr.Redacted()
+		}
+		response.Attributes = append(response.Attributes, uniqueAttributeResponse{
+			AttributeType:   attr.Type,
+			Value:           attr.Value,
+			RawValue:        attr.RawValue,
+			ConfidenceScore: attr.ConfidenceScore,
+			Origin:          attr.Origin,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+func (h *APIHandlers) handleUserComponentSize(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	query := r.URL.Query()
+	var edgeTypes []string
+	for _, edgeType := range strings.Split(query.Get("edgeTypes"), ",") {
+		edgeType = strings.ToUpper(strings.TrimSpace(edgeType))
+		if edgeType == "" {
+			continue
+		}
+		if !slices.Contains(repository.ComponentEdgeTypes, edgeType) {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("unsupported edge type %q; expected one of %s", edgeType, strings.Join(repository.ComponentEdgeTypes, ", ")))
+			return
+		}
+		edgeTypes = append(edgeTypes, edgeType)
+	}
+	maxNodes := 0
+	if raw := query.Get("maxNodes"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed <= 0 || parsed > repository.MaxComponentNodes {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("maxNodes must be between 1 and %d", repository.MaxComponentNodes))
+			return
+		}
+		maxNodes = parsed
+	}
+
+	component, err := h.service.ComponentSize(r.Context(), userID, edgeTypes, maxNodes)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to compute component size", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to compute component size")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, componentSizeResponse{
+		UserID:    component.UserID,
+		EdgeTypes: component.EdgeTypes,
+		Size:      component.Size,
+		Truncated: component.Truncated,
+	})
+}
+
+type componentSizeResponse struct {
+	UserID    string   `json:"userId"`
+	EdgeTypes []string `json:"edgeTypes"`
+	Size      int64    `json:"size"`
+	Truncated bool     `json:"truncated"`
+}
+
+type uniqueAttributeResponse struct {
+	AttributeType   string  `json:"attributeType"`
+	Value           string  `json:"value"`
+	RawValue        string  `json:"rawValue,omitempty"`
+	ConfidenceScore float64 `json:"confidenceScore"`
+	Origin          string  `json:"origin,omitempty"`
+}
+
+type uniqueAttributesResponse struct {
+	UserID     string                    `json:"userId"`
+	Attributes []uniqueAttributeResponse `json:"attributes"`
+}
+
+type relationshipCountsResponse struct {
+	UserID       string `json:"userId"`
+	Outbound     int64  `json:"outbound"`
+	Inbound      int64  `json:"inbound"`
+	Transactions int64  `json:"transactions"`
+	Attributes   int64  `json:"attributes"`
+	// SharedAttributes maps attribute type to the number of other users sharing it.
+	SharedAttributes map[string]int64 `json:"sharedAttributes"`
+}
+
+type riskComponentResponse struct {
+	Name         string  `json:"name"`
+	Value        float64 `json:"value"`
+	Score        float64 `json:"score"`
+	Weight       float64 `json:"weight"`
+	Contribution float64 `json:"contribution"`
+	Detail       string  `json:"detail"`
+}
+
+type riskExplanationResponse struct {
+	UserID        string                  `json:"userId"`
+	Model         string                  `json:"model"`
+	StoredScore   float64                 `json:"storedScore"`
+	ComputedScore float64                 `json:"computedScore"`
+	Components    []riskComponentResponse `json:"components"`
+}
+
+type addUserAttributesRequest struct {
+	Attributes []attributeRequest `json:"attributes"`
+}
+
+type addUserAttributesResponse struct {
+	UserID string `json:"userId"`
+	// Added counts attributes newly linked to the user; the rest were already linked.
+	Added int `json:"added"`
+}
+
+// handleAddUserAttributes links attributes to an existing user without rewriting its
+// profile, as POST /users would.
+func (h *APIHandlers) handleAddUserAttributes(w http.ResponseWriter, r *http.Request, userID string) {
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	var req addUserAttributesRequest
+	if err := decodeJSON(r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid attributes payload: "+err.Error())
+		return
+	}
+	inputs := make([]service.AttributeInput, 0, len(req.Attributes))
+	for _, attr := range req.Attributes {
+		inputs = append(inputs, service.AttributeInput{
+			Type:            attr.Type,
+			Value:           attr.Value,
+			RawValue:        attr.RawValue,
+			ConfidenceScore: attr.ConfidenceScore,
+		})
+	}
+
+	added, err := h.service.AddUserAttributes(r.Context(), userID, inputs)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrNoAttributes), errors.Is(err, service.ErrLimitExceeded):
+			writeError(w, http.StatusBadRequest, err.Error())
+		case errors.Is(err, repository.ErrNotFound):
+			writeError(w, http.StatusNotFound, "user not found")
+		default:
+			h.logger.Error("failed to add user attributes", "error", err, "userId", userID)
+			writeServerError(w, err, "failed to add user attributes")
+		}
+		return
+	}
+
+	respondJSON(w, http.StatusOK, addUserAttributesResponse{UserID: userID, Added: added})
+}
+
+// handleUser serves GET and DELETE on /users/{id}.
+func (h *APIHandlers) handleUser(w http.ResponseWriter, r *http.Request, userID string) {
+	switch r.Method {
+	case http.MethodGet:
+		h.getUser(w, r, userID)
+	case http.MethodDelete:
+		h.deleteUser(w, r, userID)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodDelete)
+	}
+}
+
+// getUser returns the full profile in the shape POST /users accepts, with derived
+// attributes included, so it can be edited and posted back.
+func (h *APIHandlers) getUser(w http.ResponseWriter, r *http.Request, userID string) {
+	user, err := h.service.GetUser(r.Context(), userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to get user", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to get user")
+		return
+	}
+	if h.shouldRedactPII(r) {
+		user = user.Redacted()
+	}
+
+	response := userRequest{
+		UserID:   user.ID,
+		FullName: user.FullName,
+		Email:    user.Email,
+		Phone:    user.Phone,
+		Address: addressRequest{
+			Line1:      user.Address.Line1,
+			Line2:      user.Address.Line2,
+			City:       user.Address.City,
+			State:      user.Address.State,
+			PostalCode: user.Address.PostalCode,
+			Country:    user.Address.Country,
+		},
+		KYCStatus:      user.KYCStatus,
+		RiskScore:      user.RiskScore,
+		PaymentMethods: []paymentMethodRequest{},
+		Attributes:     []attributeRequest{},
+		CreatedAt:      formatTime(user.CreatedAt),
+		UpdatedAt:      formatTime(user.UpdatedAt),
+	}
+	if user.DateOfBirth != nil {
+		response.DateOfBirth = user.DateOfBirth.UTC().Format("2006-01-02")
+	}
+	for _, pm := range user.PaymentMethods {
+		response.PaymentMethods = append(response.PaymentMethods, paymentMethodRequest{
+			ID:          pm.ID,
+			MethodType:  pm.MethodType,
+			Provider:    pm.Provider,
+			Masked:      pm.Masked,
+			Fingerprint: pm.Fingerprint,
+			FirstUsedAt: formatTimePtr(pm.FirstUsedAt),
+			LastUsedAt:  formatTimePtr(pm.LastUsedAt),
+		})
+	}
+	for _, attr := range user.Attributes {
+		response.Attributes = append(response.Attributes, attributeRequest{
+			Type:            attr.Type,
+			Value:           attr.Value,
+			RawValue:        attr.RawValue,
+			ConfidenceScore: attr.ConfidenceScore,
+		})
+	}
+
+	respondJSON(w, http.StatusOK, response)
+}
+
+// deleteUser serves DELETE /users/{id}, for data-deletion requests.
+func (h *APIHandlers) deleteUser(w http.ResponseWriter, r *http.Request, userID string) {
+	purgeOrphans := false
+	if raw := r.URL.Query().Get("purgeOrphans"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "purgeOrphans must be a boolean")
+			return
+		}
+		purgeOrphans = parsed
+	}
+
+	purged, err := h.service.DeleteUser(r.Context(), userID, purgeOrphans)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			writeError(w, http.StatusNotFound, "user not found")
+			return
+		}
+		h.logger.Error("failed to delete user", "error", err, "userId", userID)
+		writeServerError(w, err, "failed to delete user")
+		return
+	}
+
+	h.logger.Info("deleted user", "userId", userID, "purgedOrphans", purged)
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// apiVersionHeader selects the request schema version when the path has no /vN prefix.
+// Responses always carry the version that was applied.
+const apiVersionHeader = "X-API-Version"
+
+// apiVersion identifies a request schema version. Unversioned paths use latestAPIVersion.
+//
+// Versioning policy: adding optional request fields does not need a new version. A change
+// that would reject or reinterpret payloads valid under the latest version adds a new
+// version; the previous request DTOs are frozen under their version and converted to the
+// latest DTOs by that version's decoder, so older clients keep working on /vN paths.
+type apiVersion int
+
+const (
+	apiV1            apiVersion = 1
+	latestAPIVersion            = apiV1
+)
+
+// userRequestDecoders and transactionRequestDecoders decode each version's payload into
+// the latest request DTO.
+var (
+	userRequestDecoders = map[apiVersion]func(*http.Request) (userRequest, error){
+		apiV1: func(r *http.Request) (userRequest, error) {
+			var payload userRequest
+			err := decodeJSON(r, &payload)
+			return payload, err
+		},
+	}
+	transactionRequestDecoders = map[apiVersion]func(*http.Request) (transactionRequest, error){
+		apiV1: func(r *http.Request) (transactionRequest, error) {
+			var payload transactionRequest
+			err := decodeJSON(r, &payload)
+			return payload, err
+		},
+	}
+)
+
+type apiVersionKey struct{}
+
+// versionMiddleware resolves the API version from a /vN path prefix or the
+// X-API-Version header, strips the prefix so versioned paths reach the same handlers,
+// and rejects unknown versions.
+func versionMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version := latestAPIVersion
+		path := r.URL.Path
+		prefixed, rest, hasPrefix := splitVersionPrefix(path)
+		header := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(r.Header.Get(apiVersionHeader))), "v")
+
+		switch {
+		case hasPrefix:
+			if header != "" && header != strconv.Itoa(int(prefixed)) {
+				writeError(w, http.StatusBadRequest, "X-API-Version does not match the version in the path")
+				return
+			}
+			version = prefixed
+			path = rest
+		case header != "":
+			n, err := strconv.Atoi(header)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, "invalid X-API-Version")
+				return
+			}
+			version = apiVersion(n)
+		}
+		if _, ok := userRequestDecoders[version]; !ok {
+			writeError(w, http.StatusBadRequest, "unsupported API version "+strconv.Itoa(int(version)))
+			return
+		}
+
+		w.Header().Set(apiVersionHeader, strconv.Itoa(int(version)))
+		ctx := context.WithValue(r.Context(), apiVersionKey{}, version)
+		r = r.WithContext(ctx)
+		if path != r.URL.Path {
+			u := *r.URL
+			u.Path = path
+			u.RawPath = ""
+			r.URL = &u
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// splitVersionPrefix parses paths such as /v1/users into version 1 and /users.
+func splitVersionPrefix(path string) (apiVersion, string, bool) {
+	if !strings.HasPrefix(path, "/v") {
+		return 0, path, false
+	}
+	segment, rest, _ := strings.Cut(path[2:], "/")
+	n, err := strconv.Atoi(segment)
+	if err != nil || n <= 0 {
+		return 0, path, false
+	}
+	return apiVersion(n), "/" + rest, true
+}
+
+func requestAPIVersion(r *http.Request) apiVersion {
+	if v, ok := r.Context().Value(apiVersionKey{}).(apiVersion); ok {
+		return v
+	}
+	return latestAPIVersion
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// userRequestV2 stands in for a future schema that adds a field v1 never had. The
+// decoder converts it to the DTO the handlers consume, as a real v2 would.
+type userRequestV2 struct {
+	userRequest
+	PreferredName string `json:"preferredName"`
+}
+
+func TestV1PayloadDecodesAfterV2FieldIsAdded(t *testing.T) {
+	const apiV2 apiVersion = 2
+	userRequestDecoders[apiV2] = func(r *http.Request) (userRequest, error) {
+		var payload userRequestV2
+		if err := decodeJSON(r, &payload); err != nil {
+			return userRequest{}, err
+		}
+		if payload.FullName == "" {
+			payload.FullName = payload.PreferredName
+		}
+		return payload.userRequest, nil
+	}
+	t.Cleanup(func() { delete(userRequestDecoders, apiV2) })
+
+	v1Payload := `{"userId":"USR-1","fullName":"Ann Lee","email":"ann@example.com"}`
+	v2Payload := `{"userId":"USR-2","preferredName":"Bo","email":"bo@example.com"}`
+	tests := []struct {
+		name        string
+		path        string
+		header      string
+		body        string
+		wantStatus  int
+		wantVersion string
+		wantName    string
+	}{
+		{name: "v1 payload on /v1", path: "/v1/users", body: v1Payload, wantStatus: http.StatusCreated, wantVersion: "1", wantName: "Ann Lee"},
+		{name: "v1 payload by header", path: "/users", header: "1", body: v1Payload, wantStatus: http.StatusCreated, wantVersion: "1", wantName: "Ann Lee"},
+		{name: "v1 payload on /v2", path: "/v2/users", body: v1Payload, wantStatus: http.StatusCreated, wantVersion: "2", wantName: "Ann Lee"},
+		{name: "v2 payload on /v2", path: "/v2/users", body: v2Payload, wantStatus: http.StatusCreated, wantVersion: "2", wantName: "Bo"},
+		{name: "v1 schema stays frozen", path: "/v1/users", body: v2Payload, wantStatus: http.StatusBadRequest, wantVersion: "1"},
+		{name: "unknown version", path: "/v3/users", body: v1Payload, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubRepository{}
+			handler := versionMiddleware(http.HandlerFunc(newStubHandlers(repo).handleUsers))
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			if tt.header != "" {
+				req.Header.Set(apiVersionHeader, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if got := rec.Header().Get(apiVersionHeader); got != tt.wantVersion {
+				t.Errorf("%s = %q, want %q", apiVersionHeader, got, tt.wantVersion)
+			}
+			if tt.wantName == "" {
+				return
+			}
+			if len(repo.users) != 1 || repo.users[0].FullName != tt.wantName {
+				t.Errorf("stored users = %+v, want one named %q", repo.users, tt.wantName)
+			}
+		})
+	}
+}
+
+func TestUnversionedPathsUseLatestVersion(t *testing.T) {
+	var body map[string]any
+	handler := versionMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]any{"version": int(requestAPIVersion(r)), "path": r.URL.Path})
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/U1", nil))
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body["version"] != float64(latestAPIVersion) || body["path"] != "/users/U1" {
+		t.Errorf("handler saw %v, want the latest version and the path unchanged", body)
+	}
+	if got, want := rec.Header().Get(apiVersionHeader), fmt.Sprint(int(latestAPIVersion)); got != want {
+		t.Errorf("%s = %q, want %q", apiVersionHeader, got, want)
+	}
+}
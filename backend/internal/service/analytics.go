@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// sharedAttributeWeights scores how strongly a shared attribute type suggests the same person.
+var sharedAttributeWeights = map[string]float64{
+	AttributeTypeEmail:     0.3,
+	AttributeTypePhone:     0.3,
+	AttributeTypePayment:   0.3,
+	AttributeTypeAddress:   0.2,
+	AttributeTypeDevice:    0.2,
+	AttributeTypeIPAddress: 0.1,
+}
+
+// fieldMatchWeights covers profile fields that have no attribute counterpart.
+var fieldMatchWeights = map[string]float64{
+	"fullName":    0.1,
+	"dateOfBirth": 0.1,
+}
+
+// CompareUsers returns the overlap between two users along with a 0..1 similarity score.
+func (s *RelationshipService) CompareUsers(ctx context.Context, userA, userB string) (domain.UserComparison, error) {
+	comparison, err := s.repo.CompareUsers(ctx, userA, userB)
+	if err != nil {
+		return domain.UserComparison{}, err
+	}
+	comparison.Similarity = similarityScore(comparison)
+	return comparison, nil
+}
+
+// OnboardingVelocity buckets account creations over time and flags coordinated spikes.
+func (s *RelationshipService) OnboardingVelocity(ctx context.Context, opts repository.NewUserVelocityOptions) (domain.OnboardingVelocity, error) {
+	return s.repo.NewUserVelocity(ctx, opts)
+}
+
+// IsolatedUsers pages through users with no transactions and no shared attributes.
+func (s *RelationshipService) IsolatedUsers(ctx context.Context, page, pageSize int) (UsersPage, error) {
+	page, pageSize = normalizePagination(page, pageSize)
+	result, err := s.repo.IsolatedUsers(ctx, repository.IsolatedUsersOptions{
+		Offset: (page - 1) * pageSize,
+		Limit:  pageSize,
+	})
+	if err != nil {
+		return UsersPage{}, err
+	}
+	return UsersPage{
+		Items:      result.Items,
+		Pagination: buildPaginationMeta(page, pageSize, result.Total),
+	}, nil
+}
+
+// ComponentSize returns how large the connected component containing the user is,
+// following edgeTypes and visiting at most maxNodes nodes.
+func (s *RelationshipService) ComponentSize(ctx context.Context, userID string, edgeTypes []string, maxNodes int) (domain.ComponentSize, error) {
+	return s.repo.ComponentSize(ctx, userID, edgeTypes, maxNodes)
+}
+
+// UsersByPaymentFingerprint returns the users whose payment methods share fingerprint.
+func (s *RelationshipService) UsersByPaymentFingerprint(ctx context.Context, fingerprint string) (domain.SharedPaymentMethod, error) {
+	return s.repo.UsersByPaymentFingerprint(ctx, fingerprint)
+}
+
+// AttributeClusters returns the attributes of attributeType, or of every type when empty,
+// shared by at least opts.MinUsers users.
+func (s *RelationshipService) AttributeClusters(ctx context.Context, opts repository.AttributeClusterOptions) (domain.AttributeClusters, error) {
+	return s.repo.AttributeClusters(ctx, opts)
+}
+
+// UsersWithSharedAttributeCount returns the pairs of users sharing at least
+// minDistinctTypes distinct attribute types.
+func (s *RelationshipService) UsersWithSharedAttributeCount(ctx context.Context, minDistinctTypes int) (domain.CorrelatedUsers, error) {
+	return s.repo.UsersWithSharedAttributeCount(ctx, minDistinctTypes)
+}
+
+// ClusterProfile returns the distribution of shared attribute types among userIDs.
+func (s *RelationshipService) ClusterProfile(ctx context.Context, userIDs []string) (domain.ClusterProfile, error) {
+	return s.repo.ClusterProfile(ctx, userIDs)
+}
+
+// UsersSharingPaymentMethods returns the payment methods used by at least minUsers users.
+func (s *RelationshipService) UsersSharingPaymentMethods(ctx context.Context, minUsers int) (domain.PaymentMethodClusters, error) {
+	return s.repo.UsersSharingPaymentMethods(ctx, minUsers)
+}
+
+// TransactionsThroughBank returns the transactions routed through the bank identified by
+// bic, which must be a well-formed BIC.
+func (s *RelationshipService) TransactionsThroughBank(ctx context.Context, bic string) (domain.BankTransactions, error) {
+	bic, ok := NormalizeBIC(bic)
+	if !ok {
+		return domain.BankTransactions{}, fmt.Errorf("invalid BIC %q", bic)
+	}
+	return s.repo.TransactionsThroughBank(ctx, bic)
+}
+
+// ShortestPathsBatch computes a shortest path for each pair of users, aligned to pairs,
+// weighing edges by weighting.
+func (s *RelationshipService) ShortestPathsBatch(ctx context.Context, pairs []repository.PathPair, maxHops int, weighting repository.WeightStrategy) ([]domain.ShortestPathResult, error) {
+	return s.repo.ShortestPathsBatch(ctx, pairs, maxHops, weighting)
+}
+
+// ShortestPathsBetweenUsers returns the k shortest distinct paths between two users,
+// fewest hops first. Paths of equal length keep the repository's order.
+func (s *RelationshipService) ShortestPathsBetweenUsers(ctx context.Context, sourceID, targetID string, k int) ([]domain.ShortestPath, error) {
+	paths, err := s.repo.ShortestPathsBetweenUsers(ctx, sourceID, targetID, k)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return paths[i].Hops() < paths[j].Hops() })
+	return paths, nil
+}
+
+// AllPathsBetweenUsers returns up to limit distinct paths between two users, shortest
+// first.
+func (s *RelationshipService) AllPathsBetweenUsers(ctx context.Context, sourceID, targetID string, maxHops, limit int) ([]domain.ShortestPath, error) {
+	return s.repo.AllPathsBetweenUsers(ctx, sourceID, targetID, maxHops, limit)
+}
+
+// PathBetweenUserAndTransaction returns a shortest path from a user to a transaction.
+func (s *RelationshipService) PathBetweenUserAndTransaction(ctx context.Context, userID, txID string, maxHops int) (domain.ShortestPath, error) {
+	return s.repo.PathBetweenUserAndTransaction(ctx, userID, txID, maxHops)
+}
+
+// UserNeighborhood returns the subgraph within depth hops of a user, with the node and
+// edge properties props asks for.
+func (s *RelationshipService) UserNeighborhood(ctx context.Context, userID string, depth int, props repository.PropertyProjection) (domain.Neighborhood, error) {
+	return s.repo.ExpandUserNeighborhood(ctx, userID, depth, props)
+}
+
+// ConnectedComponents groups every user into components over payments and shared
+// attributes and returns those with at least minSize users.
+func (s *RelationshipService) ConnectedComponents(ctx context.Context, minSize int) (domain.UserComponents, error) {
+	return s.repo.ConnectedComponents(ctx, minSize)
+}
+
+// TopConnectedUsers returns the users with the most distinct payment counterparties.
+func (s *RelationshipService) TopConnectedUsers(ctx context.Context, limit int) ([]domain.UserHub, error) {
+	return s.repo.TopConnectedUsers(ctx, limit)
+}
+
+// UserCentrality ranks users by how central they are in the money-flow graph.
+func (s *RelationshipService) UserCentrality(ctx context.Context, opts repository.CentralityOptions) (domain.UserCentrality, error) {
+	return s.repo.UserCentrality(ctx, opts)
+}
+
+// DetectCycles returns closed loops of payments of at most maxLength payments, each of at
+// least minAmount.
+func (s *RelationshipService) DetectCycles(ctx context.Context, maxLength int, minAmount float64) (domain.CycleSet, error) {
+	return s.repo.DetectCycles(ctx, maxLength, minAmount)
+}
+
+func similarityScore(c domain.UserComparison) float64 {
+	score := 0.0
+	seenTypes := make(map[string]struct{}, len(c.SharedAttributes))
+	for _, attr := range c.SharedAttributes {
+		if _, ok := seenTypes[attr.AttributeType]; ok {
+			continue
+		}
+		seenTypes[attr.AttributeType] = struct{}{}
+		if weight, ok := sharedAttributeWeights[attr.AttributeType]; ok {
+			score += weight
+		} else {
+			score += 0.1
+		}
+	}
+	for _, field := range c.FieldMatches {
+		score += fieldMatchWeights[field]
+	}
+
+	counterpartyScore := 0.05 * float64(len(c.CommonCounterparties))
+	if counterpartyScore > 0.2 {
+		counterpartyScore = 0.2
+	}
+	score += counterpartyScore
+	if len(c.DirectTransactions) > 0 {
+		score += 0.1
+	}
+
+	return clampFloat(score, 0, 1)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/vanshika/fintrace/backend/internal/config"
+)
+
+// NewAttributeGeneratorFromConfig assembles the attribute generator from configuration,
+// warning on logger when hashes are truncated. The server and ingestion both build their
+// generator here: a hasher or registry that differed between them would hash the same
+// value differently and stop users from linking.
+func NewAttributeGeneratorFromConfig(logger *slog.Logger, cfg config.AttributeConfig) (DefaultAttributeGenerator, error) {
+	hasher, err := NewHasher(cfg.HashAlgorithm, []byte(cfg.HashKey), cfg.HashLength)
+	if err != nil {
+		return DefaultAttributeGenerator{}, err
+	}
+	if cfg.HashLength > 0 {
+		logger.Warn("attribute hashes are truncated; unrelated values may collide and link",
+			"algorithm", cfg.HashAlgorithm,
+			"length", cfg.HashLength,
+			"collisionLikelyAfter", fmt.Sprintf("~2^%d distinct values", HashCollisionBound(cfg.HashLength)),
+		)
+	}
+
+	customTypes := make([]CustomAttributeType, 0, len(cfg.CustomTypes))
+	for _, t := range cfg.CustomTypes {
+		customTypes = append(customTypes, CustomAttributeType{Type: t.Type, Normalizer: t.Normalizer, Redact: t.Redact})
+	}
+	registry, err := NewAttributeRegistry(customTypes...)
+	if err != nil {
+		return DefaultAttributeGenerator{}, err
+	}
+
+	return DefaultAttributeGenerator{
+		DayBucketSampleRate: cfg.DayBucketSampleRate,
+		SamplingSeed:        cfg.SamplingSeed,
+		Hasher:              hasher,
+		Registry:            registry,
+	}, nil
+}
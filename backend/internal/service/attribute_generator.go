@@ -1,6 +1,9 @@
 package service
 
 import (
+	"fmt"
+	"hash/fnv"
+	"math"
 	"strings"
 	"time"
 
@@ -8,35 +11,55 @@ import (
 )
 
 // DefaultAttributeGenerator implements AttributeGenerator using built-in normalization rules.
-type DefaultAttributeGenerator struct{}
+type DefaultAttributeGenerator struct {
+	// DayBucketSampleRate is the fraction of transactions that emit the TX_DAY_BUCKET attribute.
+	// Values <= 0 or >= 1 keep the attribute on every transaction.
+	DayBucketSampleRate float64
+	// SamplingSeed salts the sampling decision so a given seed always selects the same transactions.
+	SamplingSeed int64
+	// Hasher derives attribute identifiers. Nil uses the full SHA-256 hex digest.
+	Hasher Hasher
+	// Registry lists deployment-specific attribute types handled by FromCustom.
+	Registry *AttributeRegistry
+}
 
-func (DefaultAttributeGenerator) FromUser(input UserInput) []domain.Attribute {
+func (g DefaultAttributeGenerator) hash(value string) string {
+	if g.Hasher == nil {
+		return hashValue(value)
+	}
+	return g.Hasher.Hash(value)
+}
+
+func (g DefaultAttributeGenerator) FromUser(input UserInput) []domain.Attribute {
 	var attrs []domain.Attribute
 
 	if email := normalizeEmail(input.Email); email != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypeEmail,
-			Value:           hashValue(email),
+			Value:           g.hash(email),
 			RawValue:        email,
 			ConfidenceScore: defaultConfidenceScore,
+			Origin:          domain.AttributeOriginUser,
 		})
 	}
 
 	if phone := normalizePhone(input.Phone); phone != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypePhone,
-			Value:           hashValue(phone),
+			Value:           g.hash(phone),
 			RawValue:        phone,
 			ConfidenceScore: defaultConfidenceScore,
+			Origin:          domain.AttributeOriginUser,
 		})
 	}
 
 	if addr := normalizeAddress(input.Address); strings.Trim(addr, "|") != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypeAddress,
-			Value:           hashValue(addr),
+			Value:           g.hash(addr),
 			RawValue:        addr,
 			ConfidenceScore: 0.9,
+			Origin:          domain.AttributeOriginUser,
 		})
 	}
 
@@ -55,52 +78,93 @@ func (DefaultAttributeGenerator) FromUser(input UserInput) []domain.Attribute {
 		seenPaymentIdentifiers[identifier] = struct{}{}
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypePayment,
-			Value:           hashValue(identifier),
+			Value:           g.hash(identifier),
 			RawValue:        identifier,
 			ConfidenceScore: 0.95,
+			Origin:          domain.AttributeOriginUser,
 		})
 	}
 
 	return attrs
 }
 
-func (DefaultAttributeGenerator) FromTransaction(input TransactionInput) []domain.Attribute {
+func (g DefaultAttributeGenerator) FromTransaction(input TransactionInput) []domain.Attribute {
 	var attrs []domain.Attribute
 
 	if ip := strings.TrimSpace(input.IPAddress); ip != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypeIPAddress,
-			Value:           hashValue(ip),
+			Value:           g.hash(ip),
 			RawValue:        ip,
 			ConfidenceScore: 0.85,
+			Origin:          domain.AttributeOriginTransaction,
 		})
 	}
 
 	if device := strings.TrimSpace(input.DeviceID); device != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypeDevice,
-			Value:           hashValue(device),
+			Value:           g.hash(device),
 			RawValue:        device,
 			ConfidenceScore: 0.9,
+			Origin:          domain.AttributeOriginTransaction,
 		})
 	}
 
 	if pm := strings.TrimSpace(input.PaymentMethodID); pm != "" {
 		attrs = append(attrs, domain.Attribute{
 			Type:            AttributeTypePayment,
-			Value:           hashValue(pm),
+			Value:           g.hash(pm),
 			RawValue:        pm,
 			ConfidenceScore: 0.9,
+			Origin:          domain.AttributeOriginTransaction,
+		})
+	}
+
+	// The largest banks route a good share of all payments, so a shared bank is weak
+	// evidence on its own and its links carry a low score.
+	seenBanks := make(map[string]struct{}, 2)
+	for _, bic := range []string{input.OriginatorBIC, input.BeneficiaryBIC} {
+		bic, ok := NormalizeBIC(bic)
+		if !ok {
+			continue
+		}
+		institution := bicInstitution(bic)
+		if _, exists := seenBanks[institution]; exists {
+			continue
+		}
+		seenBanks[institution] = struct{}{}
+		attrs = append(attrs, domain.Attribute{
+			Type:            AttributeTypeBank,
+			Value:           g.hash(institution),
+			RawValue:        institution,
+			ConfidenceScore: 0.2,
+			Origin:          domain.AttributeOriginTransaction,
 		})
 	}
 
 	// Ensure timestamp attribute can be used for clustering time-based analytics.
-	attrs = append(attrs, domain.Attribute{
-		Type:            "TX_DAY_BUCKET",
-		Value:           hashValue(input.Timestamp.UTC().Format(time.DateOnly)),
-		RawValue:        input.Timestamp.UTC().Format(time.RFC3339),
-		ConfidenceScore: 0.5,
-	})
+	// Every transaction on the same day links to every other one, so deployments can
+	// sample the attribute to keep some temporal clustering without the full N² fan-out.
+	if g.sampled(input.ID, g.DayBucketSampleRate) {
+		attrs = append(attrs, domain.Attribute{
+			Type:            AttributeTypeDayBucket,
+			Value:           g.hash(input.Timestamp.UTC().Format(time.DateOnly)),
+			RawValue:        input.Timestamp.UTC().Format(time.RFC3339),
+			ConfidenceScore: 0.5,
+			Origin:          domain.AttributeOriginDerived,
+		})
+	}
 
 	return attrs
 }
+
+// sampled deterministically decides whether the entity identified by key falls within rate.
+func (g DefaultAttributeGenerator) sampled(key string, rate float64) bool {
+	if rate <= 0 || rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	_, _ = fmt.Fprintf(h, "%d:%s", g.SamplingSeed, key)
+	return float64(h.Sum64())/math.MaxUint64 < rate
+}
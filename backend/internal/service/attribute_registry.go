@@ -0,0 +1,152 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// AttributeNormalizer canonicalises a raw attribute value before hashing.
+type AttributeNormalizer func(value string) string
+
+var nonAlnumRegex = regexp.MustCompile(`[^0-9A-Za-z]+`)
+
+// attributeNormalizers are the named rules deployments can pick for custom types.
+var attributeNormalizers = map[string]AttributeNormalizer{
+	"trim":   strings.TrimSpace,
+	"lower":  func(v string) string { return strings.ToLower(sanitizeString(v)) },
+	"upper":  func(v string) string { return strings.ToUpper(sanitizeString(v)) },
+	"digits": func(v string) string { return nonDigitRegex.ReplaceAllString(v, "") },
+	"alnum":  func(v string) string { return strings.ToUpper(nonAlnumRegex.ReplaceAllString(v, "")) },
+}
+
+// builtInAttributeTypes are reserved and cannot be registered as custom types.
+var builtInAttributeTypes = map[string]struct{}{
+	AttributeTypeEmail:     {},
+	AttributeTypePhone:     {},
+	AttributeTypeAddress:   {},
+	AttributeTypeIPAddress: {},
+	AttributeTypeDevice:    {},
+	AttributeTypePayment:   {},
+	AttributeTypeBusiness:  {},
+	AttributeTypeCustom:    {},
+	AttributeTypeDayBucket: {},
+	AttributeTypeBank:      {},
+}
+
+// CustomAttributeType describes a deployment-specific attribute type.
+type CustomAttributeType struct {
+	Type string
+	// Normalizer names the rule applied before hashing: trim, lower, upper, digits or alnum.
+	Normalizer string
+	// Redact drops the raw value so only the hash is stored (for national IDs and similar).
+	Redact bool
+}
+
+// AttributeRegistry holds custom attribute types. Custom attributes of a registered type
+// are normalised and hashed like built-in ones, so equal values link across users.
+type AttributeRegistry struct {
+	types map[string]registeredAttributeType
+}
+
+type registeredAttributeType struct {
+	normalize AttributeNormalizer
+	redact    bool
+}
+
+// NewAttributeRegistry validates and registers the given types.
+func NewAttributeRegistry(types ...CustomAttributeType) (*AttributeRegistry, error) {
+	registry := &AttributeRegistry{types: make(map[string]registeredAttributeType)}
+	for _, t := range types {
+		if err := registry.Register(t); err != nil {
+			return nil, err
+		}
+	}
+	return registry, nil
+}
+
+// Register adds a custom type. It fails for built-in or already registered types and
+// for unknown normalizers.
+func (r *AttributeRegistry) Register(t CustomAttributeType) error {
+	name := strings.ToUpper(strings.TrimSpace(t.Type))
+	if name == "" {
+		return fmt.Errorf("custom attribute type name is required")
+	}
+	if _, builtIn := builtInAttributeTypes[name]; builtIn {
+		return fmt.Errorf("custom attribute type %s collides with a built-in type", name)
+	}
+	if _, exists := r.types[name]; exists {
+		return fmt.Errorf("custom attribute type %s is already registered", name)
+	}
+	rule := strings.ToLower(strings.TrimSpace(t.Normalizer))
+	if rule == "" {
+		rule = "trim"
+	}
+	normalize, ok := attributeNormalizers[rule]
+	if !ok {
+		return fmt.Errorf("unknown normalizer %q for custom attribute type %s", t.Normalizer, name)
+	}
+	r.types[name] = registeredAttributeType{normalize: normalize, redact: t.Redact}
+	return nil
+}
+
+// Types returns the registered type names.
+func (r *AttributeRegistry) Types() []string {
+	if r == nil {
+		return nil
+	}
+	names := make([]string, 0, len(r.types))
+	for name := range r.types {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r *AttributeRegistry) lookup(attrType string) (registeredAttributeType, bool) {
+	if r == nil {
+		return registeredAttributeType{}, false
+	}
+	t, ok := r.types[strings.ToUpper(strings.TrimSpace(attrType))]
+	return t, ok
+}
+
+// FromCustom converts caller supplied attributes. Registered types are normalised from
+// RawValue (or Value when RawValue is empty) and hashed with the generator's Hasher;
+// anything else is passed through unchanged, as before registries existed.
+func (g DefaultAttributeGenerator) FromCustom(inputs []AttributeInput) []domain.Attribute {
+	var result []domain.Attribute
+	var passthrough []AttributeInput
+	for _, in := range inputs {
+		def, ok := g.Registry.lookup(in.Type)
+		if !ok {
+			passthrough = append(passthrough, in)
+			continue
+		}
+		source := in.RawValue
+		if source == "" {
+			source = in.Value
+		}
+		normalized := def.normalize(source)
+		if normalized == "" {
+			continue
+		}
+		conf := in.ConfidenceScore
+		if conf == 0 {
+			conf = defaultConfidenceScore
+		}
+		raw := normalized
+		if def.redact {
+			raw = ""
+		}
+		result = append(result, domain.Attribute{
+			Type:            strings.ToUpper(strings.TrimSpace(in.Type)),
+			Value:           g.hash(normalized),
+			RawValue:        raw,
+			ConfidenceScore: conf,
+			Origin:          domain.AttributeOriginUser,
+		})
+	}
+	return append(result, convertCustomAttributes(passthrough)...)
+}
@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestNormalizeBIC(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{in: "DEUTDEFF", want: "DEUTDEFF", wantOK: true},
+		{in: "deut de ff 500", want: "DEUTDEFF500", wantOK: true},
+		{in: "NWBKGB2L", want: "NWBKGB2L", wantOK: true},
+		{in: "DEUTDEF", want: "DEUTDEF"},
+		{in: "DEUTDEFF50", want: "DEUTDEFF50"},
+		{in: "1EUTDEFF", want: "1EUTDEFF"},
+		{in: "", want: ""},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizeBIC(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("NormalizeBIC(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// bankAttributes returns the BANK attributes in attrs.
+func bankAttributes(attrs []domain.Attribute) []domain.Attribute {
+	var banks []domain.Attribute
+	for _, attr := range attrs {
+		if attr.Type == AttributeTypeBank {
+			banks = append(banks, attr)
+		}
+	}
+	return banks
+}
+
+func TestFromTransactionEmitsBankAttributes(t *testing.T) {
+	gen := DefaultAttributeGenerator{}
+	tests := []struct {
+		name        string
+		originator  string
+		beneficiary string
+		want        []string
+	}{
+		{name: "both banks", originator: "DEUTDEFF500", beneficiary: "NWBKGB2L", want: []string{"DEUTDEFF", "NWBKGB2L"}},
+		{name: "branches of one bank", originator: "DEUTDEFF500", beneficiary: "DEUTDEFFXXX", want: []string{"DEUTDEFF"}},
+		{name: "originator only", originator: "deutdeff", want: []string{"DEUTDEFF"}},
+		{name: "malformed", originator: "NOT-A-BIC", beneficiary: "DEUT"},
+		{name: "none"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			banks := bankAttributes(gen.FromTransaction(TransactionInput{ID: "TX-1", OriginatorBIC: tt.originator, BeneficiaryBIC: tt.beneficiary}))
+			if len(banks) != len(tt.want) {
+				t.Fatalf("got %d BANK attributes %+v, want %v", len(banks), banks, tt.want)
+			}
+			for i, bank := range banks {
+				if bank.RawValue != tt.want[i] || bank.Value != hashValue(tt.want[i]) {
+					t.Errorf("attribute %d = %q (%q), want %q hashed", i, bank.RawValue, bank.Value, tt.want[i])
+				}
+				if bank.ConfidenceScore >= 0.5 {
+					t.Errorf("attribute %d confidence = %v, want a low score", i, bank.ConfidenceScore)
+				}
+				if bank.Origin != domain.AttributeOriginTransaction {
+					t.Errorf("attribute %d origin = %q, want %q", i, bank.Origin, domain.AttributeOriginTransaction)
+				}
+			}
+		})
+	}
+}
+
+func TestUpsertTransactionsLinksTransactionsThroughSharedBank(t *testing.T) {
+	txs := testTransactions(3)
+	txs[0].OriginatorBIC = "deutdeff500"
+	txs[1].BeneficiaryBIC = "DEUTDEFFXXX"
+	txs[2].OriginatorBIC = "NWBKGB2L"
+	client := &recordingClient{}
+	svc := NewRelationshipService(repository.New(client), nil)
+
+	if _, err := svc.UpsertTransactions(context.Background(), txs); err != nil {
+		t.Fatalf("UpsertTransactions() error = %v", err)
+	}
+	rows := client.writes[0]["transactions"].([]map[string]any)
+	if len(rows) != 3 {
+		t.Fatalf("statement carries %d rows, want 3", len(rows))
+	}
+
+	// The upsert links each transaction to those sharing an attribute value, scored by
+	// the attribute, so the same value on two rows is a link between them.
+	values := make([]any, len(rows))
+	for i, row := range rows {
+		var banks []map[string]any
+		for _, attr := range row["attributes"].([]map[string]any) {
+			if attr["type"] == AttributeTypeBank {
+				banks = append(banks, attr)
+			}
+		}
+		if len(banks) != 1 {
+			t.Fatalf("row %d has %d BANK attributes, want 1", i, len(banks))
+		}
+		if score, _ := banks[0]["score"].(float64); score <= 0 || score >= 0.5 {
+			t.Errorf("row %d bank link score = %v, want a low positive score", i, banks[0]["score"])
+		}
+		values[i] = banks[0]["value"]
+	}
+	if values[0] != values[1] {
+		t.Errorf("branches of one bank got values %v and %v, want one shared value to link on", values[0], values[1])
+	}
+	if values[2] == values[0] {
+		t.Errorf("a different bank shares value %v, want it unlinked", values[2])
+	}
+
+	props := rows[0]["props"].(map[string]any)
+	if props["originatorBic"] != "DEUTDEFF500" {
+		t.Errorf("stored originatorBic = %v, want the normalized DEUTDEFF500", props["originatorBic"])
+	}
+}
+
+func TestUpsertTransactionRejectsMalformedBIC(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRelationshipService(repo, nil)
+	for _, input := range []TransactionInput{
+		{OriginatorBIC: "DEUT-DE"},
+		{BeneficiaryBIC: "DEUTDEFF5"},
+	} {
+		tx := testTransactions(1)[0]
+		tx.OriginatorBIC, tx.BeneficiaryBIC = input.OriginatorBIC, input.BeneficiaryBIC
+		if err := svc.UpsertTransaction(context.Background(), tx); err == nil {
+			t.Errorf("UpsertTransaction(%+v) succeeded, want an invalid BIC error", input)
+		}
+	}
+	if len(repo.transactions) != 0 {
+		t.Errorf("stored %d transactions, want none", len(repo.transactions))
+	}
+}
+
+func TestTransactionsThroughBankRejectsMalformedBIC(t *testing.T) {
+	svc := NewRelationshipService(&memoryRepository{}, nil)
+	if _, err := svc.TransactionsThroughBank(context.Background(), "not a bic"); err == nil {
+		t.Error("TransactionsThroughBank() succeeded, want an invalid BIC error")
+	}
+}
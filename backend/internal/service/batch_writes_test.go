@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/graph"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// recordingClient is a graph.Client that answers every query with an empty result and
+// records the Cypher and parameters of every write.
+type recordingClient struct {
+	mu      sync.Mutex
+	cyphers []string
+	writes  []map[string]any
+}
+
+func (c *recordingClient) ExecuteRead(context.Context, string, map[string]any) (graph.Result, error) {
+	return graph.Result{}, nil
+}
+
+func (c *recordingClient) ExecuteWrite(_ context.Context, cypher string, params map[string]any) (graph.Result, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cyphers = append(c.cyphers, cypher)
+	c.writes = append(c.writes, params)
+	return graph.Result{}, nil
+}
+
+func (c *recordingClient) VerifyConnectivity(context.Context) error { return nil }
+
+func (c *recordingClient) Close(context.Context) error { return nil }
+
+// WriteCalls reports how many writes the client has run.
+func (c *recordingClient) WriteCalls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.writes)
+}
+
+func TestIngestUsersWritesOncePerBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		users     int
+		batchSize int
+		want      int
+	}{
+		{name: "partial last batch", users: 10, batchSize: 4, want: 3},
+		{name: "exact batches", users: 8, batchSize: 4, want: 2},
+		{name: "single users", users: 5, batchSize: 1, want: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &recordingClient{}
+			svc := NewRelationshipService(repository.New(client), nil)
+			ingestor := NewBulkIngestor(svc, 2).WithUserBatchSize(tt.batchSize)
+
+			result, err := ingestor.IngestUsers(context.Background(), testUsers(tt.users))
+			if err != nil {
+				t.Fatalf("IngestUsers() error = %v", err)
+			}
+			if result.Written != tt.users {
+				t.Errorf("Written = %d, want %d", result.Written, tt.users)
+			}
+			if got := client.WriteCalls(); got != tt.want {
+				t.Errorf("WriteCalls() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertTransactionsLinksSharedIPAcrossBatch(t *testing.T) {
+	txs := testTransactions(3)
+	for i := range txs {
+		txs[i].IPAddress = "203.0.113.7"
+	}
+	client := &recordingClient{}
+	svc := NewRelationshipService(repository.New(client), nil)
+
+	if _, err := svc.UpsertTransactions(context.Background(), txs); err != nil {
+		t.Fatalf("UpsertTransactions() error = %v", err)
+	}
+	if got := client.WriteCalls(); got != 1 {
+		t.Fatalf("WriteCalls() = %d, want 1 for the batch", got)
+	}
+	rows := client.writes[0]["transactions"].([]map[string]any)
+	if len(rows) != 3 {
+		t.Fatalf("statement carries %d rows, want 3", len(rows))
+	}
+	// Rows run in input order and each carries the same IP attribute, so every row
+	// merges the one Attribute node and links to the transactions before it.
+	var ipValue any
+	for i, row := range rows {
+		if want := txs[i].ID; row["transactionId"] != want {
+			t.Errorf("row %d is %v, want %s", i, row["transactionId"], want)
+		}
+		var ips []map[string]any
+		for _, attr := range row["attributes"].([]map[string]any) {
+			if attr["type"] == AttributeTypeIPAddress {
+				ips = append(ips, attr)
+			}
+		}
+		if len(ips) != 1 {
+			t.Fatalf("row %d has %d IP attributes, want 1", i, len(ips))
+		}
+		if i == 0 {
+			ipValue = ips[0]["value"]
+		} else if ips[0]["value"] != ipValue {
+			t.Errorf("row %d IP value = %v, want %v shared with the first row", i, ips[0]["value"], ipValue)
+		}
+	}
+	if ipValue == "" || ipValue == nil {
+		t.Error("IP attribute has no value to link on")
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// DeadLetterSink receives the records bulk ingestion could not write, with the reason,
+// so they can be fixed and ingested again. Implementations must be safe for concurrent
+// use by the ingestion workers.
+type DeadLetterSink interface {
+	WriteUser(input UserInput, err error) error
+	WriteTransaction(input TransactionInput, err error) error
+}
+
+// DeadLetterEntry is one line written by JSONLinesDeadLetterSink. Record holds the input
+// unchanged, in the form the ingest dataset files use.
+type DeadLetterEntry struct {
+	Kind   string `json:"kind"`
+	ID     string `json:"id"`
+	Error  string `json:"error"`
+	Record any    `json:"record"`
+}
+
+// Dead-letter entry kinds.
+const (
+	DeadLetterUser        = "user"
+	DeadLetterTransaction = "transaction"
+)
+
+// JSONLinesDeadLetterSink writes each failed record as one JSON DeadLetterEntry per line.
+type JSONLinesDeadLetterSink struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+}
+
+// NewJSONLinesDeadLetterSink returns a sink writing newline-delimited JSON to w.
+func NewJSONLinesDeadLetterSink(w io.Writer) *JSONLinesDeadLetterSink {
+	return &JSONLinesDeadLetterSink{enc: json.NewEncoder(w)}
+}
+
+// WriteUser records a user that failed with err.
+func (s *JSONLinesDeadLetterSink) WriteUser(input UserInput, err error) error {
+	return s.write(DeadLetterEntry{Kind: DeadLetterUser, ID: input.ID, Error: err.Error(), Record: input})
+}
+
+// WriteTransaction records a transaction that failed with err.
+func (s *JSONLinesDeadLetterSink) WriteTransaction(input TransactionInput, err error) error {
+	return s.write(DeadLetterEntry{Kind: DeadLetterTransaction, ID: input.ID, Error: err.Error(), Record: input})
+}
+
+func (s *JSONLinesDeadLetterSink) write(entry DeadLetterEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.enc.Encode(entry); err != nil {
+		return fmt.Errorf("write dead letter for %s %s: %w", entry.Kind, entry.ID, err)
+	}
+	return nil
+}
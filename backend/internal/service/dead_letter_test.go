@@ -0,0 +1,111 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testUsers(n int) []UserInput {
+	users := make([]UserInput, n)
+	for i := range users {
+		users[i] = UserInput{ID: fmt.Sprintf("USR-%d", i+1), Email: fmt.Sprintf("user%d@example.com", i+1)}
+	}
+	return users
+}
+
+func testTransactions(n int) []TransactionInput {
+	txs := make([]TransactionInput, n)
+	for i := range txs {
+		txs[i] = TransactionInput{
+			ID:             fmt.Sprintf("TX-%d", i+1),
+			SenderUserID:   "USR-1",
+			ReceiverUserID: "USR-2",
+			Amount:         10,
+			Currency:       "USD",
+			Type:           "TRANSFER",
+			Timestamp:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	}
+	return txs
+}
+
+func readDeadLetters(t *testing.T, out *bytes.Buffer) map[string]DeadLetterEntry {
+	t.Helper()
+	entries := make(map[string]DeadLetterEntry)
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry DeadLetterEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("decode dead letter %q: %v", line, err)
+		}
+		entries[entry.ID] = entry
+	}
+	return entries
+}
+
+func TestDeadLetterSinkReceivesFailedRecords(t *testing.T) {
+	for _, batchSize := range []int{1, 4} {
+		t.Run(fmt.Sprintf("batch size %d", batchSize), func(t *testing.T) {
+			repo := &failingRepository{fail: map[string]bool{"USR-3": true, "USR-7": true, "TX-2": true}}
+			var out bytes.Buffer
+			ingestor := NewBulkIngestor(NewRelationshipService(repo, nil), 3).
+				WithUserBatchSize(batchSize).
+				WithTransactionBatchSize(batchSize).
+				WithDeadLetterSink(NewJSONLinesDeadLetterSink(&out))
+
+			users, err := ingestor.IngestUsers(context.Background(), testUsers(10))
+			if err != nil {
+				t.Fatalf("IngestUsers() error = %v, want failures dead-lettered", err)
+			}
+			if users.Written != 8 || users.DeadLettered != 2 {
+				t.Errorf("IngestUsers() = %+v, want 8 written and 2 dead-lettered", users)
+			}
+			txs, err := ingestor.IngestTransactions(context.Background(), testTransactions(5))
+			if err != nil {
+				t.Fatalf("IngestTransactions() error = %v, want failures dead-lettered", err)
+			}
+			if txs.Written != 4 || txs.DeadLettered != 1 {
+				t.Errorf("IngestTransactions() = %+v, want 4 written and 1 dead-lettered", txs)
+			}
+
+			entries := readDeadLetters(t, &out)
+			if len(entries) != 3 {
+				t.Fatalf("dead letters = %v, want USR-3, USR-7 and TX-2", entries)
+			}
+			for id, kind := range map[string]string{"USR-3": DeadLetterUser, "USR-7": DeadLetterUser, "TX-2": DeadLetterTransaction} {
+				entry, ok := entries[id]
+				if !ok {
+					t.Errorf("%s was not dead-lettered", id)
+					continue
+				}
+				if entry.Kind != kind || !strings.Contains(entry.Error, "constraint violated by "+id) {
+					t.Errorf("dead letter for %s = %+v, want kind %s and its own error", id, entry, kind)
+				}
+				// The record is kept as ingested, so it can be fixed and ingested again.
+				if record, _ := entry.Record.(map[string]any); record["ID"] != id {
+					t.Errorf("dead letter record for %s = %v, want the input", id, entry.Record)
+				}
+			}
+		})
+	}
+}
+
+func TestFailedRecordsWithoutSinkAreReturned(t *testing.T) {
+	repo := &failingRepository{fail: map[string]bool{"USR-2": true}}
+	ingestor := NewBulkIngestor(NewRelationshipService(repo, nil), 2).WithUserBatchSize(1)
+
+	result, err := ingestor.IngestUsers(context.Background(), testUsers(3))
+	if err == nil || !strings.Contains(err.Error(), "USR-2") {
+		t.Fatalf("IngestUsers() error = %v, want the USR-2 failure", err)
+	}
+	if result.Written != 2 || result.DeadLettered != 0 {
+		t.Errorf("IngestUsers() = %+v, want 2 written and nothing dead-lettered", result)
+	}
+}
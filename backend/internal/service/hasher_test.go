@@ -0,0 +1,136 @@
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+func TestNewHasherConsistentOutput(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		key       string
+		length    int
+		wantLen   int
+	}{
+		{name: "default", wantLen: 64},
+		{name: "sha256 truncated", algorithm: HashAlgorithmSHA256, length: 16, wantLen: 16},
+		{name: "sha512", algorithm: HashAlgorithmSHA512, wantLen: 128},
+		{name: "sha512 truncated", algorithm: HashAlgorithmSHA512, length: minHashLength, wantLen: minHashLength},
+		{name: "hmac", algorithm: HashAlgorithmHMACSHA256, key: "secret", wantLen: 64},
+		{name: "hmac truncated", algorithm: HashAlgorithmHMACSHA256, key: "secret", length: 20, wantLen: 20},
+		{name: "full length means untruncated", algorithm: HashAlgorithmSHA256, length: 64, wantLen: 64},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hasher, err := NewHasher(tt.algorithm, []byte(tt.key), tt.length)
+			if err != nil {
+				t.Fatalf("NewHasher() error = %v", err)
+			}
+			// A second hasher built from the same settings stands in for another
+			// producer or process in the same deployment.
+			again, err := NewHasher(tt.algorithm, []byte(tt.key), tt.length)
+			if err != nil {
+				t.Fatalf("NewHasher() error = %v", err)
+			}
+			first := hasher.Hash("alice@example.com")
+			if len(first) != tt.wantLen {
+				t.Errorf("len(Hash()) = %d, want %d", len(first), tt.wantLen)
+			}
+			if hasher.Hash("alice@example.com") != first || again.Hash("alice@example.com") != first {
+				t.Error("the same value hashed differently")
+			}
+			if hasher.Hash("bob@example.com") == first {
+				t.Error("different values hashed the same")
+			}
+		})
+	}
+}
+
+func TestNewHasherTruncationIsPrefix(t *testing.T) {
+	full, err := NewHasher(HashAlgorithmSHA256, nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated, err := NewHasher(HashAlgorithmSHA256, nil, 12)
+	if err != nil {
+		t.Fatal(err)
+	}
+	digest := full.Hash("203.0.113.7")
+	if digest != hashValue("203.0.113.7") {
+		t.Errorf("default SHA-256 hasher = %s, want the built-in %s", digest, hashValue("203.0.113.7"))
+	}
+	if got := truncated.Hash("203.0.113.7"); !strings.HasPrefix(digest, got) {
+		t.Errorf("truncated hash %s is not a prefix of %s", got, digest)
+	}
+}
+
+func TestNewHasherHMACDependsOnKey(t *testing.T) {
+	a, err := NewHasher(HashAlgorithmHMACSHA256, []byte("key-a"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewHasher(HashAlgorithmHMACSHA256, []byte("key-b"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.Hash("value") == b.Hash("value") {
+		t.Error("HMAC hashes with different keys match")
+	}
+	if a.Hash("value") == hashValue("value") {
+		t.Error("HMAC hash matches the unkeyed SHA-256 digest")
+	}
+}
+
+func TestNewHasherRejectsInvalidSettings(t *testing.T) {
+	tests := []struct {
+		name      string
+		algorithm string
+		key       string
+		length    int
+	}{
+		{name: "unknown algorithm", algorithm: "md5"},
+		{name: "hmac without key", algorithm: HashAlgorithmHMACSHA256},
+		{name: "negative length", length: -1},
+		{name: "too short", length: minHashLength - 1},
+		{name: "longer than digest", algorithm: HashAlgorithmSHA256, length: 65},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewHasher(tt.algorithm, []byte(tt.key), tt.length); err == nil {
+				t.Error("NewHasher() succeeded, want an error")
+			}
+		})
+	}
+}
+
+func TestGeneratorHasherLinksUsersAndTransactions(t *testing.T) {
+	hasher, err := NewHasher(HashAlgorithmSHA256, nil, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := DefaultAttributeGenerator{Hasher: hasher}
+	userAttrs := gen.FromUser(UserInput{ID: "USR-1", PaymentMethods: []PaymentMethodInput{{ID: "PM-1"}}})
+	txAttrs := gen.FromTransaction(TransactionInput{ID: "TX-1", PaymentMethodID: "PM-1"})
+
+	userValue := attributeValue(userAttrs, AttributeTypePayment)
+	txValue := attributeValue(txAttrs, AttributeTypePayment)
+	if userValue == "" || userValue != txValue {
+		t.Errorf("payment method hashed as %q on the user and %q on the transaction, want one truncated value", userValue, txValue)
+	}
+	if userValue != hasher.Hash("PM-1") {
+		t.Errorf("payment method value = %q, want the configured hasher's %q", userValue, hasher.Hash("PM-1"))
+	}
+}
+
+// attributeValue returns the value of the first attribute of attrType in attrs.
+func attributeValue(attrs []domain.Attribute, attrType string) string {
+	for _, attr := range attrs {
+		if attr.Type == attrType {
+			return attr.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+)
+
+// ErrLimitExceeded reports a user or transaction carrying more attributes or payment
+// methods than EntityLimits allows, when the limits are strict.
+var ErrLimitExceeded = errors.New("entity limit exceeded")
+
+// EntityLimits caps what a single upsert may attach to one entity, so one payload cannot
+// fan out into thousands of attribute nodes and links. A zero cap is no cap.
+type EntityLimits struct {
+	// MaxAttributes caps the attributes of a user or transaction, derived ones included.
+	MaxAttributes int
+	// MaxPaymentMethods caps the payment methods of a user.
+	MaxPaymentMethods int
+	// Strict rejects an entity over a cap with ErrLimitExceeded. Otherwise the excess is
+	// dropped, keeping the first entries, and a warning is logged.
+	Strict bool
+}
+
+// WithEntityLimits applies limits to every user and transaction upsert and to attribute
+// updates. Warnings about truncated entities go to logger, or slog.Default when nil.
+func (s *RelationshipService) WithEntityLimits(limits EntityLimits, logger *slog.Logger) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	s.limits = limits
+	s.logger = logger
+}
+
+// limitAttributes applies MaxAttributes to the attributes of the entity kind and id.
+func (s *RelationshipService) limitAttributes(kind, id string, attrs []domain.Attribute) ([]domain.Attribute, error) {
+	n, err := s.applyLimit(kind, id, "attributes", len(attrs), s.limits.MaxAttributes)
+	if err != nil {
+		return nil, err
+	}
+	return attrs[:n], nil
+}
+
+// limitPaymentMethods applies MaxPaymentMethods to the payment methods of user id.
+func (s *RelationshipService) limitPaymentMethods(id string, methods []domain.PaymentMethod) ([]domain.PaymentMethod, error) {
+	n, err := s.applyLimit("user", id, "payment methods", len(methods), s.limits.MaxPaymentMethods)
+	if err != nil {
+		return nil, err
+	}
+	return methods[:n], nil
+}
+
+// applyLimit returns how many of count entries to keep under max.
+func (s *RelationshipService) applyLimit(kind, id, what string, count, max int) (int, error) {
+	if max <= 0 || count <= max {
+		return count, nil
+	}
+	if s.limits.Strict {
+		return 0, fmt.Errorf("%w: %s %s has %d %s, the maximum is %d", ErrLimitExceeded, kind, id, count, what, max)
+	}
+	s.logger.Warn("truncating entity over limit", "kind", kind, "id", id, "field", what, "count", count, "max", max)
+	return max, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// oversizedUser has five custom attributes and three payment methods, and no email,
+// phone or address to derive more attributes from. Payment methods derive an attribute
+// each, so the user carries eight attributes in all.
+func oversizedUser() UserInput {
+	user := UserInput{ID: "USR-1"}
+	for i := 1; i <= 5; i++ {
+		user.Attributes = append(user.Attributes, AttributeInput{Type: "DEVICE", Value: fmt.Sprintf("dev-%d", i)})
+	}
+	for i := 1; i <= 3; i++ {
+		user.PaymentMethods = append(user.PaymentMethods, PaymentMethodInput{ID: fmt.Sprintf("PM-%d", i)})
+	}
+	return user
+}
+
+func TestEntityLimitsStrictRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits EntityLimits
+	}{
+		{name: "attributes", limits: EntityLimits{MaxAttributes: 4, Strict: true}},
+		{name: "payment methods", limits: EntityLimits{MaxPaymentMethods: 2, Strict: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memoryRepository{}
+			svc := NewRelationshipService(repo, nil)
+			svc.WithEntityLimits(tt.limits, nil)
+
+			err := svc.UpsertUser(context.Background(), oversizedUser())
+			if !errors.Is(err, ErrLimitExceeded) {
+				t.Fatalf("UpsertUser() error = %v, want ErrLimitExceeded", err)
+			}
+			if len(repo.users) != 0 {
+				t.Error("a user over the cap was written")
+			}
+		})
+	}
+}
+
+func TestEntityLimitsTruncateWithWarning(t *testing.T) {
+	var logs bytes.Buffer
+	repo := &memoryRepository{}
+	svc := NewRelationshipService(repo, nil)
+	svc.WithEntityLimits(EntityLimits{MaxAttributes: 4, MaxPaymentMethods: 2}, slog.New(slog.NewTextHandler(&logs, nil)))
+
+	if err := svc.UpsertUser(context.Background(), oversizedUser()); err != nil {
+		t.Fatalf("UpsertUser() error = %v", err)
+	}
+	if len(repo.users) != 1 {
+		t.Fatalf("wrote %d users, want 1", len(repo.users))
+	}
+	user := repo.users[0]
+	if len(user.PaymentMethods) != 2 || user.PaymentMethods[0].ID != "PM-1" || user.PaymentMethods[1].ID != "PM-2" {
+		t.Errorf("payment methods = %+v, want the first two", user.PaymentMethods)
+	}
+	if len(user.Attributes) != 4 {
+		t.Errorf("wrote %d attributes, want 4", len(user.Attributes))
+	}
+	for _, field := range []string{"field=attributes", `field="payment methods"`} {
+		if !strings.Contains(logs.String(), field) {
+			t.Errorf("no truncation warning with %s in:\n%s", field, logs.String())
+		}
+	}
+}
+
+func TestEntityLimitsAllowAtCap(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRelationshipService(repo, nil)
+	svc.WithEntityLimits(EntityLimits{MaxAttributes: 8, MaxPaymentMethods: 3, Strict: true}, nil)
+	if err := svc.UpsertUser(context.Background(), oversizedUser()); err != nil {
+		t.Fatalf("UpsertUser() at the cap error = %v", err)
+	}
+	if got := len(repo.users[0].Attributes); got != 8 {
+		t.Errorf("wrote %d attributes, want 8", got)
+	}
+}
+
+func TestEntityLimitsApplyToTransactions(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRelationshipService(repo, nil)
+	svc.WithEntityLimits(EntityLimits{MaxAttributes: 1, Strict: true}, nil)
+	tx := testTransactions(1)[0]
+	tx.IPAddress, tx.DeviceID = "203.0.113.7", "dev-1"
+
+	if err := svc.UpsertTransaction(context.Background(), tx); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("UpsertTransaction() error = %v, want ErrLimitExceeded", err)
+	}
+	if len(repo.transactions) != 0 {
+		t.Error("a transaction over the cap was written")
+	}
+}
@@ -1,8 +1,13 @@
 package service
 
 import (
+	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/sha512"
 	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
 	"regexp"
 	"strings"
 )
@@ -10,6 +15,7 @@ import (
 var (
 	whitespaceRegex = regexp.MustCompile(`\s+`)
 	nonDigitRegex   = regexp.MustCompile(`\D+`)
+	bicRegex        = regexp.MustCompile(`^[A-Z]{6}[A-Z0-9]{2}([A-Z0-9]{3})?$`)
 )
 
 // AttributeType constants used by the relationship engine.
@@ -22,6 +28,8 @@ const (
 	AttributeTypePayment   = "PAYMENT_METHOD"
 	AttributeTypeBusiness  = "BUSINESS"
 	AttributeTypeCustom    = "CUSTOM"
+	AttributeTypeDayBucket = "TX_DAY_BUCKET"
+	AttributeTypeBank      = "BANK"
 	defaultConfidenceScore = 1.0
 )
 
@@ -47,6 +55,22 @@ func normalizePhone(phone string) string {
 	return phone
 }
 
+// NormalizeBIC uppercases a BIC/SWIFT code and strips its whitespace, reporting whether
+// the result is a well-formed 8 or 11 character code.
+func NormalizeBIC(bic string) (string, bool) {
+	bic = strings.ToUpper(whitespaceRegex.ReplaceAllString(bic, ""))
+	return bic, bicRegex.MatchString(bic)
+}
+
+// bicInstitution returns the bank and location part of a normalized BIC, dropping the
+// branch code so transactions through different branches of one bank still link.
+func bicInstitution(bic string) string {
+	if len(bic) > 8 {
+		return bic[:8]
+	}
+	return bic
+}
+
 // normalizeAddress concatenates address fields into a single canonical string.
 func normalizeAddress(addr AddressInput) string {
 	components := []string{
@@ -60,6 +84,73 @@ func normalizeAddress(addr AddressInput) string {
 	return strings.Join(components, "|")
 }
 
+// Hasher turns a normalized attribute value into the identifier stored on Attribute
+// nodes. Every producer in a deployment must use the same Hasher, otherwise identical
+// values no longer link.
+type Hasher interface {
+	Hash(value string) string
+}
+
+// Supported hash algorithms for NewHasher.
+const (
+	HashAlgorithmSHA256     = "sha256"
+	HashAlgorithmSHA512     = "sha512"
+	HashAlgorithmHMACSHA256 = "hmac-sha256"
+)
+
+// minHashLength is the shortest truncation NewHasher accepts (32 bits).
+const minHashLength = 8
+
+// NewHasher returns a Hasher for algorithm. HMAC algorithms require key. A positive
+// length truncates the hex digest to that many characters; truncation trades storage
+// for collision resistance, see HashCollisionBound.
+func NewHasher(algorithm string, key []byte, length int) (Hasher, error) {
+	var newHash func() hash.Hash
+	switch strings.ToLower(strings.TrimSpace(algorithm)) {
+	case "", HashAlgorithmSHA256:
+		newHash = sha256.New
+	case HashAlgorithmSHA512:
+		newHash = sha512.New
+	case HashAlgorithmHMACSHA256:
+		if len(key) == 0 {
+			return nil, errors.New("hmac-sha256 requires a key")
+		}
+		newHash = func() hash.Hash { return hmac.New(sha256.New, key) }
+	default:
+		return nil, fmt.Errorf("unsupported hash algorithm %q", algorithm)
+	}
+
+	maxLength := newHash().Size() * 2
+	if length < 0 || (length > 0 && length < minHashLength) || length > maxLength {
+		return nil, fmt.Errorf("hash length must be 0 (full digest) or between %d and %d, got %d", minHashLength, maxLength, length)
+	}
+	if length == maxLength {
+		length = 0
+	}
+	return digestHasher{newHash: newHash, length: length}, nil
+}
+
+// HashCollisionBound returns b such that a truncated hash of length hex characters has
+// roughly even odds of a collision after 2^b distinct values (the birthday bound).
+func HashCollisionBound(length int) int {
+	return length * 4 / 2
+}
+
+type digestHasher struct {
+	newHash func() hash.Hash
+	length  int
+}
+
+func (h digestHasher) Hash(value string) string {
+	sum := h.newHash()
+	sum.Write([]byte(value))
+	digest := hex.EncodeToString(sum.Sum(nil))
+	if h.length > 0 {
+		return digest[:h.length]
+	}
+	return digest
+}
+
 // hashValue returns a deterministic SHA-256 hash for the provided value.
 func hashValue(value string) string {
 	sum := sha256.Sum256([]byte(value))
@@ -2,7 +2,9 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"math"
 	"time"
 
@@ -13,11 +15,51 @@ import (
 // GraphRepository is the storage contract required by the relationship service.
 type GraphRepository interface {
 	UpsertUser(ctx context.Context, user domain.User) error
+	UpsertUsers(ctx context.Context, users []domain.User) (repository.BatchWriteResult, error)
+	AddUserAttributes(ctx context.Context, userID string, attributes []domain.Attribute) (int, error)
+	GetUser(ctx context.Context, userID string) (domain.User, error)
+	DeleteUser(ctx context.Context, userID string, purgeOrphans bool) (int, error)
+	DeleteTransaction(ctx context.Context, txID string) (int, error)
 	UpsertTransaction(ctx context.Context, tx domain.Transaction, attributes []domain.Attribute) error
-	FetchUserRelationships(ctx context.Context, userID string) (domain.UserRelationships, error)
-	FetchTransactionRelationships(ctx context.Context, transactionID string) (domain.TransactionRelationships, error)
+	UpsertTransactions(ctx context.Context, txs []domain.Transaction, attributes [][]domain.Attribute) (repository.BatchWriteResult, error)
+	FetchUserRelationships(ctx context.Context, userID string, opts repository.UserRelationshipOptions) (domain.UserRelationships, error)
+	FetchTransactionRelationships(ctx context.Context, transactionID string, opts repository.TransactionRelationshipOptions) (domain.TransactionRelationships, error)
 	ListUsers(ctx context.Context, opts repository.ListUsersOptions) (domain.UserListResult, error)
 	ListTransactions(ctx context.Context, opts repository.ListTransactionsOptions) (domain.TransactionListResult, error)
+	PruneOrphansWithOptions(ctx context.Context, opts repository.BatchOptions) (int, error)
+	CompareUsers(ctx context.Context, userA, userB string) (domain.UserComparison, error)
+	NewUserVelocity(ctx context.Context, opts repository.NewUserVelocityOptions) (domain.OnboardingVelocity, error)
+	IsolatedUsers(ctx context.Context, opts repository.IsolatedUsersOptions) (domain.UserListResult, error)
+	LookupUsers(ctx context.Context, ids []string) ([]domain.UserLookup, error)
+	LookupTransactions(ctx context.Context, ids []string) ([]domain.TransactionLookup, error)
+	GetStats(ctx context.Context) (domain.GraphStats, error)
+	FetchUserRiskFeatures(ctx context.Context, userID string, opts repository.RiskFeatureOptions) (domain.UserRiskFeatures, error)
+	UserRelationshipCounts(ctx context.Context, userID string) (domain.UserRelationshipCounts, error)
+	UserUniqueAttributes(ctx context.Context, userID string) ([]domain.Attribute, error)
+	ComponentSize(ctx context.Context, userID string, edgeTypes []string, maxNodes int) (domain.ComponentSize, error)
+	UsersByPaymentFingerprint(ctx context.Context, fingerprint string) (domain.SharedPaymentMethod, error)
+	UsersSharingPaymentMethods(ctx context.Context, minUsers int) (domain.PaymentMethodClusters, error)
+	ClusterProfile(ctx context.Context, userIDs []string) (domain.ClusterProfile, error)
+	AttributeClusters(ctx context.Context, opts repository.AttributeClusterOptions) (domain.AttributeClusters, error)
+	UsersWithSharedAttributeCount(ctx context.Context, minDistinctTypes int) (domain.CorrelatedUsers, error)
+	TransactionsThroughBank(ctx context.Context, bic string) (domain.BankTransactions, error)
+	ShortestPathsBatch(ctx context.Context, pairs []repository.PathPair, maxHops int, weighting repository.WeightStrategy) ([]domain.ShortestPathResult, error)
+	PathBetweenUserAndTransaction(ctx context.Context, userID, txID string, maxHops int) (domain.ShortestPath, error)
+	ExpandUserNeighborhood(ctx context.Context, userID string, depth int, props repository.PropertyProjection) (domain.Neighborhood, error)
+	AllPathsBetweenUsers(ctx context.Context, sourceID, targetID string, maxHops, limit int) ([]domain.ShortestPath, error)
+	ShortestPathsBetweenUsers(ctx context.Context, sourceID, targetID string, k int) ([]domain.ShortestPath, error)
+	DetectCycles(ctx context.Context, maxLength int, minAmount float64) (domain.CycleSet, error)
+	ConnectedComponents(ctx context.Context, minSize int) (domain.UserComponents, error)
+	UserCentrality(ctx context.Context, opts repository.CentralityOptions) (domain.UserCentrality, error)
+	TopConnectedUsers(ctx context.Context, limit int) ([]domain.UserHub, error)
+	GetTransaction(ctx context.Context, txID string) (domain.Transaction, error)
+	RecomputeTransactionLinks(ctx context.Context, txID string, attributes []domain.Attribute) ([]domain.LinkedTransaction, error)
+}
+
+// sessionBinder is implemented by repositories that can bind a copy of themselves to a
+// single reused session (see repository.Repository.WithSession).
+type sessionBinder interface {
+	WithSession(ctx context.Context) (*repository.Repository, func(context.Context) error, error)
 }
 
 // AttributeGenerator handles attribute extraction and hashing.
@@ -26,11 +68,22 @@ type AttributeGenerator interface {
 	FromTransaction(input TransactionInput) []domain.Attribute
 }
 
+// customAttributeGenerator is implemented by generators that also convert caller supplied
+// attributes, for example to normalise and hash registered custom types.
+type customAttributeGenerator interface {
+	FromCustom(inputs []AttributeInput) []domain.Attribute
+}
+
 // RelationshipService orchestrates ingestion logic and delegates persistence to the repository.
 type RelationshipService struct {
 	repo       GraphRepository
 	attributes AttributeGenerator
+	riskModel  RiskModel
 	nowFn      func() time.Time
+	synonyms   ValueSynonyms
+	limits     EntityLimits
+	logger     *slog.Logger
+	sorts      SortDefaults
 }
 
 // PaginationMeta captures pagination metadata returned to API clients.
@@ -39,6 +92,8 @@ type PaginationMeta struct {
 	PageSize   int
 	TotalItems int64
 	TotalPages int
+	// NextCursor is the token for the next page of a cursor listing, if there is one.
+	NextCursor string
 }
 
 // UsersPage represents paginated users with metadata.
@@ -66,6 +121,12 @@ type ListUsersParams struct {
 	EmailDomain string
 	SortField   string
 	SortOrder   string
+	// CreatedAfter and CreatedBefore bound the users' creation time; either may be nil.
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// Cursor selects keyset pagination, resuming after the After token; Page is ignored.
+	Cursor bool
+	After  string
 }
 
 // ListTransactionsParams defines filters for listing transactions.
@@ -81,8 +142,21 @@ type ListTransactionsParams struct {
 	StartTime *time.Time
 	EndTime   *time.Time
 	Channel   string
-	SortField string
-	SortOrder string
+	Currency  string
+	// DeviceID and IPAddress match transactions exactly, and with IncludeLinked also
+	// those sharing their device or IP attribute.
+	DeviceID      string
+	IPAddress     string
+	IncludeLinked bool
+	// SenderRiskMin, ReceiverRiskMin and AnyParticipantRiskMin filter on the participants'
+	// risk scores; values are clamped to [0, 1].
+	SenderRiskMin         *float64
+	ReceiverRiskMin       *float64
+	AnyParticipantRiskMin *float64
+	// ParticipantKYC filters on participants' KYC status (NONE_VERIFIED or ANY_VERIFIED).
+	ParticipantKYC string
+	SortField      string
+	SortOrder      string
 }
 
 // NewRelationshipService constructs a RelationshipService with optional overrides.
@@ -93,10 +167,28 @@ func NewRelationshipService(repo GraphRepository, gen AttributeGenerator) *Relat
 	return &RelationshipService{
 		repo:       repo,
 		attributes: gen,
+		riskModel:  DefaultRiskModel{},
 		nowFn:      time.Now,
+		logger:     slog.Default(),
 	}
 }
 
+// withSession returns a copy of the service whose writes share one repository session,
+// and a function releasing it. Repositories without session support return s unchanged.
+func (s *RelationshipService) withSession(ctx context.Context) (*RelationshipService, func(context.Context) error, error) {
+	binder, ok := s.repo.(sessionBinder)
+	if !ok {
+		return s, func(context.Context) error { return nil }, nil
+	}
+	repo, release, err := binder.WithSession(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	clone := *s
+	clone.repo = repo
+	return &clone, release, nil
+}
+
 // WithClock overrides the time provider (used primarily in tests).
 func (s *RelationshipService) WithClock(nowFn func() time.Time) {
 	if nowFn != nil {
@@ -106,6 +198,9 @@ func (s *RelationshipService) WithClock(nowFn func() time.Time) {
 
 // ListUsers retrieves paginated users matching provided filters.
 func (s *RelationshipService) ListUsers(ctx context.Context, params ListUsersParams) (UsersPage, error) {
+	if params.Cursor {
+		params.Page = 1
+	}
 	page, pageSize := normalizePagination(params.Page, params.PageSize)
 	offset := (page - 1) * pageSize
 
@@ -122,25 +217,31 @@ func (s *RelationshipService) ListUsers(ctx context.Context, params ListUsersPar
 	}
 
 	result, err := s.repo.ListUsers(ctx, repository.ListUsersOptions{
-		Offset:      offset,
-		Limit:       pageSize,
-		KYCStatus:   params.KYCStatus,
-		RiskMin:     riskMin,
-		RiskMax:     riskMax,
-		Search:      params.Search,
-		Country:     params.Country,
-		City:        params.City,
-		EmailDomain: params.EmailDomain,
-		SortField:   params.SortField,
-		SortOrder:   params.SortOrder,
+		Offset:        offset,
+		Limit:         pageSize,
+		KYCStatus:     params.KYCStatus,
+		RiskMin:       riskMin,
+		RiskMax:       riskMax,
+		Search:        params.Search,
+		Country:       params.Country,
+		City:          params.City,
+		EmailDomain:   params.EmailDomain,
+		SortField:     params.SortField,
+		SortOrder:     defaultSortOrder(s.sorts.Users, repository.UserSortFields, params.SortField, params.SortOrder),
+		CreatedAfter:  params.CreatedAfter,
+		CreatedBefore: params.CreatedBefore,
+		Cursor:        params.Cursor,
+		After:         params.After,
 	})
 	if err != nil {
 		return UsersPage{}, err
 	}
 
+	pagination := buildPaginationMeta(page, pageSize, result.Total)
+	pagination.NextCursor = result.NextCursor
 	return UsersPage{
 		Items:      result.Items,
-		Pagination: buildPaginationMeta(page, pageSize, result.Total),
+		Pagination: pagination,
 	}, nil
 }
 
@@ -162,19 +263,27 @@ func (s *RelationshipService) ListTransactions(ctx context.Context, params ListT
 	}
 
 	result, err := s.repo.ListTransactions(ctx, repository.ListTransactionsOptions{
-		Offset:    offset,
-		Limit:     pageSize,
-		UserID:    params.UserID,
-		Status:    params.Status,
-		Type:      params.Type,
-		MinAmount: minAmount,
-		MaxAmount: maxAmount,
-		Search:    params.Search,
-		StartTs:   params.StartTime,
-		EndTs:     params.EndTime,
-		Channel:   params.Channel,
-		SortField: params.SortField,
-		SortOrder: params.SortOrder,
+		Offset:                offset,
+		Limit:                 pageSize,
+		UserID:                params.UserID,
+		Status:                params.Status,
+		Type:                  canonicalValue(s.synonyms.Types, params.Type),
+		MinAmount:             minAmount,
+		MaxAmount:             maxAmount,
+		Search:                params.Search,
+		StartTs:               params.StartTime,
+		EndTs:                 params.EndTime,
+		Channel:               canonicalValue(s.synonyms.Channels, params.Channel),
+		Currency:              params.Currency,
+		DeviceID:              params.DeviceID,
+		IPAddress:             params.IPAddress,
+		IncludeLinked:         params.IncludeLinked,
+		SenderRiskMin:         riskBound(params.SenderRiskMin),
+		ReceiverRiskMin:       riskBound(params.ReceiverRiskMin),
+		AnyParticipantRiskMin: riskBound(params.AnyParticipantRiskMin),
+		ParticipantKYC:        params.ParticipantKYC,
+		SortField:             params.SortField,
+		SortOrder:             defaultSortOrder(s.sorts.Transactions, repository.TransactionSortFields, params.SortField, params.SortOrder),
 	})
 	if err != nil {
 		return TransactionsPage{}, err
@@ -188,8 +297,42 @@ func (s *RelationshipService) ListTransactions(ctx context.Context, params ListT
 
 // UpsertUser ingests a user payload, derives attributes, and persists graph mutations.
 func (s *RelationshipService) UpsertUser(ctx context.Context, input UserInput) error {
+	user, err := s.buildUser(input)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpsertUser(ctx, user)
+}
+
+// UpsertUsers ingests user payloads like UpsertUser, but persists them in a single
+// graph write. Inputs without an ID are not written; they are reported in the returned
+// error once the valid inputs have been persisted.
+func (s *RelationshipService) UpsertUsers(ctx context.Context, inputs []UserInput) (repository.BatchWriteResult, error) {
+	users := make([]domain.User, 0, len(inputs))
+	var invalid []error
+	for i, input := range inputs {
+		user, err := s.buildUser(input)
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("user %d: %w", i, err))
+			continue
+		}
+		users = append(users, user)
+	}
+	if len(users) == 0 {
+		return repository.BatchWriteResult{}, errors.Join(invalid...)
+	}
+	result, err := s.repo.UpsertUsers(ctx, users)
+	if err != nil {
+		return result, err
+	}
+	return result, errors.Join(invalid...)
+}
+
+// buildUser normalizes input into the user written to the graph, with its derived
+// attributes.
+func (s *RelationshipService) buildUser(input UserInput) (domain.User, error) {
 	if input.ID == "" {
-		return fmt.Errorf("user ID is required")
+		return domain.User{}, fmt.Errorf("user ID is required")
 	}
 
 	now := s.nowFn().UTC()
@@ -213,6 +356,9 @@ func (s *RelationshipService) UpsertUser(ctx context.Context, input UserInput) e
 		RiskScore:   input.RiskScore,
 		CreatedAt:   createdAt,
 		UpdatedAt:   updatedAt,
+
+		CreatedAtDefaulted: input.CreatedAt == nil,
+		UpdatedAtDefaulted: input.UpdatedAt == nil,
 	}
 
 	paymentMethods := make([]domain.PaymentMethod, 0, len(input.PaymentMethods))
@@ -227,24 +373,138 @@ func (s *RelationshipService) UpsertUser(ctx context.Context, input UserInput) e
 			LastUsedAt:  pm.LastUsedAt,
 		})
 	}
-	user.PaymentMethods = paymentMethods
+	var err error
+	if user.PaymentMethods, err = s.limitPaymentMethods(input.ID, paymentMethods); err != nil {
+		return domain.User{}, err
+	}
 
 	attrs := s.attributes.FromUser(input)
 	if len(input.Attributes) > 0 {
-		attrs = append(attrs, convertCustomAttributes(input.Attributes)...)
+		attrs = append(attrs, s.customAttributes(input.Attributes)...)
+	}
+	if user.Attributes, err = s.limitAttributes("user", input.ID, attrs); err != nil {
+		return domain.User{}, err
 	}
-	user.Attributes = attrs
+	return user, nil
+}
 
-	return s.repo.UpsertUser(ctx, user)
+// customAttributes converts caller supplied attributes, normalising and hashing
+// registered custom types when the generator supports it.
+func (s *RelationshipService) customAttributes(inputs []AttributeInput) []domain.Attribute {
+	if custom, ok := s.attributes.(customAttributeGenerator); ok {
+		return custom.FromCustom(inputs)
+	}
+	return convertCustomAttributes(inputs)
+}
+
+// ErrNoAttributes reports an attribute update with no usable attribute in it.
+var ErrNoAttributes = errors.New("at least one attribute with a type and value is required")
+
+// AddUserAttributes links attributes to an existing user without a full profile upsert,
+// converting them as UpsertUser converts its Attributes. It returns how many were not
+// linked to the user before.
+func (s *RelationshipService) AddUserAttributes(ctx context.Context, userID string, inputs []AttributeInput) (int, error) {
+	attrs := s.customAttributes(inputs)
+	if len(attrs) == 0 {
+		return 0, ErrNoAttributes
+	}
+	attrs, err := s.limitAttributes("user", userID, attrs)
+	if err != nil {
+		return 0, err
+	}
+	return s.repo.AddUserAttributes(ctx, userID, attrs)
+}
+
+// GetUser returns a user's full profile with its attributes and payment methods.
+func (s *RelationshipService) GetUser(ctx context.Context, userID string) (domain.User, error) {
+	return s.repo.GetUser(ctx, userID)
+}
+
+// DeleteUser removes a user and its relationships, and with purgeOrphans the attributes
+// and payment methods only it was linked to. It returns how many of those were removed.
+func (s *RelationshipService) DeleteUser(ctx context.Context, userID string, purgeOrphans bool) (int, error) {
+	return s.repo.DeleteUser(ctx, userID, purgeOrphans)
 }
 
 // UpsertTransaction ingests a transaction payload, deriving edges and attributes before persisting.
 func (s *RelationshipService) UpsertTransaction(ctx context.Context, input TransactionInput) error {
+	tx, attrs, err := s.buildTransaction(input)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpsertTransaction(ctx, tx, attrs)
+}
+
+// GetTransaction returns a stored transaction with its participants and metadata.
+func (s *RelationshipService) GetTransaction(ctx context.Context, txID string) (domain.Transaction, error) {
+	return s.repo.GetTransaction(ctx, txID)
+}
+
+// DeleteTransaction removes a transaction with its edges and the links other
+// transactions hold to it, returning how many LINKED_TO edges went with it.
+func (s *RelationshipService) DeleteTransaction(ctx context.Context, txID string) (int, error) {
+	return s.repo.DeleteTransaction(ctx, txID)
+}
+
+// UpsertTransactions ingests transaction payloads like UpsertTransaction, but persists
+// them in a single graph write, in input order. Invalid inputs are not written; they are
+// reported in the returned error alongside any the repository rejected.
+func (s *RelationshipService) UpsertTransactions(ctx context.Context, inputs []TransactionInput) (repository.BatchWriteResult, error) {
+	txs := make([]domain.Transaction, 0, len(inputs))
+	attrs := make([][]domain.Attribute, 0, len(inputs))
+	var invalid []error
+	for _, input := range inputs {
+		tx, txAttrs, err := s.buildTransaction(input)
+		if err != nil {
+			invalid = append(invalid, fmt.Errorf("transaction %s: %w", input.ID, err))
+			continue
+		}
+		txs = append(txs, tx)
+		attrs = append(attrs, txAttrs)
+	}
+	if len(txs) == 0 {
+		return repository.BatchWriteResult{}, errors.Join(invalid...)
+	}
+	result, err := s.repo.UpsertTransactions(ctx, txs, attrs)
+	return result, errors.Join(append(invalid, err)...)
+}
+
+// buildTransaction validates and canonicalizes input into the transaction written to
+// the graph, with its derived attributes.
+func (s *RelationshipService) buildTransaction(input TransactionInput) (domain.Transaction, []domain.Attribute, error) {
 	if input.ID == "" {
-		return fmt.Errorf("transaction ID is required")
+		return domain.Transaction{}, nil, fmt.Errorf("transaction ID is required")
 	}
-	if input.SenderUserID == "" || input.ReceiverUserID == "" {
-		return fmt.Errorf("sender and receiver user IDs are required")
+	input.Type = canonicalValue(s.synonyms.Types, input.Type)
+	input.Channel = canonicalValue(s.synonyms.Channels, input.Channel)
+	monetary := domain.IsMonetaryType(input.Type)
+	if monetary && (input.SenderUserID == "" || input.ReceiverUserID == "") {
+		return domain.Transaction{}, nil, fmt.Errorf("sender and receiver user IDs are required")
+	}
+	if !monetary {
+		if input.SenderUserID == "" {
+			return domain.Transaction{}, nil, fmt.Errorf("sender user ID is required for %s events", input.Type)
+		}
+		if input.Amount != 0 || input.Currency != "" {
+			return domain.Transaction{}, nil, fmt.Errorf("amount and currency are not allowed on %s events", input.Type)
+		}
+		if input.ReversalOfTransactionID != "" {
+			return domain.Transaction{}, nil, fmt.Errorf("%s events cannot reverse a transaction", input.Type)
+		}
+	}
+	if input.ReversalOfTransactionID == input.ID {
+		return domain.Transaction{}, nil, fmt.Errorf("a transaction cannot reverse itself")
+	}
+	var ok bool
+	if input.OriginatorBIC != "" {
+		if input.OriginatorBIC, ok = NormalizeBIC(input.OriginatorBIC); !ok {
+			return domain.Transaction{}, nil, fmt.Errorf("invalid originator BIC %q", input.OriginatorBIC)
+		}
+	}
+	if input.BeneficiaryBIC != "" {
+		if input.BeneficiaryBIC, ok = NormalizeBIC(input.BeneficiaryBIC); !ok {
+			return domain.Transaction{}, nil, fmt.Errorf("invalid beneficiary BIC %q", input.BeneficiaryBIC)
+		}
 	}
 
 	now := s.nowFn().UTC()
@@ -269,24 +529,90 @@ func (s *RelationshipService) UpsertTransaction(ctx context.Context, input Trans
 		IPAddress:       input.IPAddress,
 		DeviceID:        input.DeviceID,
 		PaymentMethodID: input.PaymentMethodID,
+		OriginatorBIC:   input.OriginatorBIC,
+		BeneficiaryBIC:  input.BeneficiaryBIC,
+		Monetary:        monetary,
 		Timestamp:       input.Timestamp.UTC(),
 		Metadata:        input.Metadata,
 		CreatedAt:       createdAt,
 		UpdatedAt:       updatedAt,
+		ReversalOf:      input.ReversalOfTransactionID,
+
+		CreatedAtDefaulted: input.CreatedAt == nil,
+		UpdatedAtDefaulted: input.UpdatedAt == nil,
 	}
 
-	attrs := s.attributes.FromTransaction(input)
-	return s.repo.UpsertTransaction(ctx, tx, attrs)
+	attrs, err := s.limitAttributes("transaction", input.ID, s.attributes.FromTransaction(input))
+	if err != nil {
+		return domain.Transaction{}, nil, err
+	}
+	return tx, attrs, nil
+}
+
+// LookupUsers resolves a batch of user IDs to summaries, in request order.
+func (s *RelationshipService) LookupUsers(ctx context.Context, ids []string) ([]domain.UserLookup, error) {
+	return s.repo.LookupUsers(ctx, ids)
+}
+
+// LookupTransactions resolves a batch of transaction IDs to summaries, in request order.
+func (s *RelationshipService) LookupTransactions(ctx context.Context, ids []string) ([]domain.TransactionLookup, error) {
+	return s.repo.LookupTransactions(ctx, ids)
 }
 
 // GetUserRelationships fetches relationship data for the provided user ID.
-func (s *RelationshipService) GetUserRelationships(ctx context.Context, userID string) (domain.UserRelationships, error) {
-	return s.repo.FetchUserRelationships(ctx, userID)
+func (s *RelationshipService) GetUserRelationships(ctx context.Context, userID string, opts repository.UserRelationshipOptions) (domain.UserRelationships, error) {
+	return s.repo.FetchUserRelationships(ctx, userID, opts)
+}
+
+// GetUserRelationshipCounts returns per-type relationship counts for the provided user ID.
+func (s *RelationshipService) GetUserRelationshipCounts(ctx context.Context, userID string) (domain.UserRelationshipCounts, error) {
+	return s.repo.UserRelationshipCounts(ctx, userID)
+}
+
+// RecomputeTransactionLinks re-derives a stored transaction's attributes with the current
+// generator and rebuilds its LINKED_TO edges, returning the new links.
+func (s *RelationshipService) RecomputeTransactionLinks(ctx context.Context, txID string) ([]domain.LinkedTransaction, error) {
+	tx, err := s.repo.GetTransaction(ctx, txID)
+	if err != nil {
+		return nil, err
+	}
+	attrs := s.attributes.FromTransaction(TransactionInput{
+		ID:              tx.ID,
+		SenderUserID:    tx.SenderUserID,
+		ReceiverUserID:  tx.ReceiverUserID,
+		Amount:          tx.Amount,
+		Currency:        tx.Currency,
+		Type:            tx.Type,
+		Status:          tx.Status,
+		Channel:         tx.Channel,
+		IPAddress:       tx.IPAddress,
+		DeviceID:        tx.DeviceID,
+		PaymentMethodID: tx.PaymentMethodID,
+		OriginatorBIC:   tx.OriginatorBIC,
+		BeneficiaryBIC:  tx.BeneficiaryBIC,
+		Timestamp:       tx.Timestamp,
+	})
+	return s.repo.RecomputeTransactionLinks(ctx, txID, attrs)
+}
+
+// GetUserUniqueAttributes returns the attributes no other user or transaction shares.
+func (s *RelationshipService) GetUserUniqueAttributes(ctx context.Context, userID string) ([]domain.Attribute, error) {
+	return s.repo.UserUniqueAttributes(ctx, userID)
 }
 
 // GetTransactionRelationships fetches relationship data for the provided transaction ID.
-func (s *RelationshipService) GetTransactionRelationships(ctx context.Context, txID string) (domain.TransactionRelationships, error) {
-	return s.repo.FetchTransactionRelationships(ctx, txID)
+func (s *RelationshipService) GetTransactionRelationships(ctx context.Context, txID string, opts repository.TransactionRelationshipOptions) (domain.TransactionRelationships, error) {
+	return s.repo.FetchTransactionRelationships(ctx, txID, opts)
+}
+
+// PruneOrphans removes Attribute and PaymentMethod nodes left without relationships.
+func (s *RelationshipService) PruneOrphans(ctx context.Context, opts repository.BatchOptions) (int, error) {
+	return s.repo.PruneOrphansWithOptions(ctx, opts)
+}
+
+// GetStats returns the write-maintained graph counters.
+func (s *RelationshipService) GetStats(ctx context.Context) (domain.GraphStats, error) {
+	return s.repo.GetStats(ctx)
 }
 
 func normalizePagination(page, pageSize int) (int, int) {
@@ -302,6 +628,14 @@ func normalizePagination(page, pageSize int) (int, int) {
 	return page, pageSize
 }
 
+// riskBound clamps an optional risk score filter to [0, 1], with nil meaning 0.
+func riskBound(value *float64) float64 {
+	if value == nil {
+		return 0
+	}
+	return clampFloat(*value, 0, 1)
+}
+
 func buildPaginationMeta(page, pageSize int, total int64) PaginationMeta {
 	totalPages := 0
 	if pageSize > 0 {
@@ -343,6 +677,7 @@ func convertCustomAttributes(attrs []AttributeInput) []domain.Attribute {
 			Value:           attr.Value,
 			RawValue:        attr.RawValue,
 			ConfidenceScore: conf,
+			Origin:          domain.AttributeOriginUser,
 		})
 	}
 	return result
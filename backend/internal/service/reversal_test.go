@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestUpsertTransactionWritesReversalEdge(t *testing.T) {
+	client := &recordingClient{}
+	svc := NewRelationshipService(repository.New(client), nil)
+	reversal := testTransactions(2)[1]
+	reversal.ReversalOfTransactionID = "TX-1"
+
+	if err := svc.UpsertTransaction(context.Background(), reversal); err != nil {
+		t.Fatalf("UpsertTransaction() error = %v", err)
+	}
+	if len(client.writes) != 1 {
+		t.Fatalf("ran %d writes, want 1", len(client.writes))
+	}
+	row := client.writes[0]["transaction"].(map[string]any)
+	if row["reversalOf"] != "TX-1" {
+		t.Errorf("reversalOf = %v, want TX-1", row["reversalOf"])
+	}
+	// The stored property lets a reversal ingested before its original be linked later.
+	if got := row["props"].(map[string]any)["reversalOfTransactionId"]; got != "TX-1" {
+		t.Errorf("reversalOfTransactionId = %v, want TX-1", got)
+	}
+	for _, fragment := range []string{
+		"MATCH (original:Transaction {transactionId: row.reversalOf})",
+		"MERGE (t)-[:REVERSES]->(original)",
+		"MERGE (reversal)-[:REVERSES]->(t)",
+	} {
+		if !strings.Contains(client.cyphers[0], fragment) {
+			t.Errorf("upsert lacks %q", fragment)
+		}
+	}
+}
+
+func TestUpsertTransactionWithoutReversal(t *testing.T) {
+	client := &recordingClient{}
+	svc := NewRelationshipService(repository.New(client), nil)
+	if err := svc.UpsertTransaction(context.Background(), testTransactions(1)[0]); err != nil {
+		t.Fatalf("UpsertTransaction() error = %v", err)
+	}
+	row := client.writes[0]["transaction"].(map[string]any)
+	// An empty reversalOf matches no original, and the nil property removes a stale one.
+	if row["reversalOf"] != "" || row["props"].(map[string]any)["reversalOfTransactionId"] != nil {
+		t.Errorf("reversalOf = %v, property = %v, want neither set", row["reversalOf"], row["props"].(map[string]any)["reversalOfTransactionId"])
+	}
+}
+
+func TestUpsertTransactionRejectsSelfReversal(t *testing.T) {
+	client := &recordingClient{}
+	svc := NewRelationshipService(repository.New(client), nil)
+	tx := testTransactions(1)[0]
+	tx.ReversalOfTransactionID = tx.ID
+	if err := svc.UpsertTransaction(context.Background(), tx); err == nil {
+		t.Fatal("UpsertTransaction() succeeded for a transaction reversing itself")
+	}
+	if len(client.writes) != 0 {
+		t.Errorf("ran %d writes for a rejected transaction, want 0", len(client.writes))
+	}
+}
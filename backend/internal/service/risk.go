@@ -0,0 +1,112 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// RiskModel scores a user from graph features and explains each feature's contribution.
+type RiskModel interface {
+	Name() string
+	// FeatureOptions returns the windows and thresholds the model wants features collected with.
+	FeatureOptions(now time.Time) repository.RiskFeatureOptions
+	Explain(features domain.UserRiskFeatures) domain.RiskExplanation
+}
+
+// DefaultRiskModel is a weighted sum of four normalised features: transaction velocity,
+// counterparty risk, shared-attribute density and structuring.
+type DefaultRiskModel struct{}
+
+const (
+	// structuringThreshold is the reporting threshold amounts are structured to stay under.
+	structuringThreshold = 10000.0
+	// structuringBand is how far below the threshold an amount counts as structuring.
+	structuringBand   = 0.1
+	structuringWindow = 30 * 24 * time.Hour
+
+	velocitySaturation       = 20.0 // transactions in 7 days scoring 1.0
+	sharedUsersSaturation    = 5.0  // users sharing attributes scoring 1.0
+	structuringSaturation    = 3.0  // structuring transactions scoring 1.0
+	velocityWeight           = 0.3
+	counterpartyRiskWeight   = 0.3
+	sharedAttributeWeight    = 0.25
+	structuringFeatureWeight = 0.15
+)
+
+func (DefaultRiskModel) Name() string { return "default-v1" }
+
+func (DefaultRiskModel) FeatureOptions(now time.Time) repository.RiskFeatureOptions {
+	return repository.RiskFeatureOptions{
+		Now:                now,
+		StructuringFloor:   structuringThreshold * (1 - structuringBand),
+		StructuringCeiling: structuringThreshold,
+		StructuringWindow:  structuringWindow,
+	}
+}
+
+func (m DefaultRiskModel) Explain(f domain.UserRiskFeatures) domain.RiskExplanation {
+	components := []domain.RiskComponent{
+		riskComponent("velocity", float64(f.Transactions7d), saturate(float64(f.Transactions7d), velocitySaturation), velocityWeight,
+			fmt.Sprintf("%d transactions in the last 7 days (%d in the last 24 hours)", f.Transactions7d, f.Transactions24h)),
+		riskComponent("counterpartyRisk", f.AvgCounterpartyRisk, clampFloat(f.AvgCounterpartyRisk, 0, 1), counterpartyRiskWeight,
+			fmt.Sprintf("average risk %.2f (max %.2f) across %d counterparties", f.AvgCounterpartyRisk, f.MaxCounterpartyRisk, f.Counterparties)),
+		riskComponent("sharedAttributeDensity", float64(f.SharedAttributeUsers), saturate(float64(f.SharedAttributeUsers), sharedUsersSaturation), sharedAttributeWeight,
+			fmt.Sprintf("%d attributes shared with %d other users", f.SharedAttributes, f.SharedAttributeUsers)),
+		riskComponent("structuring", float64(f.StructuringTransactions), saturate(float64(f.StructuringTransactions), structuringSaturation), structuringFeatureWeight,
+			fmt.Sprintf("%d outgoing transactions within %.0f%% below %.0f in the last 30 days", f.StructuringTransactions, structuringBand*100, structuringThreshold)),
+	}
+
+	total := 0.0
+	for _, c := range components {
+		total += c.Contribution
+	}
+	return domain.RiskExplanation{
+		UserID:        f.UserID,
+		Model:         m.Name(),
+		StoredScore:   f.StoredScore,
+		ComputedScore: clampFloat(total, 0, 1),
+		Components:    components,
+	}
+}
+
+func riskComponent(name string, value, score, weight float64, detail string) domain.RiskComponent {
+	return domain.RiskComponent{
+		Name:         name,
+		Value:        value,
+		Score:        score,
+		Weight:       weight,
+		Contribution: math.Round(score*weight*1e4) / 1e4,
+		Detail:       detail,
+	}
+}
+
+func saturate(value, saturation float64) float64 {
+	if saturation <= 0 {
+		return 0
+	}
+	return clampFloat(value/saturation, 0, 1)
+}
+
+// WithRiskModel overrides the model used by ExplainUserRisk.
+func (s *RelationshipService) WithRiskModel(model RiskModel) {
+	if model != nil {
+		s.riskModel = model
+	}
+}
+
+// ExplainUserRisk returns the per-feature breakdown of a user's risk under the configured model.
+func (s *RelationshipService) ExplainUserRisk(ctx context.Context, userID string) (domain.RiskExplanation, error) {
+	if userID == "" {
+		return domain.RiskExplanation{}, fmt.Errorf("user ID is required")
+	}
+	features, err := s.repo.FetchUserRiskFeatures(ctx, userID, s.riskModel.FeatureOptions(s.nowFn()))
+	if err != nil {
+		return domain.RiskExplanation{}, err
+	}
+	return s.riskModel.Explain(features), nil
+}
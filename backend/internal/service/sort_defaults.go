@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// SortDefaults sets the direction, ASC or DESC, a listing sorts by a field in when the
+// request gives no sortOrder, e.g. riskScore descending but fullName ascending. Keys are
+// sortField names, ignoring case. Fields without an entry keep the built-in default:
+// ascending for users and descending for transactions.
+type SortDefaults struct {
+	Users        map[string]string
+	Transactions map[string]string
+}
+
+// WithSortDefaults applies defaults to user and transaction listings. It rejects fields
+// the listings cannot sort by and directions other than ASC and DESC.
+func (s *RelationshipService) WithSortDefaults(defaults SortDefaults) error {
+	users, err := foldSortDefaults("user", defaults.Users, repository.UserSortFields)
+	if err != nil {
+		return err
+	}
+	transactions, err := foldSortDefaults("transaction", defaults.Transactions, repository.TransactionSortFields)
+	if err != nil {
+		return err
+	}
+	s.sorts = SortDefaults{Users: users, Transactions: transactions}
+	return nil
+}
+
+func foldSortDefaults(kind string, defaults map[string]string, fields []string) (map[string]string, error) {
+	if len(defaults) == 0 {
+		return nil, nil
+	}
+	folded := make(map[string]string, len(defaults))
+	for field, order := range defaults {
+		key, ok := sortFieldKey(fields, field)
+		if !ok {
+			return nil, fmt.Errorf("unknown %s sort field %q; expected one of %s", kind, field, strings.Join(fields, ", "))
+		}
+		order = strings.ToUpper(strings.TrimSpace(order))
+		if order != "ASC" && order != "DESC" {
+			return nil, fmt.Errorf("invalid sort order %q for %s sort field %s; expected ASC or DESC", order, kind, field)
+		}
+		folded[key] = order
+	}
+	return folded, nil
+}
+
+// defaultSortOrder returns order, or when it is empty the configured default for field.
+// An empty or unknown field sorts as the first of fields, so it takes that one's default.
+func defaultSortOrder(defaults map[string]string, fields []string, field, order string) string {
+	if strings.TrimSpace(order) != "" || len(defaults) == 0 {
+		return order
+	}
+	key, ok := sortFieldKey(fields, field)
+	if !ok {
+		key = strings.ToLower(fields[0])
+	}
+	return defaults[key]
+}
+
+func sortFieldKey(fields []string, field string) (string, bool) {
+	field = strings.TrimSpace(field)
+	for _, candidate := range fields {
+		if strings.EqualFold(candidate, field) {
+			return strings.ToLower(candidate), true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+func TestDefaultSortOrder(t *testing.T) {
+	defaults := map[string]string{"riskscore": "DESC", "userid": "DESC", "fullname": "ASC"}
+	fields := repository.UserSortFields
+	tests := []struct {
+		name     string
+		defaults map[string]string
+		field    string
+		order    string
+		want     string
+	}{
+		{name: "explicit order wins", defaults: defaults, field: "riskScore", order: "asc", want: "asc"},
+		{name: "configured field default", defaults: defaults, field: "riskScore", want: "DESC"},
+		{name: "field matched without regard to case", defaults: defaults, field: "FULLNAME", want: "ASC"},
+		{name: "unconfigured field keeps the built-in default", defaults: defaults, field: "createdAt", want: ""},
+		{name: "empty field uses the first field's default", defaults: defaults, field: "", want: "DESC"},
+		{name: "unknown field uses the first field's default", defaults: defaults, field: "shoeSize", want: "DESC"},
+		{name: "no defaults leaves the order empty", field: "riskScore", want: ""},
+		{name: "blank order counts as none", defaults: defaults, field: "riskScore", order: "  ", want: "DESC"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := defaultSortOrder(tt.defaults, fields, tt.field, tt.order); got != tt.want {
+				t.Errorf("defaultSortOrder(%q, %q) = %q, want %q", tt.field, tt.order, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldSortDefaults(t *testing.T) {
+	folded, err := foldSortDefaults("transaction", map[string]string{"Amount": "desc", " timestamp ": " asc "}, repository.TransactionSortFields)
+	if err != nil {
+		t.Fatalf("foldSortDefaults() error = %v", err)
+	}
+	want := map[string]string{"amount": "DESC", "timestamp": "ASC"}
+	if len(folded) != len(want) {
+		t.Fatalf("foldSortDefaults() = %v, want %v", folded, want)
+	}
+	for key, order := range want {
+		if folded[key] != order {
+			t.Errorf("folded[%q] = %q, want %q", key, folded[key], order)
+		}
+	}
+
+	if folded, err := foldSortDefaults("user", nil, repository.UserSortFields); err != nil || folded != nil {
+		t.Errorf("foldSortDefaults(nil) = %v, %v, want nil, nil", folded, err)
+	}
+	if _, err := foldSortDefaults("user", map[string]string{"shoeSize": "ASC"}, repository.UserSortFields); err == nil {
+		t.Error("unknown field was accepted")
+	}
+	if _, err := foldSortDefaults("user", map[string]string{"riskScore": "DOWN"}, repository.UserSortFields); err == nil {
+		t.Error("bad direction was accepted")
+	}
+}
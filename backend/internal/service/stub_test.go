@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vanshika/fintrace/backend/internal/domain"
+	"github.com/vanshika/fintrace/backend/internal/repository"
+)
+
+// memoryRepository keeps written users and transactions in memory and lists them back with the
+// type and channel filters applied, enough to check what ingest stores and what a filter
+// finds. Calls it does not override panic through the nil embedded interface.
+type memoryRepository struct {
+	GraphRepository
+	users        []domain.User
+	transactions []domain.Transaction
+	listed       []repository.ListTransactionsOptions
+}
+
+func (m *memoryRepository) UpsertUser(_ context.Context, user domain.User) error {
+	m.users = append(m.users, user)
+	return nil
+}
+
+func (m *memoryRepository) UpsertTransaction(_ context.Context, tx domain.Transaction, _ []domain.Attribute) error {
+	m.transactions = append(m.transactions, tx)
+	return nil
+}
+
+func (m *memoryRepository) ListTransactions(_ context.Context, opts repository.ListTransactionsOptions) (domain.TransactionListResult, error) {
+	m.listed = append(m.listed, opts)
+	var result domain.TransactionListResult
+	for _, tx := range m.transactions {
+		if opts.Type != "" && !strings.EqualFold(tx.Type, opts.Type) {
+			continue
+		}
+		if opts.Channel != "" && !strings.EqualFold(tx.Channel, opts.Channel) {
+			continue
+		}
+		result.Items = append(result.Items, domain.TransactionSummary{ID: tx.ID, Type: tx.Type, Channel: tx.Channel})
+	}
+	result.Total = int64(len(result.Items))
+	return result, nil
+}
+
+// failingRepository fails every write touching an ID in fail, as the graph would reject
+// a bad record, and counts the records it wrote. A batch with one bad record fails
+// whole. It is safe for concurrent use by the ingestion workers.
+type failingRepository struct {
+	GraphRepository
+	fail map[string]bool
+
+	mu      sync.Mutex
+	written []string
+}
+
+func (f *failingRepository) write(ids ...string) error {
+	for _, id := range ids {
+		if f.fail[id] {
+			return fmt.Errorf("constraint violated by %s", id)
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written = append(f.written, ids...)
+	return nil
+}
+
+func (f *failingRepository) UpsertUser(_ context.Context, user domain.User) error {
+	return f.write(user.ID)
+}
+
+func (f *failingRepository) UpsertUsers(_ context.Context, users []domain.User) (repository.BatchWriteResult, error) {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	if err := f.write(ids...); err != nil {
+		return repository.BatchWriteResult{}, err
+	}
+	return repository.BatchWriteResult{Written: len(ids)}, nil
+}
+
+func (f *failingRepository) UpsertTransaction(_ context.Context, tx domain.Transaction, _ []domain.Attribute) error {
+	return f.write(tx.ID)
+}
+
+func (f *failingRepository) UpsertTransactions(_ context.Context, txs []domain.Transaction, _ [][]domain.Attribute) (repository.BatchWriteResult, error) {
+	ids := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		ids = append(ids, tx.ID)
+	}
+	if err := f.write(ids...); err != nil {
+		return repository.BatchWriteResult{}, err
+	}
+	return repository.BatchWriteResult{Written: len(ids)}, nil
+}
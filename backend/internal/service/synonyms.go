@@ -0,0 +1,44 @@
+package service
+
+import "strings"
+
+// ValueSynonyms maps the spellings upstream systems use for transaction types and
+// channels to one canonical value each, e.g. "wire" and "transfer" to "WIRE_TRANSFER".
+// Keys are synonyms and values canonical names. Matching ignores case and surrounding
+// space; values without an entry pass through unchanged.
+type ValueSynonyms struct {
+	Types    map[string]string
+	Channels map[string]string
+}
+
+// WithValueSynonyms canonicalizes transaction types and channels before they are stored,
+// and in transaction list filters so a synonym finds the canonical value.
+func (s *RelationshipService) WithValueSynonyms(synonyms ValueSynonyms) {
+	s.synonyms = ValueSynonyms{
+		Types:    foldSynonyms(synonyms.Types),
+		Channels: foldSynonyms(synonyms.Channels),
+	}
+}
+
+func foldSynonyms(synonyms map[string]string) map[string]string {
+	if len(synonyms) == 0 {
+		return nil
+	}
+	folded := make(map[string]string, len(synonyms))
+	for synonym, canonical := range synonyms {
+		folded[synonymKey(synonym)] = canonical
+	}
+	return folded
+}
+
+func synonymKey(value string) string {
+	return strings.ToUpper(strings.TrimSpace(value))
+}
+
+// canonicalValue returns the canonical value value is a synonym for, or value itself.
+func canonicalValue(synonyms map[string]string, value string) string {
+	if canonical, ok := synonyms[synonymKey(value)]; ok {
+		return canonical
+	}
+	return value
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestValueSynonymsCanonicalizeIngestAndFilters(t *testing.T) {
+	repo := &memoryRepository{}
+	svc := NewRelationshipService(repo, nil)
+	svc.WithValueSynonyms(ValueSynonyms{
+		Types:    map[string]string{"wire": "WIRE_TRANSFER", "transfer": "WIRE_TRANSFER"},
+		Channels: map[string]string{"web": "ONLINE"},
+	})
+
+	ctx := context.Background()
+	for i, spelling := range []string{"wire", "WIRE_TRANSFER", " Transfer "} {
+		input := TransactionInput{
+			ID:             fmt.Sprintf("TX-%d", i+1),
+			SenderUserID:   "U1",
+			ReceiverUserID: "U2",
+			Amount:         10,
+			Currency:       "USD",
+			Type:           spelling,
+			Channel:        "WEB",
+			Timestamp:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+		if err := svc.UpsertTransaction(ctx, input); err != nil {
+			t.Fatalf("UpsertTransaction(%q) error = %v", spelling, err)
+		}
+	}
+	for _, tx := range repo.transactions {
+		if tx.Type != "WIRE_TRANSFER" || tx.Channel != "ONLINE" {
+			t.Errorf("stored %s as type %q, channel %q, want WIRE_TRANSFER, ONLINE", tx.ID, tx.Type, tx.Channel)
+		}
+	}
+
+	// A filter spelled as any synonym finds every stored transaction.
+	for _, spelling := range []string{"wire", "transfer", "WIRE_TRANSFER"} {
+		page, err := svc.ListTransactions(ctx, ListTransactionsParams{Type: spelling, Channel: "web"})
+		if err != nil {
+			t.Fatalf("ListTransactions(%q) error = %v", spelling, err)
+		}
+		if got := len(page.Items); got != 3 {
+			t.Errorf("ListTransactions(type %q) = %d items, want 3", spelling, got)
+		}
+		if opts := repo.listed[len(repo.listed)-1]; opts.Type != "WIRE_TRANSFER" || opts.Channel != "ONLINE" {
+			t.Errorf("filter %q reached the repository as type %q, channel %q", spelling, opts.Type, opts.Channel)
+		}
+	}
+}
+
+func TestValueSynonymsPassThroughUnknownValues(t *testing.T) {
+	synonyms := foldSynonyms(map[string]string{" wire ": "WIRE_TRANSFER"})
+	if got := canonicalValue(synonyms, "WIRE"); got != "WIRE_TRANSFER" {
+		t.Errorf("canonicalValue(WIRE) = %q, want WIRE_TRANSFER", got)
+	}
+	if got := canonicalValue(synonyms, "card"); got != "card" {
+		t.Errorf("canonicalValue(card) = %q, want it unchanged", got)
+	}
+	if got := canonicalValue(nil, "wire"); got != "wire" {
+		t.Errorf("canonicalValue without synonyms = %q, want it unchanged", got)
+	}
+}
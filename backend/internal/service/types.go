@@ -64,10 +64,16 @@ type TransactionInput struct {
 	IPAddress       string
 	DeviceID        string
 	PaymentMethodID string
-	Timestamp       time.Time
-	Metadata        map[string]any
-	CreatedAt       *time.Time
-	UpdatedAt       *time.Time
+	// OriginatorBIC and BeneficiaryBIC are the BIC/SWIFT codes of the sending and
+	// receiving banks, when the transaction crossed banks.
+	OriginatorBIC  string
+	BeneficiaryBIC string
+	Timestamp      time.Time
+	Metadata       map[string]any
+	CreatedAt      *time.Time
+	UpdatedAt      *time.Time
+	// ReversalOfTransactionID names the transaction this one reverses, if any.
+	ReversalOfTransactionID string
 }
 
 // ToDomainAddress converts the AddressInput to a domain.Address value.
@@ -3,11 +3,15 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+
+	"github.com/vanshika/fintrace/backend/internal/repository"
 )
 
 // TaskError accumulates multiple errors produced during bulk ingestion.
@@ -43,38 +47,238 @@ func (e *TaskError) asError() error {
 	return e
 }
 
+// RecordError is a failure of the input records First through Last, as positions in the
+// slice passed to IngestUsers or IngestTransactions. First equals Last for a single
+// record; a failed batch covers all of its records.
+type RecordError struct {
+	First int
+	Last  int
+	Err   error
+}
+
+func (e *RecordError) Error() string {
+	if e.First == e.Last {
+		return fmt.Sprintf("record %d: %v", e.First, e.Err)
+	}
+	return fmt.Sprintf("records %d-%d: %v", e.First, e.Last, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
 // BulkIngestor processes large user and transaction datasets using worker pools.
 type BulkIngestor struct {
 	service *RelationshipService
 	workers int
+	// sessionPerWrite disables per-worker session reuse.
+	sessionPerWrite bool
+	// userBatchSize and transactionBatchSize are how many records each graph write
+	// carries; 1 writes them singly.
+	userBatchSize        int
+	transactionBatchSize int
+	// deadLetters, when set, receives records that failed instead of the returned error.
+	deadLetters DeadLetterSink
+	// orderedResults reports errors in input order, as RecordErrors.
+	orderedResults bool
 }
 
+const (
+	defaultUserBatchSize        = 500
+	defaultTransactionBatchSize = 500
+)
+
 // NewBulkIngestor creates a new BulkIngestor instance with the provided concurrency.
 func NewBulkIngestor(service *RelationshipService, workers int) *BulkIngestor {
 	if workers <= 0 {
 		workers = 4
 	}
 	return &BulkIngestor{
-		service: service,
-		workers: workers,
+		service:              service,
+		workers:              workers,
+		userBatchSize:        defaultUserBatchSize,
+		transactionBatchSize: defaultTransactionBatchSize,
 	}
 }
 
-// IngestUsers processes the provided user inputs concurrently.
-func (bi *BulkIngestor) IngestUsers(ctx context.Context, users []UserInput) error {
-	return bi.run(ctx, len(users), func(idx int) error {
-		return bi.withRetry(ctx, func() error {
-			return bi.service.UpsertUser(ctx, users[idx])
-		})
-	})
+// WithUserBatchSize sets how many users IngestUsers writes per graph statement (default
+// 500). A size of 1 writes every user with its own UpsertUser call; zero or less keeps
+// the default.
+func (bi *BulkIngestor) WithUserBatchSize(size int) *BulkIngestor {
+	if size > 0 {
+		bi.userBatchSize = size
+	}
+	return bi
+}
+
+// WithSessionPerWrite makes every write open its own graph session instead of each
+// worker reusing one. It exists mainly to compare the two modes.
+func (bi *BulkIngestor) WithSessionPerWrite(enabled bool) *BulkIngestor {
+	bi.sessionPerWrite = enabled
+	return bi
 }
 
-// IngestTransactions processes transaction inputs concurrently.
-func (bi *BulkIngestor) IngestTransactions(ctx context.Context, txs []TransactionInput) error {
-	return bi.run(ctx, len(txs), func(idx int) error {
-		return bi.withRetry(ctx, func() error {
-			return bi.service.UpsertTransaction(ctx, txs[idx])
+// WithTransactionBatchSize sets how many transactions IngestTransactions writes per graph
+// statement (default 500), with the same meaning of 1 and zero as WithUserBatchSize.
+func (bi *BulkIngestor) WithTransactionBatchSize(size int) *BulkIngestor {
+	if size > 0 {
+		bi.transactionBatchSize = size
+	}
+	return bi
+}
+
+// WithDeadLetterSink hands every record that fails to sink, with its error, instead of
+// returning the error, so one bad record does not fail the run. A batch that fails is
+// retried one record at a time first, so only the records that fail on their own are
+// dead-lettered. Errors writing to sink are still returned, as are cancellations.
+func (bi *BulkIngestor) WithDeadLetterSink(sink DeadLetterSink) *BulkIngestor {
+	bi.deadLetters = sink
+	return bi
+}
+
+// WithOrderedResults makes the returned TaskError list its errors in input order, each
+// a *RecordError naming the records it covers, so callers can map failures back to
+// their inputs. Without it, errors come in completion order and unwrapped, which needs
+// no bookkeeping and suits fire-and-forget ingestion.
+func (bi *BulkIngestor) WithOrderedResults(enabled bool) *BulkIngestor {
+	bi.orderedResults = enabled
+	return bi
+}
+
+// recordError ties err to the input records first through last in ordered mode.
+func (bi *BulkIngestor) recordError(first, last int, err error) error {
+	if err == nil || !bi.orderedResults {
+		return err
+	}
+	return &RecordError{First: first, Last: last, Err: err}
+}
+
+// IngestResult counts the outcome of a bulk ingestion run.
+type IngestResult struct {
+	// Written is the number of records written to the graph.
+	Written int
+	// Skipped is the number of records left untouched because their content hash
+	// matched the stored one (see repository.Repository.WithContentHashing).
+	Skipped int
+	// DeadLettered is the number of records handed to the dead-letter sink.
+	DeadLettered int
+}
+
+// ingestCounter tallies IngestResult fields from concurrent workers.
+type ingestCounter struct {
+	written      atomic.Int64
+	skipped      atomic.Int64
+	deadLettered atomic.Int64
+}
+
+// record counts the outcome of one write and returns err unless it only reports an
+// unchanged record.
+func (c *ingestCounter) record(err error) error {
+	switch {
+	case err == nil:
+		c.written.Add(1)
+	case errors.Is(err, repository.ErrUnchanged):
+		c.skipped.Add(1)
+	default:
+		return err
+	}
+	return nil
+}
+
+func (c *ingestCounter) result() IngestResult {
+	return IngestResult{
+		Written:      int(c.written.Load()),
+		Skipped:      int(c.skipped.Load()),
+		DeadLettered: int(c.deadLettered.Load()),
+	}
+}
+
+// IngestUsers processes the provided user inputs concurrently, in batches of the
+// configured user batch size.
+func (bi *BulkIngestor) IngestUsers(ctx context.Context, users []UserInput) (IngestResult, error) {
+	var counter ingestCounter
+	writeOne := func(svc *RelationshipService, idx int) error {
+		err := counter.record(bi.withRetry(ctx, func() error {
+			return svc.UpsertUser(ctx, users[idx])
+		}))
+		return bi.recordError(idx, idx, bi.deadLetter(ctx, &counter, err, func(sink DeadLetterSink) error {
+			return sink.WriteUser(users[idx], err)
+		}))
+	}
+	if bi.userBatchSize > 1 {
+		err := bi.runBatches(ctx, len(users), bi.userBatchSize, &counter, func(svc *RelationshipService, lo, hi int) (repository.BatchWriteResult, error) {
+			return svc.UpsertUsers(ctx, users[lo:hi])
+		}, writeOne)
+		return counter.result(), err
+	}
+	err := bi.run(ctx, len(users), writeOne)
+	return counter.result(), err
+}
+
+// IngestTransactions processes transaction inputs concurrently, in batches of the
+// configured transaction batch size. Transactions within a batch are written in order
+// and see each other, but a batch only links to other batches once they have committed,
+// so with several workers some links between concurrent batches can be missed; one
+// worker links every transaction as a sequential ingest would.
+func (bi *BulkIngestor) IngestTransactions(ctx context.Context, txs []TransactionInput) (IngestResult, error) {
+	var counter ingestCounter
+	writeOne := func(svc *RelationshipService, idx int) error {
+		err := counter.record(bi.withRetry(ctx, func() error {
+			return svc.UpsertTransaction(ctx, txs[idx])
+		}))
+		return bi.recordError(idx, idx, bi.deadLetter(ctx, &counter, err, func(sink DeadLetterSink) error {
+			return sink.WriteTransaction(txs[idx], err)
+		}))
+	}
+	if bi.transactionBatchSize > 1 {
+		err := bi.runBatches(ctx, len(txs), bi.transactionBatchSize, &counter, func(svc *RelationshipService, lo, hi int) (repository.BatchWriteResult, error) {
+			return svc.UpsertTransactions(ctx, txs[lo:hi])
+		}, writeOne)
+		return counter.result(), err
+	}
+	err := bi.run(ctx, len(txs), writeOne)
+	return counter.result(), err
+}
+
+// deadLetter hands a failed record to the dead-letter sink through write and counts it,
+// returning nil, when a sink is configured. Otherwise, and for cancellations, err is
+// returned unchanged.
+func (bi *BulkIngestor) deadLetter(ctx context.Context, counter *ingestCounter, err error, write func(DeadLetterSink) error) error {
+	if err == nil || bi.deadLetters == nil || ctx.Err() != nil {
+		return err
+	}
+	if sinkErr := write(bi.deadLetters); sinkErr != nil {
+		return errors.Join(err, sinkErr)
+	}
+	counter.deadLettered.Add(1)
+	return nil
+}
+
+// runBatches splits total records into batches of size and writes each with write,
+// retrying transient failures, while counting the outcome into counter. A batch's
+// error, possibly joining several records' errors, is reported once in the TaskError.
+// With a dead-letter sink, a failed batch is instead rewritten record by record with
+// writeOne, which counts and dead-letters each record itself.
+func (bi *BulkIngestor) runBatches(ctx context.Context, total, size int, counter *ingestCounter, write func(svc *RelationshipService, lo, hi int) (repository.BatchWriteResult, error), writeOne func(svc *RelationshipService, idx int) error) error {
+	batches := (total + size - 1) / size
+	return bi.run(ctx, batches, func(svc *RelationshipService, idx int) error {
+		lo, hi := idx*size, min((idx+1)*size, total)
+		var result repository.BatchWriteResult
+		err := bi.withRetry(ctx, func() error {
+			var err error
+			result, err = write(svc, lo, hi)
+			return err
 		})
+		if err != nil && bi.deadLetters != nil && ctx.Err() == nil {
+			var failed []error
+			for i := lo; i < hi; i++ {
+				failed = append(failed, writeOne(svc, i))
+			}
+			return errors.Join(failed...)
+		}
+		counter.written.Add(int64(result.Written))
+		counter.skipped.Add(int64(result.Unchanged))
+		return bi.recordError(lo, hi-1, err)
 	})
 }
 
@@ -124,18 +328,40 @@ func isRetryableError(err error) bool {
 	return false
 }
 
-func (bi *BulkIngestor) run(ctx context.Context, total int, workerFn func(idx int) error) error {
+func (bi *BulkIngestor) run(ctx context.Context, total int, workerFn func(svc *RelationshipService, idx int) error) error {
 	if total == 0 {
 		return nil
 	}
 	indexCh := make(chan int)
-	errCh := make(chan error, total)
+	errCh := make(chan error, total+bi.workers)
 	var wg sync.WaitGroup
+	// In ordered mode task errors are kept by index instead of sent on errCh; each index
+	// is handled by exactly one worker, so the slots need no locking.
+	var ordered []error
+	if bi.orderedResults {
+		ordered = make([]error, total)
+	}
 
 	worker := func() {
 		defer wg.Done()
+		svc, release, err := bi.workerService(ctx)
+		if err != nil {
+			errCh <- err
+			// Keep draining so the producer is never blocked on a dead worker.
+			for range indexCh {
+			}
+			return
+		}
+		defer func() {
+			// Release with a fresh context so sessions are closed even after cancellation.
+			_ = release(context.Background())
+		}()
 		for idx := range indexCh {
-			if err := workerFn(idx); err != nil {
+			if err := workerFn(svc, idx); err != nil {
+				if ordered != nil {
+					ordered[idx] = err
+					continue
+				}
 				select {
 				case errCh <- err:
 				case <-ctx.Done():
@@ -172,5 +398,20 @@ Loop:
 		}
 		taskErr.append(err)
 	}
+	for _, err := range ordered {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return err
+		}
+		taskErr.append(err)
+	}
 	return taskErr.asError()
 }
+
+// workerService returns the service a worker writes through: by default one bound to a
+// session the worker reuses for all of its writes.
+func (bi *BulkIngestor) workerService(ctx context.Context) (*RelationshipService, func(context.Context) error, error) {
+	if bi.sessionPerWrite {
+		return bi.service, func(context.Context) error { return nil }, nil
+	}
+	return bi.service.withSession(ctx)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestOrderedResultsFollowInputOrder(t *testing.T) {
+	// Failures spread over the input, so several workers finish them in any order.
+	fail := map[string]bool{}
+	var want []int
+	for i, user := range testUsers(40) {
+		if i%3 == 0 {
+			fail[user.ID] = true
+			want = append(want, i)
+		}
+	}
+	for run := 0; run < 5; run++ {
+		repo := &failingRepository{fail: fail}
+		ingestor := NewBulkIngestor(NewRelationshipService(repo, nil), 8).WithUserBatchSize(1).WithOrderedResults(true)
+
+		_, err := ingestor.IngestUsers(context.Background(), testUsers(40))
+		var taskErr *TaskError
+		if !errors.As(err, &taskErr) {
+			t.Fatalf("IngestUsers() error = %v, want a *TaskError", err)
+		}
+		if len(taskErr.Errors) != len(want) {
+			t.Fatalf("errors = %d, want %d", len(taskErr.Errors), len(want))
+		}
+		for i, err := range taskErr.Errors {
+			var recErr *RecordError
+			if !errors.As(err, &recErr) {
+				t.Fatalf("error %d = %v, want a *RecordError", i, err)
+			}
+			if recErr.First != want[i] || recErr.Last != want[i] {
+				t.Errorf("error %d covers records %d-%d, want %d", i, recErr.First, recErr.Last, want[i])
+			}
+		}
+	}
+}
+
+func TestOrderedResultsCoverFailedBatches(t *testing.T) {
+	repo := &failingRepository{fail: map[string]bool{"USR-2": true, "USR-9": true}}
+	ingestor := NewBulkIngestor(NewRelationshipService(repo, nil), 4).WithUserBatchSize(4).WithOrderedResults(true)
+
+	_, err := ingestor.IngestUsers(context.Background(), testUsers(10))
+	var taskErr *TaskError
+	if !errors.As(err, &taskErr) {
+		t.Fatalf("IngestUsers() error = %v, want a *TaskError", err)
+	}
+	// USR-2 fails records 0-3 and USR-9 the last batch, 8-9.
+	want := [][2]int{{0, 3}, {8, 9}}
+	if len(taskErr.Errors) != len(want) {
+		t.Fatalf("errors = %v, want batches %v", taskErr.Errors, want)
+	}
+	for i, err := range taskErr.Errors {
+		var recErr *RecordError
+		if !errors.As(err, &recErr) || recErr.First != want[i][0] || recErr.Last != want[i][1] {
+			t.Errorf("error %d = %v, want records %d-%d", i, err, want[i][0], want[i][1])
+		}
+	}
+}
+
+func TestRecordErrorMessage(t *testing.T) {
+	cause := errors.New("boom")
+	single := &RecordError{First: 4, Last: 4, Err: cause}
+	if single.Error() != "record 4: boom" || !errors.Is(single, cause) {
+		t.Errorf("single record error = %q", single.Error())
+	}
+	if batch := (&RecordError{First: 0, Last: 3, Err: cause}); batch.Error() != "records 0-3: boom" {
+		t.Errorf("batch error = %q", batch.Error())
+	}
+}